@@ -10,6 +10,9 @@ Corefile: |-
       errors
       health
       ready
+      {{- if not .Values.controlPlane.coredns.embedded }}
+      reload
+      {{- end }}
       rewrite name regex .*\.nodes\.vcluster\.com kubernetes.default.svc.cluster.local
       kubernetes cluster.local in-addr.arpa ip6.arpa {
           {{- if .Values.controlPlane.coredns.embedded }}
@@ -46,5 +49,8 @@ Corefile: |-
   }
 
   import /etc/coredns/custom/*.server
+  {{- if not .Values.controlPlane.coredns.embedded }}
+  import /etc/coredns/headless/*.server
+  {{- end }}
   {{- end }}
 {{- end -}}
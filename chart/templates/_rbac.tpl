@@ -26,7 +26,9 @@
     .Values.sync.fromHost.ingressClasses.enabled
     .Values.sync.fromHost.storageClasses.enabled
     .Values.sync.fromHost.nodes.enabled
+    .Values.sync.fromHost.nodes.reflectCordons
     .Values.observability.metrics.proxy.nodes
+    .Values.observability.metrics.federation.enabled
     .Values.experimental.multiNamespaceMode.enabled -}}
 {{- true -}}
 {{- end -}}
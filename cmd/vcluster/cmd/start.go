@@ -13,6 +13,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/scheme"
 	"github.com/loft-sh/vcluster/pkg/setup"
 	"github.com/loft-sh/vcluster/pkg/telemetry"
+	"github.com/loft-sh/vcluster/pkg/tracing"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -96,6 +97,15 @@ func ExecuteStart(ctx context.Context, vConfig *config.VirtualClusterConfig) err
 	// init telemetry
 	telemetry.Collector.Init(controlPlaneConfig, controlPlaneNamespace, vConfig)
 
+	// init tracing
+	shutdownTracing, err := tracing.Start(ctx, vConfig.Name, vConfig.Observability.Tracing)
+	if err != nil {
+		return fmt.Errorf("start tracing: %w", err)
+	}
+	defer func() {
+		_ = shutdownTracing(context.Background())
+	}()
+
 	// initialize feature gate from environment
 	err = pro.LicenseInit(ctx, controlPlaneConfig, controlPlaneNamespace, vConfig)
 	if err != nil {
@@ -162,9 +172,15 @@ func ExecuteStart(ctx context.Context, vConfig *config.VirtualClusterConfig) err
 	}
 
 	// start leader election + controllers
-	err = StartLeaderElection(controllerCtx, func() error {
-		return setup.StartControllers(controllerCtx, controlPlaneNamespace, controlPlaneService, controlPlaneConfig)
-	})
+	if vConfig.ControlPlane.StatefulSet.HighAvailability.Sharding.Enabled {
+		err = setup.StartShardedControllers(controllerCtx, controlPlaneNamespace, controlPlaneService, controlPlaneConfig, func(startLeading func() error) error {
+			return StartLeaderElection(controllerCtx, startLeading)
+		})
+	} else {
+		err = StartLeaderElection(controllerCtx, func() error {
+			return setup.StartControllers(controllerCtx, controlPlaneNamespace, controlPlaneService, controlPlaneConfig)
+		})
+	}
 	if err != nil {
 		return fmt.Errorf("start controllers: %w", err)
 	}
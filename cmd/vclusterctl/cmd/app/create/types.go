@@ -18,6 +18,8 @@ type Options struct {
 	Expose          bool
 	ExposeLocal     bool
 
+	ConnectStrategy string
+
 	Connect bool
 	Upgrade bool
 
@@ -3,7 +3,6 @@ package localkubernetes
 import (
 	"context"
 	"fmt"
-	"net/url"
 	"os"
 	"os/exec"
 	"strconv"
@@ -28,24 +27,27 @@ func (c ClusterType) LocalKubernetes() bool {
 		c == ClusterTypeRancherDesktop ||
 		c == ClusterTypeKIND ||
 		c == ClusterTypeMinikube ||
-		c == ClusterTypeK3D
+		c == ClusterTypeK3D ||
+		c == ClusterTypeOrbStack ||
+		c == ClusterTypeColima ||
+		c == ClusterTypeMicroK8s ||
+		c == ClusterTypeK0s
 }
 
-func ExposeLocal(ctx context.Context, vClusterName, vClusterNamespace string, rawConfig *clientcmdapi.Config, vRawConfig *clientcmdapi.Config, service *corev1.Service, localPort int, log log.Logger) (string, error) {
+func ExposeLocal(ctx context.Context, vClusterName, vClusterNamespace string, rawConfig *clientcmdapi.Config, vRawConfig *clientcmdapi.Config, service *corev1.Service, localPort int, strategy ConnectStrategy, log log.Logger) (string, error) {
 	// Timeout to wait for connection before falling back to port-forwarding
 	timeout := time.Second * 30
-	clusterType := DetectClusterType(rawConfig)
-	switch clusterType {
-	case ClusterTypeDockerDesktop:
-		return directConnection(ctx, vRawConfig, service, timeout)
-	case ClusterTypeRancherDesktop:
-		return directConnection(ctx, vRawConfig, service, timeout)
-	case ClusterTypeKIND:
-		return kindProxy(ctx, vClusterName, vClusterNamespace, rawConfig, vRawConfig, service, localPort, timeout, log)
-	case ClusterTypeMinikube:
-		return minikubeProxy(ctx, vClusterName, vClusterNamespace, rawConfig, vRawConfig, service, localPort, timeout, log)
-	case ClusterTypeK3D:
-		return k3dProxy(ctx, vClusterName, vClusterNamespace, rawConfig, vRawConfig, service, localPort, timeout, log)
+	decision := ChooseStrategy(rawConfig, strategy)
+	log.Debugf("Using connect strategy %s: %s", decision.Strategy, decision.Reason)
+	switch decision.Strategy {
+	case ConnectStrategyNodePort:
+		return nodePortConnection(ctx, vRawConfig, service, decision, timeout)
+	case ConnectStrategyProxyContainer:
+		if decision.NodeContainer == "" {
+			return "", nil
+		}
+
+		return nodeProxy(ctx, vClusterName, vClusterNamespace, rawConfig, vRawConfig, service, localPort, decision, timeout, log)
 	default:
 	}
 
@@ -53,113 +55,98 @@ func ExposeLocal(ctx context.Context, vClusterName, vClusterNamespace string, ra
 }
 
 func CleanupLocal(vClusterName, vClusterNamespace string, rawConfig *clientcmdapi.Config, log log.Logger) error {
-	clusterType := DetectClusterType(rawConfig)
-	switch clusterType {
-	case ClusterTypeMinikube:
-		if containerExists(rawConfig.CurrentContext) {
-			return cleanupProxy(vClusterName, vClusterNamespace, rawConfig, log)
-		}
-
-		return nil
-	case ClusterTypeKIND:
-		return cleanupProxy(vClusterName, vClusterNamespace, rawConfig, log)
-	case ClusterTypeK3D:
-		return cleanupProxy(vClusterName, vClusterNamespace, rawConfig, log)
-	default:
+	decision := ChooseStrategy(rawConfig, ConnectStrategyAuto)
+	if decision.ContainerRuntime != "" {
+		return cleanupProxy(vClusterName, vClusterNamespace, rawConfig, decision.ContainerRuntime, log)
 	}
 
 	return nil
 }
 
-func k3dProxy(ctx context.Context, vClusterName, vClusterNamespace string, rawConfig *clientcmdapi.Config, vRawConfig *clientcmdapi.Config, service *corev1.Service, localPort int, timeout time.Duration, log log.Logger) (string, error) {
+func nodeProxy(ctx context.Context, vClusterName, vClusterNamespace string, rawConfig *clientcmdapi.Config, vRawConfig *clientcmdapi.Config, service *corev1.Service, localPort int, decision Decision, timeout time.Duration, log log.Logger) (string, error) {
 	if len(service.Spec.Ports) == 0 {
 		return "", fmt.Errorf("service has %d ports (expected 1 port)", len(service.Spec.Ports))
 	}
 
 	// see if we already have a proxy container running
-	server, err := getServerFromExistingProxyContainer(ctx, vClusterName, vClusterNamespace, rawConfig, vRawConfig, service, log)
+	server, err := getServerFromExistingProxyContainer(ctx, vClusterName, vClusterNamespace, rawConfig, vRawConfig, service, decision, log)
 	if err != nil {
 		return "", err
 	} else if server != "" {
 		return server, nil
 	}
 
-	k3dName := strings.TrimPrefix(rawConfig.CurrentContext, "k3d-")
-	return createProxyContainer(ctx, vClusterName, vClusterNamespace, rawConfig, vRawConfig, service, localPort, timeout, "k3d-"+k3dName+"-server-0", "k3d-"+k3dName, log)
+	// container names are not resolvable in the default bridge network
+	backendHost := decision.NodeContainer
+	if decision.Network == "bridge" {
+		backendHost, err = containerIP(decision.ContainerRuntime, decision.NodeContainer, decision.Network)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return createProxyContainer(ctx, vClusterName, vClusterNamespace, rawConfig, vRawConfig, service, localPort, timeout, backendHost, decision, log)
 }
 
-func minikubeProxy(ctx context.Context, vClusterName, vClusterNamespace string, rawConfig *clientcmdapi.Config, vRawConfig *clientcmdapi.Config, service *corev1.Service, localPort int, timeout time.Duration, log log.Logger) (string, error) {
+func nodePortConnection(ctx context.Context, vRawConfig *clientcmdapi.Config, service *corev1.Service, decision Decision, timeout time.Duration) (string, error) {
 	if len(service.Spec.Ports) == 0 {
 		return "", fmt.Errorf("service has %d ports (expected 1 port)", len(service.Spec.Ports))
 	}
 
-	// check if docker driver or vm
-	minikubeName := rawConfig.CurrentContext
-	if containerExists(minikubeName) {
-		// see if we already have a proxy container running
-		server, err := getServerFromExistingProxyContainer(ctx, vClusterName, vClusterNamespace, rawConfig, vRawConfig, service, log)
+	server := fmt.Sprintf("https://%s:%v", decision.NodeAddress, service.Spec.Ports[0].NodePort)
+	err := waitForConnection(ctx, vRawConfig, server, decision.NodeAddressInsecure, timeout)
+	if err != nil {
+		return "", err
+	}
+
+	return server, nil
+}
+
+// waitForConnection waits until the vcluster is reachable under the given server. If insecure is true,
+// the vRawConfig is changed to skip tls verification after the connection was tested successfully.
+func waitForConnection(ctx context.Context, vRawConfig *clientcmdapi.Config, server string, insecure bool, timeout time.Duration) error {
+	// workaround for the fact that vcluster certificate is not made valid for the node IPs
+	// but avoid modifying the passed config before the connection is tested
+	testvConfig := vRawConfig
+	if insecure {
+		testvConfig = vRawConfig.DeepCopy()
+		for k := range testvConfig.Clusters {
+			testvConfig.Clusters[k].CertificateAuthorityData = nil
+			testvConfig.Clusters[k].InsecureSkipTLSVerify = true
+		}
+	}
+
+	// test local connection
+	var err error
+	waitErr := wait.PollUntilContextTimeout(ctx, time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		err = testConnectionWithServer(ctx, testvConfig, server)
 		if err != nil {
-			return "", err
-		} else if server != "" {
-			return server, nil
+			return false, nil
 		}
 
-		// create proxy container if missing
-		return createProxyContainer(ctx, vClusterName, vClusterNamespace, rawConfig, vRawConfig, service, localPort, timeout, minikubeName, minikubeName, log)
+		return true, nil
+	})
+	if waitErr != nil {
+		return fmt.Errorf("test connection: %w %w", waitErr, err)
 	}
 
-	// in case other type of driver (e.g. VM on linux) is used
-	// check if the service is reacheable directly via the minikube IP
-	c := rawConfig.Contexts[rawConfig.CurrentContext]
-	if c != nil {
-		s := rawConfig.Clusters[c.Cluster]
-		if s != nil {
-			u, err := url.Parse(s.Server)
-			if err == nil {
-				splitted := strings.Split(u.Host, ":")
-				server := fmt.Sprintf("https://%s:%v", splitted[0], service.Spec.Ports[0].NodePort)
-
-				// workaround for the fact that vcluster certificate is not made valid for the node IPs
-				// but avoid modifying the passed config before the connection is tested
-				testvConfig := vRawConfig.DeepCopy()
-				for k := range testvConfig.Clusters {
-					testvConfig.Clusters[k].CertificateAuthorityData = nil
-					testvConfig.Clusters[k].InsecureSkipTLSVerify = true
-				}
-
-				// test local connection
-				waitErr := wait.PollUntilContextTimeout(ctx, time.Second, timeout, true, func(ctx context.Context) (bool, error) {
-					err = testConnectionWithServer(ctx, testvConfig, server)
-					if err != nil {
-						return false, nil
-					}
-
-					return true, nil
-				})
-				if waitErr != nil {
-					return "", fmt.Errorf("test connection: %w %w", waitErr, err)
-				}
-
-				// now it's safe to modify the vRawConfig struct that was passed in as a pointer
-				for k := range vRawConfig.Clusters {
-					vRawConfig.Clusters[k].CertificateAuthorityData = nil
-					vRawConfig.Clusters[k].InsecureSkipTLSVerify = true
-				}
-
-				return server, nil
-			}
+	// now it's safe to modify the vRawConfig struct that was passed in as a pointer
+	if insecure {
+		for k := range vRawConfig.Clusters {
+			vRawConfig.Clusters[k].CertificateAuthorityData = nil
+			vRawConfig.Clusters[k].InsecureSkipTLSVerify = true
 		}
 	}
 
-	return "", nil
+	return nil
 }
 
 func CleanupBackgroundProxy(proxyName string, log log.Logger) error {
 	// check if background proxy container already exists
-	if containerExists(proxyName) {
+	if containerExists(ContainerRuntimeDocker, proxyName) {
 		// remove background proxy container
 		cmd := exec.Command(
-			"docker",
+			ContainerRuntimeDocker,
 			"container",
 			"rm",
 			proxyName,
@@ -171,69 +158,29 @@ func CleanupBackgroundProxy(proxyName string, log log.Logger) error {
 	return nil
 }
 
-func cleanupProxy(vClusterName, vClusterNamespace string, rawConfig *clientcmdapi.Config, log log.Logger) error {
+func cleanupProxy(vClusterName, vClusterNamespace string, rawConfig *clientcmdapi.Config, runtime string, log log.Logger) error {
 	// construct proxy name
 	proxyName := find.VClusterContextName(vClusterName, vClusterNamespace, rawConfig.CurrentContext)
 
 	// check if proxy container already exists
 	cmd := exec.Command(
-		"docker",
+		runtime,
 		"stop",
 		proxyName,
 	)
-	log.Infof("Stopping docker proxy...")
+	log.Infof("Stopping %s proxy...", runtime)
 	_, _ = cmd.Output()
 	return nil
 }
 
-func kindProxy(ctx context.Context, vClusterName, vClusterNamespace string, rawConfig *clientcmdapi.Config, vRawConfig *clientcmdapi.Config, service *corev1.Service, localPort int, timeout time.Duration, log log.Logger) (string, error) {
-	if len(service.Spec.Ports) == 0 {
-		return "", fmt.Errorf("service has %d ports (expected 1 port)", len(service.Spec.Ports))
-	}
-
-	// see if we already have a proxy container running
-	server, err := getServerFromExistingProxyContainer(ctx, vClusterName, vClusterNamespace, rawConfig, vRawConfig, service, log)
-	if err != nil {
-		return "", err
-	} else if server != "" {
-		return server, nil
-	}
-
-	// name is prefixed with kind- and suffixed with -control-plane
-	controlPlane := strings.TrimPrefix(rawConfig.CurrentContext, "kind-") + "-control-plane"
-	return createProxyContainer(ctx, vClusterName, vClusterNamespace, rawConfig, vRawConfig, service, localPort, timeout, controlPlane, "kind", log)
-}
-
-func directConnection(ctx context.Context, vRawConfig *clientcmdapi.Config, service *corev1.Service, timeout time.Duration) (string, error) {
-	if len(service.Spec.Ports) == 0 {
-		return "", fmt.Errorf("service has %d ports (expected 1 port)", len(service.Spec.Ports))
-	}
-
-	server := fmt.Sprintf("https://127.0.0.1:%v", service.Spec.Ports[0].NodePort)
-	var err error
-	waitErr := wait.PollUntilContextTimeout(ctx, time.Second, timeout, true, func(ctx context.Context) (bool, error) {
-		err = testConnectionWithServer(ctx, vRawConfig, server)
-		if err != nil {
-			return false, nil
-		}
-
-		return true, nil
-	})
-	if waitErr != nil {
-		return "", fmt.Errorf("test connection: %w %w", waitErr, err)
-	}
-
-	return server, nil
-}
-
-func createProxyContainer(ctx context.Context, vClusterName, vClusterNamespace string, rawConfig *clientcmdapi.Config, vRawConfig *clientcmdapi.Config, service *corev1.Service, localPort int, timeout time.Duration, backendHost, network string, log log.Logger) (string, error) {
+func createProxyContainer(ctx context.Context, vClusterName, vClusterNamespace string, rawConfig *clientcmdapi.Config, vRawConfig *clientcmdapi.Config, service *corev1.Service, localPort int, timeout time.Duration, backendHost string, decision Decision, log log.Logger) (string, error) {
 	// construct proxy name
 	proxyName := find.VClusterContextName(vClusterName, vClusterNamespace, rawConfig.CurrentContext)
 
 	// in general, we need to run this statement to expose the correct port for this
 	// docker run -d -p LOCAL_PORT:NODE_PORT --rm -e "BACKEND_HOST=NAME-control-plane" -e "BACKEND_PORT=NODE_PORT" --network=NETWORK ghcr.io/loft-sh/docker-tcp-proxy
 	cmd := exec.Command(
-		"docker",
+		decision.ContainerRuntime,
 		"run",
 		"-d",
 		"-p",
@@ -244,26 +191,20 @@ func createProxyContainer(ctx context.Context, vClusterName, vClusterNamespace s
 		fmt.Sprintf("BACKEND_HOST=%s", backendHost),
 		"-e",
 		fmt.Sprintf("BACKEND_PORT=%v", service.Spec.Ports[0].NodePort),
-		fmt.Sprintf("--network=%s", network),
+		fmt.Sprintf("--network=%s", decision.Network),
 		"ghcr.io/loft-sh/docker-tcp-proxy",
 	)
 	log.Infof("Starting proxy container...")
 	out, err := cmd.Output()
 	if err != nil {
-		return "", errors.Errorf("error starting kind proxy: %s %v", string(out), err)
+		return "", errors.Errorf("error starting %s proxy: %s %v", decision.ClusterType, string(out), err)
 	}
 
-	server := fmt.Sprintf("https://127.0.0.1:%v", localPort)
-	waitErr := wait.PollUntilContextTimeout(ctx, time.Second, timeout, true, func(ctx context.Context) (bool, error) {
-		err = testConnectionWithServer(ctx, vRawConfig, server)
-		if err != nil {
-			return false, nil
-		}
-
-		return true, nil
-	})
-	if waitErr != nil {
-		return "", fmt.Errorf("test connection: %w %w", waitErr, err)
+	// the vcluster certificate is not valid for a remote docker host
+	server := fmt.Sprintf("https://%s:%v", decision.ProxyAddress, localPort)
+	err = waitForConnection(ctx, vRawConfig, server, isRemoteAddress(decision.ProxyAddress), timeout)
+	if err != nil {
+		return "", err
 	}
 
 	return server, nil
@@ -299,9 +240,27 @@ func CreateBackgroundProxyContainer(ctx context.Context, vClusterName, vClusterN
 	// check if the background proxy container for this vcluster is running and then remove it.
 	_ = CleanupBackgroundProxy(proxyName, log)
 
+	// bind mounts and the host network refer to the docker host, so for a remote docker context
+	// the kube config is copied into the container and the port is published instead
+	remoteHost := RemoteRuntimeHost(ContainerRuntimeDocker)
+	if remoteHost != "" {
+		log.Infof("Docker context points to remote host %s, make sure the host cluster is reachable from there", remoteHost)
+		err = createRemoteBackgroundProxyContainer(proxyName, kubeConfigPath, vClusterName, vClusterNamespace, localPort)
+		if err != nil {
+			return "", err
+		}
+
+		server := fmt.Sprintf("https://%s:%v", remoteHost, localPort)
+		err = waitForConnection(ctx, vRawConfig, server, true, time.Second*60)
+		if err != nil {
+			return "", err
+		}
+		return server, nil
+	}
+
 	// docker run -d --network=host -v /root/.kube/config:/root/.kube/config ghcr.io/loft-sh/vcluster-cli vcluster connect vcluster -n vcluster --local-port 13300
 	cmd := exec.Command(
-		"docker",
+		ContainerRuntimeDocker,
 		"run",
 		"-d",
 		"-v",
@@ -323,22 +282,54 @@ func CreateBackgroundProxyContainer(ctx context.Context, vClusterName, vClusterN
 		return "", errors.Errorf("error starting background proxy : %s %v", string(out), err)
 	}
 	server := fmt.Sprintf("https://127.0.0.1:%v", localPort)
-	waitErr := wait.PollUntilContextTimeout(ctx, time.Second, time.Second*60, true, func(ctx context.Context) (bool, error) {
-		err = testConnectionWithServer(ctx, vRawConfig, server)
-		if err != nil {
-			return false, nil
-		}
-		return true, nil
-	})
-	if waitErr != nil {
-		return "", fmt.Errorf("test connection: %w %w", waitErr, err)
+	err = waitForConnection(ctx, vRawConfig, server, false, time.Second*60)
+	if err != nil {
+		return "", err
 	}
 	return server, nil
 }
 
+func createRemoteBackgroundProxyContainer(proxyName, kubeConfigPath, vClusterName, vClusterNamespace string, localPort int) error {
+	// docker create -p 13300:13300 -e KUBECONFIG=/tmp/kubeconfig ghcr.io/loft-sh/vcluster-cli vcluster connect vcluster -n vcluster --local-port 13300 --address 0.0.0.0
+	out, err := exec.Command(
+		ContainerRuntimeDocker,
+		"create",
+		"-p",
+		fmt.Sprintf("%v:%v", localPort, localPort),
+		"-e",
+		"KUBECONFIG=/tmp/kubeconfig",
+		fmt.Sprintf("--name=%s", proxyName),
+		"ghcr.io/loft-sh/vcluster-cli"+upgrade.GetVersion(),
+		"vcluster",
+		"connect",
+		vClusterName,
+		"--local-port",
+		strconv.Itoa(localPort),
+		"--address",
+		"0.0.0.0",
+		"-n",
+		vClusterNamespace,
+	).CombinedOutput()
+	if err != nil {
+		return errors.Errorf("error creating background proxy : %s %v", string(out), err)
+	}
+
+	out, err = exec.Command(ContainerRuntimeDocker, "cp", kubeConfigPath, proxyName+":/tmp/kubeconfig").CombinedOutput()
+	if err != nil {
+		return errors.Errorf("error copying kube config into background proxy : %s %v", string(out), err)
+	}
+
+	out, err = exec.Command(ContainerRuntimeDocker, "start", proxyName).CombinedOutput()
+	if err != nil {
+		return errors.Errorf("error starting background proxy : %s %v", string(out), err)
+	}
+
+	return nil
+}
+
 func IsDockerInstalledAndUpAndRunning() bool {
 	cmd := exec.Command(
-		"docker",
+		ContainerRuntimeDocker,
 		"ps",
 	)
 	_, err := cmd.Output()
@@ -372,13 +363,13 @@ func testConnectionWithServer(ctx context.Context, vRawConfig *clientcmdapi.Conf
 	return nil
 }
 
-func getServerFromExistingProxyContainer(ctx context.Context, vClusterName, vClusterNamespace string, rawConfig *clientcmdapi.Config, vRawConfig *clientcmdapi.Config, service *corev1.Service, log log.Logger) (string, error) {
+func getServerFromExistingProxyContainer(ctx context.Context, vClusterName, vClusterNamespace string, rawConfig *clientcmdapi.Config, vRawConfig *clientcmdapi.Config, service *corev1.Service, decision Decision, log log.Logger) (string, error) {
 	// construct proxy name
 	proxyName := find.VClusterContextName(vClusterName, vClusterNamespace, rawConfig.CurrentContext)
 
 	// check if proxy container already exists
 	cmd := exec.Command(
-		"docker",
+		decision.ContainerRuntime,
 		"inspect",
 		proxyName,
 		"-f",
@@ -388,28 +379,20 @@ func getServerFromExistingProxyContainer(ctx context.Context, vClusterName, vClu
 	if err == nil {
 		localPort, err := strconv.Atoi(strings.TrimSpace(string(out)))
 		if err == nil && localPort != 0 {
-			server := fmt.Sprintf("https://127.0.0.1:%v", localPort)
-			waitErr := wait.PollUntilContextTimeout(ctx, time.Second, time.Second*5, true, func(ctx context.Context) (bool, error) {
-				err = testConnectionWithServer(ctx, vRawConfig, server)
-				if err != nil {
-					return false, nil
-				}
-
-				return true, nil
-			})
-			if waitErr != nil {
-				// return err here as waitErr is only timed out
-				return "", errors.Wrap(err, "test connection")
+			server := fmt.Sprintf("https://%s:%v", decision.ProxyAddress, localPort)
+			err = waitForConnection(ctx, vRawConfig, server, isRemoteAddress(decision.ProxyAddress), time.Second*5)
+			if err != nil {
+				return "", err
 			}
 
 			return server, nil
 		}
 	} else {
-		log.Debugf("Error running docker inspect with go template: %v", err)
+		log.Debugf("Error running %s inspect with go template: %v", decision.ContainerRuntime, err)
 	}
 
-	if containerExists(proxyName) {
-		err := cleanupProxy(vClusterName, vClusterNamespace, rawConfig, log)
+	if containerExists(decision.ContainerRuntime, proxyName) {
+		err := cleanupProxy(vClusterName, vClusterNamespace, rawConfig, decision.ContainerRuntime, log)
 		if err != nil {
 			return "", err
 		}
@@ -418,13 +401,6 @@ func getServerFromExistingProxyContainer(ctx context.Context, vClusterName, vClu
 	return "", nil
 }
 
-func containerExists(containerName string) bool {
-	cmd := exec.Command(
-		"docker",
-		"inspect",
-		"--type=container",
-		containerName,
-	)
-	_, err := cmd.Output()
-	return err == nil
+func isRemoteAddress(address string) bool {
+	return address != "" && address != "127.0.0.1"
 }
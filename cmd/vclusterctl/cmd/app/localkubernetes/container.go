@@ -0,0 +1,117 @@
+package localkubernetes
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const (
+	ContainerRuntimeDocker = "docker"
+	ContainerRuntimePodman = "podman"
+)
+
+// detectContainerRuntime returns the cli that knows the given container, kind for example
+// can run its nodes in podman instead of docker
+func detectContainerRuntime(containerName string) string {
+	for _, runtime := range []string{ContainerRuntimeDocker, ContainerRuntimePodman} {
+		if containerExists(runtime, containerName) {
+			return runtime
+		}
+	}
+
+	return ""
+}
+
+// RuntimeEndpoint returns the endpoint the given container runtime cli talks to, which
+// is the endpoint of the active docker context for docker
+func RuntimeEndpoint(runtime string) string {
+	if runtime == ContainerRuntimePodman {
+		return os.Getenv("CONTAINER_HOST")
+	}
+	if dockerHost := os.Getenv("DOCKER_HOST"); dockerHost != "" {
+		return dockerHost
+	}
+
+	args := []string{"context", "inspect", "--format", "{{ .Endpoints.docker.Host }}"}
+	if dockerContext := os.Getenv("DOCKER_CONTEXT"); dockerContext != "" {
+		args = append(args, dockerContext)
+	}
+	out, err := exec.Command(ContainerRuntimeDocker, args...).Output()
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+// RemoteRuntimeHost returns the host of the container runtime if it points to a remote
+// machine, ports published by containers are then not reachable via localhost
+func RemoteRuntimeHost(runtime string) string {
+	endpoint := RuntimeEndpoint(runtime)
+	if endpoint == "" {
+		return ""
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return ""
+	}
+	switch u.Scheme {
+	case "tcp", "ssh", "http", "https":
+	default:
+		// unix sockets and named pipes are always local
+		return ""
+	}
+
+	host := u.Hostname()
+	if host == "" || host == "localhost" || host == "127.0.0.1" || host == "::1" {
+		return ""
+	}
+
+	return host
+}
+
+// proxyAddress returns the address published container ports are reachable under and
+// extends the reason if that address is a remote host
+func proxyAddress(runtime, reason string) (string, string) {
+	remoteHost := RemoteRuntimeHost(runtime)
+	if remoteHost == "" {
+		return "127.0.0.1", reason
+	}
+
+	return remoteHost, fmt.Sprintf("%s (%s context points to remote host %s)", reason, runtime, remoteHost)
+}
+
+func containerExists(runtime, containerName string) bool {
+	cmd := exec.Command(
+		runtime,
+		"inspect",
+		"--type=container",
+		containerName,
+	)
+	_, err := cmd.Output()
+	return err == nil
+}
+
+func containerIP(runtime, containerName, network string) (string, error) {
+	out, err := exec.Command(
+		runtime,
+		"inspect",
+		containerName,
+		"-f",
+		fmt.Sprintf("{{ (index .NetworkSettings.Networks %q).IPAddress }}", network),
+	).Output()
+	if err != nil {
+		return "", fmt.Errorf("inspect container %s: %w", containerName, err)
+	}
+
+	ip := strings.TrimSpace(string(out))
+	if ip == "" {
+		return "", fmt.Errorf("container %s has no ip in network %s", containerName, network)
+	}
+
+	return ip, nil
+}
@@ -24,6 +24,8 @@ const (
 	ClusterTypeK3D            ClusterType = "k3d"
 	ClusterTypeRancherDesktop ClusterType = "rancher-desktop"
 	ClusterTypeColima         ClusterType = "colima"
+	ClusterTypeOrbStack       ClusterType = "orbstack"
+	ClusterTypeK0s            ClusterType = "k0s"
 )
 
 // DetectClusterType detects the k8s distro locally.
@@ -68,6 +70,10 @@ func DetectClusterType(config *clientcmdapi.Config) ClusterType {
 		return ClusterTypeRancherDesktop
 	} else if strings.HasPrefix(cn, "colima") {
 		return ClusterTypeColima
+	} else if strings.HasPrefix(cn, "orbstack") {
+		return ClusterTypeOrbStack
+	} else if strings.HasPrefix(cn, "k0s") {
+		return ClusterTypeK0s
 	}
 
 	loc := c.LocationOfOrigin
@@ -0,0 +1,216 @@
+package localkubernetes
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ConnectStrategy defines how the cli makes a virtual cluster reachable from the local machine
+type ConnectStrategy string
+
+func (c ConnectStrategy) String() string { return string(c) }
+
+const (
+	// ConnectStrategyAuto picks a strategy based on the detected cluster type
+	ConnectStrategyAuto ConnectStrategy = ""
+	// ConnectStrategyNodePort connects directly to the NodePort of the vcluster service
+	ConnectStrategyNodePort ConnectStrategy = "nodeport"
+	// ConnectStrategyProxyContainer starts a container that proxies to the vcluster
+	ConnectStrategyProxyContainer ConnectStrategy = "proxy-container"
+	// ConnectStrategyPortForward port-forwards to the vcluster pod
+	ConnectStrategyPortForward ConnectStrategy = "port-forward"
+	// ConnectStrategyIngress connects through the vcluster control plane ingress
+	ConnectStrategyIngress ConnectStrategy = "ingress"
+)
+
+// ConnectStrategies are all strategies that can be selected by the user
+var ConnectStrategies = []ConnectStrategy{
+	ConnectStrategyNodePort,
+	ConnectStrategyProxyContainer,
+	ConnectStrategyPortForward,
+	ConnectStrategyIngress,
+}
+
+// ParseConnectStrategy parses a user specified strategy, an empty string means auto detection
+func ParseConnectStrategy(strategy string) (ConnectStrategy, error) {
+	if strategy == "" || strategy == "auto" {
+		return ConnectStrategyAuto, nil
+	}
+
+	for _, s := range ConnectStrategies {
+		if string(s) == strategy {
+			return s, nil
+		}
+	}
+
+	allowed := make([]string, 0, len(ConnectStrategies))
+	for _, s := range ConnectStrategies {
+		allowed = append(allowed, string(s))
+	}
+	return "", fmt.Errorf("unsupported connect strategy %s, please select one of: %s", strategy, strings.Join(allowed, ", "))
+}
+
+// Decision describes the strategy that was chosen to connect to a vcluster and why
+type Decision struct {
+	ClusterType ClusterType
+	Strategy    ConnectStrategy
+	Reason      string
+
+	// NodeAddress is the address NodePorts are reachable under for the nodeport strategy
+	NodeAddress string
+	// NodeAddressInsecure is true if the vcluster certificate is not valid for NodeAddress
+	NodeAddressInsecure bool
+
+	// ContainerRuntime is the cli (docker or podman) that manages the cluster node containers
+	ContainerRuntime string
+	// NodeContainer is the container the proxy container forwards traffic to
+	NodeContainer string
+	// Network is the container network the proxy container is started in
+	Network string
+	// ProxyAddress is the address ports published by the container runtime are reachable under
+	ProxyAddress string
+}
+
+// UsesLocalKubernetes returns true if the vcluster should be deployed with a NodePort service
+func (d Decision) UsesLocalKubernetes() bool {
+	return d.Strategy == ConnectStrategyNodePort || (d.Strategy == ConnectStrategyProxyContainer && d.NodeContainer != "")
+}
+
+// ChooseStrategy decides how to connect to a vcluster in the current context of the given config.
+// If override is set, it always wins over the detected strategy.
+func ChooseStrategy(rawConfig *clientcmdapi.Config, override ConnectStrategy) Decision {
+	clusterType := DetectClusterType(rawConfig)
+	nodeContainer, network := nodeContainerName(rawConfig, clusterType)
+	containerRuntime := ""
+	if nodeContainer != "" {
+		containerRuntime = detectContainerRuntime(nodeContainer)
+	}
+
+	decision := chooseStrategy(clusterType, override, nodeContainer, network, containerRuntime, apiServerHost(rawConfig))
+	if decision.ContainerRuntime != "" {
+		decision.ProxyAddress, decision.Reason = proxyAddress(decision.ContainerRuntime, decision.Reason)
+	} else if decision.Strategy == ConnectStrategyProxyContainer {
+		decision.ProxyAddress, decision.Reason = proxyAddress("docker", decision.Reason)
+	}
+
+	return decision
+}
+
+func chooseStrategy(clusterType ClusterType, override ConnectStrategy, nodeContainer, network, containerRuntime, apiServerHost string) Decision {
+	decision := Decision{
+		ClusterType: clusterType,
+		Strategy:    override,
+	}
+	if containerRuntime != "" {
+		decision.ContainerRuntime = containerRuntime
+		decision.NodeContainer = nodeContainer
+		decision.Network = network
+	}
+
+	switch override {
+	case ConnectStrategyNodePort:
+		decision.NodeAddress, decision.NodeAddressInsecure = nodePortAddress(clusterType, apiServerHost)
+		decision.Reason = fmt.Sprintf("strategy nodeport was requested, NodePorts are expected to be reachable via %s", decision.NodeAddress)
+		return decision
+	case ConnectStrategyProxyContainer:
+		if decision.NodeContainer != "" {
+			decision.Reason = fmt.Sprintf("strategy proxy-container was requested, will proxy to node container %s via %s", decision.NodeContainer, decision.ContainerRuntime)
+		} else {
+			decision.Reason = "strategy proxy-container was requested and no node container was found, will start a background proxy container"
+		}
+		return decision
+	case ConnectStrategyPortForward:
+		decision.Reason = "strategy port-forward was requested"
+		return decision
+	case ConnectStrategyIngress:
+		decision.Reason = "strategy ingress was requested, will use the host of the vcluster control plane ingress"
+		return decision
+	}
+
+	switch clusterType {
+	case ClusterTypeDockerDesktop, ClusterTypeRancherDesktop, ClusterTypeOrbStack, ClusterTypeColima:
+		decision.Strategy = ConnectStrategyNodePort
+		decision.NodeAddress = "127.0.0.1"
+		decision.Reason = fmt.Sprintf("detected %s, which forwards NodePorts to localhost", clusterType)
+	case ClusterTypeMicroK8s:
+		decision.Strategy = ConnectStrategyNodePort
+		decision.NodeAddress, decision.NodeAddressInsecure = nodePortAddress(clusterType, apiServerHost)
+		decision.Reason = fmt.Sprintf("detected %s, NodePorts are reachable via the API server address %s", clusterType, decision.NodeAddress)
+	case ClusterTypeKIND, ClusterTypeK3D, ClusterTypeMinikube, ClusterTypeK0s:
+		if decision.NodeContainer != "" {
+			decision.Strategy = ConnectStrategyProxyContainer
+			decision.Reason = fmt.Sprintf("detected %s with node container %s running in %s, NodePorts are only reachable through a proxy container", clusterType, decision.NodeContainer, decision.ContainerRuntime)
+		} else if clusterType == ClusterTypeMinikube || clusterType == ClusterTypeK0s {
+			// minikube with a VM driver or k0s running directly on a host
+			decision.Strategy = ConnectStrategyNodePort
+			decision.NodeAddress, decision.NodeAddressInsecure = nodePortAddress(clusterType, apiServerHost)
+			decision.Reason = fmt.Sprintf("detected %s without a node container, NodePorts are reachable via the API server address %s", clusterType, decision.NodeAddress)
+		} else {
+			decision.Strategy = ConnectStrategyPortForward
+			decision.Reason = fmt.Sprintf("detected %s, but node container %s was not found in docker or podman", clusterType, nodeContainer)
+		}
+	default:
+		decision.Strategy = ConnectStrategyPortForward
+		decision.Reason = fmt.Sprintf("cluster type %s is not a known local distro", clusterType)
+	}
+
+	return decision
+}
+
+// nodeContainerName returns the name and network of the container that runs the cluster node for container based distros
+func nodeContainerName(rawConfig *clientcmdapi.Config, clusterType ClusterType) (string, string) {
+	switch clusterType {
+	case ClusterTypeKIND:
+		// name is prefixed with kind- and suffixed with -control-plane
+		return strings.TrimPrefix(rawConfig.CurrentContext, "kind-") + "-control-plane", "kind"
+	case ClusterTypeK3D:
+		k3dName := strings.TrimPrefix(rawConfig.CurrentContext, "k3d-")
+		return "k3d-" + k3dName + "-server-0", "k3d-" + k3dName
+	case ClusterTypeMinikube:
+		return rawConfig.CurrentContext, rawConfig.CurrentContext
+	case ClusterTypeK0s:
+		// k0s in docker is usually started with --name k0s in the default bridge network
+		return "k0s", "bridge"
+	}
+
+	return "", ""
+}
+
+func nodePortAddress(clusterType ClusterType, apiServerHost string) (string, bool) {
+	switch clusterType {
+	case ClusterTypeDockerDesktop, ClusterTypeRancherDesktop, ClusterTypeOrbStack, ClusterTypeColima:
+		return "127.0.0.1", false
+	}
+	if apiServerHost == "" {
+		return "127.0.0.1", false
+	}
+
+	// the vcluster certificate is not made valid for the node IPs
+	return apiServerHost, apiServerHost != "127.0.0.1" && apiServerHost != "localhost"
+}
+
+func apiServerHost(rawConfig *clientcmdapi.Config) string {
+	if rawConfig == nil || rawConfig.Contexts == nil || rawConfig.Clusters == nil {
+		return ""
+	}
+
+	c := rawConfig.Contexts[rawConfig.CurrentContext]
+	if c == nil {
+		return ""
+	}
+
+	cl := rawConfig.Clusters[c.Cluster]
+	if cl == nil {
+		return ""
+	}
+
+	u, err := url.Parse(cl.Server)
+	if err != nil {
+		return ""
+	}
+
+	return u.Hostname()
+}
@@ -0,0 +1,120 @@
+package localkubernetes
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestChooseStrategy(t *testing.T) {
+	testTable := []struct {
+		desc             string
+		clusterType      ClusterType
+		override         ConnectStrategy
+		nodeContainer    string
+		network          string
+		containerRuntime string
+		apiServerHost    string
+
+		expectedStrategy    ConnectStrategy
+		expectedNodeAddress string
+		expectedInsecure    bool
+		expectedContainer   string
+	}{
+		{
+			desc:                "orbstack forwards node ports",
+			clusterType:         ClusterTypeOrbStack,
+			expectedStrategy:    ConnectStrategyNodePort,
+			expectedNodeAddress: "127.0.0.1",
+		},
+		{
+			desc:                "colima forwards node ports",
+			clusterType:         ClusterTypeColima,
+			apiServerHost:       "192.168.106.2",
+			expectedStrategy:    ConnectStrategyNodePort,
+			expectedNodeAddress: "127.0.0.1",
+		},
+		{
+			desc:                "microk8s uses the api server address",
+			clusterType:         ClusterTypeMicroK8s,
+			apiServerHost:       "192.168.64.5",
+			expectedStrategy:    ConnectStrategyNodePort,
+			expectedNodeAddress: "192.168.64.5",
+			expectedInsecure:    true,
+		},
+		{
+			desc:              "kind in podman",
+			clusterType:       ClusterTypeKIND,
+			nodeContainer:     "test-control-plane",
+			network:           "kind",
+			containerRuntime:  ContainerRuntimePodman,
+			expectedStrategy:  ConnectStrategyProxyContainer,
+			expectedContainer: "test-control-plane",
+		},
+		{
+			desc:             "kind without node container",
+			clusterType:      ClusterTypeKIND,
+			nodeContainer:    "test-control-plane",
+			expectedStrategy: ConnectStrategyPortForward,
+		},
+		{
+			desc:              "k0s in docker",
+			clusterType:       ClusterTypeK0s,
+			nodeContainer:     "k0s",
+			network:           "bridge",
+			containerRuntime:  ContainerRuntimeDocker,
+			expectedStrategy:  ConnectStrategyProxyContainer,
+			expectedContainer: "k0s",
+		},
+		{
+			desc:                "k0s on a host",
+			clusterType:         ClusterTypeK0s,
+			nodeContainer:       "k0s",
+			apiServerHost:       "10.0.0.3",
+			expectedStrategy:    ConnectStrategyNodePort,
+			expectedNodeAddress: "10.0.0.3",
+			expectedInsecure:    true,
+		},
+		{
+			desc:             "unknown cluster",
+			clusterType:      ClusterTypeUnknown,
+			expectedStrategy: ConnectStrategyPortForward,
+		},
+		{
+			desc:             "override wins",
+			clusterType:      ClusterTypeDockerDesktop,
+			override:         ConnectStrategyIngress,
+			expectedStrategy: ConnectStrategyIngress,
+		},
+		{
+			desc:                "override nodeport on unknown cluster",
+			clusterType:         ClusterTypeUnknown,
+			override:            ConnectStrategyNodePort,
+			apiServerHost:       "localhost",
+			expectedStrategy:    ConnectStrategyNodePort,
+			expectedNodeAddress: "localhost",
+		},
+	}
+
+	for _, testCase := range testTable {
+		decision := chooseStrategy(testCase.clusterType, testCase.override, testCase.nodeContainer, testCase.network, testCase.containerRuntime, testCase.apiServerHost)
+		assert.Equal(t, decision.Strategy, testCase.expectedStrategy, "unexpected strategy in test case %s", testCase.desc)
+		assert.Equal(t, decision.NodeAddress, testCase.expectedNodeAddress, "unexpected node address in test case %s", testCase.desc)
+		assert.Equal(t, decision.NodeAddressInsecure, testCase.expectedInsecure, "unexpected insecure in test case %s", testCase.desc)
+		assert.Equal(t, decision.NodeContainer, testCase.expectedContainer, "unexpected node container in test case %s", testCase.desc)
+		assert.Assert(t, decision.Reason != "", "expected reason in test case %s", testCase.desc)
+	}
+}
+
+func TestParseConnectStrategy(t *testing.T) {
+	strategy, err := ParseConnectStrategy("")
+	assert.NilError(t, err)
+	assert.Equal(t, strategy, ConnectStrategyAuto)
+
+	strategy, err = ParseConnectStrategy("proxy-container")
+	assert.NilError(t, err)
+	assert.Equal(t, strategy, ConnectStrategyProxyContainer)
+
+	_, err = ParseConnectStrategy("vpn")
+	assert.ErrorContains(t, err, "unsupported connect strategy vpn")
+}
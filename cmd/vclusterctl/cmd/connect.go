@@ -63,6 +63,8 @@ type ConnectCmd struct {
 	KubeConfig                string
 	Project                   string
 	ServiceAccount            string
+	ConnectStrategy           string
+	connectStrategy           localkubernetes.ConnectStrategy
 	LocalPort                 int
 	ServiceAccountExpiration  int
 	Print                     bool
@@ -120,6 +122,7 @@ vcluster connect test -n test -- kubectl get ns
 	cobraCmd.Flags().IntVar(&cmd.ServiceAccountExpiration, "token-expiration", 0, "If specified, vcluster will create the service account token for the given duration in seconds. Defaults to eternal")
 	cobraCmd.Flags().BoolVar(&cmd.Insecure, "insecure", false, "If specified, vcluster will create the kube config with insecure-skip-tls-verify")
 	cobraCmd.Flags().BoolVar(&cmd.BackgroundProxy, "background-proxy", false, "If specified, vcluster will create the background proxy in docker [its mainly used for vclusters with no nodeport service.]")
+	cobraCmd.Flags().StringVar(&cmd.ConnectStrategy, "connect-strategy", "", "If specified, overrides how vcluster makes the virtual cluster reachable locally. One of: nodeport, proxy-container, port-forward, ingress. Run vcluster doctor to see which strategy is detected")
 
 	// pro
 	cobraCmd.Flags().StringVar(&cmd.Project, "project", "", "[PRO] The pro project the vcluster is in")
@@ -165,6 +168,12 @@ func (cmd *ConnectCmd) validateFlags() error {
 		return fmt.Errorf("expected --service-account to be defined as well")
 	}
 
+	connectStrategy, err := localkubernetes.ParseConnectStrategy(cmd.ConnectStrategy)
+	if err != nil {
+		return err
+	}
+	cmd.connectStrategy = connectStrategy
+
 	return nil
 }
 
@@ -216,6 +225,9 @@ func (cmd *ConnectCmd) validateProFlags() error {
 	if cmd.Address != "" {
 		return fmt.Errorf("cannot use --address with a pro vCluster")
 	}
+	if cmd.ConnectStrategy != "" {
+		return fmt.Errorf("cannot use --connect-strategy with a pro vCluster")
+	}
 
 	return nil
 }
@@ -592,6 +604,20 @@ func (cmd *ConnectCmd) getVClusterKubeConfig(ctx context.Context, vclusterName s
 }
 
 func (cmd *ConnectCmd) setServerIfExposed(ctx context.Context, vClusterName string, vClusterConfig *clientcmdapi.Config) error {
+	switch cmd.connectStrategy {
+	case localkubernetes.ConnectStrategyPortForward:
+		return nil
+	case localkubernetes.ConnectStrategyIngress:
+		return cmd.setServerFromIngress(ctx, vClusterName)
+	case localkubernetes.ConnectStrategyProxyContainer:
+		decision := localkubernetes.ChooseStrategy(&cmd.rawConfig, cmd.connectStrategy)
+		if decision.NodeContainer == "" {
+			cmd.Log.Infof("No node container found for cluster type %s, will use a background proxy container", decision.ClusterType)
+			cmd.BackgroundProxy = true
+			return nil
+		}
+	}
+
 	printedWaiting := false
 	err := wait.PollUntilContextTimeout(ctx, time.Second*2, time.Minute*5, true, func(ctx context.Context) (done bool, err error) {
 		// first check for load balancer service, look for the other service if it's not there
@@ -615,7 +641,7 @@ func (cmd *ConnectCmd) setServerIfExposed(ctx context.Context, vClusterName stri
 
 		// not a load balancer? Then don't wait
 		if service.Spec.Type == corev1.ServiceTypeNodePort {
-			server, err := localkubernetes.ExposeLocal(ctx, vClusterName, cmd.Namespace, &cmd.rawConfig, vClusterConfig, service, cmd.LocalPort, cmd.Log)
+			server, err := localkubernetes.ExposeLocal(ctx, vClusterName, cmd.Namespace, &cmd.rawConfig, vClusterConfig, service, cmd.LocalPort, cmd.connectStrategy, cmd.Log)
 			if err != nil {
 				cmd.Log.Warnf("Error exposing local vcluster, will fallback to port-forwarding: %v", err)
 			}
@@ -623,6 +649,10 @@ func (cmd *ConnectCmd) setServerIfExposed(ctx context.Context, vClusterName stri
 			cmd.Server = server
 			return true, nil
 		} else if service.Spec.Type != corev1.ServiceTypeLoadBalancer {
+			if cmd.connectStrategy == localkubernetes.ConnectStrategyNodePort {
+				cmd.Log.Warnf("Service %s/%s is of type %s instead of NodePort, will fallback to port-forwarding", cmd.Namespace, vClusterName, service.Spec.Type)
+			}
+
 			return true, nil
 		}
 
@@ -655,6 +685,30 @@ func (cmd *ConnectCmd) setServerIfExposed(ctx context.Context, vClusterName stri
 	return nil
 }
 
+func (cmd *ConnectCmd) setServerFromIngress(ctx context.Context, vClusterName string) error {
+	ingress, err := cmd.kubeClient.NetworkingV1().Ingresses(cmd.Namespace).Get(ctx, vClusterName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return fmt.Errorf("vcluster %s has no ingress, please enable controlPlane.ingress or choose another --connect-strategy", vClusterName)
+		}
+
+		return errors.Wrap(err, "get vcluster ingress")
+	}
+
+	for _, rule := range ingress.Spec.Rules {
+		if rule.Host != "" {
+			cmd.Server = rule.Host
+			break
+		}
+	}
+	if cmd.Server == "" {
+		return fmt.Errorf("ingress %s/%s has no host defined", cmd.Namespace, vClusterName)
+	}
+
+	cmd.Log.Infof("Using vcluster %s ingress endpoint: %s", vClusterName, cmd.Server)
+	return nil
+}
+
 // exchangeContextName switches the context name specified in the remote kubeconfig with
 // the context name specified by the user. It cannot correctly handle kubeconfigs with multiple entries
 // for clusters, authInfos, contexts, but ideally this is pointed at a secret created by us.
@@ -101,6 +101,7 @@ vcluster create test --namespace test
 	cobraCmd.Flags().BoolVar(&cmd.Expose, "expose", false, "If true will create a load balancer service to expose the vcluster endpoint")
 
 	cobraCmd.Flags().BoolVar(&cmd.Connect, "connect", true, "If true will run vcluster connect directly after the vcluster was created")
+	cobraCmd.Flags().StringVar(&cmd.ConnectStrategy, "connect-strategy", "", "If specified, overrides how vcluster makes the virtual cluster reachable locally. One of: nodeport, proxy-container, port-forward, ingress")
 	cobraCmd.Flags().BoolVar(&cmd.Upgrade, "upgrade", false, "If true will try to upgrade the vcluster instead of failing if it already exists")
 	cobraCmd.Flags().BoolVar(&cmd.DisablePro, "disable-pro", false, "If true vcluster will not try to create a vCluster.Pro. You can also use 'vcluster logout' to prevent vCluster from creating any pro clusters")
 
@@ -250,6 +251,7 @@ func (cmd *CreateCmd) Run(ctx context.Context, args []string) error {
 					UpdateCurrent:         cmd.UpdateCurrent,
 					KubeConfigContextName: cmd.KubeConfigContextName,
 					KubeConfig:            "./kubeconfig.yaml",
+					ConnectStrategy:       cmd.ConnectStrategy,
 					Log:                   cmd.log,
 				}
 
@@ -274,6 +276,7 @@ func (cmd *CreateCmd) Run(ctx context.Context, args []string) error {
 			UpdateCurrent:         cmd.UpdateCurrent,
 			KubeConfigContextName: cmd.KubeConfigContextName,
 			KubeConfig:            "./kubeconfig.yaml",
+			ConnectStrategy:       cmd.ConnectStrategy,
 			Log:                   cmd.log,
 		}
 
@@ -403,9 +406,13 @@ func (cmd *CreateCmd) ToChartOptions(kubernetesVersion *version.Info, log log.Lo
 	}
 
 	// check if we should create with node port
-	clusterType := localkubernetes.DetectClusterType(&cmd.rawConfig)
-	if cmd.ExposeLocal && clusterType.LocalKubernetes() {
-		cmd.log.Infof("Detected local kubernetes cluster %s. Will deploy vcluster with a NodePort & sync real nodes", clusterType)
+	connectStrategy, err := localkubernetes.ParseConnectStrategy(cmd.ConnectStrategy)
+	if err != nil {
+		return nil, err
+	}
+	decision := localkubernetes.ChooseStrategy(&cmd.rawConfig, connectStrategy)
+	if cmd.ExposeLocal && decision.UsesLocalKubernetes() {
+		cmd.log.Infof("Detected local kubernetes cluster %s (%s). Will deploy vcluster with a NodePort & sync real nodes", decision.ClusterType, decision.Reason)
 		cmd.localCluster = true
 	}
 
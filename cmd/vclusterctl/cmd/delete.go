@@ -11,6 +11,7 @@ import (
 	loftctlUtil "github.com/loft-sh/loftctl/v3/pkg/util"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/app/localkubernetes"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/find"
+	"github.com/loft-sh/vcluster/pkg/lifecycle"
 	"github.com/loft-sh/vcluster/pkg/procli"
 	"github.com/loft-sh/vcluster/pkg/util/clihelper"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
@@ -22,6 +23,7 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/loft-sh/log"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/flags"
@@ -235,6 +237,22 @@ func (cmd *DeleteCmd) Run(cobraCmd *cobra.Command, args []string) error {
 		// delete all namespaces
 		if namespaces != nil && len(namespaces.Items) > 0 {
 			for _, namespace := range namespaces.Items {
+				// adopted namespaces existed before the virtual cluster, so only remove what the virtual cluster created
+				if lifecycle.IsAdoptedNamespace(&namespace) {
+					hostClient, err := ctrlclient.New(cmd.restConfig, ctrlclient.Options{})
+					if err != nil {
+						return errors.Wrap(err, "create host client")
+					}
+
+					err = lifecycle.ReleaseAdoptedNamespace(ctx, client, hostClient, &namespace)
+					if err != nil {
+						return errors.Wrap(err, "release adopted namespace")
+					}
+
+					cmd.log.Donef("Successfully released adopted virtual cluster namespace %s", namespace.Name)
+					continue
+				}
+
 				err = client.CoreV1().Namespaces().Delete(ctx, namespace.Name, metav1.DeleteOptions{})
 				if err != nil {
 					if !kerrors.IsNotFound(err) {
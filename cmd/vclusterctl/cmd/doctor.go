@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/app/localkubernetes"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/flags"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// DoctorCmd holds the doctor cmd flags
+type DoctorCmd struct {
+	*flags.GlobalFlags
+
+	ConnectStrategy string
+
+	log log.Logger
+}
+
+// NewDoctorCmd creates a new command
+func NewDoctorCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &DoctorCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Checks how vcluster connects to virtual clusters in the current context",
+		Long: `
+#######################################################
+################### vcluster doctor ###################
+#######################################################
+Doctor detects the kubernetes distro of the current
+context and explains which strategy vcluster connect
+uses to make a virtual cluster reachable and why
+
+Example:
+vcluster doctor
+vcluster doctor --connect-strategy port-forward
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return cmd.Run()
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.ConnectStrategy, "connect-strategy", "", "If specified, checks the given strategy instead of the detected one. One of: nodeport, proxy-container, port-forward, ingress")
+	return cobraCmd
+}
+
+// Run executes the functionality
+func (cmd *DoctorCmd) Run() error {
+	connectStrategy, err := localkubernetes.ParseConnectStrategy(cmd.ConnectStrategy)
+	if err != nil {
+		return err
+	}
+
+	rawConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{
+		CurrentContext: cmd.Context,
+	}).RawConfig()
+	if err != nil {
+		return fmt.Errorf("load kube config: %w", err)
+	}
+	if cmd.Context != "" {
+		rawConfig.CurrentContext = cmd.Context
+	}
+
+	decision := localkubernetes.ChooseStrategy(&rawConfig, connectStrategy)
+	cmd.log.WriteString(logrus.InfoLevel, fmt.Sprintf("Kube context:      %s\n", rawConfig.CurrentContext))
+	cmd.log.WriteString(logrus.InfoLevel, fmt.Sprintf("Cluster type:      %s\n", decision.ClusterType))
+	if decision.ContainerRuntime != "" {
+		cmd.log.WriteString(logrus.InfoLevel, fmt.Sprintf("Node container:    %s (%s, network %s)\n", decision.NodeContainer, decision.ContainerRuntime, decision.Network))
+	}
+	if endpoint := localkubernetes.RuntimeEndpoint(localkubernetes.ContainerRuntimeDocker); endpoint != "" {
+		cmd.log.WriteString(logrus.InfoLevel, fmt.Sprintf("Docker endpoint:   %s\n", endpoint))
+	}
+	cmd.log.WriteString(logrus.InfoLevel, fmt.Sprintf("Connect strategy:  %s\n", decision.Strategy))
+	cmd.log.WriteString(logrus.InfoLevel, fmt.Sprintf("Reason:            %s\n", decision.Reason))
+
+	// the proxy strategies need a working container runtime
+	if decision.Strategy == localkubernetes.ConnectStrategyProxyContainer && decision.ContainerRuntime == "" && !localkubernetes.IsDockerInstalledAndUpAndRunning() {
+		cmd.log.Warnf("Strategy %s needs docker, but docker does not seem to be running. vcluster connect will fallback to port-forwarding", decision.Strategy)
+	} else if decision.Strategy == localkubernetes.ConnectStrategyPortForward {
+		cmd.log.Infof("vcluster connect will port-forward to the vcluster pod, the terminal needs to stay open while using the virtual cluster")
+	}
+
+	return nil
+}
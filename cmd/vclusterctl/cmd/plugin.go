@@ -0,0 +1,376 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/app/create"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/find"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/flags"
+	"github.com/loft-sh/vcluster/pkg/helm"
+	"github.com/loft-sh/vcluster/pkg/util/clihelper"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// NewPluginCmd creates a new command
+func NewPluginCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	pluginCmd := &cobra.Command{
+		Use:   "plugin",
+		Short: "Manages vcluster plugins",
+		Long: `
+#######################################################
+################### vcluster plugin ###################
+#######################################################
+Install, remove, list and scaffold vcluster plugins
+	`,
+		Args: cobra.NoArgs,
+	}
+
+	pluginCmd.AddCommand(newPluginInstallCmd(globalFlags))
+	pluginCmd.AddCommand(newPluginRemoveCmd(globalFlags))
+	pluginCmd.AddCommand(newPluginListCmd(globalFlags))
+	pluginCmd.AddCommand(newPluginInitCmd())
+	return pluginCmd
+}
+
+// PluginInstallCmd holds the cmd flags
+type PluginInstallCmd struct {
+	*flags.GlobalFlags
+	log log.Logger
+
+	Image           string
+	ImagePullPolicy string
+	ConfigFile      string
+	Optional        bool
+	pluginReleaseOptions
+}
+
+type pluginReleaseOptions struct {
+	ChartRepo string
+	Timeout   time.Duration
+}
+
+func newPluginInstallCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &PluginInstallCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "install VCLUSTER_NAME PLUGIN_NAME",
+		Short: "Installs a plugin into a virtual cluster",
+		Long: `
+#######################################################
+############### vcluster plugin install ###############
+#######################################################
+Adds the plugin to the values of the virtual cluster
+helm release, upgrades the release and waits until the
+virtual cluster was rolled out with the plugin.
+
+Example:
+vcluster plugin install my-vcluster my-plugin --image my-org/my-plugin:v1 -n my-vcluster
+vcluster plugin install my-vcluster my-plugin --image my-org/my-plugin:v1 --config plugin-config.yaml --optional
+#######################################################
+	`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: newValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context(), args)
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.Image, "image", "", "The container image of the plugin")
+	cobraCmd.Flags().StringVar(&cmd.ImagePullPolicy, "image-pull-policy", "", "The pull policy of the plugin image")
+	cobraCmd.Flags().StringVar(&cmd.ConfigFile, "config", "", "A yaml file with the plugin config")
+	cobraCmd.Flags().BoolVar(&cmd.Optional, "optional", false, "If enabled, the virtual cluster keeps running if the plugin fails")
+	addPluginReleaseFlags(cobraCmd, &cmd.pluginReleaseOptions)
+	_ = cobraCmd.MarkFlagRequired("image")
+	return cobraCmd
+}
+
+// Run executes the functionality
+func (cmd *PluginInstallCmd) Run(ctx context.Context, args []string) error {
+	pluginValues := map[string]interface{}{
+		"image": cmd.Image,
+	}
+	if cmd.ImagePullPolicy != "" {
+		pluginValues["imagePullPolicy"] = cmd.ImagePullPolicy
+	}
+	if cmd.Optional {
+		pluginValues["optional"] = true
+	}
+	if cmd.ConfigFile != "" {
+		rawConfig, err := os.ReadFile(cmd.ConfigFile)
+		if err != nil {
+			return fmt.Errorf("read plugin config: %w", err)
+		}
+
+		pluginConfig := map[string]interface{}{}
+		err = yaml.Unmarshal(rawConfig, &pluginConfig)
+		if err != nil {
+			return fmt.Errorf("parse plugin config: %w", err)
+		}
+		pluginValues["config"] = pluginConfig
+	}
+
+	vClusterName, pluginName := args[0], args[1]
+	err := updatePluginRelease(ctx, cmd.GlobalFlags, vClusterName, cmd.pluginReleaseOptions, cmd.log, func(values map[string]interface{}) error {
+		setPluginValues(values, pluginName, pluginValues)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	cmd.log.Donef("Successfully installed plugin %s into virtual cluster %s", pluginName, vClusterName)
+	return nil
+}
+
+// PluginRemoveCmd holds the cmd flags
+type PluginRemoveCmd struct {
+	*flags.GlobalFlags
+	log log.Logger
+
+	pluginReleaseOptions
+}
+
+func newPluginRemoveCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &PluginRemoveCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:     "remove VCLUSTER_NAME PLUGIN_NAME",
+		Aliases: []string{"rm", "uninstall"},
+		Short:   "Removes a plugin from a virtual cluster",
+		Long: `
+#######################################################
+############### vcluster plugin remove ################
+#######################################################
+Removes the plugin from the values of the virtual cluster
+helm release, upgrades the release and waits until the
+virtual cluster was rolled out without the plugin.
+
+Example:
+vcluster plugin remove my-vcluster my-plugin -n my-vcluster
+#######################################################
+	`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: newValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context(), args)
+		},
+	}
+
+	addPluginReleaseFlags(cobraCmd, &cmd.pluginReleaseOptions)
+	return cobraCmd
+}
+
+// Run executes the functionality
+func (cmd *PluginRemoveCmd) Run(ctx context.Context, args []string) error {
+	vClusterName, pluginName := args[0], args[1]
+	err := updatePluginRelease(ctx, cmd.GlobalFlags, vClusterName, cmd.pluginReleaseOptions, cmd.log, func(values map[string]interface{}) error {
+		if !removePluginValues(values, pluginName) {
+			return fmt.Errorf("plugin %s is not installed in virtual cluster %s", pluginName, vClusterName)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	cmd.log.Donef("Successfully removed plugin %s from virtual cluster %s", pluginName, vClusterName)
+	return nil
+}
+
+func addPluginReleaseFlags(cobraCmd *cobra.Command, options *pluginReleaseOptions) {
+	cobraCmd.Flags().StringVar(&options.ChartRepo, "chart-repo", create.LoftChartRepo, "The vcluster chart repo to use")
+	cobraCmd.Flags().DurationVar(&options.Timeout, "timeout", 5*time.Minute, "How long to wait for the virtual cluster to roll out")
+}
+
+// updatePluginRelease changes the values of the vcluster release, upgrades it and waits for the rollout
+func updatePluginRelease(ctx context.Context, globalFlags *flags.GlobalFlags, vClusterName string, options pluginReleaseOptions, log log.Logger, mutate func(values map[string]interface{}) error) error {
+	vCluster, _, err := find.GetVCluster(ctx, nil, globalFlags.Context, vClusterName, globalFlags.Namespace, "", log)
+	if err != nil {
+		return err
+	}
+
+	rawConfig, restConfig, kubeClient, err := vClusterHostClients(vCluster)
+	if err != nil {
+		return err
+	}
+
+	// get the current values of the release
+	release, err := helm.NewSecrets(kubeClient).Get(ctx, vCluster.Name, vCluster.Namespace)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return fmt.Errorf("couldn't find a helm release for virtual cluster %s in namespace %s", vCluster.Name, vCluster.Namespace)
+		}
+
+		return errors.Wrap(err, "get helm release")
+	} else if release.Chart == nil || release.Chart.Metadata == nil {
+		return fmt.Errorf("helm release %s has no chart information", vCluster.Name)
+	}
+
+	values := release.Config
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	err = mutate(values)
+	if err != nil {
+		return err
+	}
+
+	rawValues, err := yaml.Marshal(values)
+	if err != nil {
+		return errors.Wrap(err, "marshal values")
+	}
+
+	// test for helm
+	helmBinaryPath, err := GetHelmBinaryPath(ctx, log)
+	if err != nil {
+		return err
+	}
+	output, err := exec.Command(helmBinaryPath, "version", "--client").CombinedOutput()
+	if errHelm := clihelper.CheckHelmVersion(string(output)); errHelm != nil {
+		return errHelm
+	} else if err != nil {
+		return fmt.Errorf("seems like there are issues with your helm client: \n\n%s", output)
+	}
+
+	// upgrade the release with the same chart version
+	chartName, chartVersion := release.Chart.Metadata.Name, release.Chart.Metadata.Version
+	upgradeOptions := helm.UpgradeOptions{
+		Chart:   chartName,
+		Repo:    options.ChartRepo,
+		Version: chartVersion,
+		Values:  string(rawValues),
+		Debug:   globalFlags.Debug,
+	}
+	if options.ChartRepo == create.LoftChartRepo && chartVersion != "" {
+		upgradeOptions.Path = create.LoftChartRepo + "/charts/" + chartName + "-" + strings.TrimPrefix(chartVersion, "v") + ".tgz"
+	}
+
+	log.Infof("Upgrade vcluster %s...", vCluster.Name)
+	err = helm.NewClient(rawConfig, log, helmBinaryPath).Upgrade(ctx, vCluster.Name, vCluster.Namespace, upgradeOptions)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Waiting for vcluster %s to roll out...", vCluster.Name)
+	return waitForVClusterRollout(ctx, restConfig, vCluster.Name, vCluster.Namespace, options.Timeout)
+}
+
+func vClusterHostClients(vCluster *find.VCluster) (*clientcmdapi.Config, *rest.Config, *kubernetes.Clientset, error) {
+	rawConfig, err := vCluster.ClientFactory.RawConfig()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("there is an error loading your current kube config (%w), please make sure you have access to a kubernetes cluster and the command `kubectl get namespaces` is working", err)
+	}
+	rawConfig.CurrentContext = vCluster.Context
+
+	restConfig, err := vCluster.ClientFactory.ClientConfig()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return &rawConfig, restConfig, kubeClient, nil
+}
+
+// waitForVClusterRollout waits until the vcluster statefulset or deployment rolled out all replicas
+func waitForVClusterRollout(ctx context.Context, restConfig *rest.Config, name, namespace string, timeout time.Duration) error {
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	return wait.PollUntilContextTimeout(ctx, time.Second*2, timeout, true, func(ctx context.Context) (bool, error) {
+		statefulSet, err := kubeClient.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err == nil {
+			replicas := int32(1)
+			if statefulSet.Spec.Replicas != nil {
+				replicas = *statefulSet.Spec.Replicas
+			}
+
+			return statefulSet.Status.ObservedGeneration >= statefulSet.Generation &&
+				statefulSet.Status.UpdatedReplicas == replicas &&
+				statefulSet.Status.ReadyReplicas == replicas &&
+				statefulSet.Status.CurrentRevision == statefulSet.Status.UpdateRevision, nil
+		} else if !kerrors.IsNotFound(err) {
+			return false, err
+		}
+
+		deployment, err := kubeClient.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+
+		return deployment.Status.ObservedGeneration >= deployment.Generation &&
+			deployment.Status.UpdatedReplicas == replicas &&
+			deployment.Status.AvailableReplicas == replicas &&
+			deployment.Status.Replicas == replicas, nil
+	})
+}
+
+// setPluginValues sets the plugin in the plugins section of the values, existing settings such as rbac are kept
+func setPluginValues(values map[string]interface{}, pluginName string, pluginValues map[string]interface{}) {
+	plugins, ok := values["plugins"].(map[string]interface{})
+	if !ok {
+		plugins = map[string]interface{}{}
+		values["plugins"] = plugins
+	}
+
+	existing, ok := plugins[pluginName].(map[string]interface{})
+	if !ok {
+		existing = map[string]interface{}{}
+	}
+	for k, v := range pluginValues {
+		existing[k] = v
+	}
+	plugins[pluginName] = existing
+}
+
+// removePluginValues removes the plugin from the plugins and the legacy plugin section of the values
+func removePluginValues(values map[string]interface{}, pluginName string) bool {
+	found := false
+	for _, key := range []string{"plugins", "plugin"} {
+		plugins, ok := values[key].(map[string]interface{})
+		if !ok {
+			continue
+		} else if _, ok := plugins[pluginName]; !ok {
+			continue
+		}
+
+		delete(plugins, pluginName)
+		if len(plugins) == 0 {
+			delete(values, key)
+		}
+		found = true
+	}
+
+	return found
+}
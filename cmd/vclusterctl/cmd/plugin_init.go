@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/plugin/scaffold"
+	"github.com/spf13/cobra"
+)
+
+// PluginInitCmd holds the cmd flags
+type PluginInitCmd struct {
+	log log.Logger
+
+	Module string
+	Image  string
+	Dir    string
+}
+
+func newPluginInitCmd() *cobra.Command {
+	cmd := &PluginInitCmd{
+		log: log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "init PLUGIN_NAME",
+		Short: "Scaffolds a new plugin project",
+		Long: `
+#######################################################
+################ vcluster plugin init #################
+#######################################################
+Creates a new Go project for a vcluster plugin with a
+hello world hook that mutates pods, a test for the hook,
+a Dockerfile and a plugin.yaml to install the plugin.
+
+Example:
+vcluster plugin init my-plugin --module github.com/my-org/my-plugin
+#######################################################
+	`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return cmd.Run(args)
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.Module, "module", "", "The go module path of the plugin. Defaults to the plugin name")
+	cobraCmd.Flags().StringVar(&cmd.Image, "image", "", "The container image of the plugin. Defaults to PLUGIN_NAME:latest")
+	cobraCmd.Flags().StringVar(&cmd.Dir, "dir", "", "The directory to create the project in. Defaults to ./PLUGIN_NAME")
+	return cobraCmd
+}
+
+// Run executes the functionality
+func (cmd *PluginInitCmd) Run(args []string) error {
+	dir := cmd.Dir
+	if dir == "" {
+		dir = args[0]
+	}
+
+	files, err := scaffold.Generate(dir, scaffold.Options{
+		Name:   args[0],
+		Module: cmd.Module,
+		Image:  cmd.Image,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		cmd.log.Debugf("Created %s", file)
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		absDir = dir
+	}
+	cmd.log.Donef("Successfully created plugin %s in %s\n- Run `go mod tidy && go test ./...` within the directory to fetch the dependencies and run the tests\n- Run `make image` to build and push the plugin image\n- Run `vcluster plugin install VCLUSTER_NAME %s --image IMAGE` to install the plugin", args[0], absDir, args[0])
+	return nil
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/log/table"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/find"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/flags"
+	plugintypes "github.com/loft-sh/vcluster/pkg/plugin/types"
+	"github.com/loft-sh/vcluster/pkg/util/clihelper"
+	"github.com/loft-sh/vcluster/pkg/util/portforward"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// PluginListCmd holds the cmd flags
+type PluginListCmd struct {
+	*flags.GlobalFlags
+	log log.Logger
+
+	Output string
+}
+
+func newPluginListCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &PluginListCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:     "list VCLUSTER_NAME",
+		Aliases: []string{"ls"},
+		Short:   "Lists the plugins loaded by a virtual cluster",
+		Long: `
+#######################################################
+################ vcluster plugin list #################
+#######################################################
+Lists the plugins that are loaded by the running
+virtual cluster, their status and their hooks.
+
+Example:
+vcluster plugin list my-vcluster -n my-vcluster
+vcluster plugin list my-vcluster -n my-vcluster --output json
+#######################################################
+	`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: newValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context(), args)
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.Output, "output", "table", "Choose the format of the output. [table|json]")
+	return cobraCmd
+}
+
+// Run executes the functionality
+func (cmd *PluginListCmd) Run(ctx context.Context, args []string) error {
+	vCluster, _, err := find.GetVCluster(ctx, nil, cmd.Context, args[0], cmd.Namespace, "", cmd.log)
+	if err != nil {
+		return err
+	}
+
+	pluginStatus, err := cmd.getPluginStatus(ctx, vCluster)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Output == "json" {
+		out, err := json.MarshalIndent(pluginStatus, "", "    ")
+		if err != nil {
+			return errors.Wrap(err, "json marshal plugins")
+		}
+
+		cmd.log.WriteString(logrus.InfoLevel, string(out)+"\n")
+		return nil
+	}
+
+	header := []string{"NAME", "PHASE", "OPTIONAL", "RESTARTS", "SINCE", "HOOKS"}
+	values := [][]string{}
+	for _, p := range pluginStatus {
+		since := ""
+		if !p.LastTransitionTime.IsZero() {
+			since = duration.HumanDuration(time.Since(p.LastTransitionTime))
+		}
+
+		values = append(values, []string{p.Name, p.Phase, strconv.FormatBool(p.Optional), strconv.Itoa(p.Restarts), since, formatPluginHooks(p.Hooks)})
+	}
+	table.PrintTable(cmd.log, header, values)
+	return nil
+}
+
+// getPluginStatus retrieves the plugin status from the syncer through a port-forwarding to the vcluster pod
+func (cmd *PluginListCmd) getPluginStatus(ctx context.Context, vCluster *find.VCluster) ([]plugintypes.PluginStatus, error) {
+	_, restConfig, kubeClient, err := vClusterHostClients(vCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	podName, err := findRunningVClusterPod(ctx, kubeClient, vCluster.Name, vCluster.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeConfig, err := clihelper.GetKubeConfig(ctx, kubeClient, vCluster.Name, vCluster.Namespace, cmd.log)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse kube config")
+	}
+
+	// forward the vcluster port to a random local port
+	localPort := clihelper.RandomPort()
+	remotePort := "8443"
+	for k := range kubeConfig.Clusters {
+		if serverURL, err := url.Parse(kubeConfig.Clusters[k].Server); err == nil && serverURL.Port() != "" {
+			remotePort = serverURL.Port()
+		}
+
+		kubeConfig.Clusters[k].Server = "https://localhost:" + strconv.Itoa(localPort)
+	}
+
+	stopChan, err := portforward.StartPortForwarding(restConfig, kubeClient, "", podName, vCluster.Namespace, strconv.Itoa(localPort), remotePort, io.Discard, io.Discard, cmd.log)
+	if err != nil {
+		return nil, errors.Wrap(err, "start port forwarding")
+	}
+	defer close(stopChan)
+
+	vRestConfig, err := clientcmd.NewDefaultClientConfig(*kubeConfig, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "create virtual rest config")
+	}
+	vKubeClient, err := kubernetes.NewForConfig(vRestConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "create virtual kube client")
+	}
+
+	rawStatus, err := vKubeClient.CoreV1().RESTClient().Get().AbsPath("/vcluster/plugins/status").DoRaw(ctx)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			// no plugins are loaded or the vcluster version does not support plugin status yet
+			return []plugintypes.PluginStatus{}, nil
+		}
+
+		return nil, errors.Wrap(err, "retrieve plugin status")
+	}
+
+	pluginStatus := []plugintypes.PluginStatus{}
+	err = json.Unmarshal(rawStatus, &pluginStatus)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse plugin status")
+	}
+
+	return pluginStatus, nil
+}
+
+func findRunningVClusterPod(ctx context.Context, kubeClient kubernetes.Interface, name, namespace string) (string, error) {
+	pods, err := kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "app=vcluster,release=" + name,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// sort by newest
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].CreationTimestamp.Unix() > pods.Items[j].CreationTimestamp.Unix()
+	})
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp == nil && pod.Status.Phase == corev1.PodRunning {
+			return pod.Name, nil
+		}
+	}
+
+	return "", fmt.Errorf("can't find a running vcluster pod in namespace %s", namespace)
+}
+
+func formatPluginHooks(hooks []plugintypes.PluginHook) string {
+	formatted := []string{}
+	for _, hook := range hooks {
+		switch hook.Type {
+		case "HTTP":
+			formatted = append(formatted, "http "+hook.PathPrefix)
+		default:
+			formatted = append(formatted, fmt.Sprintf("%s %s/%s [%s]", strings.ToLower(hook.Type), hook.APIVersion, hook.Kind, strings.Join(hook.Types, ",")))
+		}
+	}
+
+	return strings.Join(formatted, "; ")
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSetPluginValues(t *testing.T) {
+	values := map[string]interface{}{
+		"plugins": map[string]interface{}{
+			"my-plugin": map[string]interface{}{
+				"image":  "old:latest",
+				"config": map[string]interface{}{"a": "b"},
+			},
+		},
+	}
+
+	setPluginValues(values, "my-plugin", map[string]interface{}{"image": "new:latest"})
+	setPluginValues(values, "other-plugin", map[string]interface{}{"image": "other:latest"})
+	assert.DeepEqual(t, values, map[string]interface{}{
+		"plugins": map[string]interface{}{
+			"my-plugin": map[string]interface{}{
+				"image":  "new:latest",
+				"config": map[string]interface{}{"a": "b"},
+			},
+			"other-plugin": map[string]interface{}{
+				"image": "other:latest",
+			},
+		},
+	})
+}
+
+func TestRemovePluginValues(t *testing.T) {
+	values := map[string]interface{}{
+		"plugins": map[string]interface{}{
+			"my-plugin":    map[string]interface{}{"image": "my-plugin:latest"},
+			"other-plugin": map[string]interface{}{"image": "other:latest"},
+		},
+		"plugin": map[string]interface{}{
+			"my-plugin": map[string]interface{}{"image": "my-plugin:latest"},
+		},
+	}
+
+	assert.Assert(t, removePluginValues(values, "my-plugin"))
+	assert.DeepEqual(t, values, map[string]interface{}{
+		"plugins": map[string]interface{}{
+			"other-plugin": map[string]interface{}{"image": "other:latest"},
+		},
+	})
+	assert.Assert(t, !removePluginValues(values, "my-plugin"))
+}
@@ -87,11 +87,13 @@ func BuildRoot(log log.Logger) (*cobra.Command, error) {
 	rootCmd.AddCommand(NewPauseCmd(globalFlags))
 	rootCmd.AddCommand(NewResumeCmd(globalFlags))
 	rootCmd.AddCommand(NewDisconnectCmd(globalFlags))
+	rootCmd.AddCommand(NewDoctorCmd(globalFlags))
 	rootCmd.AddCommand(NewUpgradeCmd())
 	rootCmd.AddCommand(get.NewGetCmd(globalFlags))
 	rootCmd.AddCommand(cmdtelemetry.NewTelemetryCmd())
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(NewInfoCmd())
+	rootCmd.AddCommand(NewPluginCmd(globalFlags))
 
 	// add pro commands
 	proCmd, err := cmdpro.NewProCmd(globalFlags)
@@ -61,6 +61,62 @@ type ExportKubeConfig struct {
 	// If this is not defined, vCluster create it with `vc-NAME`. If you specify another name,
 	// vCluster creates the config in this other secret.
 	Secret ExportKubeConfigSecretReference `json:"secret,omitempty"`
+
+	// AdditionalSecrets are additional kubeconfig secrets vCluster should create. Each secret has its own context, server and
+	// identity, which allows handing out scoped access to the virtual cluster, e.g. for CI or dashboards. Secrets, service accounts
+	// and bindings of removed entries are deleted again.
+	AdditionalSecrets []ExportKubeConfigAdditionalSecret `json:"additionalSecrets,omitempty"`
+}
+
+type ExportKubeConfigAdditionalSecret struct {
+	// Name is the name of the secret where the kubeconfig should get stored.
+	Name string `json:"name,omitempty"`
+
+	// Namespace where vCluster should store the kubeconfig secret. Defaults to the vCluster namespace.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Context is the name of the context within the generated kubeconfig. Defaults to the secret name.
+	Context string `json:"context,omitempty"`
+
+	// Server is the server within the generated kubeconfig. Defaults to exportKubeConfig.server.
+	Server string `json:"server,omitempty"`
+
+	// Certificate defines a client certificate identity for the kubeconfig.
+	Certificate ExportKubeConfigCertificate `json:"certificate,omitempty"`
+
+	// ServiceAccount defines a service account token identity for the kubeconfig.
+	ServiceAccount ExportKubeConfigServiceAccount `json:"serviceAccount,omitempty"`
+}
+
+type ExportKubeConfigCertificate struct {
+	// User is the user name (common name) of the client certificate.
+	User string `json:"user,omitempty"`
+
+	// Groups are the groups (organizations) of the client certificate.
+	Groups []string `json:"groups,omitempty"`
+
+	// Expiration is the validity of the client certificate, e.g. 720h. The certificate is rotated automatically before it expires.
+	// Defaults to 8760h.
+	Expiration string `json:"expiration,omitempty"`
+}
+
+type ExportKubeConfigServiceAccount struct {
+	// Name of the service account within the virtual cluster. The service account is created if it does not exist.
+	Name string `json:"name,omitempty"`
+
+	// Namespace of the service account within the virtual cluster. Defaults to default.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Expiration is the validity of the service account token, e.g. 24h. The token is rotated automatically before it expires.
+	// Defaults to 24h.
+	Expiration string `json:"expiration,omitempty"`
+
+	// ClusterRole is the cluster role that should get bound to the service account within the virtual cluster.
+	ClusterRole string `json:"clusterRole,omitempty"`
+
+	// BindingNamespaces are the namespaces within the virtual cluster where the cluster role should get bound through a role binding.
+	// If empty, the cluster role is bound cluster wide through a cluster role binding.
+	BindingNamespaces []string `json:"bindingNamespaces,omitempty"`
 }
 
 // Declare in which host cluster secret vCluster should store the generated virtual cluster kubeconfig.
@@ -96,14 +152,17 @@ type SyncToHost struct {
 	Services EnableSwitch `json:"services,omitempty"`
 	// Endpoints defines if endpoints created within the virtual cluster should get synced to the host cluster.
 	Endpoints EnableSwitch `json:"endpoints,omitempty"`
+	// EndpointSlices defines if endpoint slices of selector-less services created within the virtual cluster should get synced to the host cluster.
+	// If enabled, vCluster disables endpoint slice mirroring for the synced endpoints in the host cluster.
+	EndpointSlices EnableSwitch `json:"endpointSlices,omitempty"`
 	// NetworkPolicies defines if network policies created within the virtual cluster should get synced to the host cluster.
-	NetworkPolicies EnableSwitch `json:"networkPolicies,omitempty"`
+	NetworkPolicies SyncNetworkPolicies `json:"networkPolicies,omitempty"`
 	// PersistentVolumeClaims defines if persistent volume claims created within the virtual cluster should get synced to the host cluster.
-	PersistentVolumeClaims EnableSwitch `json:"persistentVolumeClaims,omitempty"`
+	PersistentVolumeClaims SyncPersistentVolumeClaims `json:"persistentVolumeClaims,omitempty"`
 	// PersistentVolumes defines if persistent volumes created within the virtual cluster should get synced to the host cluster.
 	PersistentVolumes EnableSwitch `json:"persistentVolumes,omitempty"`
 	// VolumeSnapshots defines if volume snapshots created within the virtual cluster should get synced to the host cluster.
-	VolumeSnapshots EnableSwitch `json:"volumeSnapshots,omitempty"`
+	VolumeSnapshots SyncVolumeSnapshots `json:"volumeSnapshots,omitempty"`
 	// StorageClasses defines if storage classes created within the virtual cluster should get synced to the host cluster.
 	StorageClasses EnableSwitch `json:"storageClasses,omitempty"`
 	// ServiceAccounts defines if service accounts created within the virtual cluster should get synced to the host cluster.
@@ -163,6 +222,78 @@ type SyncPods struct {
 	// a small container to each stateful set pod that will initially rewrite the /etc/hosts file to match the FQDN expected by
 	// the virtual cluster.
 	RewriteHosts SyncRewriteHosts `json:"rewriteHosts,omitempty"`
+
+	// HostMutations reflects changes that mutating webhooks in the host cluster made to synced pods back into the virtual cluster.
+	HostMutations SyncPodHostMutations `json:"hostMutations,omitempty"`
+
+	// NodeDebug allows members of the configured groups to use `kubectl debug node/NAME` within the virtual cluster.
+	NodeDebug SyncPodNodeDebug `json:"nodeDebug,omitempty"`
+}
+
+type SyncPodNodeDebug struct {
+	// Enabled allows node debug pods. These pods run with host access on the real host node, so they are only synced
+	// if they were created by a member of one of the groups and only use approved images.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Groups are the virtual cluster groups that are allowed to debug nodes.
+	Groups []string `json:"groups,omitempty"`
+
+	// Images are the approved debug images. A trailing "*" matches all images with the given prefix, e.g. "busybox:*".
+	Images []string `json:"images,omitempty"`
+}
+
+type SyncPodHostMutations struct {
+	// Enabled reflects containers and volumes injected into the host pod as well as changed container resources as annotations
+	// and a condition on the virtual pod. These are informational only and never synced back to the host pod.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Annotations are host pod annotations that should be copied to the virtual pod. A trailing "*" matches all annotations
+	// with the given prefix, e.g. "sidecar.istio.io/*".
+	Annotations []string `json:"annotations,omitempty"`
+}
+
+type SyncNetworkPolicies struct {
+	// Enabled defines if network policy syncing should be enabled.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// AllowDNS automatically allows egress traffic to the virtual cluster DNS for all pods selected by an egress network policy.
+	AllowDNS bool `json:"allowDNS,omitempty"`
+
+	// HostPodCIDRs are the pod CIDRs of the host cluster. IPBlocks of synced network policies are scoped so they can't select
+	// host pods, use pod and namespace selectors instead.
+	HostPodCIDRs []string `json:"hostPodCIDRs,omitempty"`
+
+	// HostServiceCIDRs are the service CIDRs of the host cluster. IPBlocks of synced network policies are scoped so they can't
+	// select host services.
+	HostServiceCIDRs []string `json:"hostServiceCIDRs,omitempty"`
+}
+
+type SyncPersistentVolumeClaims struct {
+	// Enabled defines if persistent volume claim syncing should be enabled.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// StorageClassMapping maps the storage classes used within the virtual cluster to storage classes of the host cluster.
+	StorageClassMapping ClassMapping `json:"storageClassMapping,omitempty"`
+}
+
+type SyncVolumeSnapshots struct {
+	// Enabled defines if volume snapshot syncing should be enabled.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// VolumeSnapshotClassMapping maps the volume snapshot classes used within the virtual cluster to volume snapshot classes of the host cluster.
+	VolumeSnapshotClassMapping ClassMapping `json:"volumeSnapshotClassMapping,omitempty"`
+}
+
+type ClassMapping struct {
+	// Mappings maps a class name used within the virtual cluster to a class name of the host cluster.
+	Mappings map[string]string `json:"mappings,omitempty"`
+
+	// Default is the host class used for objects within the virtual cluster that do not specify a class.
+	Default string `json:"default,omitempty"`
+
+	// Deny is a list of class names that cannot be used within the virtual cluster. Use "*" to only allow the classes
+	// defined in mappings.
+	Deny []string `json:"deny,omitempty"`
 }
 
 type SyncRewriteHosts struct {
@@ -183,8 +314,61 @@ type SyncNodes struct {
 	// ClearImageStatus will erase the image status when syncing a node. This allows to hide images that are pulled by the node.
 	ClearImageStatus bool `json:"clearImageStatus,omitempty"`
 
+	// ReflectCordons marks fake nodes as unschedulable while their host node is cordoned. This requires vCluster to watch
+	// host nodes. Real nodes always reflect host cordons.
+	ReflectCordons bool `json:"reflectCordons,omitempty"`
+
 	// Selector can be used to define more granular what nodes should get synced from the host cluster to the virtual cluster.
 	Selector SyncNodeSelector `json:"selector,omitempty"`
+
+	// Rules rewrite the labels and taints of host nodes before they are synced to the virtual cluster. Node selectors and
+	// node affinities of virtual pods are translated back to the host labels.
+	Rules SyncNodeRules `json:"rules,omitempty"`
+
+	// Pools group host nodes into named virtual node pools. Virtual nodes of a pool get the label vcluster.loft.sh/node-pool
+	// and the labels of the pool, which gives pods within the virtual cluster a stable scheduling surface even if the host
+	// node labels change. A host node belongs to the first pool whose selector matches.
+	Pools []SyncNodePool `json:"pools,omitempty"`
+}
+
+type SyncNodeRules struct {
+	// Labels rewrite, strip or add labels of the virtual nodes.
+	Labels SyncNodeLabelRules `json:"labels,omitempty"`
+
+	// Taints add or remove taints of the virtual nodes.
+	Taints SyncNodeTaintRules `json:"taints,omitempty"`
+}
+
+type SyncNodeLabelRules struct {
+	// Rewrite renames host node label keys to the given virtual label keys.
+	Rewrite map[string]string `json:"rewrite,omitempty"`
+
+	// Strip removes host node labels from the virtual nodes. Keys ending with * remove all labels with that prefix,
+	// e.g. topology.gke.io/*.
+	Strip []string `json:"strip,omitempty"`
+
+	// Add adds labels to all virtual nodes. These labels only exist within the virtual cluster.
+	Add map[string]string `json:"add,omitempty"`
+}
+
+type SyncNodeTaintRules struct {
+	// Add adds taints in the form key[=value]:effect to all virtual nodes. These taints only exist within the virtual cluster.
+	Add []string `json:"add,omitempty"`
+
+	// Remove removes host node taints with the given keys from the virtual nodes. Keys ending with * remove all taints
+	// with that prefix.
+	Remove []string `json:"remove,omitempty"`
+}
+
+type SyncNodePool struct {
+	// Name of the node pool. This is the value of the vcluster.loft.sh/node-pool label of the virtual nodes.
+	Name string `json:"name,omitempty"`
+
+	// Selector are the host node labels that select the nodes of this pool.
+	Selector map[string]string `json:"selector,omitempty"`
+
+	// Labels are added to all virtual nodes of the pool.
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 type SyncNodeSelector struct {
@@ -198,6 +382,28 @@ type SyncNodeSelector struct {
 type Observability struct {
 	// Metrics allows to proxy metrics server apis from host to virtual cluster.
 	Metrics ObservabilityMetrics `json:"metrics,omitempty"`
+
+	// Tracing allows to export OpenTelemetry traces of the vCluster proxy and syncer to an OTLP endpoint.
+	Tracing ObservabilityTracing `json:"tracing,omitempty"`
+}
+
+type ObservabilityTracing struct {
+	// Enabled defines if vCluster should create and export OpenTelemetry traces.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Endpoint is the OTLP gRPC endpoint in the form host:port where traces should get exported to, e.g. otel-collector.observability:4317.
+	// If empty, vCluster will fall back to the OTEL_EXPORTER_OTLP_ENDPOINT environment variable.
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Insecure disables TLS when connecting to the OTLP endpoint.
+	Insecure bool `json:"insecure,omitempty"`
+
+	// Headers are extra headers that are sent with every export request, e.g. for authentication.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// SamplingRatio is the ratio of traces between 0 and 1 that should get sampled. Defaults to 1 which samples every trace,
+	// 0 samples no traces that were not already sampled by the caller.
+	SamplingRatio *float64 `json:"samplingRatio,omitempty"`
 }
 
 type ServiceMonitor struct {
@@ -214,6 +420,16 @@ type ServiceMonitor struct {
 type ObservabilityMetrics struct {
 	// Proxy holds the configuration what metrics-server apis should get proxied.
 	Proxy MetricsProxy `json:"proxy,omitempty"`
+
+	// Federation holds the configuration for the metrics federation endpoint.
+	Federation MetricsFederation `json:"federation,omitempty"`
+}
+
+type MetricsFederation struct {
+	// Enabled defines if vCluster should serve the kubelet and cAdvisor metrics of its pods together with pod state metrics
+	// at /vcluster/metrics/federate. Host names are relabeled to virtual names and users only see the metrics of the
+	// namespaces they are allowed to list pods in.
+	Enabled bool `json:"enabled,omitempty"`
 }
 
 type MetricsProxy struct {
@@ -222,6 +438,14 @@ type MetricsProxy struct {
 
 	// Pods defines if metrics-server pods api should get proxied from host to virtual cluster.
 	Pods bool `json:"pods,omitempty"`
+
+	// CustomMetrics defines if the custom.metrics.k8s.io api of a metrics adapter in the host cluster (e.g. prometheus-adapter)
+	// should get proxied to the virtual cluster, so that horizontal pod autoscalers can use custom metrics.
+	CustomMetrics bool `json:"customMetrics,omitempty"`
+
+	// ExternalMetrics defines if the external.metrics.k8s.io api of a metrics adapter in the host cluster (e.g. KEDA)
+	// should get proxied to the virtual cluster, so that horizontal pod autoscalers can use external metrics.
+	ExternalMetrics bool `json:"externalMetrics,omitempty"`
 }
 
 type Networking struct {
@@ -307,7 +531,6 @@ type Plugin struct {
 	ReadinessProbe map[string]interface{} `json:"readinessProbe,omitempty"`
 	StartupProbe   map[string]interface{} `json:"startupProbe,omitempty"`
 	WorkingDir     string                 `json:"workingDir,omitempty"`
-	Optional       bool                   `json:"optional,omitempty"`
 }
 
 type Plugins struct {
@@ -332,6 +555,9 @@ type Plugins struct {
 	Resources map[string]interface{} `json:"resources,omitempty"`
 	// VolumeMounts are extra volume mounts for the init container
 	VolumeMounts []interface{} `json:"volumeMounts,omitempty"`
+	// Optional defines if vCluster should keep running if the plugin fails to start or crashes. Hooks of an
+	// optional plugin are skipped while the plugin is not running.
+	Optional bool `json:"optional,omitempty"`
 }
 
 type PluginsRBAC struct {
@@ -658,6 +884,65 @@ type ControlPlaneProxy struct {
 	Port int `json:"port,omitempty"`
 	// ExtraSANs are extra hostnames to sign the vCluster proxy certificate for.
 	ExtraSANs []string `json:"extraSANs,omitempty"`
+
+	// FlowControl limits the requests that users and groups can send through the proxy, so that a single
+	// noisy client cannot starve other users or the syncer.
+	FlowControl ControlPlaneProxyFlowControl `json:"flowControl,omitempty"`
+}
+
+type ControlPlaneProxyFlowControl struct {
+	// Enabled defines if flow control should be enabled in the proxy. Requests of the syncer, the system:masters group
+	// and system users other than service accounts are always exempt.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// MaxInFlight is the number of requests of the default priority level that can be served concurrently. Long running
+	// requests such as watches are not counted. 0 means no limit.
+	MaxInFlight int `json:"maxInFlight,omitempty"`
+
+	// PerUser is the rate limit that applies to each user separately.
+	PerUser ProxyRateLimit `json:"perUser,omitempty"`
+
+	// PerGroup are rate limits that are shared by all users of a group.
+	PerGroup []ProxyGroupRateLimit `json:"perGroup,omitempty"`
+
+	// PriorityLevels assign users and groups to separate priority levels. A request is assigned to the first priority
+	// level that matches its user or one of its groups, otherwise the default priority level is used.
+	PriorityLevels []ProxyPriorityLevel `json:"priorityLevels,omitempty"`
+}
+
+type ProxyRateLimit struct {
+	// QPS is the number of requests per second that are allowed. 0 means no limit.
+	QPS int `json:"qps,omitempty"`
+
+	// Burst is the number of requests that can be sent at once. Defaults to QPS.
+	Burst int `json:"burst,omitempty"`
+}
+
+type ProxyGroupRateLimit struct {
+	// Group is the name of the group the rate limit applies to.
+	Group string `json:"group,omitempty"`
+
+	ProxyRateLimit `json:",inline"`
+}
+
+type ProxyPriorityLevel struct {
+	// Name of the priority level.
+	Name string `json:"name,omitempty"`
+
+	// Users that are assigned to this priority level.
+	Users []string `json:"users,omitempty"`
+
+	// Groups that are assigned to this priority level.
+	Groups []string `json:"groups,omitempty"`
+
+	// Exempt defines if requests of this priority level are never limited.
+	Exempt bool `json:"exempt,omitempty"`
+
+	// MaxInFlight is the number of requests of this priority level that can be served concurrently. 0 means no limit.
+	MaxInFlight int `json:"maxInFlight,omitempty"`
+
+	// PerUser overrides the per user rate limit for users of this priority level.
+	PerUser *ProxyRateLimit `json:"perUser,omitempty"`
 }
 
 type ControlPlaneService struct {
@@ -700,6 +985,22 @@ type ControlPlaneHighAvailability struct {
 
 	// RetryPeriod is the time until a replica will retry to get a lease.
 	RetryPeriod int `json:"retryPeriod,omitempty"`
+
+	// Sharding distributes the sync work across all replicas instead of running a single active syncer.
+	Sharding ControlPlaneSharding `json:"sharding,omitempty"`
+}
+
+type ControlPlaneSharding struct {
+	// Enabled defines if the sync controllers should run on all replicas. Each replica only syncs the objects of the
+	// shards it owns, while all other controllers keep running on the elected leader only.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Mode defines how objects are assigned to shards. With "namespace", objects are assigned by the hash of their virtual
+	// namespace. With "resource", all objects of a resource type are assigned to the same shard.
+	Mode string `json:"mode,omitempty"`
+
+	// Shards is the number of shards the sync work is split into. Defaults to the number of replicas.
+	Shards int `json:"shards,omitempty"`
 }
 
 type ControlPlaneAdvanced struct {
@@ -721,6 +1022,49 @@ type ControlPlaneAdvanced struct {
 
 	// GlobalMetadata is metadata that will be added to all resources deployed by Helm.
 	GlobalMetadata ControlPlaneGlobalMetadata `json:"globalMetadata,omitempty"`
+
+	// ServiceAccountIssuerDiscovery allows external systems to verify service account tokens issued by the virtual cluster, e.g. for
+	// cloud provider or Vault workload identity federation.
+	ServiceAccountIssuerDiscovery ControlPlaneServiceAccountIssuerDiscovery `json:"serviceAccountIssuerDiscovery,omitempty"`
+}
+
+type ControlPlaneServiceAccountIssuerDiscovery struct {
+	// Enabled defines if the vCluster proxy should serve the OpenID discovery document (/.well-known/openid-configuration) and the
+	// JWKS (/openid/v1/jwks) of the virtual cluster service account issuer without authentication.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Issuer is the issuer url of the virtual cluster service account tokens. This should be an https url that is reachable by the
+	// systems verifying the tokens. If empty and the ingress is enabled, defaults to https://<ingress host>. New tokens are issued
+	// by this issuer, while tokens of the default issuer stay valid. Not supported with k0s.
+	Issuer string `json:"issuer,omitempty"`
+
+	// ConfigMap publishes the discovery document and JWKS into a ConfigMap within the host namespace.
+	ConfigMap ServiceAccountIssuerDiscoveryConfigMap `json:"configMap,omitempty"`
+
+	// Ingress deploys an ingress via Helm that exposes only the discovery document and JWKS of the vCluster.
+	Ingress ServiceAccountIssuerDiscoveryIngress `json:"ingress,omitempty"`
+}
+
+type ServiceAccountIssuerDiscoveryConfigMap struct {
+	// Enabled defines if the discovery document and JWKS should get published to a host ConfigMap
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Name is the name of the ConfigMap. Defaults to vc-oidc-<vcluster-name>.
+	Name string `json:"name,omitempty"`
+}
+
+type ServiceAccountIssuerDiscoveryIngress struct {
+	// Enabled defines if the discovery ingress should be deployed
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Host is the host where the discovery document and JWKS will be reachable
+	Host string `json:"host,omitempty"`
+	// PathType is the path type of the ingress
+	PathType string `json:"pathType,omitempty"`
+	// Spec allows you to configure extra ingress options.
+	Spec map[string]interface{} `json:"spec,omitempty"`
+
+	LabelsAndAnnotations `json:",inline"`
 }
 
 type ControlPlaneHeadlessService struct {
@@ -1003,6 +1347,21 @@ type ExperimentalMultiNamespaceMode struct {
 
 	// NamespaceLabels are extra labels that will be added by vCluster to each created namespace.
 	NamespaceLabels map[string]string `json:"namespaceLabels,omitempty"`
+
+	// NamespaceNameTemplate is a Go template used to build the host namespace name of a virtual namespace. Available
+	// fields are .VirtualNamespace, .Hash (a short hash of the virtual namespace), .VClusterName and .VClusterNamespace.
+	// For example "{{ .VClusterName }}-{{ .VirtualNamespace }}". Host namespaces matching the template are considered part
+	// of the vCluster, so the template should contain a unique prefix or suffix. Defaults to a name consisting of hashes.
+	NamespaceNameTemplate string `json:"namespaceNameTemplate,omitempty"`
+
+	// NamespaceMappings maps virtual namespaces to host namespaces, e.g. team-a: prod-team-a-ns. Mappings take precedence
+	// over the namespace name template.
+	NamespaceMappings map[string]string `json:"namespaceMappings,omitempty"`
+
+	// AdoptNamespaces are existing host namespaces vCluster uses instead of creating them, once a virtual namespace maps
+	// to them. Only the listed namespaces are adopted, system namespaces and namespaces of other vClusters never are. Adopted
+	// namespaces keep their labels and annotations and are not deleted when the virtual namespace or the vCluster is deleted.
+	AdoptNamespaces []string `json:"adoptNamespaces,omitempty"`
 }
 
 type ExperimentalIsolatedControlPlane struct {
@@ -1033,12 +1392,42 @@ type ExperimentalSyncSettings struct {
 }
 
 type ExperimentalDeploy struct {
-	// Manifests are raw Kubernetes manifests that should get applied within the virtual cluster.
+	// Manifests are raw Kubernetes manifests that should get applied within the virtual cluster. Manifests are server-side applied
+	// in waves (custom resource definitions first, then namespaces, then everything else), the wave of an object can be overridden
+	// with the vcluster.loft.sh/apply-wave annotation. Objects that are removed from the manifests are pruned.
 	Manifests string `json:"manifests,omitempty"`
 	// ManifestsTemplate is a Kubernetes manifest template that will be rendered with vCluster values before applying it within the virtual cluster.
 	ManifestsTemplate string `json:"manifestsTemplate,omitempty"`
 	// Helm are Helm charts that should get deployed into the virtual cluster
 	Helm []ExperimentalDeployHelm `json:"helm,omitempty"`
+	// Kustomize are kustomizations that should get rendered and applied within the virtual cluster
+	Kustomize []ExperimentalDeployKustomize `json:"kustomize,omitempty"`
+	// OCI are OCI artifacts containing Kubernetes manifests that should get applied within the virtual cluster
+	OCI []ExperimentalDeployOCI `json:"oci,omitempty"`
+}
+
+type ExperimentalDeployKustomize struct {
+	// Name is a unique name for the kustomization that is used to track its status
+	Name string `json:"name,omitempty"`
+	// Path is the path to a kustomization directory within the vCluster container, e.g. a mounted volume
+	Path string `json:"path,omitempty"`
+	// URL is a kustomization within a git repository, e.g. https://github.com/my-org/my-repo//overlays/prod?ref=v1.0.0. The ref can be a
+	// branch, tag or commit. The repository is cloned by vCluster itself and only again if the ref points to a new commit.
+	URL string `json:"url,omitempty"`
+}
+
+type ExperimentalDeployOCI struct {
+	// Name is a unique name for the artifact that is used to track its status
+	Name string `json:"name,omitempty"`
+	// Reference of the OCI artifact, e.g. ghcr.io/my-org/my-bundle:v1.0.0. Layers of the artifact can either be yaml files
+	// or (gzipped) tar archives containing yaml files.
+	Reference string `json:"reference,omitempty"`
+	// Username for the OCI registry
+	Username string `json:"username,omitempty"`
+	// Password for the OCI registry
+	Password string `json:"password,omitempty"`
+	// Insecure allows plain http and skips tls verification when pulling the artifact
+	Insecure bool `json:"insecure,omitempty"`
 }
 
 type ExperimentalDeployHelm struct {
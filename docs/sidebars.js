@@ -191,6 +191,7 @@ module.exports = {
             "o11y/metrics/metrics_server_proxy",
             "o11y/metrics/metrics_server",
             "o11y/metrics/monitoring_vcluster",
+            "o11y/metrics/federation",
           ],
         },
         {
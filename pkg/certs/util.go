@@ -366,6 +366,27 @@ func GetAPIServerVirtualIP(svcSubnetList string) (net.IP, error) {
 	return internalAPIServerVirtualIP, nil
 }
 
+// GetAPIServerVirtualIPs returns the IPs of the internal Kubernetes API service for every service cidr, so that
+// dual-stack clusters can reach the API server through both ip families.
+func GetAPIServerVirtualIPs(svcSubnetList string) ([]net.IP, error) {
+	svcSubnets, err := netutils.ParseCIDRs(strings.Split(svcSubnetList, ","))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse ServiceSubnet %v", svcSubnetList)
+	}
+
+	ips := []net.IP{}
+	for _, svcSubnet := range svcSubnets {
+		ip, err := netutils.GetIndexedIP(svcSubnet, 1)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to get the first IP address from the given CIDR: %s", svcSubnet.String())
+		}
+
+		ips = append(ips, ip)
+	}
+
+	return ips, nil
+}
+
 // GetAPIServerAltNames builds an AltNames object for to be used when generating apiserver certificate
 func GetAPIServerAltNames(cfg *InitConfiguration) (*certutil.AltNames, error) {
 	// advertise address
@@ -375,7 +396,7 @@ func GetAPIServerAltNames(cfg *InitConfiguration) (*certutil.AltNames, error) {
 			cfg.LocalAPIEndpoint.AdvertiseAddress)
 	}
 
-	internalAPIServerVirtualIP, err := GetAPIServerVirtualIP(cfg.Networking.ServiceSubnet)
+	internalAPIServerVirtualIPs, err := GetAPIServerVirtualIPs(cfg.Networking.ServiceSubnet)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to get first IP address from the given CIDR: %v", cfg.Networking.ServiceSubnet)
 	}
@@ -389,10 +410,7 @@ func GetAPIServerAltNames(cfg *InitConfiguration) (*certutil.AltNames, error) {
 			"kubernetes.default.svc",
 			fmt.Sprintf("kubernetes.default.svc.%s", cfg.Networking.DNSDomain),
 		},
-		IPs: []net.IP{
-			internalAPIServerVirtualIP,
-			advertiseAddress,
-		},
+		IPs: append(internalAPIServerVirtualIPs, advertiseAddress),
 	}
 
 	// add cluster controlPlaneEndpoint if present (dns or ip)
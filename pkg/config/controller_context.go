@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	servertypes "github.com/loft-sh/vcluster/pkg/server/types"
+	"github.com/loft-sh/vcluster/pkg/sharding"
 	"k8s.io/apimachinery/pkg/version"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -28,4 +29,7 @@ type ControllerContext struct {
 
 	// set of extra services that should handle the traffic or pass it along
 	ExtraHandlers []func(http.Handler) http.Handler
+
+	// Sharding distributes the sync work across replicas, nil if sharding is disabled
+	Sharding *sharding.Manager
 }
@@ -6,10 +6,14 @@ import (
 	"fmt"
 	"net/url"
 	"slices"
+	"strings"
+	"time"
 
 	"github.com/ghodss/yaml"
 	"github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/util/noderules"
 	"github.com/loft-sh/vcluster/pkg/util/toleration"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	"k8s.io/apimachinery/pkg/api/validation"
 )
@@ -38,6 +42,17 @@ func ValidateConfigAndSetDefaults(config *VirtualClusterConfig) error {
 		}
 	}
 
+	// validate node debug
+	if config.Sync.ToHost.Pods.NodeDebug.Enabled && (len(config.Sync.ToHost.Pods.NodeDebug.Groups) == 0 || len(config.Sync.ToHost.Pods.NodeDebug.Images) == 0) {
+		return fmt.Errorf("sync.toHost.pods.nodeDebug.groups and sync.toHost.pods.nodeDebug.images are required if node debugging is enabled")
+	}
+
+	// validate node rules and pools
+	_, err := noderules.New(config.Sync.FromHost.Nodes)
+	if err != nil {
+		return err
+	}
+
 	// check if enable scheduler works correctly
 	if config.ControlPlane.Advanced.VirtualScheduler.Enabled && !config.Sync.FromHost.Nodes.Selector.All && len(config.Sync.FromHost.Nodes.Selector.Labels) == 0 {
 		config.Sync.FromHost.Nodes.Selector.All = true
@@ -63,8 +78,24 @@ func ValidateConfigAndSetDefaults(config *VirtualClusterConfig) error {
 		return fmt.Errorf("you cannot enable both sync.fromHost.storageClasses.enabled and sync.toHost.storageClasses.enabled at the same time. Choose only one of them")
 	}
 
+	// validate storage class and volume snapshot class mappings
+	err = validateClassMapping(config.Sync.ToHost.PersistentVolumeClaims.StorageClassMapping, "sync.toHost.persistentVolumeClaims.storageClassMapping")
+	if err != nil {
+		return err
+	}
+	err = validateClassMapping(config.Sync.ToHost.VolumeSnapshots.VolumeSnapshotClassMapping, "sync.toHost.volumeSnapshots.volumeSnapshotClassMapping")
+	if err != nil {
+		return err
+	}
+
+	// validate multi namespace mode
+	err = validateMultiNamespaceMode(config.Experimental.MultiNamespaceMode)
+	if err != nil {
+		return err
+	}
+
 	// validate central admission control
-	err := validateCentralAdmissionControl(config)
+	err = validateCentralAdmissionControl(config)
 	if err != nil {
 		return err
 	}
@@ -95,6 +126,165 @@ func ValidateConfigAndSetDefaults(config *VirtualClusterConfig) error {
 		return err
 	}
 
+	// validate experimental deploy sources
+	err = validateDeploySources(config.Experimental.Deploy)
+	if err != nil {
+		return err
+	}
+
+	// validate service account issuer discovery
+	err = validateServiceAccountIssuerDiscovery(config)
+	if err != nil {
+		return err
+	}
+
+	// validate additional kube config secrets
+	err = validateAdditionalKubeConfigSecrets(config.ExportKubeConfig.AdditionalSecrets)
+	if err != nil {
+		return err
+	}
+
+	// validate sharding
+	err = validateSharding(config)
+	if err != nil {
+		return err
+	}
+
+	// validate tracing
+	err = validateTracing(config)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func validateTracing(config *VirtualClusterConfig) error {
+	// an unset sampling ratio samples every trace, 0 is a valid ratio that samples none
+	samplingRatio := config.Observability.Tracing.SamplingRatio
+	if samplingRatio != nil && (*samplingRatio < 0 || *samplingRatio > 1) {
+		return fmt.Errorf("observability.tracing.samplingRatio must be between 0 and 1")
+	}
+
+	return nil
+}
+
+func validateDeploySources(deploy config.ExperimentalDeploy) error {
+	names := map[string]bool{}
+	for i, kustomization := range deploy.Kustomize {
+		if kustomization.Name == "" {
+			return fmt.Errorf("experimental.deploy.kustomize[%d].name is required", i)
+		} else if names["kustomize/"+kustomization.Name] {
+			return fmt.Errorf("duplicate kustomization name %s in experimental.deploy.kustomize", kustomization.Name)
+		} else if (kustomization.Path == "") == (kustomization.URL == "") {
+			return fmt.Errorf("experimental.deploy.kustomize[%d] requires exactly one of path or url", i)
+		}
+
+		names["kustomize/"+kustomization.Name] = true
+	}
+	for i, artifact := range deploy.OCI {
+		if artifact.Name == "" {
+			return fmt.Errorf("experimental.deploy.oci[%d].name is required", i)
+		} else if names["oci/"+artifact.Name] {
+			return fmt.Errorf("duplicate artifact name %s in experimental.deploy.oci", artifact.Name)
+		} else if artifact.Reference == "" {
+			return fmt.Errorf("experimental.deploy.oci[%d].reference is required", i)
+		}
+
+		names["oci/"+artifact.Name] = true
+	}
+
+	return nil
+}
+
+func validateSharding(config *VirtualClusterConfig) error {
+	sharding := &config.ControlPlane.StatefulSet.HighAvailability.Sharding
+	if !sharding.Enabled {
+		return nil
+	}
+
+	if sharding.Mode == "" {
+		sharding.Mode = "namespace"
+	} else if sharding.Mode != "namespace" && sharding.Mode != "resource" {
+		return fmt.Errorf("controlPlane.statefulSet.highAvailability.sharding.mode must be either namespace or resource, got %s", sharding.Mode)
+	}
+	if sharding.Shards < 0 {
+		return fmt.Errorf("controlPlane.statefulSet.highAvailability.sharding.shards must not be negative")
+	} else if sharding.Shards == 0 {
+		sharding.Shards = max(int(config.ControlPlane.StatefulSet.HighAvailability.Replicas), 1)
+	}
+	if config.Experimental.SyncSettings.DisableSync {
+		return fmt.Errorf("controlPlane.statefulSet.highAvailability.sharding cannot be used together with experimental.syncSettings.disableSync")
+	}
+
+	return nil
+}
+
+func validateServiceAccountIssuerDiscovery(vConfig *VirtualClusterConfig) error {
+	discovery := &vConfig.ControlPlane.Advanced.ServiceAccountIssuerDiscovery
+	if !discovery.Enabled {
+		return nil
+	}
+
+	// default the issuer to the ingress host
+	if discovery.Issuer == "" && discovery.Ingress.Enabled && discovery.Ingress.Host != "" {
+		discovery.Issuer = "https://" + discovery.Ingress.Host
+	}
+	if discovery.Issuer == "" {
+		return nil
+	}
+
+	issuerURL, err := url.Parse(discovery.Issuer)
+	if err != nil {
+		return fmt.Errorf("parse controlPlane.advanced.serviceAccountIssuerDiscovery.issuer: %w", err)
+	} else if issuerURL.Scheme != "https" || issuerURL.Host == "" {
+		return fmt.Errorf("controlPlane.advanced.serviceAccountIssuerDiscovery.issuer must be an https url, got %s", discovery.Issuer)
+	} else if issuerURL.RawQuery != "" || issuerURL.Fragment != "" {
+		return fmt.Errorf("controlPlane.advanced.serviceAccountIssuerDiscovery.issuer must not contain a query or fragment")
+	}
+
+	// k0s only passes a single value per api server flag, so the default issuer can't be kept next to the custom
+	// issuer and replacing it would invalidate all existing service account tokens
+	if vConfig.ControlPlane.Distro.K0S.Enabled {
+		return fmt.Errorf("controlPlane.advanced.serviceAccountIssuerDiscovery.issuer is not supported with k0s, as k0s cannot keep the default issuer next to it")
+	}
+
+	return nil
+}
+
+func validateAdditionalKubeConfigSecrets(secrets []config.ExportKubeConfigAdditionalSecret) error {
+	names := map[string]bool{}
+	for i, secret := range secrets {
+		if secret.Name == "" {
+			return fmt.Errorf("exportKubeConfig.additionalSecrets[%d].name is required", i)
+		} else if names[secret.Namespace+"/"+secret.Name] {
+			return fmt.Errorf("duplicate secret %s in exportKubeConfig.additionalSecrets", secret.Name)
+		}
+		names[secret.Namespace+"/"+secret.Name] = true
+
+		if (secret.Certificate.User == "") == (secret.ServiceAccount.Name == "") {
+			return fmt.Errorf("exportKubeConfig.additionalSecrets[%d] requires exactly one of certificate.user or serviceAccount.name", i)
+		}
+
+		if secret.Certificate.Expiration != "" {
+			_, err := time.ParseDuration(secret.Certificate.Expiration)
+			if err != nil {
+				return fmt.Errorf("parse exportKubeConfig.additionalSecrets[%d].certificate.expiration: %w", i, err)
+			}
+		}
+		if secret.ServiceAccount.Expiration != "" {
+			expiration, err := time.ParseDuration(secret.ServiceAccount.Expiration)
+			if err != nil {
+				return fmt.Errorf("parse exportKubeConfig.additionalSecrets[%d].serviceAccount.expiration: %w", i, err)
+			} else if expiration < 10*time.Minute {
+				return fmt.Errorf("exportKubeConfig.additionalSecrets[%d].serviceAccount.expiration must be at least 10m", i)
+			}
+		}
+		if secret.ServiceAccount.ClusterRole == "" && len(secret.ServiceAccount.BindingNamespaces) > 0 {
+			return fmt.Errorf("exportKubeConfig.additionalSecrets[%d].serviceAccount.bindingNamespaces requires serviceAccount.clusterRole", i)
+		}
+	}
+
 	return nil
 }
 
@@ -278,6 +468,69 @@ func validateImportDuplicates(imports []*config.Import) error {
 	return nil
 }
 
+func validateClassMapping(mapping config.ClassMapping, path string) error {
+	for virtualName, hostName := range mapping.Mappings {
+		if virtualName == "" || hostName == "" {
+			return fmt.Errorf("%s.mappings: class names cannot be empty", path)
+		}
+		for _, denied := range mapping.Deny {
+			if denied == virtualName {
+				return fmt.Errorf("%s: class %s is both mapped and denied", path, virtualName)
+			}
+		}
+	}
+	for _, denied := range mapping.Deny {
+		if denied == "" {
+			return fmt.Errorf("%s.deny: class names cannot be empty", path)
+		}
+	}
+
+	return nil
+}
+
+func validateMultiNamespaceMode(multiNamespaceMode config.ExperimentalMultiNamespaceMode) error {
+	if multiNamespaceMode.NamespaceNameTemplate != "" {
+		if !strings.Contains(multiNamespaceMode.NamespaceNameTemplate, ".VirtualNamespace") && !strings.Contains(multiNamespaceMode.NamespaceNameTemplate, ".Hash") {
+			return fmt.Errorf("experimental.multiNamespaceMode.namespaceNameTemplate must contain either .VirtualNamespace or .Hash to generate unique namespace names")
+		}
+
+		namespaceNameTemplate, err := translate.ParseNamespaceNameTemplate(multiNamespaceMode.NamespaceNameTemplate)
+		if err != nil {
+			return fmt.Errorf("experimental.multiNamespaceMode.namespaceNameTemplate: %w", err)
+		}
+
+		namespace, err := translate.ExecuteNamespaceNameTemplate(namespaceNameTemplate, "vcluster-namespace", "default")
+		if err != nil {
+			return fmt.Errorf("experimental.multiNamespaceMode.namespaceNameTemplate: %w", err)
+		} else if errs := validation.ValidateNamespaceName(namespace, false); len(errs) > 0 {
+			return fmt.Errorf("experimental.multiNamespaceMode.namespaceNameTemplate renders invalid namespace name %q: %s", namespace, strings.Join(errs, ", "))
+		}
+	}
+
+	hostNamespaces := map[string]string{}
+	for virtualNamespace, hostNamespace := range multiNamespaceMode.NamespaceMappings {
+		if errs := validation.ValidateNamespaceName(virtualNamespace, false); len(errs) > 0 {
+			return fmt.Errorf("experimental.multiNamespaceMode.namespaceMappings: invalid virtual namespace %q: %s", virtualNamespace, strings.Join(errs, ", "))
+		} else if errs := validation.ValidateNamespaceName(hostNamespace, false); len(errs) > 0 {
+			return fmt.Errorf("experimental.multiNamespaceMode.namespaceMappings: invalid host namespace %q: %s", hostNamespace, strings.Join(errs, ", "))
+		} else if other, ok := hostNamespaces[hostNamespace]; ok {
+			return fmt.Errorf("experimental.multiNamespaceMode.namespaceMappings: virtual namespaces %s and %s are both mapped to host namespace %s", other, virtualNamespace, hostNamespace)
+		}
+
+		hostNamespaces[hostNamespace] = virtualNamespace
+	}
+
+	for _, namespace := range multiNamespaceMode.AdoptNamespaces {
+		if errs := validation.ValidateNamespaceName(namespace, false); len(errs) > 0 {
+			return fmt.Errorf("experimental.multiNamespaceMode.adoptNamespaces: invalid namespace %q: %s", namespace, strings.Join(errs, ", "))
+		} else if translate.IsSystemNamespace(namespace) {
+			return fmt.Errorf("experimental.multiNamespaceMode.adoptNamespaces: system namespace %s can't be adopted", namespace)
+		}
+	}
+
+	return nil
+}
+
 func validateCentralAdmissionControl(config *VirtualClusterConfig) error {
 	_, _, err := ParseExtraHooks(config.Policies.CentralAdmission.ValidatingWebhooks, config.Policies.CentralAdmission.MutatingWebhooks)
 	return err
@@ -3,7 +3,9 @@ package config
 import (
 	"testing"
 
+	"github.com/loft-sh/vcluster/config"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/utils/ptr"
 )
 
 func Test(t *testing.T) {
@@ -123,3 +125,53 @@ func mutHookToBase64(clientCfg admissionregistrationv1.WebhookClientConfig) inte
 	}
 	return hook
 }
+
+func TestValidateServiceAccountIssuerDiscovery(t *testing.T) {
+	vConfig := &VirtualClusterConfig{}
+	vConfig.ControlPlane.Advanced.ServiceAccountIssuerDiscovery.Enabled = true
+	vConfig.ControlPlane.Advanced.ServiceAccountIssuerDiscovery.Issuer = "https://vcluster.example.com"
+	if err := validateServiceAccountIssuerDiscovery(vConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// k0s can't keep the default issuer next to the custom one
+	vConfig.ControlPlane.Distro.K0S.Enabled = true
+	if err := validateServiceAccountIssuerDiscovery(vConfig); err == nil {
+		t.Fatal("expected an error for k0s")
+	}
+}
+
+func TestValidateTracing(t *testing.T) {
+	vConfig := &VirtualClusterConfig{}
+	if err := validateTracing(vConfig); err != nil {
+		t.Fatalf("unexpected error for an unset sampling ratio: %v", err)
+	}
+
+	for _, samplingRatio := range []float64{0, 0.5, 1} {
+		vConfig.Observability.Tracing.SamplingRatio = ptr.To(samplingRatio)
+		if err := validateTracing(vConfig); err != nil {
+			t.Fatalf("unexpected error for sampling ratio %v: %v", samplingRatio, err)
+		}
+	}
+
+	for _, samplingRatio := range []float64{-0.1, 1.5} {
+		vConfig.Observability.Tracing.SamplingRatio = ptr.To(samplingRatio)
+		if err := validateTracing(vConfig); err == nil {
+			t.Fatalf("expected an error for sampling ratio %v", samplingRatio)
+		}
+	}
+}
+
+func TestValidateAdoptNamespaces(t *testing.T) {
+	multiNamespaceMode := config.ExperimentalMultiNamespaceMode{AdoptNamespaces: []string{"prod-team-a-ns"}}
+	if err := validateMultiNamespaceMode(multiNamespaceMode); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, namespace := range []string{"kube-system", "kube-public", "default", "Invalid"} {
+		multiNamespaceMode.AdoptNamespaces = []string{namespace}
+		if err := validateMultiNamespaceMode(multiNamespaceMode); err == nil {
+			t.Fatalf("expected an error for namespace %s", namespace)
+		}
+	}
+}
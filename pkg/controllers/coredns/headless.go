@@ -0,0 +1,243 @@
+package coredns
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	RecordTypeA    = "A"
+	RecordTypeAAAA = "AAAA"
+	RecordTypeSRV  = "SRV"
+
+	// recordTTL is the ttl of the records, it matches the default of the kubernetes plugin
+	recordTTL = 5
+	// serverPort is the port CoreDNS listens on within the virtual cluster
+	serverPort = 1053
+)
+
+// Record is a DNS record of a virtual headless service as defined by the Kubernetes DNS specification:
+// https://github.com/kubernetes/dns/blob/master/docs/specification.md
+type Record struct {
+	// Name is the fully qualified name of the record without trailing dot
+	Name string
+	// Type is either A, AAAA or SRV
+	Type string
+	// Value is the ip address for A and AAAA records and the fully qualified target for SRV records
+	Value string
+	// Port is the target port of SRV records
+	Port int32
+}
+
+func (r Record) String() string {
+	if r.Type == RecordTypeSRV {
+		return fmt.Sprintf("%s. IN SRV 0 100 %d %s.", r.Name, r.Port, r.Value)
+	}
+
+	return fmt.Sprintf("%s. IN %s %s", r.Name, r.Type, r.Value)
+}
+
+// HeadlessRecords generates the A, AAAA and SRV records for all virtual headless services with a selector and their
+// pods. The records only depend on the virtual objects, so they stay stable regardless of how pods are named in the
+// host cluster.
+func HeadlessRecords(services []corev1.Service, pods []corev1.Pod, clusterDomain string) []Record {
+	records := []Record{}
+	for i := range services {
+		service := &services[i]
+		if service.Spec.ClusterIP != corev1.ClusterIPNone || len(service.Spec.Selector) == 0 {
+			continue
+		}
+
+		serviceName := fmt.Sprintf("%s.%s.svc.%s", service.Name, service.Namespace, clusterDomain)
+		selector := labels.SelectorFromSet(service.Spec.Selector)
+		for j := range pods {
+			pod := &pods[j]
+			if pod.Namespace != service.Namespace || !selector.Matches(labels.Set(pod.Labels)) || !isEndpoint(pod, service.Spec.PublishNotReadyAddresses) {
+				continue
+			}
+
+			records = append(records, podRecords(service, pod, serviceName)...)
+		}
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].String() < records[j].String()
+	})
+	return dedupRecords(records)
+}
+
+func podRecords(service *corev1.Service, pod *corev1.Pod, serviceName string) []Record {
+	records := []Record{}
+	podIPs := podIPs(pod)
+	for _, podIP := range podIPs {
+		records = append(records, Record{Name: serviceName, Type: recordType(podIP), Value: podIP})
+	}
+
+	// pods with a matching subdomain get their hostname, all others are addressed by their dashed ip
+	targets := []string{}
+	if pod.Spec.Hostname != "" && pod.Spec.Subdomain == service.Name {
+		targets = append(targets, pod.Spec.Hostname+"."+serviceName)
+		for _, podIP := range podIPs {
+			records = append(records, Record{Name: targets[0], Type: recordType(podIP), Value: podIP})
+		}
+	} else {
+		for _, podIP := range podIPs {
+			target := dashedIP(podIP) + "." + serviceName
+			targets = append(targets, target)
+			records = append(records, Record{Name: target, Type: recordType(podIP), Value: podIP})
+		}
+	}
+
+	for _, port := range service.Spec.Ports {
+		if port.Name == "" {
+			continue
+		}
+
+		targetPort, ok := resolveTargetPort(pod, port)
+		if !ok {
+			continue
+		}
+
+		protocol := port.Protocol
+		if protocol == "" {
+			protocol = corev1.ProtocolTCP
+		}
+		for _, target := range targets {
+			records = append(records, Record{
+				Name:  fmt.Sprintf("_%s._%s.%s", port.Name, strings.ToLower(string(protocol)), serviceName),
+				Type:  RecordTypeSRV,
+				Value: target,
+				Port:  targetPort,
+			})
+		}
+	}
+
+	return records
+}
+
+// isEndpoint returns true if the pod would be part of the service endpoints
+func isEndpoint(pod *corev1.Pod, publishNotReadyAddresses bool) bool {
+	if pod.DeletionTimestamp != nil || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed || len(podIPs(pod)) == 0 {
+		return false
+	} else if publishNotReadyAddresses {
+		return true
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+func podIPs(pod *corev1.Pod) []string {
+	ips := []string{}
+	for _, podIP := range pod.Status.PodIPs {
+		if net.ParseIP(podIP.IP) != nil {
+			ips = append(ips, podIP.IP)
+		}
+	}
+	if len(ips) == 0 && net.ParseIP(pod.Status.PodIP) != nil {
+		ips = append(ips, pod.Status.PodIP)
+	}
+
+	return ips
+}
+
+func resolveTargetPort(pod *corev1.Pod, port corev1.ServicePort) (int32, bool) {
+	if port.TargetPort.StrVal == "" {
+		if port.TargetPort.IntVal == 0 {
+			return port.Port, true
+		}
+
+		return port.TargetPort.IntVal, true
+	}
+
+	for _, container := range pod.Spec.Containers {
+		for _, containerPort := range container.Ports {
+			if containerPort.Name == port.TargetPort.StrVal && (containerPort.Protocol == port.Protocol || port.Protocol == "" && containerPort.Protocol == corev1.ProtocolTCP) {
+				return containerPort.ContainerPort, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+func recordType(ip string) string {
+	if net.ParseIP(ip).To4() != nil {
+		return RecordTypeA
+	}
+
+	return RecordTypeAAAA
+}
+
+func dashedIP(ip string) string {
+	if net.ParseIP(ip).To4() != nil {
+		return strings.ReplaceAll(ip, ".", "-")
+	}
+
+	return strings.ReplaceAll(ip, ":", "-")
+}
+
+func dedupRecords(records []Record) []Record {
+	out := []Record{}
+	for i, record := range records {
+		if i > 0 && records[i-1] == record {
+			continue
+		}
+
+		out = append(out, record)
+	}
+
+	return out
+}
+
+// ZoneName returns the DNS zone of a headless service. Each headless service gets its own zone, so that all other
+// names are still answered by the kubernetes plugin.
+func ZoneName(service *corev1.Service, clusterDomain string) string {
+	return fmt.Sprintf("%s.%s.svc.%s", service.Name, service.Namespace, clusterDomain)
+}
+
+// ZoneFile renders the records of a zone in the zone file format of the CoreDNS file plugin. The serial is derived
+// from the records, as the file plugin only reloads zones with a changed serial.
+func ZoneFile(zone string, records []Record, clusterDomain string) string {
+	lines := make([]string, 0, len(records))
+	for _, record := range records {
+		lines = append(lines, record.String())
+	}
+	body := strings.Join(lines, "\n")
+
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(body))
+	header := fmt.Sprintf(`$ORIGIN %s.
+$TTL %d
+@ IN SOA ns.dns.%s. hostmaster.%s. %d 7200 1800 86400 %d
+@ IN NS ns.dns.%s.
+`, zone, recordTTL, clusterDomain, clusterDomain, hash.Sum32(), recordTTL, clusterDomain)
+	if body == "" {
+		return header
+	}
+
+	return header + body + "\n"
+}
+
+// ServerBlock renders the CoreDNS server block that serves the zone file, it is imported by the Corefile
+func ServerBlock(zone string) string {
+	return fmt.Sprintf(`%s:%d {
+    errors
+    file %s/%s {
+        reload 5s
+    }
+    loadbalance
+}
+`, zone, serverPort, HeadlessMountPath, zoneFileKey(zone))
+}
@@ -0,0 +1,141 @@
+package coredns
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestHeadlessRecords(t *testing.T) {
+	headless := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "test"},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: corev1.ClusterIPNone,
+			Selector:  map[string]string{"app": "web"},
+			Ports: []corev1.ServicePort{
+				{Name: "http", Port: 80, TargetPort: intstr.FromString("http")},
+				{Name: "metrics", Port: 9090, Protocol: corev1.ProtocolTCP},
+				{Port: 8080},
+			},
+		},
+	}
+	statefulSetPod := func(name, ip string, ready bool) corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test", Labels: map[string]string{"app": "web"}},
+			Spec: corev1.PodSpec{
+				Hostname:  name,
+				Subdomain: "web",
+				Containers: []corev1.Container{{
+					Name:  "web",
+					Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8000, Protocol: corev1.ProtocolTCP}},
+				}},
+			},
+			Status: corev1.PodStatus{
+				PodIP:      ip,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+			},
+		}
+	}
+
+	notReadyPublished := *headless.DeepCopy()
+	notReadyPublished.Spec.PublishNotReadyAddresses = true
+
+	dualStackPod := statefulSetPod("web-0", "10.0.0.1", true)
+	dualStackPod.Status.PodIPs = []corev1.PodIP{{IP: "10.0.0.1"}, {IP: "fd00::1"}}
+
+	noSubdomainPod := statefulSetPod("web-0", "10.0.0.1", true)
+	noSubdomainPod.Spec.Subdomain = ""
+
+	otherNamespacePod := statefulSetPod("web-0", "10.0.0.1", true)
+	otherNamespacePod.Namespace = "other"
+
+	clusterIPService := *headless.DeepCopy()
+	clusterIPService.Spec.ClusterIP = "10.96.0.10"
+
+	testCases := []struct {
+		name     string
+		services []corev1.Service
+		pods     []corev1.Pod
+		expected []string
+	}{
+		{
+			name:     "stateful set pods",
+			services: []corev1.Service{headless},
+			pods:     []corev1.Pod{statefulSetPod("web-0", "10.0.0.1", true), statefulSetPod("web-1", "10.0.0.2", true)},
+			expected: []string{
+				"_http._tcp.web.test.svc.cluster.local. IN SRV 0 100 8000 web-0.web.test.svc.cluster.local.",
+				"_http._tcp.web.test.svc.cluster.local. IN SRV 0 100 8000 web-1.web.test.svc.cluster.local.",
+				"_metrics._tcp.web.test.svc.cluster.local. IN SRV 0 100 9090 web-0.web.test.svc.cluster.local.",
+				"_metrics._tcp.web.test.svc.cluster.local. IN SRV 0 100 9090 web-1.web.test.svc.cluster.local.",
+				"web-0.web.test.svc.cluster.local. IN A 10.0.0.1",
+				"web-1.web.test.svc.cluster.local. IN A 10.0.0.2",
+				"web.test.svc.cluster.local. IN A 10.0.0.1",
+				"web.test.svc.cluster.local. IN A 10.0.0.2",
+			},
+		},
+		{
+			name:     "not ready pods are omitted",
+			services: []corev1.Service{headless},
+			pods:     []corev1.Pod{statefulSetPod("web-0", "10.0.0.1", false)},
+			expected: []string{},
+		},
+		{
+			name:     "not ready pods are published",
+			services: []corev1.Service{notReadyPublished},
+			pods:     []corev1.Pod{statefulSetPod("web-0", "10.0.0.1", false)},
+			expected: []string{
+				"_http._tcp.web.test.svc.cluster.local. IN SRV 0 100 8000 web-0.web.test.svc.cluster.local.",
+				"_metrics._tcp.web.test.svc.cluster.local. IN SRV 0 100 9090 web-0.web.test.svc.cluster.local.",
+				"web-0.web.test.svc.cluster.local. IN A 10.0.0.1",
+				"web.test.svc.cluster.local. IN A 10.0.0.1",
+			},
+		},
+		{
+			name:     "dual stack",
+			services: []corev1.Service{headless},
+			pods:     []corev1.Pod{dualStackPod},
+			expected: []string{
+				"_http._tcp.web.test.svc.cluster.local. IN SRV 0 100 8000 web-0.web.test.svc.cluster.local.",
+				"_metrics._tcp.web.test.svc.cluster.local. IN SRV 0 100 9090 web-0.web.test.svc.cluster.local.",
+				"web-0.web.test.svc.cluster.local. IN A 10.0.0.1",
+				"web-0.web.test.svc.cluster.local. IN AAAA fd00::1",
+				"web.test.svc.cluster.local. IN A 10.0.0.1",
+				"web.test.svc.cluster.local. IN AAAA fd00::1",
+			},
+		},
+		{
+			name:     "pods without subdomain are addressed by ip",
+			services: []corev1.Service{headless},
+			pods:     []corev1.Pod{noSubdomainPod},
+			expected: []string{
+				"10-0-0-1.web.test.svc.cluster.local. IN A 10.0.0.1",
+				"_http._tcp.web.test.svc.cluster.local. IN SRV 0 100 8000 10-0-0-1.web.test.svc.cluster.local.",
+				"_metrics._tcp.web.test.svc.cluster.local. IN SRV 0 100 9090 10-0-0-1.web.test.svc.cluster.local.",
+				"web.test.svc.cluster.local. IN A 10.0.0.1",
+			},
+		},
+		{
+			name:     "pods in other namespaces and cluster ip services are ignored",
+			services: []corev1.Service{headless, clusterIPService},
+			pods:     []corev1.Pod{otherNamespacePod},
+			expected: []string{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		records := HeadlessRecords(testCase.services, testCase.pods, "cluster.local")
+		actual := []string{}
+		for _, record := range records {
+			actual = append(actual, record.String())
+		}
+		assert.DeepEqual(t, actual, testCase.expected)
+	}
+}
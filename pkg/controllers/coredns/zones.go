@@ -0,0 +1,217 @@
+package coredns
+
+import (
+	"context"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// HeadlessConfigMapName is the ConfigMap in kube-system that holds the zone files and server blocks of the
+	// headless services. CoreDNS mounts it at HeadlessMountPath and imports the server blocks from there.
+	HeadlessConfigMapName = "coredns-headless"
+	HeadlessMountPath     = "/etc/coredns/headless"
+
+	// MaxHeadlessConfigMapSize keeps the ConfigMap below the 1MiB limit of Kubernetes objects
+	MaxHeadlessConfigMapSize = 900 * 1024
+
+	serverBlockSuffix = ".server"
+	zoneFilePrefix    = "db."
+)
+
+// HeadlessZonesReconciler keeps the zone of each virtual headless service in the coredns-headless ConfigMap up to
+// date. Each service is reconciled on its own and only its keys within the ConfigMap are patched.
+type HeadlessZonesReconciler struct {
+	client.Client
+	Log loghelper.Logger
+
+	ClusterDomain string
+}
+
+func (r *HeadlessZonesReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	zone := ZoneName(&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: req.Namespace, Name: req.Name}}, r.ClusterDomain)
+	data, err := r.zoneData(ctx, req.NamespacedName, zone)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err = r.Client.Get(ctx, types.NamespacedName{Namespace: Namespace, Name: HeadlessConfigMapName}, configMap)
+	if kerrors.IsNotFound(err) {
+		if data == nil {
+			return ctrl.Result{}, nil
+		}
+
+		configMap = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: Namespace, Name: HeadlessConfigMapName}}
+		err = r.Client.Create(ctx, configMap)
+		if err != nil && !kerrors.IsAlreadyExists(err) {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{Requeue: true}, nil
+	} else if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// check if the zone fits into the ConfigMap, otherwise it is removed and the kubernetes plugin answers the
+	// queries of the service instead
+	if data != nil && configMapSize(configMap)-zoneSize(configMap.Data, zone)+zoneSize(data, zone) > MaxHeadlessConfigMapSize {
+		r.Log.Errorf("zone %s does not fit into ConfigMap %s/%s, DNS queries of the service are answered by the kubernetes plugin", zone, Namespace, HeadlessConfigMapName)
+		data = nil
+	}
+
+	beforeChanges := configMap.DeepCopy()
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	for _, key := range []string{serverBlockKey(zone), zoneFileKey(zone)} {
+		if data == nil {
+			delete(configMap.Data, key)
+		} else {
+			configMap.Data[key] = data[key]
+		}
+	}
+	if maps.Equal(beforeChanges.Data, configMap.Data) {
+		return ctrl.Result{}, nil
+	}
+
+	err = r.Client.Patch(ctx, configMap, client.MergeFrom(beforeChanges))
+	if err != nil {
+		return ctrl.Result{RequeueAfter: time.Second}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// zoneData returns the ConfigMap keys of the service zone or nil if the service has no zone
+func (r *HeadlessZonesReconciler) zoneData(ctx context.Context, name types.NamespacedName, zone string) (map[string]string, error) {
+	service := &corev1.Service{}
+	err := r.Client.Get(ctx, name, service)
+	if kerrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	} else if service.Spec.ClusterIP != corev1.ClusterIPNone || len(service.Spec.Selector) == 0 {
+		return nil, nil
+	}
+
+	pods := &corev1.PodList{}
+	err = r.Client.List(ctx, pods, client.InNamespace(service.Namespace), client.MatchingLabels(service.Spec.Selector))
+	if err != nil {
+		return nil, err
+	}
+
+	records := HeadlessRecords([]corev1.Service{*service}, pods.Items, r.ClusterDomain)
+	return map[string]string{
+		serverBlockKey(zone): ServerBlock(zone),
+		zoneFileKey(zone):    ZoneFile(zone, records, r.ClusterDomain),
+	}, nil
+}
+
+// SetupWithManager adds the controller to the manager
+func (r *HeadlessZonesReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// reconcile the services that select the pod
+	podHandler := handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+		services := &corev1.ServiceList{}
+		err := r.Client.List(ctx, services, client.InNamespace(obj.GetNamespace()))
+		if err != nil {
+			r.Log.Errorf("error listing services in namespace %s: %v", obj.GetNamespace(), err)
+			return nil
+		}
+
+		requests := []reconcile.Request{}
+		for _, service := range services.Items {
+			if service.Spec.ClusterIP == corev1.ClusterIPNone && len(service.Spec.Selector) > 0 && labels.SelectorFromSet(service.Spec.Selector).Matches(labels.Set(obj.GetLabels())) {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: service.Namespace, Name: service.Name}})
+			}
+		}
+		return requests
+	})
+
+	// reconcile the services of all zones within the ConfigMap, so zones of services that were deleted while the
+	// syncer was not running are removed
+	configMapHandler := handler.EnqueueRequestsFromMapFunc(func(_ context.Context, obj client.Object) []reconcile.Request {
+		configMap, ok := obj.(*corev1.ConfigMap)
+		if !ok {
+			return nil
+		}
+
+		requests := []reconcile.Request{}
+		for key := range configMap.Data {
+			if name, ok := r.serviceFromServerBlockKey(key); ok {
+				requests = append(requests, reconcile.Request{NamespacedName: name})
+			}
+		}
+		return requests
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{
+			CacheSyncTimeout: constants.DefaultCacheSyncTimeout,
+		}).
+		Named("coredns_headless_zones").
+		For(&corev1.Service{}).
+		Watches(&corev1.Pod{}, podHandler).
+		Watches(&corev1.ConfigMap{}, configMapHandler, builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+			return object.GetNamespace() == Namespace && object.GetName() == HeadlessConfigMapName
+		}))).
+		Complete(r)
+}
+
+func (r *HeadlessZonesReconciler) serviceFromServerBlockKey(key string) (types.NamespacedName, bool) {
+	zone, ok := strings.CutSuffix(key, serverBlockSuffix)
+	if !ok {
+		return types.NamespacedName{}, false
+	}
+
+	name, namespace, ok := strings.Cut(strings.TrimSuffix(zone, ".svc."+r.ClusterDomain), ".")
+	if !ok || name == "" || namespace == "" || strings.Contains(namespace, ".") {
+		return types.NamespacedName{}, false
+	}
+
+	return types.NamespacedName{Namespace: namespace, Name: name}, true
+}
+
+func serverBlockKey(zone string) string {
+	return zone + serverBlockSuffix
+}
+
+func zoneFileKey(zone string) string {
+	return zoneFilePrefix + zone
+}
+
+func zoneSize(data map[string]string, zone string) int {
+	size := 0
+	for _, key := range []string{serverBlockKey(zone), zoneFileKey(zone)} {
+		if value, ok := data[key]; ok {
+			size += len(key) + len(value)
+		}
+	}
+
+	return size
+}
+
+func configMapSize(configMap *corev1.ConfigMap) int {
+	size := 0
+	for key, value := range configMap.Data {
+		size += len(key) + len(value)
+	}
+
+	return size
+}
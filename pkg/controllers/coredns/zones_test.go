@@ -0,0 +1,218 @@
+package coredns
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestHeadlessConformance checks the answers of the zone served for a headless service against the Kubernetes DNS
+// specification: https://github.com/kubernetes/dns/blob/master/docs/specification.md
+func TestHeadlessConformance(t *testing.T) {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "test"},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: corev1.ClusterIPNone,
+			Selector:  map[string]string{"app": "web"},
+			Ports:     []corev1.ServicePort{{Name: "http", Port: 80, TargetPort: intstr.FromInt32(8080), Protocol: corev1.ProtocolTCP}},
+		},
+	}
+	pods := []corev1.Pod{
+		newReadyPod("web-0", "web", []string{"10.0.0.1", "fd00::1"}),
+		newReadyPod("web-1", "web", []string{"10.0.0.2"}),
+		newReadyPod("other", "", []string{"10.0.0.3"}),
+	}
+
+	zone := ZoneName(service, "cluster.local")
+	zoneFile := ZoneFile(zone, HeadlessRecords([]corev1.Service{*service}, pods, "cluster.local"), "cluster.local")
+	answers := parseZoneFile(t, zoneFile)
+
+	testCases := []struct {
+		spec     string
+		name     string
+		qtype    string
+		expected []string
+	}{
+		{
+			spec:     "2.4.1 A records of the service",
+			name:     "web.test.svc.cluster.local.",
+			qtype:    RecordTypeA,
+			expected: []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"},
+		},
+		{
+			spec:     "2.4.1 AAAA records of the service",
+			name:     "web.test.svc.cluster.local.",
+			qtype:    RecordTypeAAAA,
+			expected: []string{"fd00::1"},
+		},
+		{
+			spec:     "2.4.1 A records of a pod with hostname and subdomain",
+			name:     "web-0.web.test.svc.cluster.local.",
+			qtype:    RecordTypeA,
+			expected: []string{"10.0.0.1"},
+		},
+		{
+			spec:     "2.4.1 AAAA records of a pod with hostname and subdomain",
+			name:     "web-0.web.test.svc.cluster.local.",
+			qtype:    RecordTypeAAAA,
+			expected: []string{"fd00::1"},
+		},
+		{
+			spec:     "2.4.1 A records of a pod without hostname",
+			name:     "10-0-0-3.web.test.svc.cluster.local.",
+			qtype:    RecordTypeA,
+			expected: []string{"10.0.0.3"},
+		},
+		{
+			spec:  "2.4.2 SRV records of a named port",
+			name:  "_http._tcp.web.test.svc.cluster.local.",
+			qtype: RecordTypeSRV,
+			expected: []string{
+				"0 100 8080 10-0-0-3.web.test.svc.cluster.local.",
+				"0 100 8080 web-0.web.test.svc.cluster.local.",
+				"0 100 8080 web-1.web.test.svc.cluster.local.",
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		assert.Check(t, cmp.DeepEqual(answers[testCase.name+" "+testCase.qtype], testCase.expected), testCase.spec)
+	}
+
+	// every srv target is resolvable within the same zone
+	for _, srv := range answers["_http._tcp.web.test.svc.cluster.local. "+RecordTypeSRV] {
+		target := srv[strings.LastIndex(srv, " ")+1:]
+		assert.Assert(t, len(answers[target+" "+RecordTypeA]) > 0, "srv target %s is not resolvable", target)
+	}
+
+	// the server block serves the zone from the mounted zone file
+	assert.Equal(t, ServerBlock(zone), `web.test.svc.cluster.local:1053 {
+    errors
+    file /etc/coredns/headless/db.web.test.svc.cluster.local {
+        reload 5s
+    }
+    loadbalance
+}
+`)
+}
+
+func TestHeadlessZonesReconciler(t *testing.T) {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "test"},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: corev1.ClusterIPNone,
+			Selector:  map[string]string{"app": "web"},
+		},
+	}
+	pod := newReadyPod("web-0", "web", []string{"10.0.0.1"})
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: HeadlessConfigMapName, Namespace: Namespace},
+		Data:       map[string]string{"other.server": "other"},
+	}
+	vClient := fake.NewClientBuilder().WithScheme(testingutil.NewScheme()).WithObjects(service, &pod, configMap).Build()
+	r := &HeadlessZonesReconciler{Client: vClient, Log: loghelper.New("test"), ClusterDomain: "cluster.local"}
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "test", Name: "web"}}
+	getData := func() map[string]string {
+		return getConfigMap(t, vClient).Data
+	}
+
+	// the zone of the service is added next to the other keys
+	_, err := r.Reconcile(context.TODO(), request)
+	assert.NilError(t, err)
+	data := getData()
+	assert.Equal(t, len(data), 3)
+	assert.Equal(t, data["web.test.svc.cluster.local.server"], ServerBlock("web.test.svc.cluster.local"))
+	assert.Assert(t, strings.Contains(data["db.web.test.svc.cluster.local"], "web-0.web.test.svc.cluster.local. IN A 10.0.0.1"))
+
+	// zones that would exceed the size of the ConfigMap are removed
+	big := getConfigMap(t, vClient)
+	big.Data["other.server"] = strings.Repeat("a", MaxHeadlessConfigMapSize)
+	assert.NilError(t, vClient.Update(context.TODO(), big))
+	_, err = r.Reconcile(context.TODO(), request)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, keys(getData()), []string{"other.server"})
+
+	// zones of deleted services are removed
+	big = getConfigMap(t, vClient)
+	big.Data["other.server"] = "other"
+	assert.NilError(t, vClient.Update(context.TODO(), big))
+	_, err = r.Reconcile(context.TODO(), request)
+	assert.NilError(t, err)
+	assert.Equal(t, len(getData()), 3)
+	assert.NilError(t, vClient.Delete(context.TODO(), service))
+	_, err = r.Reconcile(context.TODO(), request)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, keys(getData()), []string{"other.server"})
+
+	// services are recovered from the keys of the ConfigMap
+	name, ok := r.serviceFromServerBlockKey("web.test.svc.cluster.local.server")
+	assert.Assert(t, ok)
+	assert.Equal(t, name, request.NamespacedName)
+	_, ok = r.serviceFromServerBlockKey("db.web.test.svc.cluster.local")
+	assert.Assert(t, !ok)
+}
+
+func newReadyPod(hostname, subdomain string, ips []string) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: hostname, Namespace: "test", Labels: map[string]string{"app": "web"}},
+		Spec:       corev1.PodSpec{Subdomain: subdomain},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	if subdomain != "" {
+		pod.Spec.Hostname = hostname
+	}
+	for _, ip := range ips {
+		pod.Status.PodIPs = append(pod.Status.PodIPs, corev1.PodIP{IP: ip})
+	}
+
+	return pod
+}
+
+// parseZoneFile returns the answers of the zone file by name and type
+func parseZoneFile(t *testing.T, zoneFile string) map[string][]string {
+	answers := map[string][]string{}
+	for _, line := range strings.Split(strings.TrimSpace(zoneFile), "\n") {
+		fields := strings.Fields(line)
+		if strings.HasPrefix(line, "$") || fields[0] == "@" {
+			continue
+		}
+
+		assert.Assert(t, len(fields) >= 4 && fields[1] == "IN", "invalid record %q", line)
+		key := fields[0] + " " + fields[2]
+		answers[key] = append(answers[key], strings.Join(fields[3:], " "))
+	}
+	for key := range answers {
+		sort.Strings(answers[key])
+	}
+
+	return answers
+}
+
+func getConfigMap(t *testing.T, c client.Client) *corev1.ConfigMap {
+	configMap := &corev1.ConfigMap{}
+	assert.NilError(t, c.Get(context.TODO(), client.ObjectKey{Namespace: Namespace, Name: HeadlessConfigMapName}, configMap))
+	return configMap
+}
+
+func keys(data map[string]string) []string {
+	out := []string{}
+	for key := range data {
+		out = append(out, key)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -16,7 +16,9 @@ import (
 	vclusterconfig "github.com/loft-sh/vcluster/config"
 	"github.com/loft-sh/vcluster/pkg/config"
 	"github.com/loft-sh/vcluster/pkg/k0s"
+	"github.com/loft-sh/vcluster/pkg/tracing"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -47,6 +49,7 @@ const (
 	InstallError   = "InstallFailed"
 	UpgradeError   = "UpgradeFailed"
 	UninstallError = "UninstallFailed"
+	WaveNotReady   = "WaveNotReady"
 
 	VClusterDeployConfigMap          = "vcluster-deploy"
 	VClusterDeployConfigMapNamespace = "kube-system"
@@ -57,14 +60,24 @@ type Deployer struct {
 
 	VirtualManager ctrl.Manager
 	HelmClient     helm.Client
+
+	// pulledArtifacts caches the manifests of OCI sources by source name
+	pulledArtifacts map[string]pulledArtifact
+	// renderedKustomizations caches the manifests of remote kustomizations by source name
+	renderedKustomizations map[string]pulledArtifact
 }
 
 func (r *Deployer) Apply(ctx context.Context, vConfig *config.VirtualClusterConfig) (result ctrl.Result, err error) {
+	ctx, span := tracing.StartSpan(ctx, "deploy Apply")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	// get config map
 	configMap := &corev1.ConfigMap{}
 	err = r.VirtualManager.GetClient().Get(ctx, types.NamespacedName{Name: VClusterDeployConfigMap, Namespace: VClusterDeployConfigMapNamespace}, configMap)
 	if kerrors.IsNotFound(err) {
-		if vConfig.Experimental.Deploy.Manifests == "" && vConfig.Experimental.Deploy.ManifestsTemplate == "" && len(vConfig.Experimental.Deploy.Helm) == 0 {
+		if vConfig.Experimental.Deploy.Manifests == "" && vConfig.Experimental.Deploy.ManifestsTemplate == "" && len(vConfig.Experimental.Deploy.Helm) == 0 && len(vConfig.Experimental.Deploy.Kustomize) == 0 && len(vConfig.Experimental.Deploy.OCI) == 0 {
 			return ctrl.Result{}, nil
 		}
 
@@ -99,6 +112,14 @@ func (r *Deployer) Apply(ctx context.Context, vConfig *config.VirtualClusterConf
 		return ctrl.Result{Requeue: true}, nil
 	}
 
+	// process the kustomizations and oci artifacts
+	requeue, err = r.ProcessSources(ctx, vConfig, configMap)
+	if err != nil {
+		return ctrl.Result{}, err
+	} else if requeue {
+		return ctrl.Result{Requeue: true}, nil
+	}
+
 	// process the helm charts
 	requeue, err = r.ProcessHelmChart(ctx, vConfig, configMap)
 	if err != nil {
@@ -120,14 +141,32 @@ func (r *Deployer) UpdateConfigMap(ctx context.Context, lastError error, requeue
 	currentStatus.Message = ""
 
 	// check manifests status
+	unfinished := false
 	if currentStatus.Manifests.Phase != string(StatusSuccess) {
-		currentStatus.Phase = string(StatusFailed)
+		unfinished = true
+		currentStatus.Phase = unfinishedPhase(currentStatus.Manifests.Phase)
 		currentStatus.Reason = currentStatus.Manifests.Reason
 		currentStatus.Message = currentStatus.Manifests.Message
-	} else {
-		// check if all charts were deployed correctly
+	}
+
+	// check if all sources were applied correctly
+	if !unfinished {
+		for _, sourceStatus := range currentStatus.Sources {
+			if sourceStatus.Phase != string(StatusSuccess) {
+				unfinished = true
+				currentStatus.Phase = unfinishedPhase(sourceStatus.Phase)
+				currentStatus.Reason = sourceStatus.Reason
+				currentStatus.Message = sourceStatus.Message
+				break
+			}
+		}
+	}
+
+	// check if all charts were deployed correctly
+	if !unfinished {
 		for _, chartStatus := range currentStatus.Charts {
 			if chartStatus.Phase != string(StatusSuccess) {
+				unfinished = true
 				currentStatus.Phase = string(StatusFailed)
 				currentStatus.Reason = chartStatus.Reason
 				currentStatus.Message = chartStatus.Message
@@ -137,7 +176,7 @@ func (r *Deployer) UpdateConfigMap(ctx context.Context, lastError error, requeue
 	}
 
 	// check if there was an error otherwise set to success
-	if currentStatus.Phase == string(StatusPending) {
+	if !unfinished {
 		if lastError == nil {
 			if requeue {
 				currentStatus.Phase = string(StatusPending)
@@ -177,8 +216,22 @@ func (r *Deployer) UpdateConfigMap(ctx context.Context, lastError error, requeue
 	return nil
 }
 
-func (r *Deployer) ProcessInitManifests(ctx context.Context, vConfig *config.VirtualClusterConfig, configMap *corev1.ConfigMap) (bool, error) {
-	var err error
+// unfinishedPhase returns the overall phase for a manifests or source status that is not successful. Only waves that
+// are still becoming ready are pending, everything else failed.
+func unfinishedPhase(phase string) string {
+	if phase == string(StatusPending) {
+		return string(StatusPending)
+	}
+
+	return string(StatusFailed)
+}
+
+func (r *Deployer) ProcessInitManifests(ctx context.Context, vConfig *config.VirtualClusterConfig, configMap *corev1.ConfigMap) (_ bool, err error) {
+	ctx, span := tracing.StartSpan(ctx, "deploy ProcessInitManifests")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	manifests := vConfig.Experimental.Deploy.Manifests
 	if vConfig.Experimental.Deploy.ManifestsTemplate != "" {
 		templatedManifests, err := k0s.ExecTemplate(vConfig.Experimental.Deploy.ManifestsTemplate, vConfig.Name, vConfig.TargetNamespace, &vConfig.Config)
@@ -208,9 +261,19 @@ func (r *Deployer) ProcessInitManifests(ctx context.Context, vConfig *config.Vir
 	}
 
 	// apply manifests
-	err = ApplyGivenInitManifests(ctx, r.VirtualManager.GetClient(), r.VirtualManager.GetConfig(), manifests, lastAppliedManifests)
+	objects, err := ApplyGivenInitManifests(ctx, r.VirtualManager.GetClient(), r.VirtualManager.GetAPIReader(), manifests, lastAppliedManifests, status.Manifests.Objects)
 	if err != nil {
 		r.Log.Errorf("error applying init manifests: %v", err)
+		if objects != nil {
+			status.Manifests.Objects = objects
+			_ = r.encodeStatus(configMap, status)
+		}
+		if IsWaveNotReady(err) {
+			// apply the manifests again and keep waiting for the wave
+			_ = r.setManifestsStatus(configMap, StatusPending, WaveNotReady, err.Error())
+			return true, nil
+		}
+
 		_ = r.setManifestsStatus(configMap, StatusFailed, InstallError, err.Error())
 		return false, err
 	}
@@ -224,6 +287,7 @@ func (r *Deployer) ProcessInitManifests(ctx context.Context, vConfig *config.Vir
 
 	// update annotation
 	status.Manifests.LastAppliedManifests = compressedManifests
+	status.Manifests.Objects = objects
 	err = r.encodeStatus(configMap, status)
 	if err != nil {
 		return false, err
@@ -231,7 +295,12 @@ func (r *Deployer) ProcessInitManifests(ctx context.Context, vConfig *config.Vir
 	return true, r.setManifestsStatus(configMap, StatusSuccess, "", "")
 }
 
-func (r *Deployer) ProcessHelmChart(ctx context.Context, vConfig *config.VirtualClusterConfig, configMap *corev1.ConfigMap) (bool, error) {
+func (r *Deployer) ProcessHelmChart(ctx context.Context, vConfig *config.VirtualClusterConfig, configMap *corev1.ConfigMap) (_ bool, err error) {
+	ctx, span := tracing.StartSpan(ctx, "deploy ProcessHelmChart")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	statusMap, err := r.getStatusMap(configMap)
 	if err != nil {
 		return false, err
@@ -335,8 +404,13 @@ func (r *Deployer) checkIfUpgradeNeeded(cm *corev1.ConfigMap, chart vclusterconf
 	return true, nil
 }
 
-func (r *Deployer) initiateUpgrade(ctx context.Context, chart vclusterconfig.ExperimentalDeployHelm) error {
+func (r *Deployer) initiateUpgrade(ctx context.Context, chart vclusterconfig.ExperimentalDeployHelm) (err error) {
 	name, namespace := r.getTargetRelease(chart)
+	ctx, span := tracing.StartSpan(ctx, "deploy HelmUpgrade", attribute.String("helm.release", namespace+"/"+name))
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	path, err := r.findChart(chart)
 	if err != nil {
 		return err
@@ -427,9 +501,14 @@ func (r *Deployer) findChart(chart vclusterconfig.ExperimentalDeployHelm) (strin
 	return "", nil
 }
 
-func (r *Deployer) initiateInstall(ctx context.Context, chart vclusterconfig.ExperimentalDeployHelm) error {
+func (r *Deployer) initiateInstall(ctx context.Context, chart vclusterconfig.ExperimentalDeployHelm) (err error) {
 	// initiate install
 	name, namespace := r.getTargetRelease(chart)
+	ctx, span := tracing.StartSpan(ctx, "deploy HelmInstall", attribute.String("helm.release", namespace+"/"+name))
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	path, err := r.findChart(chart)
 	if err != nil {
 		return err
@@ -492,7 +571,12 @@ func (r *Deployer) releaseExists(chart vclusterconfig.ExperimentalDeployHelm) (b
 	return false, nil
 }
 
-func (r *Deployer) pullChartArchive(ctx context.Context, chart vclusterconfig.ExperimentalDeployHelm) error {
+func (r *Deployer) pullChartArchive(ctx context.Context, chart vclusterconfig.ExperimentalDeployHelm) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "deploy HelmPull", attribute.String("helm.chart", chart.Chart.Name))
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	tarballPath, err := r.findChart(chart)
 	if err != nil {
 		return err
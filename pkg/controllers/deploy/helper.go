@@ -2,79 +2,400 @@ package deploy
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/ghodss/yaml"
-	"github.com/loft-sh/vcluster/pkg/util/applier"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/client-go/rest"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
 
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func ApplyGivenInitManifests(ctx context.Context, vClient client.Client, vConfig *rest.Config, rawManifests, lastAppliedManifests string) error {
+const (
+	// FieldManager is the field manager used to server-side apply the init manifests
+	FieldManager = "vcluster-init-manifests"
+
+	// InventoryLabel is added to every object applied from the init manifests. Only objects with this label
+	// are pruned when they are removed from the init manifests.
+	InventoryLabel = "vcluster.loft.sh/init-manifests"
+
+	// WaveAnnotation can be set on an object within the init manifests to define in which wave the object
+	// should get applied. Waves are applied in ascending order and vCluster waits for all objects of a wave
+	// to become ready before the next wave is applied.
+	WaveAnnotation = "vcluster.loft.sh/apply-wave"
+
+	// WaveCustomResourceDefinitions is the default wave for custom resource definitions
+	WaveCustomResourceDefinitions = -2
+	// WaveNamespaces is the default wave for namespaces
+	WaveNamespaces = -1
+	// WaveDefault is the default wave for all other objects
+	WaveDefault = 0
+
+	// WaveReadyTimeout is the time vCluster waits for the objects of a wave to become ready, before the manifests
+	// are applied again and vCluster continues waiting
+	WaveReadyTimeout = 2 * time.Minute
+
+	// legacyLastAppliedAnnotation is set by the client-side applier used in earlier versions
+	legacyLastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+var (
+	customResourceDefinitionGroupKind = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+	namespaceGroupKind                = schema.GroupKind{Kind: "Namespace"}
+)
+
+// ApplyGivenInitManifests server-side applies the given manifests wave by wave and prunes all objects that were part of
+// the previously applied manifests, but are not anymore. It returns the status of every object within the manifests.
+func ApplyGivenInitManifests(ctx context.Context, vClient client.Client, vReader client.Reader, rawManifests, lastAppliedManifests string, lastObjects []ObjectStatus) ([]ObjectStatus, error) {
 	lastAppliedObjects, err := populateLastAppliedMap(lastAppliedManifests, corev1.NamespaceDefault)
 	if err != nil {
 		klog.Errorf("unable to parse objects from last applied manifests: %v", err)
-		return errors.Wrap(err, "unable to parse last applied manifests")
+		return nil, errors.Wrap(err, "unable to parse last applied manifests")
 	}
 
 	objs, err := ManifestStringToUnstructuredArray(rawManifests, corev1.NamespaceDefault)
 	if err != nil {
 		klog.Errorf("unable to parse objects: %v", err)
-		return errors.Wrap(err, "unable to parse objects")
+		return nil, errors.Wrap(err, "unable to parse objects")
 	}
-	if len(lastAppliedObjects) == 0 && len(objs) == 0 {
-		return nil
+
+	// group objects into waves
+	waves, statuses, err := groupIntoWaves(objs)
+	if err != nil {
+		return nil, err
+	}
+
+	// apply objects wave by wave
+	if len(objs) > 0 {
+		klog.Infof("got %d objs to be applied in %d waves", len(objs), len(waves))
 	}
+	for i, wave := range waves {
+		err = applyWave(ctx, vClient, wave, statuses)
+		if err != nil {
+			return statuses, err
+		}
+
+		// wait until the wave is ready if there is another wave after it
+		if i < len(waves)-1 {
+			err = waitForWave(ctx, vReader, wave, statuses)
+			if err != nil {
+				return statuses, err
+			}
+		}
+	}
+
+	// prune objects that are no longer part of the current manifests
+	err = pruneObjects(ctx, vClient, vReader, objs, lastAppliedObjects, lastObjects)
+	if err != nil {
+		return statuses, err
+	}
+
+	return statuses, nil
+}
+
+// WaveNotReadyError is returned if the objects of a wave did not become ready within WaveReadyTimeout. It is
+// not a failure, the manifests should be applied again until the wave becomes ready.
+type WaveNotReadyError struct {
+	Wave     int
+	Messages []string
+}
+
+func (e *WaveNotReadyError) Error() string {
+	return fmt.Sprintf("wave %d did not become ready yet: %s", e.Wave, strings.Join(e.Messages, ", "))
+}
+
+// IsWaveNotReady checks if the error was returned because a wave did not become ready yet
+func IsWaveNotReady(err error) bool {
+	waveErr := &WaveNotReadyError{}
+	return errors.As(err, &waveErr)
+}
+
+type manifestWave struct {
+	wave int
+	objs []*unstructured.Unstructured
+}
 
-	// create string from objs
-	processedManifests := ""
+func groupIntoWaves(objs []*unstructured.Unstructured) ([]manifestWave, []ObjectStatus, error) {
+	statuses := make([]ObjectStatus, 0, len(objs))
+	wavesMap := map[int]*manifestWave{}
 	for _, obj := range objs {
-		out, err := yaml.Marshal(obj)
+		wave, err := getWave(obj)
 		if err != nil {
-			return errors.Wrap(err, "marshal object")
+			return nil, nil, err
 		}
 
-		processedManifests += "\n---\n" + string(out)
+		if wavesMap[wave] == nil {
+			wavesMap[wave] = &manifestWave{wave: wave}
+		}
+		wavesMap[wave].objs = append(wavesMap[wave].objs, obj)
+		statuses = append(statuses, newObjectStatus(obj, wave))
 	}
 
-	// apply objects
-	if len(objs) > 0 {
-		klog.Infof("got %d objs to be applied", len(objs))
-		err = applier.ApplyManifest(ctx, vConfig, []byte(processedManifests))
+	// sort waves ascending, objects keep their order within the manifests
+	waves := make([]manifestWave, 0, len(wavesMap))
+	for _, wave := range wavesMap {
+		waves = append(waves, *wave)
+	}
+	sort.Slice(waves, func(i, j int) bool {
+		return waves[i].wave < waves[j].wave
+	})
+
+	return waves, statuses, nil
+}
+
+func getWave(obj *unstructured.Unstructured) (int, error) {
+	if rawWave, ok := obj.GetAnnotations()[WaveAnnotation]; ok {
+		wave, err := strconv.Atoi(strings.TrimSpace(rawWave))
 		if err != nil {
-			klog.Errorf("error applying manifests: %v", err)
-			return errors.Wrap(err, "apply manifests")
+			return 0, fmt.Errorf("parse %s annotation of %s %s: %w", WaveAnnotation, obj.GetKind(), obj.GetName(), err)
 		}
+
+		return wave, nil
 	}
 
+	switch obj.GroupVersionKind().GroupKind() {
+	case customResourceDefinitionGroupKind:
+		return WaveCustomResourceDefinitions, nil
+	case namespaceGroupKind:
+		return WaveNamespaces, nil
+	}
+
+	return WaveDefault, nil
+}
+
+func applyWave(ctx context.Context, vClient client.Client, wave manifestWave, statuses []ObjectStatus) error {
+	for _, obj := range wave.objs {
+		applyObj := obj.DeepCopy()
+		labels := applyObj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[InventoryLabel] = "true"
+		applyObj.SetLabels(labels)
+
+		klog.V(1).Infof("server-side apply %s %s/%s in wave %d", applyObj.GetKind(), applyObj.GetNamespace(), applyObj.GetName(), wave.wave)
+		err := vClient.Patch(ctx, applyObj, client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership)
+		if err != nil {
+			setObjectStatus(statuses, obj, StatusFailed, InstallError, err.Error())
+			return fmt.Errorf("apply %s %s: %w", obj.GetKind(), objectName(obj), err)
+		}
+
+		setObjectStatus(statuses, obj, StatusSuccess, "", "")
+	}
+
+	return nil
+}
+
+func waitForWave(ctx context.Context, vReader client.Reader, wave manifestWave, statuses []ObjectStatus) error {
+	notReady := map[KObject]string{}
+	err := wait.PollUntilContextTimeout(ctx, time.Second, WaveReadyTimeout, true, func(ctx context.Context) (bool, error) {
+		notReady = map[KObject]string{}
+		for _, obj := range wave.objs {
+			current := &unstructured.Unstructured{}
+			current.SetGroupVersionKind(obj.GroupVersionKind())
+			err := vReader.Get(ctx, client.ObjectKeyFromObject(obj), current)
+			if err != nil {
+				notReady[UnstructuredToKObject(*obj)] = err.Error()
+				continue
+			}
+
+			ready, reason := isReady(current)
+			if !ready {
+				notReady[UnstructuredToKObject(*obj)] = reason
+			}
+		}
+
+		return len(notReady) == 0, nil
+	})
+	if ctx.Err() != nil {
+		return ctx.Err()
+	} else if err != nil {
+		messages := []string{}
+		for _, obj := range wave.objs {
+			reason, ok := notReady[UnstructuredToKObject(*obj)]
+			if !ok {
+				continue
+			}
+
+			setObjectStatus(statuses, obj, StatusPending, "NotReady", reason)
+			messages = append(messages, fmt.Sprintf("%s %s: %s", obj.GetKind(), objectName(obj), reason))
+		}
+
+		return &WaveNotReadyError{Wave: wave.wave, Messages: messages}
+	}
+
+	return nil
+}
+
+// isReady checks common status fields to determine if the object is ready
+func isReady(obj *unstructured.Unstructured) (bool, string) {
+	switch obj.GroupVersionKind().GroupKind() {
+	case customResourceDefinitionGroupKind:
+		if conditionStatus(obj, "Established") != "True" {
+			return false, "custom resource definition is not established yet"
+		}
+
+		return true, ""
+	case namespaceGroupKind:
+		phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+		if phase != string(corev1.NamespaceActive) {
+			return false, "namespace is not active yet"
+		}
+
+		return true, ""
+	}
+
+	// check if the controller has observed the latest generation
+	observedGeneration, found, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if found && observedGeneration < obj.GetGeneration() {
+		return false, "latest generation was not observed yet"
+	}
+
+	// check ready and available conditions
+	for _, conditionType := range []string{"Ready", "Available"} {
+		status := conditionStatus(obj, conditionType)
+		if status != "" && status != "True" {
+			return false, fmt.Sprintf("condition %s is %s", conditionType, status)
+		}
+	}
+
+	// check ready replicas
+	replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if found {
+		readyReplicas, _, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+		if readyReplicas < replicas {
+			return false, fmt.Sprintf("%d/%d replicas are ready", readyReplicas, replicas)
+		}
+	}
+
+	return true, ""
+}
+
+func conditionStatus(obj *unstructured.Unstructured, conditionType string) string {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, condition := range conditions {
+		conditionMap, ok := condition.(map[string]interface{})
+		if !ok || conditionMap["type"] != conditionType {
+			continue
+		}
+
+		status, _ := conditionMap["status"].(string)
+		return status
+	}
+
+	return ""
+}
+
+func pruneObjects(ctx context.Context, vClient client.Client, vReader client.Reader, objs []*unstructured.Unstructured, lastAppliedObjects UnstructuredMap, lastObjects []ObjectStatus) error {
 	// register applied objects in a map
 	currentlyApplyingObjects := make(UnstructuredMap)
 	for _, obj := range objs {
 		currentlyApplyingObjects[UnstructuredToKObject(*obj)] = obj.DeepCopy()
 	}
 
-	// delete objects that are no longer part of the current manifests
-	// but exists in the last applied map and hence in the vcluster
+	// collect the objects that were applied before
+	pruneCandidates := make(UnstructuredMap)
+	pruneWaves := map[KObject]int{}
 	for key, value := range lastAppliedObjects {
+		pruneCandidates[key] = value
+		pruneWaves[key], _ = getWave(value)
+	}
+	for _, objectStatus := range lastObjects {
+		key := objectStatus.KObject()
+		if _, ok := pruneCandidates[key]; ok {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(objectStatus.APIVersion)
+		obj.SetKind(objectStatus.Kind)
+		obj.SetNamespace(objectStatus.Namespace)
+		obj.SetName(objectStatus.Name)
+		pruneCandidates[key] = obj
+		pruneWaves[key] = objectStatus.Wave
+	}
+
+	// delete objects in reverse wave order, so that workloads are removed before their namespaces and crds
+	pruneKeys := []KObject{}
+	for key := range pruneCandidates {
 		if _, ok := currentlyApplyingObjects[key]; !ok {
-			// this object is not in the list of objects to be applied
-			// but was in last applied configuration, hence proceed for its deletion
-			klog.Infof("delete non existing init object: %v", key)
-			err = vClient.Delete(ctx, value)
-			if err != nil && !kerrors.IsNotFound(err) {
-				klog.Errorf("unable to delete old object %s: %v", value.GetName(), err)
-				return errors.Wrapf(err, "unable to delete old object: %v", value.GetName())
+			pruneKeys = append(pruneKeys, key)
+		}
+	}
+	sort.SliceStable(pruneKeys, func(i, j int) bool {
+		return pruneWaves[pruneKeys[i]] > pruneWaves[pruneKeys[j]]
+	})
+
+	for _, key := range pruneKeys {
+		value := pruneCandidates[key]
+
+		// check if the object is part of our inventory
+		current := &unstructured.Unstructured{}
+		current.SetGroupVersionKind(value.GroupVersionKind())
+		err := vReader.Get(ctx, client.ObjectKeyFromObject(value), current)
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
 			}
+
+			return errors.Wrapf(err, "get old object %s", value.GetName())
+		} else if current.GetLabels()[InventoryLabel] != "true" && current.GetAnnotations()[legacyLastAppliedAnnotation] == "" {
+			klog.Infof("skip pruning init object %v as it is not managed by vCluster", key)
+			continue
+		}
+
+		// this object is not in the list of objects to be applied
+		// but was applied previously, hence proceed for its deletion
+		klog.Infof("delete non existing init object: %v", key)
+		err = vClient.Delete(ctx, current)
+		if err != nil && !kerrors.IsNotFound(err) {
+			klog.Errorf("unable to delete old object %s: %v", value.GetName(), err)
+			return errors.Wrapf(err, "unable to delete old object: %v", value.GetName())
 		}
 	}
 
 	return nil
 }
 
+func newObjectStatus(obj *unstructured.Unstructured, wave int) ObjectStatus {
+	return ObjectStatus{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+		Wave:       wave,
+		Phase:      string(StatusPending),
+	}
+}
+
+func setObjectStatus(statuses []ObjectStatus, obj *unstructured.Unstructured, phase InitObjectStatus, reason, message string) {
+	key := UnstructuredToKObject(*obj)
+	for i := range statuses {
+		if statuses[i].KObject() == key {
+			statuses[i].Phase = string(phase)
+			statuses[i].Reason = reason
+			statuses[i].Message = message
+			return
+		}
+	}
+}
+
+func objectName(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() != "" {
+		return obj.GetNamespace() + "/" + obj.GetName()
+	}
+
+	return obj.GetName()
+}
+
 func populateLastAppliedMap(manifests, defaultNamespace string) (UnstructuredMap, error) {
 	m := make(UnstructuredMap)
 	if manifests == "" {
@@ -0,0 +1,126 @@
+package deploy
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestGroupIntoWaves(t *testing.T) {
+	objs, err := ManifestStringToUnstructuredArray(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: test
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: tests.example.com
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: test
+  annotations:
+    vcluster.loft.sh/apply-wave: "5"`, "default")
+	assert.NilError(t, err)
+
+	waves, statuses, err := groupIntoWaves(objs)
+	assert.NilError(t, err)
+	assert.Equal(t, len(statuses), 4)
+	assert.Equal(t, len(waves), 4)
+
+	expected := []struct {
+		wave int
+		kind string
+	}{
+		{wave: WaveCustomResourceDefinitions, kind: "CustomResourceDefinition"},
+		{wave: WaveNamespaces, kind: "Namespace"},
+		{wave: WaveDefault, kind: "ConfigMap"},
+		{wave: 5, kind: "Secret"},
+	}
+	for i, e := range expected {
+		assert.Equal(t, waves[i].wave, e.wave)
+		assert.Equal(t, len(waves[i].objs), 1)
+		assert.Equal(t, waves[i].objs[0].GetKind(), e.kind)
+	}
+}
+
+func TestIsReady(t *testing.T) {
+	testTable := []struct {
+		desc     string
+		manifest string
+
+		expectedReady bool
+	}{
+		{
+			desc: "established crd",
+			manifest: `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: tests.example.com
+status:
+  conditions:
+  - type: Established
+    status: "True"`,
+			expectedReady: true,
+		},
+		{
+			desc: "not established crd",
+			manifest: `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: tests.example.com`,
+			expectedReady: false,
+		},
+		{
+			desc: "deployment with missing replicas",
+			manifest: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test
+spec:
+  replicas: 2
+status:
+  readyReplicas: 1`,
+			expectedReady: false,
+		},
+		{
+			desc: "config map",
+			manifest: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test`,
+			expectedReady: true,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Logf("running test: %q", testCase.desc)
+		objs, err := ManifestStringToUnstructuredArray(testCase.manifest, "default")
+		assert.NilError(t, err)
+		assert.Equal(t, len(objs), 1)
+
+		ready, _ := isReady(objs[0])
+		assert.Equal(t, ready, testCase.expectedReady, testCase.desc)
+	}
+}
+
+func TestIsWaveNotReady(t *testing.T) {
+	waveErr := &WaveNotReadyError{Wave: 1, Messages: []string{"Deployment default/web: 0/1 replicas are ready"}}
+	assert.Equal(t, waveErr.Error(), "wave 1 did not become ready yet: Deployment default/web: 0/1 replicas are ready")
+	assert.Assert(t, IsWaveNotReady(waveErr))
+	assert.Assert(t, IsWaveNotReady(fmt.Errorf("apply source: %w", waveErr)))
+	assert.Assert(t, !IsWaveNotReady(errors.New("apply Deployment default/web: forbidden")))
+
+	// a wave that is not ready keeps the overall status pending
+	assert.Equal(t, unfinishedPhase(string(StatusPending)), string(StatusPending))
+	assert.Equal(t, unfinishedPhase(string(StatusFailed)), string(StatusFailed))
+	assert.Equal(t, unfinishedPhase(""), string(StatusFailed))
+}
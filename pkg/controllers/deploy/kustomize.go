@@ -0,0 +1,144 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+)
+
+var commitHashRegEx = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// remoteKustomization is a kustomization within a git repository, e.g.
+// https://github.com/my-org/my-repo//overlays/prod?ref=v1.0.0
+type remoteKustomization struct {
+	Repository string
+	Path       string
+	Ref        string
+}
+
+// parseRemoteKustomization parses a kustomize style remote url. The path within the repository is separated by //
+// and the branch, tag or commit is given by the ref query parameter.
+func parseRemoteKustomization(rawURL string) (*remoteKustomization, error) {
+	remote := &remoteKustomization{}
+	rawURL, query, _ := strings.Cut(rawURL, "?")
+	for _, param := range strings.Split(query, "&") {
+		if ref, ok := strings.CutPrefix(param, "ref="); ok {
+			remote.Ref = ref
+		}
+	}
+
+	scheme, rest, ok := strings.Cut(rawURL, "://")
+	if !ok || rest == "" {
+		return nil, fmt.Errorf("invalid kustomization url %s, expected a url such as https://github.com/my-org/my-repo//overlays/prod?ref=v1.0.0", rawURL)
+	}
+	rest, remote.Path, _ = strings.Cut(rest, "//")
+	remote.Repository = scheme + "://" + rest
+
+	// the path has to stay within the repository
+	remote.Path = filepath.Clean("/" + remote.Path)[1:]
+	return remote, nil
+}
+
+// renderRemoteKustomization renders a kustomization within a git repository. The repository is cloned in-process and
+// only if the resolved revision has changed since the last render.
+func (r *Deployer) renderRemoteKustomization(ctx context.Context, kustomization vclusterconfig.ExperimentalDeployKustomize) (string, error) {
+	remote, err := parseRemoteKustomization(kustomization.URL)
+	if err != nil {
+		return "", err
+	}
+
+	refName, revision, err := resolveRevision(ctx, remote)
+	if err != nil {
+		return "", fmt.Errorf("resolve %s: %w", kustomization.URL, err)
+	}
+	digest := kustomization.URL + "@" + revision.String()
+	if cached, ok := r.renderedKustomizations[kustomization.Name]; ok && cached.Digest == digest {
+		return cached.Manifests, nil
+	}
+
+	manifests, err := cloneAndRenderKustomization(ctx, remote, refName, revision)
+	if err != nil {
+		return "", err
+	}
+
+	if r.renderedKustomizations == nil {
+		r.renderedKustomizations = map[string]pulledArtifact{}
+	}
+	r.renderedKustomizations[kustomization.Name] = pulledArtifact{Digest: digest, Manifests: manifests}
+	return manifests, nil
+}
+
+// resolveRevision returns the reference and commit the ref of the remote points to, without cloning the repository
+func resolveRevision(ctx context.Context, remote *remoteKustomization) (plumbing.ReferenceName, plumbing.Hash, error) {
+	if commitHashRegEx.MatchString(remote.Ref) {
+		return "", plumbing.NewHash(remote.Ref), nil
+	}
+
+	refs, err := git.NewRemote(memory.NewStorage(), &gitconfig.RemoteConfig{Name: git.DefaultRemoteName, URLs: []string{remote.Repository}}).ListContext(ctx, &git.ListOptions{})
+	if err != nil {
+		return "", plumbing.ZeroHash, err
+	}
+
+	names := []plumbing.ReferenceName{plumbing.HEAD}
+	if remote.Ref != "" {
+		names = []plumbing.ReferenceName{plumbing.NewTagReferenceName(remote.Ref), plumbing.NewBranchReferenceName(remote.Ref)}
+	}
+	for _, name := range names {
+		for _, ref := range refs {
+			if ref.Name() != name {
+				continue
+			} else if ref.Type() == plumbing.HashReference {
+				return ref.Name(), ref.Hash(), nil
+			}
+
+			// follow symbolic references such as HEAD
+			for _, target := range refs {
+				if target.Name() == ref.Target() && target.Type() == plumbing.HashReference {
+					return target.Name(), target.Hash(), nil
+				}
+			}
+		}
+	}
+
+	return "", plumbing.ZeroHash, fmt.Errorf("ref %q not found", remote.Ref)
+}
+
+func cloneAndRenderKustomization(ctx context.Context, remote *remoteKustomization, refName plumbing.ReferenceName, revision plumbing.Hash) (string, error) {
+	dir, err := os.MkdirTemp("", "vcluster-kustomize-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	// only the history of the resolved branch or tag is cloned, commits can't be cloned directly, so the whole
+	// repository is cloned and the commit is checked out
+	cloneOptions := &git.CloneOptions{URL: remote.Repository, Tags: git.NoTags}
+	if refName != "" && refName != plumbing.HEAD {
+		cloneOptions.ReferenceName = refName
+		cloneOptions.SingleBranch = true
+	}
+	repository, err := git.PlainCloneContext(ctx, dir, false, cloneOptions)
+	if err != nil {
+		return "", fmt.Errorf("clone %s: %w", remote.Repository, err)
+	}
+
+	worktree, err := repository.Worktree()
+	if err != nil {
+		return "", err
+	}
+	err = worktree.Checkout(&git.CheckoutOptions{Hash: revision, Force: true})
+	if err != nil {
+		return "", fmt.Errorf("checkout %s: %w", revision, err)
+	}
+
+	return renderKustomization(vclusterconfig.ExperimentalDeployKustomize{Path: filepath.Join(dir, remote.Path)})
+}
@@ -0,0 +1,384 @@
+package deploy
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/md5"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/config"
+	"github.com/loft-sh/vcluster/pkg/tracing"
+	"github.com/loft-sh/vcluster/pkg/util/oci"
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+const (
+	SourceTypeKustomize = "Kustomize"
+	SourceTypeOCI       = "OCI"
+
+	SourceRenderError = "RenderFailed"
+	SourcePullError   = "PullFailed"
+)
+
+// manifestSource is a source of manifests such as a kustomization or an OCI artifact
+type manifestSource struct {
+	Name string
+	Type string
+
+	// Config is the configuration of the source used to calculate the hash
+	Config interface{}
+
+	// Render returns the manifests of the source
+	Render func(ctx context.Context) (string, error)
+}
+
+// pulledArtifact are the manifests of an OCI source at the given digest or of a remote kustomization at the given
+// git revision
+type pulledArtifact struct {
+	Digest    string
+	Manifests string
+}
+
+func (r *Deployer) getManifestSources(vConfig *config.VirtualClusterConfig) []manifestSource {
+	sources := []manifestSource{}
+	for _, kustomization := range vConfig.Experimental.Deploy.Kustomize {
+		kustomization := kustomization
+		sources = append(sources, manifestSource{
+			Name:   kustomization.Name,
+			Type:   SourceTypeKustomize,
+			Config: kustomization,
+			Render: func(ctx context.Context) (string, error) {
+				if kustomization.URL != "" {
+					return r.renderRemoteKustomization(ctx, kustomization)
+				}
+
+				return renderKustomization(kustomization)
+			},
+		})
+	}
+	for _, artifact := range vConfig.Experimental.Deploy.OCI {
+		artifact := artifact
+
+		// credentials are not part of the hash, as the hash is stored in the status
+		hashConfig := artifact
+		hashConfig.Username = ""
+		hashConfig.Password = ""
+		sources = append(sources, manifestSource{
+			Name:   artifact.Name,
+			Type:   SourceTypeOCI,
+			Config: hashConfig,
+			Render: func(ctx context.Context) (string, error) {
+				return r.pullOCIManifests(ctx, artifact)
+			},
+		})
+	}
+
+	return sources
+}
+
+// ProcessSources renders and applies the kustomizations and OCI artifacts. Sources are only applied again if
+// their configuration or rendered manifests have changed.
+func (r *Deployer) ProcessSources(ctx context.Context, vConfig *config.VirtualClusterConfig, configMap *corev1.ConfigMap) (_ bool, err error) {
+	ctx, span := tracing.StartSpan(ctx, "deploy ProcessSources")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	status := ParseStatus(configMap)
+	statusMap := map[string]SourceStatus{}
+	for _, sourceStatus := range status.Sources {
+		statusMap[sourceStatus.Type+"/"+sourceStatus.Name] = sourceStatus
+	}
+
+	for _, source := range r.getManifestSources(vConfig) {
+		lastStatus := statusMap[source.Type+"/"+source.Name]
+		delete(statusMap, source.Type+"/"+source.Name)
+
+		applied, err := r.processSource(ctx, configMap, source, lastStatus)
+		if err != nil {
+			return false, err
+		} else if applied {
+			// apply only one source in each reconcile
+			return true, nil
+		}
+	}
+
+	// prune sources that were removed
+	if len(statusMap) > 0 {
+		r.Log.Debugf("following sources left in status map, should be deleted: %v", statusMap)
+		for _, sourceStatus := range statusMap {
+			_, err := ApplyGivenInitManifests(ctx, r.VirtualManager.GetClient(), r.VirtualManager.GetAPIReader(), "", "", sourceStatus.Objects)
+			if err != nil {
+				_ = r.setSourceStatus(configMap, SourceStatus{Name: sourceStatus.Name, Type: sourceStatus.Type, Objects: sourceStatus.Objects}, StatusFailed, UninstallError, err.Error())
+				return false, fmt.Errorf("delete %s source %s: %w", sourceStatus.Type, sourceStatus.Name, err)
+			}
+
+			err = r.popSourceFromStatus(configMap, sourceStatus)
+			if err != nil {
+				return false, err
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func (r *Deployer) processSource(ctx context.Context, configMap *corev1.ConfigMap, source manifestSource, lastStatus SourceStatus) (_ bool, err error) {
+	ctx, span := tracing.StartSpan(ctx, "deploy ProcessSource", attribute.String("source.type", source.Type), attribute.String("source.name", source.Name))
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	newStatus := SourceStatus{
+		Name:                  source.Name,
+		Type:                  source.Type,
+		LastAppliedConfigHash: lastStatus.LastAppliedConfigHash,
+		Objects:               lastStatus.Objects,
+	}
+
+	// render the manifests
+	r.Log.Debugf("rendering %s source %s", source.Type, source.Name)
+	manifests, err := source.Render(ctx)
+	if err != nil {
+		reason := SourceRenderError
+		if source.Type == SourceTypeOCI {
+			reason = SourcePullError
+		}
+
+		_ = r.setSourceStatus(configMap, newStatus, StatusFailed, reason, err.Error())
+		return false, fmt.Errorf("render %s source %s: %w", source.Type, source.Name, err)
+	}
+
+	// check if upgrade is needed
+	hash, err := getHashedSource(source.Config, manifests)
+	if err != nil {
+		return false, err
+	} else if hash == lastStatus.LastAppliedConfigHash && lastStatus.Phase == string(StatusSuccess) {
+		return false, nil
+	}
+
+	// apply the manifests
+	r.Log.Infof("applying %s source %s", source.Type, source.Name)
+	objects, err := ApplyGivenInitManifests(ctx, r.VirtualManager.GetClient(), r.VirtualManager.GetAPIReader(), manifests, "", lastStatus.Objects)
+	if err != nil {
+		r.Log.Errorf("error applying %s source %s: %v", source.Type, source.Name, err)
+		if objects != nil {
+			newStatus.Objects = mergeObjectStatuses(lastStatus.Objects, objects)
+		}
+		if IsWaveNotReady(err) {
+			// apply the source again and keep waiting for the wave
+			_ = r.setSourceStatus(configMap, newStatus, StatusPending, WaveNotReady, err.Error())
+			return true, nil
+		}
+
+		_ = r.setSourceStatus(configMap, newStatus, StatusFailed, InstallError, err.Error())
+		return false, err
+	}
+
+	newStatus.LastAppliedConfigHash = hash
+	newStatus.Objects = objects
+	return true, r.setSourceStatus(configMap, newStatus, StatusSuccess, "", "")
+}
+
+func (r *Deployer) setSourceStatus(cm *corev1.ConfigMap, sourceStatus SourceStatus, phase InitObjectStatus, reason, message string) error {
+	status := ParseStatus(cm)
+	sourceStatus.Phase = string(phase)
+	sourceStatus.Reason = reason
+	sourceStatus.Message = message
+
+	found := false
+	for i, existing := range status.Sources {
+		if existing.Name == sourceStatus.Name && existing.Type == sourceStatus.Type {
+			status.Sources[i] = sourceStatus
+			found = true
+			break
+		}
+	}
+	if !found {
+		status.Sources = append(status.Sources, sourceStatus)
+	}
+
+	return r.encodeStatus(cm, status)
+}
+
+func (r *Deployer) popSourceFromStatus(cm *corev1.ConfigMap, sourceStatus SourceStatus) error {
+	status := ParseStatus(cm)
+	sources := []SourceStatus{}
+	for _, existing := range status.Sources {
+		if existing.Name == sourceStatus.Name && existing.Type == sourceStatus.Type {
+			continue
+		}
+
+		sources = append(sources, existing)
+	}
+
+	status.Sources = sources
+	return r.encodeStatus(cm, status)
+}
+
+// mergeObjectStatuses keeps the previously applied objects so that they can still be pruned after a failed apply
+func mergeObjectStatuses(lastObjects, objects []ObjectStatus) []ObjectStatus {
+	merged := append([]ObjectStatus{}, objects...)
+	for _, lastObject := range lastObjects {
+		found := false
+		for _, object := range objects {
+			if object.KObject() == lastObject.KObject() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, lastObject)
+		}
+	}
+
+	return merged
+}
+
+func getHashedSource(sourceConfig interface{}, manifests string) (string, error) {
+	rawData, err := json.Marshal(sourceConfig)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", md5.Sum(append(rawData, []byte(manifests)...))), nil
+}
+
+// renderKustomization renders the kustomization at the local path
+func renderKustomization(kustomization vclusterconfig.ExperimentalDeployKustomize) (string, error) {
+	kustomizer := krusty.MakeKustomizer(krusty.MakeDefaultOptions())
+	resources, err := kustomizer.Run(filesys.MakeFsOnDisk(), kustomization.Path)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := resources.AsYaml()
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// pullOCIManifests returns the manifests of the artifact. The artifact is only pulled again if the digest of the
+// reference has changed since the last pull.
+func (r *Deployer) pullOCIManifests(ctx context.Context, artifact vclusterconfig.ExperimentalDeployOCI) (string, error) {
+	options := oci.Options{
+		Username: artifact.Username,
+		Password: artifact.Password,
+		Insecure: artifact.Insecure,
+	}
+	digest, err := oci.Resolve(ctx, artifact.Reference, options)
+	if err != nil {
+		return "", err
+	}
+	if cached, ok := r.pulledArtifacts[artifact.Name]; ok && cached.Digest == digest {
+		return cached.Manifests, nil
+	}
+
+	manifests, err := pullOCIManifests(ctx, artifact.Reference, options)
+	if err != nil {
+		return "", err
+	}
+
+	if r.pulledArtifacts == nil {
+		r.pulledArtifacts = map[string]pulledArtifact{}
+	}
+	r.pulledArtifacts[artifact.Name] = pulledArtifact{Digest: digest, Manifests: manifests}
+	return manifests, nil
+}
+
+func pullOCIManifests(ctx context.Context, reference string, options oci.Options) (string, error) {
+	pulled, err := oci.Pull(ctx, reference, options)
+	if err != nil {
+		return "", err
+	}
+
+	manifests := []string{}
+	for _, layer := range pulled.Layers {
+		layerManifests, err := extractManifests(layer)
+		if err != nil {
+			return "", fmt.Errorf("extract layer %s: %w", layer.Digest, err)
+		}
+
+		manifests = append(manifests, layerManifests...)
+	}
+	if len(manifests) == 0 {
+		return "", fmt.Errorf("artifact %s does not contain any manifests", reference)
+	}
+
+	return strings.Join(manifests, "\n---\n"), nil
+}
+
+// extractManifests returns the yaml files within the layer. Layers can either be plain yaml files or (gzipped) tar archives.
+func extractManifests(layer oci.Layer) ([]string, error) {
+	data := layer.Data
+	if strings.HasSuffix(layer.MediaType, "gzip") || bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		gzipReader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer gzipReader.Close()
+
+		data, err = io.ReadAll(io.LimitReader(gzipReader, oci.MaxLayerSize))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !strings.Contains(layer.MediaType, "tar") && !isTar(data) {
+		return []string{string(data)}, nil
+	}
+
+	files := map[string]string{}
+	tarReader := tar.NewReader(bytes.NewReader(data))
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		extension := path.Ext(header.Name)
+		if header.Typeflag != tar.TypeReg || (extension != ".yaml" && extension != ".yml" && extension != ".json") {
+			continue
+		}
+
+		content, err := io.ReadAll(io.LimitReader(tarReader, oci.MaxLayerSize))
+		if err != nil {
+			return nil, err
+		}
+		files[header.Name] = string(content)
+	}
+
+	// sort files by name to have a stable order
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	manifests := make([]string, 0, len(names))
+	for _, name := range names {
+		manifests = append(manifests, files[name])
+	}
+
+	return manifests, nil
+}
+
+func isTar(data []byte) bool {
+	return len(data) > 262 && string(data[257:262]) == "ustar"
+}
@@ -0,0 +1,129 @@
+package deploy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	"github.com/go-git/go-git/v5/plumbing/transport/server"
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/config"
+	"gotest.tools/v3/assert"
+)
+
+func TestRenderKustomization(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "kustomization.yaml"), []byte(`namespace: test
+resources:
+- configmap.yaml`), 0644)
+	assert.NilError(t, err)
+	err = os.WriteFile(filepath.Join(dir, "configmap.yaml"), []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test`), 0644)
+	assert.NilError(t, err)
+
+	manifests, err := renderKustomization(vclusterconfig.ExperimentalDeployKustomize{Name: "test", Path: dir})
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(manifests, "namespace: test"), manifests)
+
+	objs, err := ManifestStringToUnstructuredArray(manifests, "default")
+	assert.NilError(t, err)
+	assert.Equal(t, len(objs), 1)
+	assert.Equal(t, objs[0].GetNamespace(), "test")
+}
+
+func TestOCISources(t *testing.T) {
+	reference := "ghcr.io/my-org/bundle@sha256:0000000000000000000000000000000000000000000000000000000000000000"
+	newConfig := func(password string) *config.VirtualClusterConfig {
+		vConfig := &config.VirtualClusterConfig{}
+		vConfig.Experimental.Deploy.OCI = []vclusterconfig.ExperimentalDeployOCI{{Name: "bundle", Reference: reference, Username: "user", Password: password}}
+		return vConfig
+	}
+
+	// credentials don't end up in the hash stored in the status
+	deployer := &Deployer{pulledArtifacts: map[string]pulledArtifact{
+		"bundle": {Digest: strings.TrimPrefix(reference, "ghcr.io/my-org/bundle@"), Manifests: "kind: ConfigMap"},
+	}}
+	hashes := []string{}
+	for _, password := range []string{"first", "second"} {
+		sources := deployer.getManifestSources(newConfig(password))
+		assert.Equal(t, len(sources), 1)
+
+		// the artifact is served from the cache as the digest didn't change
+		manifests, err := sources[0].Render(context.TODO())
+		assert.NilError(t, err)
+		assert.Equal(t, manifests, "kind: ConfigMap")
+
+		hash, err := getHashedSource(sources[0].Config, manifests)
+		assert.NilError(t, err)
+		hashes = append(hashes, hash)
+	}
+	assert.Equal(t, hashes[0], hashes[1])
+}
+
+func TestParseRemoteKustomization(t *testing.T) {
+	remote, err := parseRemoteKustomization("https://github.com/my-org/my-repo//overlays/prod?ref=v1.0.0")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, *remote, remoteKustomization{Repository: "https://github.com/my-org/my-repo", Path: "overlays/prod", Ref: "v1.0.0"})
+
+	remote, err = parseRemoteKustomization("https://github.com/my-org/my-repo//../../etc")
+	assert.NilError(t, err)
+	assert.Equal(t, remote.Path, "etc")
+
+	_, err = parseRemoteKustomization("github.com/my-org/my-repo")
+	assert.ErrorContains(t, err, "invalid kustomization url")
+}
+
+func TestRemoteKustomization(t *testing.T) {
+	// serve file urls in-process, so the test doesn't depend on the git binary
+	client.InstallProtocol("file", server.DefaultServer)
+	defer client.InstallProtocol("file", nil)
+
+	dir := t.TempDir()
+	repository, err := git.PlainInit(dir, false)
+	assert.NilError(t, err)
+	worktree, err := repository.Worktree()
+	assert.NilError(t, err)
+	commit := func(name string) plumbing.Hash {
+		assert.NilError(t, os.MkdirAll(filepath.Join(dir, "overlay"), 0755))
+		assert.NilError(t, os.WriteFile(filepath.Join(dir, "overlay", "kustomization.yaml"), []byte("resources:\n- configmap.yaml\n"), 0644))
+		assert.NilError(t, os.WriteFile(filepath.Join(dir, "overlay", "configmap.yaml"), []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: "+name+"\n"), 0644))
+		_, err := worktree.Add("overlay")
+		assert.NilError(t, err)
+		hash, err := worktree.Commit(name, &git.CommitOptions{Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}})
+		assert.NilError(t, err)
+		return hash
+	}
+	first := commit("first")
+	_, err = repository.CreateTag("v1", first, nil)
+	assert.NilError(t, err)
+	commit("second")
+
+	deployer := &Deployer{}
+	render := func(url string) string {
+		manifests, err := deployer.renderRemoteKustomization(context.TODO(), vclusterconfig.ExperimentalDeployKustomize{Name: "test", URL: url})
+		assert.NilError(t, err)
+		return manifests
+	}
+
+	// tags, commits and the default branch are resolved
+	url := "file://" + filepath.Join(dir, ".git") + "//overlay"
+	assert.Assert(t, strings.Contains(render(url+"?ref=v1"), "name: first"))
+	assert.Assert(t, strings.Contains(render(url+"?ref="+first.String()), "name: first"))
+	assert.Assert(t, strings.Contains(render(url), "name: second"))
+
+	// the rendered manifests are cached by revision
+	cached := deployer.renderedKustomizations["test"]
+	deployer.renderedKustomizations["test"] = pulledArtifact{Digest: cached.Digest, Manifests: "kind: ConfigMap"}
+	assert.Equal(t, render(url), "kind: ConfigMap")
+	commit("third")
+	assert.Assert(t, strings.Contains(render(url), "name: third"))
+}
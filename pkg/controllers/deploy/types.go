@@ -7,6 +7,7 @@ type Status struct {
 
 	Charts    []ChartStatus   `json:"charts,omitempty"`
 	Manifests ManifestsStatus `json:"manifests,omitempty"`
+	Sources   []SourceStatus  `json:"sources,omitempty"`
 }
 
 type ManifestsStatus struct {
@@ -14,6 +15,39 @@ type ManifestsStatus struct {
 	Reason               string `json:"reason,omitempty"`
 	Message              string `json:"message,omitempty"`
 	LastAppliedManifests string `json:"lastAppliedManifests,omitempty"`
+
+	Objects []ObjectStatus `json:"objects,omitempty"`
+}
+
+type ObjectStatus struct {
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Wave       int    `json:"wave,omitempty"`
+	Phase      string `json:"phase,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+func (o ObjectStatus) KObject() KObject {
+	return KObject{
+		APIVersion: o.APIVersion,
+		Kind:       o.Kind,
+		Namespace:  o.Namespace,
+		Name:       o.Name,
+	}
+}
+
+type SourceStatus struct {
+	Name                  string `json:"name,omitempty"`
+	Type                  string `json:"type,omitempty"`
+	Phase                 string `json:"phase,omitempty"`
+	Reason                string `json:"reason,omitempty"`
+	Message               string `json:"message,omitempty"`
+	LastAppliedConfigHash string `json:"lastAppliedConfigHash,omitempty"`
+
+	Objects []ObjectStatus `json:"objects,omitempty"`
 }
 
 type ChartStatus struct {
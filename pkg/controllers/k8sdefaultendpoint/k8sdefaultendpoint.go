@@ -145,20 +145,8 @@ func (e *EndpointController) syncKubernetesServiceEndpoints(ctx context.Context,
 				newPorts = append(newPorts, p)
 			}
 
-			newAddresses := []corev1.EndpointAddress{}
-			for _, address := range subset.Addresses {
-				address.Hostname = ""
-				address.NodeName = nil
-				address.TargetRef = nil
-				newAddresses = append(newAddresses, address)
-			}
-			newNotReadyAddresses := []corev1.EndpointAddress{}
-			for _, address := range subset.NotReadyAddresses {
-				address.Hostname = ""
-				address.NodeName = nil
-				address.TargetRef = nil
-				newNotReadyAddresses = append(newNotReadyAddresses, address)
-			}
+			newAddresses := e.translateAddresses(ctx, localClient, subset.Addresses)
+			newNotReadyAddresses := e.translateAddresses(ctx, localClient, subset.NotReadyAddresses)
 
 			newSubsets = append(newSubsets, corev1.EndpointSubset{
 				Addresses:         newAddresses,
@@ -181,18 +169,31 @@ func (e *EndpointController) syncKubernetesServiceEndpoints(ctx context.Context,
 	return err
 }
 
-// allAddressesIPv6 returns true if all provided addresses are IPv6.
-// From: https://github.com/kubernetes/kubernetes/blob/7380fc735aca591325ae1fabf8dab194b40367de/pkg/controlplane/reconcilers/endpointsadapter.go#L183-L196
-func allAddressesIPv6(addresses []corev1.EndpointAddress) bool {
-	if len(addresses) == 0 {
-		return false
-	}
-
+// translateAddresses strips host specific fields from the addresses. The host endpoints only contain addresses of the
+// primary ip family, so for dual-stack control plane pods the addresses of the secondary family are added as well.
+func (e *EndpointController) translateAddresses(ctx context.Context, localClient client.Client, addresses []corev1.EndpointAddress) []corev1.EndpointAddress {
+	newAddresses := []corev1.EndpointAddress{}
+	secondaryAddresses := []corev1.EndpointAddress{}
 	for _, address := range addresses {
-		if !utilnet.IsIPv6String(address.IP) {
-			return false
+		if address.TargetRef != nil && address.TargetRef.Kind == "Pod" {
+			pod := &corev1.Pod{}
+			err := localClient.Get(ctx, types.NamespacedName{Namespace: address.TargetRef.Namespace, Name: address.TargetRef.Name}, pod)
+			if err != nil {
+				e.Log.Debugf("error retrieving control plane pod %s/%s: %v", address.TargetRef.Namespace, address.TargetRef.Name, err)
+			} else {
+				for _, podIP := range pod.Status.PodIPs {
+					if podIP.IP != address.IP && utilnet.IsIPv6String(podIP.IP) != utilnet.IsIPv6String(address.IP) {
+						secondaryAddresses = append(secondaryAddresses, corev1.EndpointAddress{IP: podIP.IP})
+					}
+				}
+			}
 		}
+
+		address.Hostname = ""
+		address.NodeName = nil
+		address.TargetRef = nil
+		newAddresses = append(newAddresses, address)
 	}
 
-	return true
+	return append(newAddresses, secondaryAddresses...)
 }
@@ -2,9 +2,11 @@ package k8sdefaultendpoint
 
 import (
 	"context"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	discoveryv1 "k8s.io/api/discovery/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilnet "k8s.io/utils/net"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -17,34 +19,69 @@ func (p *v1Provider) createClientObject() client.Object {
 	return &discoveryv1.EndpointSlice{}
 }
 func (p *v1Provider) createOrPatch(ctx context.Context, virtualClient client.Client, vEndpoints *corev1.Endpoints) error {
-	vSlices := &discoveryv1.EndpointSlice{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: "default",
-			Name:      "kubernetes",
-		},
+	newSlices := p.endpointSlicesFromEndpoints(vEndpoints)
+	for _, newSlice := range newSlices {
+		vSlices := &discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      newSlice.Name,
+			},
+		}
+		_, err := controllerutil.CreateOrPatch(ctx, virtualClient, vSlices, func() error {
+			vSlices.Labels = newSlice.Labels
+			vSlices.AddressType = newSlice.AddressType
+			vSlices.Endpoints = newSlice.Endpoints
+			vSlices.Ports = newSlice.Ports
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// remove the secondary slice if the control plane is not dual-stack anymore
+	if len(newSlices) < 2 {
+		for _, addressType := range []discoveryv1.AddressType{discoveryv1.AddressTypeIPv4, discoveryv1.AddressTypeIPv6} {
+			err := virtualClient.Delete(ctx, &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      secondarySliceName(vEndpoints.Name, addressType),
+				},
+			})
+			if err != nil && !kerrors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// endpointSlicesFromEndpoints generates an EndpointSlice for each ip family of the Endpoints resource. The slice of
+// the primary family uses the name of the endpoints, while the slice of the secondary family is suffixed with it.
+// Based on: https://github.com/kubernetes/kubernetes/blob/7380fc735aca591325ae1fabf8dab194b40367de/pkg/controlplane/reconcilers/endpointsadapter.go#L121-L151
+func (p *v1Provider) endpointSlicesFromEndpoints(endpoints *corev1.Endpoints) []*discoveryv1.EndpointSlice {
+	primary := p.endpointSliceFromEndpoints(endpoints, primaryAddressType(endpoints))
+	slices := []*discoveryv1.EndpointSlice{primary}
+
+	secondaryType := discoveryv1.AddressTypeIPv6
+	if primary.AddressType == discoveryv1.AddressTypeIPv6 {
+		secondaryType = discoveryv1.AddressTypeIPv4
+	}
+	secondary := p.endpointSliceFromEndpoints(endpoints, secondaryType)
+	if len(secondary.Endpoints) > 0 {
+		secondary.Name = secondarySliceName(endpoints.Name, secondaryType)
+		slices = append(slices, secondary)
 	}
-	_, err := controllerutil.CreateOrPatch(ctx, virtualClient, vSlices, func() error {
-		newSlice := p.endpointSliceFromEndpoints(vEndpoints)
-		vSlices.Labels = newSlice.Labels
-		vSlices.AddressType = newSlice.AddressType
-		vSlices.Endpoints = newSlice.Endpoints
-		vSlices.Ports = newSlice.Ports
-		return nil
-	})
-	return err
+
+	return slices
 }
 
-// endpointSliceFromEndpoints generates an EndpointSlice from an Endpoints
-// resource.
-// From: https://github.com/kubernetes/kubernetes/blob/7380fc735aca591325ae1fabf8dab194b40367de/pkg/controlplane/reconcilers/endpointsadapter.go#L121-L151
-func (p *v1Provider) endpointSliceFromEndpoints(endpoints *corev1.Endpoints) *discoveryv1.EndpointSlice {
+func (p *v1Provider) endpointSliceFromEndpoints(endpoints *corev1.Endpoints, addressType discoveryv1.AddressType) *discoveryv1.EndpointSlice {
 	endpointSlice := &discoveryv1.EndpointSlice{}
 	endpointSlice.Name = endpoints.Name
 	endpointSlice.Labels = map[string]string{discoveryv1.LabelServiceName: endpoints.Name}
-
-	// TODO: Add support for dual stack here (and in the rest of
-	// EndpointsAdapter).
-	endpointSlice.AddressType = discoveryv1.AddressTypeIPv4
+	endpointSlice.AddressType = addressType
 	if len(endpoints.Subsets) > 0 {
 		subset := endpoints.Subsets[0]
 		for i := range subset.Ports {
@@ -54,9 +91,6 @@ func (p *v1Provider) endpointSliceFromEndpoints(endpoints *corev1.Endpoints) *di
 				Protocol: &subset.Ports[i].Protocol,
 			})
 		}
-		if allAddressesIPv6(append(subset.Addresses, subset.NotReadyAddresses...)) {
-			endpointSlice.AddressType = discoveryv1.AddressTypeIPv6
-		}
 		endpointSlice.Endpoints = append(endpointSlice.Endpoints, p.getEndpointsFromAddresses(subset.Addresses, endpointSlice.AddressType, true)...)
 		endpointSlice.Endpoints = append(endpointSlice.Endpoints, p.getEndpointsFromAddresses(subset.NotReadyAddresses, endpointSlice.AddressType, false)...)
 	}
@@ -64,6 +98,23 @@ func (p *v1Provider) endpointSliceFromEndpoints(endpoints *corev1.Endpoints) *di
 	return endpointSlice
 }
 
+// primaryAddressType returns the address type of the first address, as the host endpoints controller only
+// publishes addresses of the primary ip family of the service.
+func primaryAddressType(endpoints *corev1.Endpoints) discoveryv1.AddressType {
+	if len(endpoints.Subsets) > 0 {
+		subset := endpoints.Subsets[0]
+		if len(subset.Addresses) > 0 && utilnet.IsIPv6String(subset.Addresses[0].IP) || len(subset.Addresses) == 0 && len(subset.NotReadyAddresses) > 0 && utilnet.IsIPv6String(subset.NotReadyAddresses[0].IP) {
+			return discoveryv1.AddressTypeIPv6
+		}
+	}
+
+	return discoveryv1.AddressTypeIPv4
+}
+
+func secondarySliceName(name string, addressType discoveryv1.AddressType) string {
+	return name + "-" + strings.ToLower(string(addressType))
+}
+
 // getEndpointsFromAddresses returns a list of Endpoints from addresses that
 // match the provided address type.
 // From: https://github.com/kubernetes/kubernetes/blob/7380fc735aca591325ae1fabf8dab194b40367de/pkg/controlplane/reconcilers/endpointsadapter.go#L153-L166
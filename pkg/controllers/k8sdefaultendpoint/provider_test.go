@@ -7,6 +7,7 @@ import (
 	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
 	"gotest.tools/assert"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -47,3 +48,34 @@ func TestCreateOrPatch(t *testing.T) {
 	err = pbeta.createOrPatch(ctx, virtualClient, endpoints)
 	assert.NilError(t, err, "")
 }
+
+func TestEndpointSlicesFromEndpointsDualStack(t *testing.T) {
+	endpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: "kubernetes", Namespace: "default"},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{{IP: "fd00::5"}, {IP: "10.0.0.5"}},
+			Ports:     []corev1.EndpointPort{{Name: "https", Port: 8443, Protocol: corev1.ProtocolTCP}},
+		}},
+	}
+
+	p := &v1Provider{}
+	slices := p.endpointSlicesFromEndpoints(endpoints)
+	assert.Equal(t, len(slices), 2)
+	assert.Equal(t, slices[0].Name, "kubernetes")
+	assert.Equal(t, slices[0].AddressType, discoveryv1.AddressTypeIPv6)
+	assert.DeepEqual(t, slices[0].Endpoints[0].Addresses, []string{"fd00::5"})
+	assert.Equal(t, slices[1].Name, "kubernetes-ipv4")
+	assert.Equal(t, slices[1].AddressType, discoveryv1.AddressTypeIPv4)
+	assert.DeepEqual(t, slices[1].Endpoints[0].Addresses, []string{"10.0.0.5"})
+
+	// single stack removes the secondary slice again
+	ctx := context.Background()
+	virtualClient := testingutil.NewFakeClient(testingutil.NewScheme())
+	assert.NilError(t, p.createOrPatch(ctx, virtualClient, endpoints))
+	endpoints.Subsets[0].Addresses = endpoints.Subsets[0].Addresses[:1]
+	assert.NilError(t, p.createOrPatch(ctx, virtualClient, endpoints))
+	sliceList := &discoveryv1.EndpointSliceList{}
+	assert.NilError(t, virtualClient.List(ctx, sliceList))
+	assert.Equal(t, len(sliceList.Items), 1)
+	assert.Equal(t, sliceList.Items[0].Name, "kubernetes")
+}
@@ -42,10 +42,8 @@ func (p *v1BetaProvider) endpointSliceFromEndpoints(endpoints *corev1.Endpoints)
 	endpointSlice.Name = endpoints.Name
 	endpointSlice.Labels = map[string]string{discoveryv1beta1.LabelServiceName: endpoints.Name}
 
-	// TODO: Add support for dual stack here (and in the rest of
-	// EndpointsAdapter).
-	endpointSlice.AddressType = discoveryv1beta1.AddressTypeIPv4
-
+	// the legacy api only gets the primary ip family of dual-stack control planes
+	endpointSlice.AddressType = discoveryv1beta1.AddressType(primaryAddressType(endpoints))
 	if len(endpoints.Subsets) > 0 {
 		subset := endpoints.Subsets[0]
 		for i := range subset.Ports {
@@ -56,10 +54,6 @@ func (p *v1BetaProvider) endpointSliceFromEndpoints(endpoints *corev1.Endpoints)
 			})
 		}
 
-		if allAddressesIPv6(append(subset.Addresses, subset.NotReadyAddresses...)) {
-			endpointSlice.AddressType = discoveryv1beta1.AddressTypeIPv6
-		}
-
 		endpointSlice.Endpoints = append(endpointSlice.Endpoints, p.getEndpointsFromAddresses(subset.Addresses, endpointSlice.AddressType, true)...)
 		endpointSlice.Endpoints = append(endpointSlice.Endpoints, p.getEndpointsFromAddresses(subset.NotReadyAddresses, endpointSlice.AddressType, false)...)
 	}
@@ -14,6 +14,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/csinodes"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/csistoragecapacities"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/endpoints"
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/endpointslices"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/events"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/ingressclasses"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/ingresses"
@@ -60,6 +61,7 @@ func getSyncers(ctx *config.ControllerContext) []initFunction {
 		isEnabled(ctx.Config.Sync.ToHost.ConfigMaps.Enabled, configmaps.New),
 		isEnabled(ctx.Config.Sync.ToHost.Secrets.Enabled, secrets.New),
 		isEnabled(ctx.Config.Sync.ToHost.Endpoints.Enabled, endpoints.New),
+		isEnabled(ctx.Config.Sync.ToHost.EndpointSlices.Enabled, endpointslices.New),
 		isEnabled(ctx.Config.Sync.ToHost.Pods.Enabled, pods.New),
 		isEnabled(ctx.Config.Sync.FromHost.Events.Enabled, events.New),
 		isEnabled(ctx.Config.Sync.ToHost.PersistentVolumeClaims.Enabled, persistentvolumeclaims.New),
@@ -151,8 +153,17 @@ func RegisterIndices(ctx *config.ControllerContext, syncers []syncertypes.Object
 }
 
 func RegisterControllers(ctx *config.ControllerContext, syncers []syncertypes.Object) error {
-	registerContext := util.ToRegisterContext(ctx)
+	err := RegisterDefaultControllers(ctx)
+	if err != nil {
+		return err
+	}
 
+	return RegisterSyncers(ctx, syncers)
+}
+
+// RegisterDefaultControllers registers all controllers that are not resource syncers. These controllers always run on
+// the leader only.
+func RegisterDefaultControllers(ctx *config.ControllerContext) error {
 	err := k8sdefaultendpoint.Register(ctx)
 	if err != nil {
 		return err
@@ -166,7 +177,7 @@ func RegisterControllers(ctx *config.ControllerContext, syncers []syncertypes.Ob
 		}
 	}
 
-	// register controller that keeps CoreDNS NodeHosts config up to date
+	// register controller that keeps CoreDNS NodeHosts config and headless service records up to date
 	err = RegisterCoreDNSController(ctx)
 	if err != nil {
 		return err
@@ -184,17 +195,18 @@ func RegisterControllers(ctx *config.ControllerContext, syncers []syncertypes.Ob
 		return err
 	}
 
-	err = RegisterGenericSyncController(ctx)
-	if err != nil {
-		return err
-	}
+	return RegisterGenericSyncController(ctx)
+}
 
-	// register controllers for resource synchronization
+// RegisterSyncers registers the controllers for resource synchronization. If sharding is enabled, the controllers only
+// sync the objects of the shards owned by this replica.
+func RegisterSyncers(ctx *config.ControllerContext, syncers []syncertypes.Object) error {
+	registerContext := util.ToRegisterContext(ctx)
 	for _, v := range syncers {
 		// fake syncer?
 		fakeSyncer, ok := v.(syncertypes.FakeSyncer)
 		if ok {
-			err = syncer.RegisterFakeSyncer(registerContext, fakeSyncer)
+			err := syncer.RegisterFakeSyncer(registerContext, fakeSyncer)
 			if err != nil {
 				return errors.Wrapf(err, "start %s syncer", v.Name())
 			}
@@ -202,7 +214,7 @@ func RegisterControllers(ctx *config.ControllerContext, syncers []syncertypes.Ob
 			// real syncer?
 			realSyncer, ok := v.(syncertypes.Syncer)
 			if ok {
-				err = syncer.RegisterSyncer(registerContext, realSyncer)
+				err := syncer.RegisterSyncer(registerContext, realSyncer)
 				if err != nil {
 					return errors.Wrapf(err, "start %s syncer", v.Name())
 				}
@@ -362,6 +374,21 @@ func RegisterCoreDNSController(ctx *config.ControllerContext) error {
 	if err != nil {
 		return fmt.Errorf("unable to setup CoreDNS NodeHosts controller: %w", err)
 	}
+
+	// the embedded CoreDNS doesn't mount the zones of headless services
+	if !ctx.Config.ControlPlane.CoreDNS.Enabled || ctx.Config.ControlPlane.CoreDNS.Embedded {
+		return nil
+	}
+
+	zonesController := &coredns.HeadlessZonesReconciler{
+		Client:        ctx.VirtualManager.GetClient(),
+		Log:           loghelper.New("corednsheadlesszones-controller"),
+		ClusterDomain: ctx.Config.Networking.Advanced.ClusterDomain,
+	}
+	err = zonesController.SetupWithManager(ctx.VirtualManager)
+	if err != nil {
+		return fmt.Errorf("unable to setup CoreDNS headless zones controller: %w", err)
+	}
 	return nil
 }
 
@@ -17,11 +17,16 @@ import (
 func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
 	return &endpointsSyncer{
 		NamespacedTranslator: translator.NewNamespacedTranslator(ctx, "endpoints", &corev1.Endpoints{}),
+
+		skipMirror: ctx.Config.Sync.ToHost.EndpointSlices.Enabled,
 	}, nil
 }
 
 type endpointsSyncer struct {
 	translator.NamespacedTranslator
+
+	// skipMirror disables endpoint slice mirroring in the host cluster, because the endpoint slices are synced directly
+	skipMirror bool
 }
 
 func (s *endpointsSyncer) SyncToHost(ctx *synccontext.SyncContext, vObj client.Object) (ctrl.Result, error) {
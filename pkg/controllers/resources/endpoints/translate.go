@@ -6,6 +6,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -19,10 +20,25 @@ func (s *endpointsSyncer) translate(ctx context.Context, vObj client.Object) *co
 	if endpoints.Annotations != nil {
 		delete(endpoints.Annotations, "control-plane.alpha.kubernetes.io/leader")
 	}
+	endpoints.Labels = s.translateLabels(endpoints.Labels)
 
 	return endpoints
 }
 
+func (s *endpointsSyncer) translateLabels(labels map[string]string) map[string]string {
+	if !s.skipMirror {
+		return labels
+	}
+
+	// the endpoint slices are synced by the endpoint slice syncer, so make sure the host
+	// cluster doesn't create additional endpoint slices from the synced endpoints
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[discoveryv1.LabelSkipMirror] = "true"
+	return labels
+}
+
 func (s *endpointsSyncer) translateSpec(endpoints *corev1.Endpoints) {
 	// translate the addresses
 	for i, subset := range endpoints.Subsets {
@@ -63,6 +79,7 @@ func (s *endpointsSyncer) translateUpdate(ctx context.Context, pObj, vObj *corev
 	// check annotations & labels
 	_, annotations, labels := s.TranslateMetadataUpdate(ctx, vObj, pObj)
 	delete(annotations, "control-plane.alpha.kubernetes.io/leader")
+	labels = s.translateLabels(labels)
 	if !equality.Semantic.DeepEqual(annotations, pObj.Annotations) || !equality.Semantic.DeepEqual(labels, pObj.Labels) {
 		updated = translator.NewIfNil(updated, pObj)
 		updated.Annotations = annotations
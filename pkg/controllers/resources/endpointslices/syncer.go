@@ -0,0 +1,110 @@
+package endpointslices
+
+import (
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"github.com/loft-sh/vcluster/pkg/specialservices"
+	syncer "github.com/loft-sh/vcluster/pkg/types"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ManagedBy is the value of the endpointslice.kubernetes.io/managed-by label of synced endpoint slices. It makes
+// sure the endpoint slice and mirroring controllers of the host cluster leave the synced endpoint slices alone.
+const ManagedBy = "vcluster.loft.sh"
+
+func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
+	return &endpointSliceSyncer{
+		NamespacedTranslator: translator.NewNamespacedTranslator(ctx, "endpointslice", &discoveryv1.EndpointSlice{}),
+	}, nil
+}
+
+type endpointSliceSyncer struct {
+	translator.NamespacedTranslator
+}
+
+func (s *endpointSliceSyncer) SyncToHost(ctx *synccontext.SyncContext, vObj client.Object) (ctrl.Result, error) {
+	return s.SyncToHostCreate(ctx, vObj, s.translate(ctx.Context, vObj.(*discoveryv1.EndpointSlice)))
+}
+
+func (s *endpointSliceSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj client.Object) (ctrl.Result, error) {
+	newEndpointSlice := s.translateUpdate(ctx.Context, pObj.(*discoveryv1.EndpointSlice), vObj.(*discoveryv1.EndpointSlice))
+	if newEndpointSlice != nil {
+		translator.PrintChanges(pObj, newEndpointSlice, ctx.Log)
+	}
+
+	return s.SyncToHostUpdate(ctx, vObj, newEndpointSlice)
+}
+
+var _ syncer.Starter = &endpointSliceSyncer{}
+
+// ReconcileStart only lets endpoint slices of selector-less services through, because the host cluster maintains
+// the endpoint slices of services with a selector itself. This mirrors the behaviour of the endpoints syncer.
+func (s *endpointSliceSyncer) ReconcileStart(ctx *synccontext.SyncContext, req ctrl.Request) (bool, error) {
+	vEndpointSlice := &discoveryv1.EndpointSlice{}
+	err := ctx.VirtualClient.Get(ctx.Context, req.NamespacedName, vEndpointSlice)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			// let the syncer delete the host endpoint slice
+			return false, nil
+		}
+
+		return true, err
+	}
+
+	serviceName := vEndpointSlice.Labels[discoveryv1.LabelServiceName]
+	if serviceName == "" {
+		return s.deleteHostEndpointSlice(ctx, req)
+	}
+
+	serviceKey := types.NamespacedName{Namespace: req.Namespace, Name: serviceName}
+	if serviceKey == specialservices.DefaultKubernetesSvcKey {
+		return true, nil
+	} else if _, ok := specialservices.Default.SpecialServicesToSync()[serviceKey]; ok {
+		return true, nil
+	}
+
+	svc := &corev1.Service{}
+	err = ctx.VirtualClient.Get(ctx.Context, serviceKey, svc)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return s.deleteHostEndpointSlice(ctx, req)
+		}
+
+		return true, err
+	} else if svc.Spec.Selector != nil {
+		return s.deleteHostEndpointSlice(ctx, req)
+	}
+
+	return false, nil
+}
+
+// deleteHostEndpointSlice removes a previously synced endpoint slice that shouldn't be managed by vCluster anymore
+func (s *endpointSliceSyncer) deleteHostEndpointSlice(ctx *synccontext.SyncContext, req ctrl.Request) (bool, error) {
+	pEndpointSlice := &discoveryv1.EndpointSlice{}
+	err := ctx.PhysicalClient.Get(ctx.Context, s.VirtualToHost(ctx.Context, req.NamespacedName, nil), pEndpointSlice)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			ctx.Log.Infof("Error retrieving endpoint slice: %v", err)
+		}
+
+		return true, nil
+	} else if pEndpointSlice.Annotations[translate.NameAnnotation] == "" {
+		return true, nil
+	}
+
+	ctx.Log.Infof("Delete endpoint slice %s/%s in physical cluster because it shouldn't be managed by vCluster anymore", pEndpointSlice.Namespace, pEndpointSlice.Name)
+	err = ctx.PhysicalClient.Delete(ctx.Context, pEndpointSlice)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return true, err
+	}
+
+	return true, nil
+}
+
+func (s *endpointSliceSyncer) ReconcileEnd() {}
@@ -0,0 +1,173 @@
+package endpointslices
+
+import (
+	"testing"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
+	"github.com/loft-sh/vcluster/pkg/specialservices"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestSync(t *testing.T) {
+	specialservices.Default = specialservices.NewDefaultServiceSyncer()
+
+	vService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-service",
+			Namespace: "test",
+		},
+	}
+	vSelectorService := &corev1.Service{
+		ObjectMeta: vService.ObjectMeta,
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{
+				"app": "test",
+			},
+		},
+	}
+	vEndpointSlice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-service-ipv6",
+			Namespace: "test",
+			Labels: map[string]string{
+				discoveryv1.LabelServiceName: vService.Name,
+			},
+		},
+		AddressType: discoveryv1.AddressTypeIPv6,
+		Endpoints: []discoveryv1.Endpoint{
+			{
+				Addresses: []string{"fd00::1"},
+				TargetRef: &corev1.ObjectReference{
+					Kind:      "Pod",
+					Name:      "test-pod",
+					Namespace: "test",
+					UID:       "123",
+				},
+			},
+		},
+		Ports: []discoveryv1.EndpointPort{
+			{
+				Name: ptr.To("http"),
+				Port: ptr.To(int32(80)),
+			},
+		},
+	}
+	vUpdatedEndpointSlice := vEndpointSlice.DeepCopy()
+	vUpdatedEndpointSlice.Endpoints[0].Addresses = []string{"fd00::2"}
+
+	pEndpointSlice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      translate.Default.PhysicalName(vEndpointSlice.Name, vEndpointSlice.Namespace),
+			Namespace: "test",
+			Annotations: map[string]string{
+				translate.NameAnnotation:      vEndpointSlice.Name,
+				translate.NamespaceAnnotation: vEndpointSlice.Namespace,
+				translate.UIDAnnotation:       "",
+			},
+			Labels: map[string]string{
+				translate.NamespaceLabel: vEndpointSlice.Namespace,
+				translate.Default.ConvertLabelKey(discoveryv1.LabelServiceName): vService.Name,
+				discoveryv1.LabelServiceName:                                    translate.Default.PhysicalName(vService.Name, vService.Namespace),
+				discoveryv1.LabelManagedBy:                                      ManagedBy,
+			},
+		},
+		AddressType: discoveryv1.AddressTypeIPv6,
+		Endpoints: []discoveryv1.Endpoint{
+			{
+				Addresses: []string{"fd00::1"},
+				TargetRef: &corev1.ObjectReference{
+					Kind:      "Pod",
+					Name:      translate.Default.PhysicalName("test-pod", "test"),
+					Namespace: "test",
+				},
+			},
+		},
+		Ports: vEndpointSlice.Ports,
+	}
+	pUpdatedEndpointSlice := pEndpointSlice.DeepCopy()
+	pUpdatedEndpointSlice.Endpoints[0].Addresses = []string{"fd00::2"}
+
+	request := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: vEndpointSlice.Namespace,
+			Name:      vEndpointSlice.Name,
+		},
+	}
+
+	generictesting.RunTests(t, []*generictesting.SyncTest{
+		{
+			Name: "Forward create",
+			InitialVirtualState: []runtime.Object{
+				vService.DeepCopy(),
+				vEndpointSlice.DeepCopy(),
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				discoveryv1.SchemeGroupVersion.WithKind("EndpointSlice"): {
+					pEndpointSlice,
+				},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				skip, err := syncer.(*endpointSliceSyncer).ReconcileStart(syncCtx, request)
+				assert.NilError(t, err)
+				assert.Equal(t, skip, false)
+
+				_, err = syncer.(*endpointSliceSyncer).SyncToHost(syncCtx, vEndpointSlice.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name: "Forward update",
+			InitialVirtualState: []runtime.Object{
+				vService.DeepCopy(),
+				vUpdatedEndpointSlice.DeepCopy(),
+			},
+			InitialPhysicalState: []runtime.Object{
+				pEndpointSlice.DeepCopy(),
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				discoveryv1.SchemeGroupVersion.WithKind("EndpointSlice"): {
+					pUpdatedEndpointSlice,
+				},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				pObj := &discoveryv1.EndpointSlice{}
+				err := syncCtx.PhysicalClient.Get(syncCtx.Context, types.NamespacedName{Namespace: pEndpointSlice.Namespace, Name: pEndpointSlice.Name}, pObj)
+				assert.NilError(t, err)
+
+				_, err = syncer.(*endpointSliceSyncer).Sync(syncCtx, pObj, vUpdatedEndpointSlice.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name: "Delete endpoint slice of service with selector",
+			InitialVirtualState: []runtime.Object{
+				vSelectorService.DeepCopy(),
+				vEndpointSlice.DeepCopy(),
+			},
+			InitialPhysicalState: []runtime.Object{
+				pEndpointSlice.DeepCopy(),
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				discoveryv1.SchemeGroupVersion.WithKind("EndpointSlice"): {},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				skip, err := syncer.(*endpointSliceSyncer).ReconcileStart(syncCtx, request)
+				assert.NilError(t, err)
+				assert.Equal(t, skip, true)
+			},
+		},
+	})
+}
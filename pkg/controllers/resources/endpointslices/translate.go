@@ -0,0 +1,70 @@
+package endpointslices
+
+import (
+	"context"
+
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+func (s *endpointSliceSyncer) translate(ctx context.Context, vObj *discoveryv1.EndpointSlice) *discoveryv1.EndpointSlice {
+	endpointSlice := s.TranslateMetadata(ctx, vObj).(*discoveryv1.EndpointSlice)
+	endpointSlice.Labels = translateLabels(vObj, endpointSlice.Labels)
+	s.translateSpec(endpointSlice, vObj.Namespace)
+	return endpointSlice
+}
+
+// translateLabels points the endpoint slice to the host service and marks it as managed by vCluster
+func translateLabels(vObj *discoveryv1.EndpointSlice, labels map[string]string) map[string]string {
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	labels[discoveryv1.LabelServiceName] = translate.Default.PhysicalName(vObj.Labels[discoveryv1.LabelServiceName], vObj.Namespace)
+	labels[discoveryv1.LabelManagedBy] = ManagedBy
+	return labels
+}
+
+// translateSpec rewrites the pod references of the endpoints. The address type is kept as is, so IPv4, IPv6 and
+// FQDN endpoint slices of dual-stack services are synced independently of each other.
+func (s *endpointSliceSyncer) translateSpec(endpointSlice *discoveryv1.EndpointSlice, vNamespace string) {
+	for i, endpoint := range endpointSlice.Endpoints {
+		if endpoint.TargetRef != nil && endpoint.TargetRef.Kind == "Pod" {
+			endpointSlice.Endpoints[i].TargetRef.Name = translate.Default.PhysicalName(endpoint.TargetRef.Name, endpoint.TargetRef.Namespace)
+			endpointSlice.Endpoints[i].TargetRef.Namespace = translate.Default.PhysicalNamespace(vNamespace)
+
+			// TODO: set the actual values here
+			endpointSlice.Endpoints[i].TargetRef.UID = ""
+			endpointSlice.Endpoints[i].TargetRef.ResourceVersion = ""
+		}
+	}
+}
+
+func (s *endpointSliceSyncer) translateUpdate(ctx context.Context, pObj, vObj *discoveryv1.EndpointSlice) *discoveryv1.EndpointSlice {
+	var updated *discoveryv1.EndpointSlice
+
+	// check endpoints & ports, the address type is immutable
+	translated := vObj.DeepCopy()
+	s.translateSpec(translated, vObj.Namespace)
+	if !equality.Semantic.DeepEqual(translated.Endpoints, pObj.Endpoints) {
+		updated = translator.NewIfNil(updated, pObj)
+		updated.Endpoints = translated.Endpoints
+	}
+	if !equality.Semantic.DeepEqual(translated.Ports, pObj.Ports) {
+		updated = translator.NewIfNil(updated, pObj)
+		updated.Ports = translated.Ports
+	}
+
+	// check annotations & labels
+	_, annotations, labels := s.TranslateMetadataUpdate(ctx, vObj, pObj)
+	labels = translateLabels(vObj, labels)
+	if !equality.Semantic.DeepEqual(annotations, pObj.Annotations) || !equality.Semantic.DeepEqual(labels, pObj.Labels) {
+		updated = translator.NewIfNil(updated, pObj)
+		updated.Annotations = annotations
+		updated.Labels = labels
+	}
+
+	return updated
+}
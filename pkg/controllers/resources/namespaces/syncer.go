@@ -1,12 +1,16 @@
 package namespaces
 
 import (
+	"fmt"
+	"slices"
+
 	"github.com/loft-sh/vcluster/pkg/constants"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
 	syncertypes "github.com/loft-sh/vcluster/pkg/types"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -37,6 +41,7 @@ func New(ctx *synccontext.RegisterContext) (syncertypes.Object, error) {
 		Translator:                 translator.NewClusterTranslator(ctx, "namespace", &corev1.Namespace{}, NamespaceNameTranslator, excludedAnnotations...),
 		workloadServiceAccountName: ctx.Config.ControlPlane.Advanced.WorkloadServiceAccount.Name,
 		namespaceLabels:            namespaceLabels,
+		adoptNamespaces:            ctx.Config.Experimental.MultiNamespaceMode.AdoptNamespaces,
 	}, nil
 }
 
@@ -44,6 +49,7 @@ type namespaceSyncer struct {
 	translator.Translator
 	workloadServiceAccountName string
 	namespaceLabels            map[string]string
+	adoptNamespaces            []string
 }
 
 var _ syncertypes.IndicesRegisterer = &namespaceSyncer{}
@@ -82,6 +88,82 @@ func (s *namespaceSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object,
 	return ctrl.Result{}, s.EnsureWorkloadServiceAccount(ctx, pObj.GetName())
 }
 
+var _ syncertypes.Starter = &namespaceSyncer{}
+
+// ReconcileStart adopts an existing host namespace before it is synced, so that the syncer treats it as managed
+func (s *namespaceSyncer) ReconcileStart(ctx *synccontext.SyncContext, req ctrl.Request) (bool, error) {
+	if len(s.adoptNamespaces) == 0 {
+		return false, nil
+	}
+
+	vNamespace := &corev1.Namespace{}
+	err := ctx.VirtualClient.Get(ctx.Context, client.ObjectKey{Name: req.Name}, vNamespace)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return true, err
+	} else if vNamespace.DeletionTimestamp != nil {
+		return false, nil
+	}
+
+	// only namespaces that are explicitly listed are adopted
+	pNamespaceName := NamespaceNameTranslator(vNamespace.Name, vNamespace)
+	if !slices.Contains(s.adoptNamespaces, pNamespaceName) || translate.IsSystemNamespace(pNamespaceName) || pNamespaceName == ctx.CurrentNamespace {
+		return false, nil
+	}
+
+	pNamespace := &corev1.Namespace{}
+	err = ctx.PhysicalClient.Get(ctx.Context, client.ObjectKey{Name: pNamespaceName}, pNamespace)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return true, err
+	} else if pNamespace.DeletionTimestamp != nil || pNamespace.Labels[translate.MarkerLabel] != "" {
+		// the namespace is either already managed by this vCluster or belongs to another one
+		return false, nil
+	} else if !isOwnedBy(pNamespace, s.namespaceLabels[VClusterNamespaceAnnotation], s.namespaceLabels[VClusterNameAnnotation]) {
+		ctx.Log.Infof("skip adopting physical namespace %s, as it belongs to vCluster %s/%s", pNamespace.Name, pNamespace.Labels[VClusterNamespaceAnnotation], pNamespace.Labels[VClusterNameAnnotation])
+		return false, nil
+	}
+
+	ctx.Log.Infof("adopt existing physical namespace %s", pNamespace.Name)
+	originalNamespace := pNamespace.DeepCopy()
+	if pNamespace.Labels == nil {
+		pNamespace.Labels = map[string]string{}
+	}
+	if pNamespace.Annotations == nil {
+		pNamespace.Annotations = map[string]string{}
+	}
+	pNamespace.Labels[translate.MarkerLabel] = translate.SafeConcatName(ctx.CurrentNamespace, "x", translate.VClusterName)
+	pNamespace.Labels[translate.AdoptedLabel] = "true"
+	pNamespace.Annotations[translate.NameAnnotation] = vNamespace.Name
+	pNamespace.Annotations[translate.UIDAnnotation] = string(vNamespace.UID)
+	pNamespace.Annotations[translate.SkipBackSyncInMultiNamespaceMode] = "true"
+	err = ctx.PhysicalClient.Patch(ctx.Context, pNamespace, client.MergeFrom(originalNamespace))
+	if err != nil {
+		return true, fmt.Errorf("adopt physical namespace %s: %w", pNamespace.Name, err)
+	}
+
+	return false, nil
+}
+
+func (s *namespaceSyncer) ReconcileEnd() {}
+
+// isOwnedBy checks that the namespace carries no vCluster labels of another vCluster
+func isOwnedBy(namespace *corev1.Namespace, vClusterNamespace, vClusterName string) bool {
+	owner, ok := namespace.Labels[VClusterNamespaceAnnotation]
+	if ok && owner != vClusterNamespace {
+		return false
+	}
+
+	owner, ok = namespace.Labels[VClusterNameAnnotation]
+	return !ok || owner == vClusterName
+}
+
 func (s *namespaceSyncer) EnsureWorkloadServiceAccount(ctx *synccontext.SyncContext, pNamespace string) error {
 	if s.workloadServiceAccountName == "" {
 		return nil
@@ -0,0 +1,83 @@
+package namespaces
+
+import (
+	"context"
+	"testing"
+
+	"github.com/loft-sh/vcluster/config"
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestReconcileStartAdoption(t *testing.T) {
+	defaultTranslator := translate.Default
+	defer func() { translate.Default = defaultTranslator }()
+
+	var err error
+	translate.Default, err = translate.NewMultiNamespaceTranslatorWithOptions("vcluster", config.ExperimentalMultiNamespaceMode{
+		NamespaceMappings: map[string]string{
+			"team-a":      "prod-team-a-ns",
+			"team-b":      "prod-team-b-ns",
+			"team-c":      "prod-team-c-ns",
+			"kube-system": "kube-system",
+			"unlisted":    "unlisted",
+		},
+	})
+	assert.NilError(t, err)
+
+	hostNamespaces := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "prod-team-a-ns"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "prod-team-b-ns", Labels: map[string]string{VClusterNamespaceAnnotation: "other"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "prod-team-c-ns", Labels: map[string]string{VClusterNamespaceAnnotation: "vcluster", VClusterNameAnnotation: "other"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "unlisted"}},
+	}
+	virtualNamespaces := []runtime.Object{}
+	for _, name := range []string{"team-a", "team-b", "team-c", "kube-system", "unlisted"} {
+		virtualNamespaces = append(virtualNamespaces, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+
+	scheme := testingutil.NewScheme()
+	pClient := testingutil.NewFakeClient(scheme, hostNamespaces...)
+	ctx := &synccontext.SyncContext{
+		Context:          context.TODO(),
+		Log:              loghelper.New("test"),
+		PhysicalClient:   pClient,
+		VirtualClient:    testingutil.NewFakeClient(scheme, virtualNamespaces...),
+		CurrentNamespace: "vcluster",
+	}
+	s := &namespaceSyncer{
+		namespaceLabels: map[string]string{VClusterNamespaceAnnotation: "vcluster", VClusterNameAnnotation: "vcluster"},
+		// kube-system is listed to check that system namespaces are refused even if the validation was bypassed
+		adoptNamespaces: []string{"prod-team-a-ns", "prod-team-b-ns", "prod-team-c-ns", "kube-system"},
+	}
+
+	testCases := []struct {
+		virtualNamespace string
+		hostNamespace    string
+		adopted          bool
+	}{
+		{virtualNamespace: "team-a", hostNamespace: "prod-team-a-ns", adopted: true},
+		{virtualNamespace: "team-b", hostNamespace: "prod-team-b-ns"},
+		{virtualNamespace: "team-c", hostNamespace: "prod-team-c-ns"},
+		{virtualNamespace: "kube-system", hostNamespace: "kube-system"},
+		{virtualNamespace: "unlisted", hostNamespace: "unlisted"},
+	}
+	for _, testCase := range testCases {
+		_, err := s.ReconcileStart(ctx, ctrl.Request{NamespacedName: client.ObjectKey{Name: testCase.virtualNamespace}})
+		assert.NilError(t, err, testCase.virtualNamespace)
+
+		pNamespace := &corev1.Namespace{}
+		assert.NilError(t, pClient.Get(context.TODO(), client.ObjectKey{Name: testCase.hostNamespace}, pNamespace))
+		assert.Equal(t, pNamespace.Labels[translate.AdoptedLabel] == "true", testCase.adopted, testCase.virtualNamespace)
+		assert.Equal(t, pNamespace.Labels[translate.MarkerLabel] != "", testCase.adopted, testCase.virtualNamespace)
+	}
+}
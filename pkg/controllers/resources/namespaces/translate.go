@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -30,6 +31,15 @@ func (s *namespaceSyncer) translateUpdate(ctx context.Context, pObj, vObj *corev
 	}
 	// set the kubernetes.io/metadata.name label
 	updatedLabels[corev1.LabelMetadataName] = pObj.Name
+
+	// keep the labels of adopted namespaces
+	if pObj.Labels[translate.AdoptedLabel] == "true" {
+		for k, v := range pObj.Labels {
+			if _, ok := updatedLabels[k]; !ok {
+				updatedLabels[k] = v
+			}
+		}
+	}
 	// check if any labels or annotations changed
 	if !equality.Semantic.DeepEqual(updatedAnnotations, pObj.GetAnnotations()) || !equality.Semantic.DeepEqual(updatedLabels, pObj.GetLabels()) {
 		updated = translator.NewIfNil(updated, pObj)
@@ -0,0 +1,112 @@
+package networkpolicies
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+// TranslationReportAnnotation is set on virtual network policies that couldn't be translated faithfully to the host
+// and contains one finding per line.
+const TranslationReportAnnotation = "vcluster.loft.sh/translation-report"
+
+type report struct {
+	messages []string
+}
+
+func (r *report) forRule(direction string, index int) *ruleReport {
+	return &ruleReport{
+		report: r,
+		prefix: fmt.Sprintf("%s[%d]", direction, index),
+	}
+}
+
+type ruleReport struct {
+	report *report
+	prefix string
+}
+
+func (r *ruleReport) add(format string, args ...interface{}) {
+	r.report.messages = append(r.report.messages, r.prefix+": "+fmt.Sprintf(format, args...))
+}
+
+// translateIPBlock scopes the ip block so that it can't select host pods or services. The host cidrs contained in
+// the block are added as exceptions, while blocks that lie within a host cidr are dropped.
+func (s *networkPolicySyncer) translateIPBlock(ipBlock *networkingv1.IPBlock, report *ruleReport) (*networkingv1.IPBlock, bool) {
+	newIPBlock := ipBlock.DeepCopy()
+	_, blockNet, err := net.ParseCIDR(ipBlock.CIDR)
+	if err != nil {
+		return newIPBlock, true
+	}
+
+	for _, hostCIDR := range s.hostCIDRs {
+		if containsCIDR(hostCIDR, blockNet) {
+			report.add("ipBlock %s was removed, because it selects host pods or services within %s, use pod and namespace selectors instead", ipBlock.CIDR, hostCIDR.String())
+			return nil, false
+		} else if containsCIDR(blockNet, hostCIDR) && !containsString(newIPBlock.Except, hostCIDR.String()) {
+			newIPBlock.Except = append(newIPBlock.Except, hostCIDR.String())
+		}
+	}
+
+	return newIPBlock, true
+}
+
+// containsCIDR returns true if inner is a subnet of outer
+func containsCIDR(outer, inner *net.IPNet) bool {
+	outerOnes, outerBits := outer.Mask.Size()
+	innerOnes, innerBits := inner.Mask.Size()
+	return outerBits == innerBits && outerOnes <= innerOnes && outer.Contains(inner.IP)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	out := []*net.IPNet{}
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, ipNet)
+	}
+
+	return out, nil
+}
+
+// updateReport sets or removes the translation report annotation on the virtual network policy and returns true
+// if the virtual object was updated.
+func (s *networkPolicySyncer) updateReport(ctx *synccontext.SyncContext, vNetworkPolicy *networkingv1.NetworkPolicy, messages []string) (bool, error) {
+	newReport := strings.Join(messages, "\n")
+	if vNetworkPolicy.Annotations[TranslationReportAnnotation] == newReport {
+		return false, nil
+	}
+
+	updated := vNetworkPolicy.DeepCopy()
+	if newReport == "" {
+		delete(updated.Annotations, TranslationReportAnnotation)
+	} else {
+		if updated.Annotations == nil {
+			updated.Annotations = map[string]string{}
+		}
+		updated.Annotations[TranslationReportAnnotation] = newReport
+		for _, message := range messages {
+			s.EventRecorder().Eventf(vNetworkPolicy, corev1.EventTypeWarning, "TranslationIncomplete", "Network policy couldn't be translated faithfully: %s", message)
+		}
+	}
+
+	ctx.Log.Infof("update translation report of virtual network policy %s/%s", vNetworkPolicy.Namespace, vNetworkPolicy.Name)
+	return true, ctx.VirtualClient.Update(ctx.Context, updated)
+}
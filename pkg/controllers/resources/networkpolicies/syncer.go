@@ -1,33 +1,77 @@
 package networkpolicies
 
 import (
+	"fmt"
+	"net"
+
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
 	syncertypes "github.com/loft-sh/vcluster/pkg/types"
 
 	networkingv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func New(ctx *synccontext.RegisterContext) (syncertypes.Object, error) {
+	hostCIDRs, err := parseCIDRs(append(ctx.Config.Sync.ToHost.NetworkPolicies.HostPodCIDRs, ctx.Config.Sync.ToHost.NetworkPolicies.HostServiceCIDRs...))
+	if err != nil {
+		return nil, fmt.Errorf("parse sync.toHost.networkPolicies host cidrs: %w", err)
+	}
+
 	return &networkPolicySyncer{
-		NamespacedTranslator: translator.NewNamespacedTranslator(ctx, "networkpolicy", &networkingv1.NetworkPolicy{}),
+		NamespacedTranslator: translator.NewNamespacedTranslator(ctx, "networkpolicy", &networkingv1.NetworkPolicy{}, TranslationReportAnnotation),
+
+		vClusterName:     ctx.Config.Name,
+		currentNamespace: ctx.CurrentNamespace,
+		allowDNS:         ctx.Config.Sync.ToHost.NetworkPolicies.AllowDNS,
+		embeddedDNS:      ctx.Config.ControlPlane.CoreDNS.Embedded,
+		hostCIDRs:        hostCIDRs,
 	}, nil
 }
 
 type networkPolicySyncer struct {
 	translator.NamespacedTranslator
+
+	vClusterName     string
+	currentNamespace string
+	allowDNS         bool
+	embeddedDNS      bool
+	hostCIDRs        []*net.IPNet
 }
 
 var _ syncertypes.Syncer = &networkPolicySyncer{}
 
 func (s *networkPolicySyncer) SyncToHost(ctx *synccontext.SyncContext, vObj client.Object) (ctrl.Result, error) {
-	return s.SyncToHostCreate(ctx, vObj, s.translate(ctx.Context, vObj.(*networkingv1.NetworkPolicy)))
+	vNetworkPolicy := vObj.(*networkingv1.NetworkPolicy)
+	pNetworkPolicy, report := s.translate(ctx.Context, vNetworkPolicy)
+	updated, err := s.updateReport(ctx, vNetworkPolicy, report)
+	if err != nil {
+		if kerrors.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, err
+	} else if updated {
+		return ctrl.Result{}, nil
+	}
+
+	return s.SyncToHostCreate(ctx, vObj, pNetworkPolicy)
 }
 
 func (s *networkPolicySyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj client.Object) (ctrl.Result, error) {
-	newNetworkPolicy := s.translateUpdate(ctx.Context, pObj.(*networkingv1.NetworkPolicy), vObj.(*networkingv1.NetworkPolicy))
+	vNetworkPolicy := vObj.(*networkingv1.NetworkPolicy)
+	newNetworkPolicy, report := s.translateUpdate(ctx.Context, pObj.(*networkingv1.NetworkPolicy), vNetworkPolicy)
+	updated, err := s.updateReport(ctx, vNetworkPolicy, report)
+	if err != nil {
+		if kerrors.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, err
+	} else if updated {
+		return ctrl.Result{}, nil
+	}
+
 	if newNetworkPolicy != nil {
 		translator.PrintChanges(pObj, newNetworkPolicy, ctx.Log)
 	}
@@ -7,6 +7,7 @@ import (
 	"gotest.tools/assert"
 	"k8s.io/utils/ptr"
 
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/namespaces"
 	podstranslate "github.com/loft-sh/vcluster/pkg/controllers/resources/pods/translate"
 	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
@@ -16,6 +17,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
+
+	corev1 "k8s.io/api/core/v1"
 )
 
 func TestSync(t *testing.T) {
@@ -161,6 +164,50 @@ func TestSync(t *testing.T) {
 			Ports: somePorts,
 			To:    []networkingv1.NetworkPolicyPeer{pnetworkPolicyWithLabelSelectorNoNs.Spec.Ingress[0].From[0]},
 		},
+		{
+			Ports: []networkingv1.NetworkPolicyPort{
+				{Protocol: ptr.To(corev1.ProtocolUDP), Port: ptr.To(intstr.FromInt32(53))},
+				{Protocol: ptr.To(corev1.ProtocolTCP), Port: ptr.To(intstr.FromInt32(53))},
+				{Protocol: ptr.To(corev1.ProtocolUDP), Port: ptr.To(intstr.FromInt32(1053))},
+				{Protocol: ptr.To(corev1.ProtocolTCP), Port: ptr.To(intstr.FromInt32(1053))},
+			},
+			To: []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					translate.Default.ConvertLabelKey("k8s-app"): "kube-dns",
+					translate.NamespaceLabel:                     "kube-system",
+					translate.MarkerLabel:                        translate.VClusterName,
+				},
+			}}},
+		},
+	}
+
+	vnetworkPolicyWithHostIPBlocks := vBaseNetworkPolicy.DeepCopy()
+	vnetworkPolicyWithHostIPBlocks.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{
+		{
+			Ports: somePorts,
+			From: []networkingv1.NetworkPolicyPeer{
+				{IPBlock: &networkingv1.IPBlock{CIDR: "0.0.0.0/0", Except: []string{"192.168.0.0/16"}}},
+				{IPBlock: &networkingv1.IPBlock{CIDR: "10.96.12.0/24"}},
+			},
+		},
+		{
+			From: []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "10.244.1.0/24"}}},
+		},
+	}
+	vnetworkPolicyWithHostIPBlocksReport := vnetworkPolicyWithHostIPBlocks.DeepCopy()
+	vnetworkPolicyWithHostIPBlocksReport.Annotations = map[string]string{
+		TranslationReportAnnotation: "ingress[0]: ipBlock 10.96.12.0/24 was removed, because it selects host pods or services within 10.96.0.0/12, use pod and namespace selectors instead\n" +
+			"ingress[1]: ipBlock 10.244.1.0/24 was removed, because it selects host pods or services within 10.244.0.0/16, use pod and namespace selectors instead\n" +
+			"ingress[1]: rule was removed, because none of its peers could be translated",
+	}
+	pnetworkPolicyWithHostIPBlocks := pBaseNetworkPolicy.DeepCopy()
+	pnetworkPolicyWithHostIPBlocks.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{
+		{
+			Ports: somePorts,
+			From: []networkingv1.NetworkPolicyPeer{
+				{IPBlock: &networkingv1.IPBlock{CIDR: "0.0.0.0/0", Except: []string{"192.168.0.0/16", "10.244.0.0/16", "10.96.0.0/12"}}},
+			},
+		},
 	}
 
 	vnetworkPolicyWithMatchExpressions := vBaseNetworkPolicy.DeepCopy()
@@ -389,5 +436,74 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name:                "Create forward - ingress policy with ip blocks that select host pods and services",
+			InitialVirtualState: []runtime.Object{vnetworkPolicyWithHostIPBlocks.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy"): {vnetworkPolicyWithHostIPBlocksReport},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy"): {pnetworkPolicyWithHostIPBlocks},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Config.Sync.ToHost.NetworkPolicies.HostPodCIDRs = []string{"10.244.0.0/16"}
+				ctx.Config.Sync.ToHost.NetworkPolicies.HostServiceCIDRs = []string{"10.96.0.0/12"}
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+
+				// first sync writes the report, second one creates the host policy
+				vNetworkPolicy := vnetworkPolicyWithHostIPBlocks.DeepCopy()
+				vNetworkPolicy.ResourceVersion = "999"
+				_, err := syncer.(*networkPolicySyncer).SyncToHost(syncCtx, vNetworkPolicy)
+				assert.NilError(t, err)
+				_, err = syncer.(*networkPolicySyncer).SyncToHost(syncCtx, vnetworkPolicyWithHostIPBlocksReport.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
+	})
+}
+
+func TestTranslateSpecMultiNamespace(t *testing.T) {
+	translate.Default = translate.NewMultiNamespaceTranslator("vcluster")
+	defer func() {
+		translate.Default = translate.NewSingleNamespaceTranslator(generictesting.DefaultTestTargetNamespace)
+	}()
+
+	syncer := &networkPolicySyncer{vClusterName: "my-vcluster", currentNamespace: "vcluster"}
+	spec, report := syncer.translateSpec(&networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+		Ingress: []networkingv1.NetworkPolicyIngressRule{{
+			From: []networkingv1.NetworkPolicyPeer{
+				{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}},
+				{NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{corev1.LabelMetadataName: "monitoring"},
+					MatchExpressions: []metav1.LabelSelectorRequirement{{
+						Key:      "team",
+						Operator: metav1.LabelSelectorOpIn,
+						Values:   []string{"a"},
+					}},
+				}},
+			},
+		}},
+	}, "test")
+	assert.Equal(t, len(report), 0)
+	assert.DeepEqual(t, spec, &networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+		Ingress: []networkingv1.NetworkPolicyIngressRule{{
+			From: []networkingv1.NetworkPolicyPeer{
+				{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}},
+				{NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						corev1.LabelMetadataName:               translate.Default.PhysicalNamespace("monitoring"),
+						namespaces.VClusterNameAnnotation:      "my-vcluster",
+						namespaces.VClusterNamespaceAnnotation: "vcluster",
+					},
+					MatchExpressions: []metav1.LabelSelectorRequirement{{
+						Key:      "team",
+						Operator: metav1.LabelSelectorOpIn,
+						Values:   []string{"a"},
+					}},
+				}},
+			},
+		}},
 	})
 }
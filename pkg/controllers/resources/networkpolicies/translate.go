@@ -3,26 +3,34 @@ package networkpolicies
 import (
 	"context"
 
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/namespaces"
 	podstranslate "github.com/loft-sh/vcluster/pkg/controllers/resources/pods/translate"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 )
 
-func (s *networkPolicySyncer) translate(ctx context.Context, vNetworkPolicy *networkingv1.NetworkPolicy) *networkingv1.NetworkPolicy {
+var dnsPorts = []int32{53, 1053}
+
+func (s *networkPolicySyncer) translate(ctx context.Context, vNetworkPolicy *networkingv1.NetworkPolicy) (*networkingv1.NetworkPolicy, []string) {
 	newNetworkPolicy := s.TranslateMetadata(ctx, vNetworkPolicy).(*networkingv1.NetworkPolicy)
-	newNetworkPolicy.Spec = *translateSpec(&vNetworkPolicy.Spec, vNetworkPolicy.GetNamespace())
-	return newNetworkPolicy
+	translatedSpec, report := s.translateSpec(&vNetworkPolicy.Spec, vNetworkPolicy.GetNamespace())
+	newNetworkPolicy.Spec = *translatedSpec
+	return newNetworkPolicy, report
 }
 
-func (s *networkPolicySyncer) translateUpdate(ctx context.Context, pObj, vObj *networkingv1.NetworkPolicy) *networkingv1.NetworkPolicy {
+func (s *networkPolicySyncer) translateUpdate(ctx context.Context, pObj, vObj *networkingv1.NetworkPolicy) (*networkingv1.NetworkPolicy, []string) {
 	var updated *networkingv1.NetworkPolicy
 
-	translatedSpec := *translateSpec(&vObj.Spec, vObj.GetNamespace())
-	if !equality.Semantic.DeepEqual(translatedSpec, pObj.Spec) {
+	translatedSpec, report := s.translateSpec(&vObj.Spec, vObj.GetNamespace())
+	if !equality.Semantic.DeepEqual(*translatedSpec, pObj.Spec) {
 		updated = translator.NewIfNil(updated, pObj)
-		updated.Spec = translatedSpec
+		updated.Spec = *translatedSpec
 	}
 
 	changed, translatedAnnotations, translatedLabels := s.TranslateMetadataUpdate(ctx, vObj, pObj)
@@ -32,78 +40,193 @@ func (s *networkPolicySyncer) translateUpdate(ctx context.Context, pObj, vObj *n
 		updated.Annotations = translatedAnnotations
 	}
 
-	return updated
+	return updated, report
 }
 
-func translateSpec(spec *networkingv1.NetworkPolicySpec, namespace string) *networkingv1.NetworkPolicySpec {
+// translateSpec translates the network policy spec to the host and returns a report of everything that
+// couldn't be translated faithfully.
+func (s *networkPolicySyncer) translateSpec(spec *networkingv1.NetworkPolicySpec, namespace string) (*networkingv1.NetworkPolicySpec, []string) {
 	if spec == nil {
-		return nil
+		return nil, nil
 	}
 
+	report := &report{}
 	outSpec := &networkingv1.NetworkPolicySpec{}
-	for _, er := range spec.Egress {
+	for i, er := range spec.Egress {
 		if outSpec.Egress == nil {
 			outSpec.Egress = []networkingv1.NetworkPolicyEgressRule{}
 		}
+
+		to, ok := s.translateNetworkPolicyPeers(er.To, namespace, report.forRule("egress", i))
+		if !ok {
+			continue
+		}
 		outSpec.Egress = append(outSpec.Egress, networkingv1.NetworkPolicyEgressRule{
 			Ports: er.Ports,
-			To:    translateNetworkPolicyPeers(er.To, namespace),
+			To:    to,
 		})
 	}
-	for _, ir := range spec.Ingress {
+	for i, ir := range spec.Ingress {
 		if outSpec.Ingress == nil {
 			outSpec.Ingress = []networkingv1.NetworkPolicyIngressRule{}
 		}
+
+		from, ok := s.translateNetworkPolicyPeers(ir.From, namespace, report.forRule("ingress", i))
+		if !ok {
+			continue
+		}
 		outSpec.Ingress = append(outSpec.Ingress, networkingv1.NetworkPolicyIngressRule{
 			Ports: ir.Ports,
-			From:  translateNetworkPolicyPeers(ir.From, namespace),
+			From:  from,
 		})
 	}
 
-	// TODO(Multi-Namespace): add support for multi-namespace translation
-	if !translate.Default.SingleNamespaceTarget() {
-		panic("Multi-Namespace Mode not supported for network policies yet!")
+	if translate.Default.SingleNamespaceTarget() {
+		outSpec.PodSelector = *translate.Default.TranslateLabelSelector(&spec.PodSelector)
+		if outSpec.PodSelector.MatchLabels == nil {
+			outSpec.PodSelector.MatchLabels = map[string]string{}
+		}
+		// add selector for namespace as NetworkPolicy podSelector applies to pods within it's namespace
+		outSpec.PodSelector.MatchLabels[translate.NamespaceLabel] = namespace
+		// add selector for the marker label to select only from pods belonging this vcluster instance
+		outSpec.PodSelector.MatchLabels[translate.MarkerLabel] = translate.VClusterName
+	} else {
+		// in multi-namespace mode the policy is synced into the host namespace of the virtual namespace
+		outSpec.PodSelector = *spec.PodSelector.DeepCopy()
 	}
 
-	outSpec.PodSelector = *translate.Default.TranslateLabelSelector(&spec.PodSelector)
-	if outSpec.PodSelector.MatchLabels == nil {
-		outSpec.PodSelector.MatchLabels = map[string]string{}
+	outSpec.PolicyTypes = spec.PolicyTypes
+	if s.allowDNS && isEgressPolicy(spec) {
+		outSpec.Egress = append(outSpec.Egress, s.dnsEgressRule())
 	}
-	// add selector for namespace as NetworkPolicy podSelector applies to pods within it's namespace
-	outSpec.PodSelector.MatchLabels[translate.NamespaceLabel] = namespace
-	// add selector for the marker label to select only from pods belonging this vcluster instance
-	outSpec.PodSelector.MatchLabels[translate.MarkerLabel] = translate.VClusterName
 
-	outSpec.PolicyTypes = spec.PolicyTypes
-	return outSpec
+	return outSpec, report.messages
 }
 
-func translateNetworkPolicyPeers(peers []networkingv1.NetworkPolicyPeer, namespace string) []networkingv1.NetworkPolicyPeer {
+// translateNetworkPolicyPeers translates the given peers to the host. If all peers of a non-empty list were dropped,
+// false is returned and the rule must be dropped, as an empty list of peers would allow all traffic.
+func (s *networkPolicySyncer) translateNetworkPolicyPeers(peers []networkingv1.NetworkPolicyPeer, namespace string, report *ruleReport) ([]networkingv1.NetworkPolicyPeer, bool) {
 	if peers == nil {
-		return nil
+		return nil, true
 	}
 	out := []networkingv1.NetworkPolicyPeer{}
 	for _, peer := range peers {
+		if peer.IPBlock != nil {
+			ipBlock, ok := s.translateIPBlock(peer.IPBlock, report)
+			if ok {
+				out = append(out, networkingv1.NetworkPolicyPeer{IPBlock: ipBlock})
+			}
+			continue
+		}
+
+		if !translate.Default.SingleNamespaceTarget() {
+			out = append(out, s.translateMultiNamespacePeer(peer))
+			continue
+		}
+
 		newPeer := networkingv1.NetworkPolicyPeer{
 			PodSelector:       translate.Default.TranslateLabelSelector(peer.PodSelector),
 			NamespaceSelector: nil, // must be set to nil as all vcluster pods are in the same host namespace as the NetworkPolicy
 		}
-		if peer.IPBlock == nil {
-			translatedNamespaceSelectors := translate.LabelSelectorWithPrefix(podstranslate.NamespaceLabelPrefix, peer.NamespaceSelector)
-			newPeer.PodSelector = translate.MergeLabelSelectors(newPeer.PodSelector, translatedNamespaceSelectors)
+		translatedNamespaceSelectors := translate.LabelSelectorWithPrefix(podstranslate.NamespaceLabelPrefix, peer.NamespaceSelector)
+		newPeer.PodSelector = translate.MergeLabelSelectors(newPeer.PodSelector, translatedNamespaceSelectors)
 
-			if newPeer.PodSelector.MatchLabels == nil {
-				newPeer.PodSelector.MatchLabels = map[string]string{}
-			}
-			if peer.NamespaceSelector == nil {
-				newPeer.PodSelector.MatchLabels[translate.NamespaceLabel] = namespace
-			}
-			// add selector for the marker label to select only from pods belonging this vcluster instance
-			newPeer.PodSelector.MatchLabels[translate.MarkerLabel] = translate.VClusterName
-		} else {
-			newPeer.IPBlock = peer.IPBlock.DeepCopy()
+		if newPeer.PodSelector.MatchLabels == nil {
+			newPeer.PodSelector.MatchLabels = map[string]string{}
+		}
+		if peer.NamespaceSelector == nil {
+			newPeer.PodSelector.MatchLabels[translate.NamespaceLabel] = namespace
 		}
+		// add selector for the marker label to select only from pods belonging this vcluster instance
+		newPeer.PodSelector.MatchLabels[translate.MarkerLabel] = translate.VClusterName
 		out = append(out, newPeer)
 	}
-	return out
+	if len(peers) > 0 && len(out) == 0 {
+		report.add("rule was removed, because none of its peers could be translated")
+		return nil, false
+	}
+
+	return out, true
+}
+
+// translateMultiNamespacePeer translates a peer in multi-namespace mode. Pod labels are kept as is, while
+// namespace selectors are restricted to the host namespaces of this virtual cluster.
+func (s *networkPolicySyncer) translateMultiNamespacePeer(peer networkingv1.NetworkPolicyPeer) networkingv1.NetworkPolicyPeer {
+	newPeer := networkingv1.NetworkPolicyPeer{
+		PodSelector: peer.PodSelector.DeepCopy(),
+	}
+	if peer.NamespaceSelector == nil {
+		return newPeer
+	}
+
+	newPeer.NamespaceSelector = &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			namespaces.VClusterNameAnnotation:      s.vClusterName,
+			namespaces.VClusterNamespaceAnnotation: s.currentNamespace,
+		},
+	}
+	for k, v := range peer.NamespaceSelector.MatchLabels {
+		if k == corev1.LabelMetadataName {
+			v = translate.Default.PhysicalNamespace(v)
+		}
+		newPeer.NamespaceSelector.MatchLabels[k] = v
+	}
+	for _, expr := range peer.NamespaceSelector.MatchExpressions {
+		newExpr := *expr.DeepCopy()
+		if newExpr.Key == corev1.LabelMetadataName {
+			for i, v := range newExpr.Values {
+				newExpr.Values[i] = translate.Default.PhysicalNamespace(v)
+			}
+		}
+		newPeer.NamespaceSelector.MatchExpressions = append(newPeer.NamespaceSelector.MatchExpressions, newExpr)
+	}
+
+	return newPeer
+}
+
+// dnsEgressRule allows egress traffic to the virtual cluster DNS, which is either the synced coredns pod or the
+// vCluster control plane itself if coredns is embedded.
+func (s *networkPolicySyncer) dnsEgressRule() networkingv1.NetworkPolicyEgressRule {
+	rule := networkingv1.NetworkPolicyEgressRule{}
+	for _, port := range dnsPorts {
+		for _, protocol := range []corev1.Protocol{corev1.ProtocolUDP, corev1.ProtocolTCP} {
+			rule.Ports = append(rule.Ports, networkingv1.NetworkPolicyPort{
+				Protocol: ptr.To(protocol),
+				Port:     ptr.To(intstr.FromInt32(port)),
+			})
+		}
+	}
+
+	dnsLabels := map[string]string{"k8s-app": "kube-dns"}
+	if s.embeddedDNS {
+		rule.To = []networkingv1.NetworkPolicyPeer{{
+			PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "vcluster", "release": s.vClusterName}},
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{corev1.LabelMetadataName: s.currentNamespace}},
+		}}
+	} else if translate.Default.SingleNamespaceTarget() {
+		podSelector := translate.Default.TranslateLabelSelector(&metav1.LabelSelector{MatchLabels: dnsLabels})
+		podSelector.MatchLabels[translate.NamespaceLabel] = "kube-system"
+		podSelector.MatchLabels[translate.MarkerLabel] = translate.VClusterName
+		rule.To = []networkingv1.NetworkPolicyPeer{{PodSelector: podSelector}}
+	} else {
+		rule.To = []networkingv1.NetworkPolicyPeer{{
+			PodSelector:       &metav1.LabelSelector{MatchLabels: dnsLabels},
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{corev1.LabelMetadataName: translate.Default.PhysicalNamespace("kube-system")}},
+		}}
+	}
+
+	return rule
+}
+
+func isEgressPolicy(spec *networkingv1.NetworkPolicySpec) bool {
+	if len(spec.Egress) > 0 {
+		return true
+	}
+	for _, policyType := range spec.PolicyTypes {
+		if policyType == networkingv1.PolicyTypeEgress {
+			return true
+		}
+	}
+
+	return false
 }
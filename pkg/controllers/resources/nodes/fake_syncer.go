@@ -9,6 +9,10 @@ import (
 	"github.com/loft-sh/vcluster/pkg/constants"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/nodes/nodeservice"
 	podtranslate "github.com/loft-sh/vcluster/pkg/controllers/resources/pods/translate"
@@ -31,16 +35,21 @@ var (
 	FakeNodesVersion = "v1.19.1"
 )
 
+// HostCordonedAnnotation is set on fake nodes that were cordoned because their host node is cordoned
+const HostCordonedAnnotation = "vcluster.loft.sh/host-cordoned"
+
 func NewFakeSyncer(ctx *synccontext.RegisterContext, nodeService nodeservice.Provider) (syncer.Object, error) {
 	return &fakeNodeSyncer{
 		nodeServiceProvider: nodeService,
 		fakeKubeletIPs:      ctx.Config.Networking.Advanced.ProxyKubelets.ByIP,
+		reflectCordons:      ctx.Config.Sync.FromHost.Nodes.ReflectCordons,
 	}, nil
 }
 
 type fakeNodeSyncer struct {
 	nodeServiceProvider nodeservice.Provider
 	fakeKubeletIPs      bool
+	reflectCordons      bool
 }
 
 func (r *fakeNodeSyncer) Resource() client.Object {
@@ -59,8 +68,20 @@ func (r *fakeNodeSyncer) RegisterIndices(ctx *synccontext.RegisterContext) error
 
 var _ syncer.ControllerModifier = &fakeNodeSyncer{}
 
-func (r *fakeNodeSyncer) ModifyController(ctx *synccontext.RegisterContext, builder *builder.Builder) (*builder.Builder, error) {
-	return modifyController(ctx, r.nodeServiceProvider, builder)
+func (r *fakeNodeSyncer) ModifyController(ctx *synccontext.RegisterContext, bld *builder.Builder) (*builder.Builder, error) {
+	bld, err := modifyController(ctx, r.nodeServiceProvider, bld)
+	if err != nil {
+		return nil, err
+	}
+
+	// watch host nodes to reflect cordons in real time
+	if r.reflectCordons {
+		bld = bld.WatchesRawSource(source.Kind(ctx.PhysicalManager.GetCache(), &corev1.Node{}), handler.EnqueueRequestsFromMapFunc(func(_ context.Context, object client.Object) []reconcile.Request {
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: object.GetName()}}}
+		}))
+	}
+
+	return bld, nil
 }
 
 var _ syncer.FakeSyncer = &fakeNodeSyncer{}
@@ -91,6 +112,22 @@ func (r *fakeNodeSyncer) FakeSync(ctx *synccontext.SyncContext, vObj client.Obje
 		return ctrl.Result{}, ctx.VirtualClient.Delete(ctx.Context, vObj)
 	}
 
+	// check if we need to update the cordon
+	if r.reflectCordons {
+		updated, err := r.updateCordonIfNeeded(ctx, node)
+		if err != nil {
+			return ctrl.Result{}, err
+		} else if updated != nil {
+			ctx.Log.Infof("Update unschedulable of fake node %s to %v", node.Name, updated.Spec.Unschedulable)
+			err := ctx.VirtualClient.Update(ctx.Context, updated)
+			if err != nil {
+				return ctrl.Result{}, errors.Wrap(err, "update node")
+			}
+
+			return ctrl.Result{}, nil
+		}
+	}
+
 	// check if we need to update node ips
 	updated := r.updateIfNeeded(ctx, node, node.Name)
 	if updated != nil {
@@ -104,6 +141,38 @@ func (r *fakeNodeSyncer) FakeSync(ctx *synccontext.SyncContext, vObj client.Obje
 	return ctrl.Result{}, nil
 }
 
+// updateCordonIfNeeded marks the fake node as unschedulable while the host node is cordoned. Cordons set within the
+// virtual cluster are kept, as only cordons reflected from the host are tracked by the annotation.
+func (r *fakeNodeSyncer) updateCordonIfNeeded(ctx *synccontext.SyncContext, node *corev1.Node) (*corev1.Node, error) {
+	pNode := &corev1.Node{}
+	err := ctx.PhysicalClient.Get(ctx.Context, types.NamespacedName{Name: node.Name}, pNode)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, errors.Wrap(err, "get host node")
+	}
+
+	hostCordoned := node.Annotations[HostCordonedAnnotation] == "true"
+	if pNode.Spec.Unschedulable && !hostCordoned {
+		updated := node.DeepCopy()
+		if updated.Annotations == nil {
+			updated.Annotations = map[string]string{}
+		}
+		updated.Annotations[HostCordonedAnnotation] = "true"
+		updated.Spec.Unschedulable = true
+		return updated, nil
+	} else if !pNode.Spec.Unschedulable && hostCordoned {
+		updated := node.DeepCopy()
+		delete(updated.Annotations, HostCordonedAnnotation)
+		updated.Spec.Unschedulable = false
+		return updated, nil
+	}
+
+	return nil, nil
+}
+
 func (r *fakeNodeSyncer) updateIfNeeded(ctx *synccontext.SyncContext, node *corev1.Node, name string) *corev1.Node {
 	var updated *corev1.Node
 
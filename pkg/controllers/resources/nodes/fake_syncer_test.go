@@ -198,3 +198,57 @@ func TestFakeSync(t *testing.T) {
 		},
 	})
 }
+
+func TestFakeSyncReflectCordons(t *testing.T) {
+	pNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "mynode"},
+		Spec:       corev1.NodeSpec{Unschedulable: true},
+	}
+	vNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "mynode"},
+	}
+	vCordonedNode := vNode.DeepCopy()
+	vCordonedNode.Annotations = map[string]string{HostCordonedAnnotation: "true"}
+	vCordonedNode.Spec.Unschedulable = true
+
+	generictesting.RunTests(t, []*generictesting.SyncTest{
+		{
+			Name:                 "Cordon fake node",
+			InitialPhysicalState: []runtime.Object{pNode.DeepCopy()},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Config.Sync.FromHost.Nodes.ReflectCordons = true
+				syncContext, syncer := newFakeFakeSyncer(t, ctx)
+
+				updated, err := syncer.updateCordonIfNeeded(syncContext, vNode.DeepCopy())
+				assert.NilError(t, err)
+				assert.DeepEqual(t, updated, vCordonedNode)
+
+				updated, err = syncer.updateCordonIfNeeded(syncContext, vCordonedNode.DeepCopy())
+				assert.NilError(t, err)
+				assert.Assert(t, updated == nil)
+			},
+		},
+		{
+			Name: "Uncordon fake node",
+			InitialPhysicalState: []runtime.Object{&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "mynode"},
+			}},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Config.Sync.FromHost.Nodes.ReflectCordons = true
+				syncContext, syncer := newFakeFakeSyncer(t, ctx)
+
+				updated, err := syncer.updateCordonIfNeeded(syncContext, vCordonedNode.DeepCopy())
+				assert.NilError(t, err)
+				assert.Equal(t, updated.Spec.Unschedulable, false)
+				assert.Equal(t, updated.Annotations[HostCordonedAnnotation], "")
+
+				// cordons of the virtual cluster itself are kept
+				tenantCordoned := vNode.DeepCopy()
+				tenantCordoned.Spec.Unschedulable = true
+				updated, err = syncer.updateCordonIfNeeded(syncContext, tenantCordoned)
+				assert.NilError(t, err)
+				assert.Assert(t, updated == nil)
+			},
+		},
+	})
+}
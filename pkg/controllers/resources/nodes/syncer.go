@@ -14,6 +14,7 @@ import (
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
 	syncertypes "github.com/loft-sh/vcluster/pkg/types"
+	"github.com/loft-sh/vcluster/pkg/util/noderules"
 	"github.com/loft-sh/vcluster/pkg/util/toleration"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	"github.com/pkg/errors"
@@ -49,6 +50,12 @@ func NewSyncer(ctx *synccontext.RegisterContext, nodeServiceProvider nodeservice
 		}
 	}
 
+	// parse node rules
+	rules, err := noderules.New(ctx.Config.Sync.FromHost.Nodes)
+	if err != nil {
+		return nil, err
+	}
+
 	return &nodeSyncer{
 		enableScheduler: ctx.Config.ControlPlane.Advanced.VirtualScheduler.Enabled,
 
@@ -62,6 +69,7 @@ func NewSyncer(ctx *synccontext.RegisterContext, nodeServiceProvider nodeservice
 		virtualClient:       ctx.VirtualManager.GetClient(),
 		nodeServiceProvider: nodeServiceProvider,
 		enforcedTolerations: tolerations,
+		rules:               rules,
 	}, nil
 }
 
@@ -72,6 +80,7 @@ type nodeSyncer struct {
 	unmanagedPodCache   client.Reader
 	nodeServiceProvider nodeservice.Provider
 	enforcedTolerations []*corev1.Toleration
+	rules               *noderules.Rules
 	enableScheduler     bool
 	clearImages         bool
 	enforceNodeSelector bool
@@ -288,7 +297,7 @@ func (s *nodeSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj
 		vNode = updatedVNode
 	}
 
-	updated := s.translateUpdateBackwards(pNode, vNode)
+	updated := s.translateUpdateBackwards(s.rules.TranslateNode(pNode), vNode)
 	if updated != nil {
 		ctx.Log.Infof("update virtual node %s, because spec has changed", pNode.Name)
 		translator.PrintChanges(vNode, updated, ctx.Log)
@@ -316,7 +325,7 @@ func (s *nodeSyncer) SyncToVirtual(ctx *synccontext.SyncContext, pObj client.Obj
 	err = ctx.VirtualClient.Create(ctx.Context, &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        pNode.Name,
-			Labels:      pNode.Labels,
+			Labels:      s.rules.TranslateLabels(pNode.Labels),
 			Annotations: pNode.Annotations,
 		},
 	})
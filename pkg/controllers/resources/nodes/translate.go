@@ -16,6 +16,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/klog/v2"
+	resourcehelper "k8s.io/kubectl/pkg/util/resource"
 )
 
 var (
@@ -174,48 +175,7 @@ func (s *nodeSyncer) translateUpdateStatus(ctx *synccontext.SyncContext, pNode *
 	if s.enableScheduler {
 		// calculate what's really allocatable
 		if translatedStatus.Allocatable != nil {
-			cpu := translatedStatus.Allocatable.Cpu().MilliValue()
-			memory := translatedStatus.Allocatable.Memory().Value()
-			storageEphemeral := translatedStatus.Allocatable.StorageEphemeral().Value()
-			pods := translatedStatus.Allocatable.Pods().Value()
-
-			var nonVClusterPods int64
-			podList := &corev1.PodList{}
-			err := s.unmanagedPodCache.List(ctx.Context, podList, client.MatchingFields{constants.IndexRunningNonVClusterPodsByNode: pNode.Name})
-			if err != nil {
-				klog.Errorf("Error listing pods: %v", err)
-			} else {
-				for _, pod := range podList.Items {
-					if !translate.Default.IsManaged(&pod) {
-						// count pods that are not synced by this vcluster
-						nonVClusterPods++
-					}
-					for _, container := range pod.Spec.InitContainers {
-						cpu -= container.Resources.Requests.Cpu().MilliValue()
-						memory -= container.Resources.Requests.Memory().Value()
-						storageEphemeral -= container.Resources.Requests.StorageEphemeral().Value()
-					}
-					for _, container := range pod.Spec.Containers {
-						cpu -= container.Resources.Requests.Cpu().MilliValue()
-						memory -= container.Resources.Requests.Memory().Value()
-						storageEphemeral -= container.Resources.Requests.StorageEphemeral().Value()
-					}
-				}
-			}
-
-			pods -= nonVClusterPods
-			if pods > 0 {
-				translatedStatus.Allocatable[corev1.ResourcePods] = *resource.NewQuantity(pods, resource.DecimalSI)
-			}
-			if cpu > 0 {
-				translatedStatus.Allocatable[corev1.ResourceCPU] = *resource.NewMilliQuantity(cpu, resource.DecimalSI)
-			}
-			if memory > 0 {
-				translatedStatus.Allocatable[corev1.ResourceMemory] = *resource.NewQuantity(memory, resource.BinarySI)
-			}
-			if storageEphemeral > 0 {
-				translatedStatus.Allocatable[corev1.ResourceEphemeralStorage] = *resource.NewQuantity(storageEphemeral, resource.BinarySI)
-			}
+			translatedStatus.Allocatable = s.hostAllocatable(ctx, pNode.Name, translatedStatus.Allocatable)
 		}
 
 		// calculate what's in capacity & allocatable
@@ -264,6 +224,52 @@ func (s *nodeSyncer) translateUpdateStatus(ctx *synccontext.SyncContext, pNode *
 	return nil, nil
 }
 
+// hostAllocatable subtracts the requests of all pods on the host node that are not synced by this vCluster from the
+// allocatable resources, so that the virtual scheduler only binds pods to nodes that have enough room left in the host
+// cluster. Resources that are used up completely are set to zero.
+func (s *nodeSyncer) hostAllocatable(ctx *synccontext.SyncContext, nodeName string, allocatable corev1.ResourceList) corev1.ResourceList {
+	podList := &corev1.PodList{}
+	err := s.unmanagedPodCache.List(ctx.Context, podList, client.MatchingFields{constants.IndexRunningNonVClusterPodsByNode: nodeName})
+	if err != nil {
+		klog.Errorf("Error listing pods: %v", err)
+		return allocatable
+	}
+
+	used := corev1.ResourceList{}
+	var nonVClusterPods int64
+	for _, pod := range podList.Items {
+		// pods synced by this vcluster are already accounted for by the virtual scheduler
+		if translate.Default.IsManaged(&pod) {
+			continue
+		}
+
+		nonVClusterPods++
+		requests, _ := resourcehelper.PodRequestsAndLimits(&pod)
+		for name, quantity := range requests {
+			total := used[name]
+			total.Add(quantity)
+			used[name] = total
+		}
+	}
+	used[corev1.ResourcePods] = *resource.NewQuantity(nonVClusterPods, resource.DecimalSI)
+
+	result := allocatable.DeepCopy()
+	for name, quantity := range allocatable {
+		usedQuantity, ok := used[name]
+		if !ok {
+			continue
+		}
+
+		quantity.Sub(usedQuantity)
+		if quantity.Sign() < 0 {
+			quantity = *resource.NewQuantity(0, quantity.Format)
+		}
+		result[name] = quantity
+	}
+
+	return result
+}
+
 func mergeStrings(physical []string, virtual []string, oldPhysical []string) []string {
 	merged := []string{}
 	merged = append(merged, physical...)
@@ -1,14 +1,21 @@
 package nodes
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"testing"
 
+	"github.com/loft-sh/vcluster/pkg/constants"
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	"gotest.tools/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 type translateBackwardsTest struct {
@@ -304,3 +311,57 @@ func mustMarshal(i interface{}) string {
 
 	return string(out)
 }
+
+func TestHostAllocatable(t *testing.T) {
+	newPod := func(name string, cpu, memory string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "other-tenant"},
+			Spec: corev1.PodSpec{
+				NodeName: "node1",
+				InitContainers: []corev1.Container{{
+					Name: "init",
+					Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse(cpu),
+					}},
+				}},
+				Containers: []corev1.Container{{
+					Name: "main",
+					Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse(cpu),
+						corev1.ResourceMemory: resource.MustParse(memory),
+					}},
+				}},
+			},
+		}
+	}
+
+	// pods of this vcluster are counted by the virtual scheduler already
+	managedPod := newPod(translate.Default.PhysicalName("own", "default"), "1", "1Gi")
+	managedPod.Namespace = translate.Default.PhysicalNamespace("default")
+	managedPod.Labels = map[string]string{translate.MarkerLabel: translate.VClusterName}
+	managedPod.Annotations = map[string]string{translate.NameAnnotation: "own", translate.NamespaceAnnotation: "default"}
+	assert.Assert(t, translate.Default.IsManaged(managedPod))
+
+	podCache := fake.NewClientBuilder().
+		WithScheme(testingutil.NewScheme()).
+		WithIndex(&corev1.Pod{}, constants.IndexRunningNonVClusterPodsByNode, func(object client.Object) []string {
+			return []string{object.(*corev1.Pod).Spec.NodeName}
+		}).
+		WithObjects(newPod("a", "1", "1Gi"), newPod("b", "2", "1Gi"), managedPod).
+		Build()
+	s := &nodeSyncer{unmanagedPodCache: podCache}
+
+	allocatable := s.hostAllocatable(&synccontext.SyncContext{Context: context.Background()}, "node1", corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("2"),
+		corev1.ResourceMemory: resource.MustParse("4Gi"),
+		corev1.ResourcePods:   resource.MustParse("110"),
+	})
+
+	// init containers don't add up with regular containers and used up resources don't go below zero
+	cpu := allocatable[corev1.ResourceCPU]
+	assert.Equal(t, cpu.Value(), int64(0))
+	memory := allocatable[corev1.ResourceMemory]
+	assert.Equal(t, memory.String(), "2Gi")
+	pods := allocatable[corev1.ResourcePods]
+	assert.Equal(t, pods.Value(), int64(108))
+}
@@ -3,6 +3,7 @@ package persistentvolumeclaims
 import (
 	"context"
 
+	vclusterconfig "github.com/loft-sh/vcluster/config"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/persistentvolumes"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
@@ -41,6 +42,8 @@ func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
 		NamespacedTranslator: translator.NewNamespacedTranslator(ctx, "persistent-volume-claim", &corev1.PersistentVolumeClaim{}, excludedAnnotations...),
 
 		storageClassesEnabled:    storageClassesEnabled,
+		storageClassMapping:      ctx.Config.Sync.ToHost.PersistentVolumeClaims.StorageClassMapping,
+		volumeSnapshotsEnabled:   ctx.Config.Sync.ToHost.VolumeSnapshots.Enabled,
 		schedulerEnabled:         ctx.Config.ControlPlane.Advanced.VirtualScheduler.Enabled,
 		useFakePersistentVolumes: !ctx.Config.Sync.ToHost.PersistentVolumes.Enabled,
 	}, nil
@@ -50,6 +53,8 @@ type persistentVolumeClaimSyncer struct {
 	translator.NamespacedTranslator
 
 	storageClassesEnabled    bool
+	storageClassMapping      vclusterconfig.ClassMapping
+	volumeSnapshotsEnabled   bool
 	schedulerEnabled         bool
 	useFakePersistentVolumes bool
 }
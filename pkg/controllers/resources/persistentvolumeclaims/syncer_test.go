@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	vclusterconfig "github.com/loft-sh/vcluster/config"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
 	"gotest.tools/assert"
@@ -17,6 +18,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
 )
 
 func TestSync(t *testing.T) {
@@ -39,9 +41,7 @@ func TestSync(t *testing.T) {
 	}
 	changedResources := corev1.VolumeResourceRequirements{
 		Requests: map[corev1.ResourceName]resource.Quantity{
-			"storage": {
-				Format: "teststoragerequest",
-			},
+			"storage": resource.MustParse("10Gi"),
 		},
 	}
 	basePvc := &corev1.PersistentVolumeClaim{
@@ -309,3 +309,117 @@ func TestSync(t *testing.T) {
 		},
 	})
 }
+
+func TestTranslateStorage(t *testing.T) {
+	storageClass := "fast"
+	deniedStorageClass := "local"
+	snapshotGroup := volumeSnapshotGroup
+	otherNamespace := "otherns"
+
+	testCases := []struct {
+		name              string
+		pvc               *corev1.PersistentVolumeClaim
+		snapshotsEnabled  bool
+		expectedClass     *string
+		expectedSource    *corev1.TypedLocalObjectReference
+		expectedSourceRef *corev1.TypedObjectReference
+		expectedErr       bool
+	}{
+		{
+			name: "Mapped storage class",
+			pvc: &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "testpvc", Namespace: "testns"},
+				Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &storageClass},
+			},
+			expectedClass: ptr.To("host-ssd"),
+		},
+		{
+			name: "Default storage class",
+			pvc: &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "testpvc", Namespace: "testns"},
+			},
+			expectedClass: ptr.To("host-standard"),
+		},
+		{
+			name: "Explicitly empty storage class",
+			pvc: &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "testpvc", Namespace: "testns"},
+				Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: ptr.To("")},
+			},
+			expectedClass: ptr.To(""),
+		},
+		{
+			name: "Denied storage class",
+			pvc: &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "testpvc", Namespace: "testns"},
+				Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &deniedStorageClass},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "Clone persistent volume claim",
+			pvc: &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "testpvc", Namespace: "testns"},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					DataSource: &corev1.TypedLocalObjectReference{Kind: "PersistentVolumeClaim", Name: "source"},
+				},
+			},
+			expectedClass: ptr.To("host-standard"),
+			expectedSource: &corev1.TypedLocalObjectReference{
+				Kind: "PersistentVolumeClaim",
+				Name: translate.Default.PhysicalName("source", "testns"),
+			},
+		},
+		{
+			name: "Restore snapshot from other namespace",
+			pvc: &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "testpvc", Namespace: "testns"},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					DataSourceRef: &corev1.TypedObjectReference{APIGroup: &snapshotGroup, Kind: "VolumeSnapshot", Name: "snapshot", Namespace: &otherNamespace},
+				},
+			},
+			snapshotsEnabled: true,
+			expectedClass:    ptr.To("host-standard"),
+			expectedSourceRef: &corev1.TypedObjectReference{
+				APIGroup:  &snapshotGroup,
+				Kind:      "VolumeSnapshot",
+				Name:      translate.Default.PhysicalName("snapshot", otherNamespace),
+				Namespace: ptr.To(translate.Default.PhysicalNamespace(otherNamespace)),
+			},
+		},
+		{
+			name: "Restore snapshot without snapshot syncing",
+			pvc: &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "testpvc", Namespace: "testns"},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					DataSource: &corev1.TypedLocalObjectReference{APIGroup: &snapshotGroup, Kind: "VolumeSnapshot", Name: "snapshot"},
+				},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			registerContext := generictesting.NewFakeRegisterContext(testingutil.NewFakeClient(testingutil.NewScheme()), testingutil.NewFakeClient(testingutil.NewScheme()))
+			registerContext.Config.Sync.ToHost.VolumeSnapshots.Enabled = testCase.snapshotsEnabled
+			registerContext.Config.Sync.ToHost.PersistentVolumeClaims.StorageClassMapping = vclusterconfig.ClassMapping{
+				Mappings: map[string]string{storageClass: "host-ssd"},
+				Default:  "host-standard",
+				Deny:     []string{deniedStorageClass},
+			}
+
+			syncCtx, syncer := generictesting.FakeStartSyncer(t, registerContext, New)
+			pPvc, err := syncer.(*persistentVolumeClaimSyncer).translate(syncCtx, testCase.pvc)
+			if testCase.expectedErr {
+				assert.Assert(t, err != nil)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.DeepEqual(t, pPvc.Spec.StorageClassName, testCase.expectedClass)
+			assert.DeepEqual(t, pPvc.Spec.DataSource, testCase.expectedSource)
+			assert.DeepEqual(t, pPvc.Spec.DataSourceRef, testCase.expectedSourceRef)
+		})
+	}
+}
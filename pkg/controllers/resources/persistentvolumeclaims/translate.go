@@ -2,6 +2,7 @@ package persistentvolumeclaims
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/loft-sh/vcluster/pkg/constants"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
@@ -12,10 +13,12 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 )
 
 var (
 	deprecatedStorageClassAnnotation = "volume.beta.kubernetes.io/storage-class"
+	volumeSnapshotGroup              = "snapshot.storage.k8s.io"
 )
 
 func (s *persistentVolumeClaimSyncer) translate(ctx *synccontext.SyncContext, vPvc *corev1.PersistentVolumeClaim) (*corev1.PersistentVolumeClaim, error) {
@@ -27,29 +30,69 @@ func (s *persistentVolumeClaimSyncer) translate(ctx *synccontext.SyncContext, vP
 
 	if vPvc.Annotations[constants.SkipTranslationAnnotation] != "true" {
 		if newPvc.Spec.DataSource != nil {
+			err = s.checkDataSource(newPvc.Spec.DataSource.APIGroup, newPvc.Spec.DataSource.Kind)
+			if err != nil {
+				return nil, err
+			}
+
 			newPvc.Spec.DataSource.Name = translate.Default.PhysicalName(newPvc.Spec.DataSource.Name, vPvc.Namespace)
 		}
 
 		if newPvc.Spec.DataSourceRef != nil {
-			newPvc.Spec.DataSourceRef.Name = translate.Default.PhysicalName(newPvc.Spec.DataSourceRef.Name, vPvc.Namespace)
+			err = s.checkDataSource(newPvc.Spec.DataSourceRef.APIGroup, newPvc.Spec.DataSourceRef.Kind)
+			if err != nil {
+				return nil, err
+			}
+
+			// cross namespace data sources reference an object in another virtual namespace
+			namespace := vPvc.Namespace
+			if newPvc.Spec.DataSourceRef.Namespace != nil && *newPvc.Spec.DataSourceRef.Namespace != "" {
+				namespace = *newPvc.Spec.DataSourceRef.Namespace
+				physicalNamespace := translate.Default.PhysicalNamespace(namespace)
+				newPvc.Spec.DataSourceRef.Namespace = &physicalNamespace
+			}
+
+			newPvc.Spec.DataSourceRef.Name = translate.Default.PhysicalName(newPvc.Spec.DataSourceRef.Name, namespace)
 		}
 	}
 
 	return newPvc, nil
 }
 
+// checkDataSource makes sure the data source of the persistent volume claim is synced to the host cluster
+func (s *persistentVolumeClaimSyncer) checkDataSource(apiGroup *string, kind string) error {
+	if apiGroup != nil && *apiGroup == volumeSnapshotGroup && kind == "VolumeSnapshot" && !s.volumeSnapshotsEnabled {
+		return fmt.Errorf("data source references volume snapshot, but volume snapshot syncing is not enabled (sync.toHost.volumeSnapshots.enabled)")
+	}
+
+	return nil
+}
+
 func (s *persistentVolumeClaimSyncer) translateSelector(ctx *synccontext.SyncContext, vPvc *corev1.PersistentVolumeClaim) (*corev1.PersistentVolumeClaim, error) {
 	vPvc = vPvc.DeepCopy()
 
-	storageClassName := ""
+	// an explicitly empty storage class disables dynamic provisioning, so the default class is not applied to it
+	var className *string
 	if vPvc.Spec.StorageClassName != nil && *vPvc.Spec.StorageClassName != "" {
-		storageClassName = *vPvc.Spec.StorageClassName
+		className = vPvc.Spec.StorageClassName
 	} else if vPvc.Annotations != nil && vPvc.Annotations[deprecatedStorageClassAnnotation] != "" {
-		storageClassName = vPvc.Annotations[deprecatedStorageClassAnnotation]
+		className = ptr.To(vPvc.Annotations[deprecatedStorageClassAnnotation])
+	} else if vPvc.Spec.StorageClassName != nil {
+		className = ptr.To("")
+	}
+	storageClassName := ptr.Deref(className, "")
+
+	// translate storage class with the configured mapping
+	mappedStorageClassName, mapped, err := translate.TranslateClassName(s.storageClassMapping, className)
+	if err != nil {
+		return nil, fmt.Errorf("storage class: %w", err)
+	} else if mapped {
+		delete(vPvc.Annotations, deprecatedStorageClassAnnotation)
+		vPvc.Spec.StorageClassName = &mappedStorageClassName
 	}
 
 	// translate storage class if we manage those in vcluster
-	if s.storageClassesEnabled && storageClassName != "" {
+	if !mapped && s.storageClassesEnabled && storageClassName != "" {
 		translated := translate.Default.PhysicalNameClusterScoped(storageClassName)
 		delete(vPvc.Annotations, deprecatedStorageClassAnnotation)
 		vPvc.Spec.StorageClassName = &translated
@@ -65,7 +108,7 @@ func (s *persistentVolumeClaimSyncer) translateSelector(ctx *synccontext.SyncCon
 				vPvc.Spec.VolumeName = translate.Default.PhysicalNameClusterScoped(vPvc.Spec.VolumeName)
 			}
 			// check if the storage class exists in the physical cluster
-			if !s.storageClassesEnabled && storageClassName != "" {
+			if !mapped && !s.storageClassesEnabled && storageClassName != "" {
 				// Should the PVC be dynamically provisioned or not?
 				if vPvc.Spec.Selector == nil && vPvc.Spec.VolumeName == "" {
 					err := ctx.PhysicalClient.Get(ctx.Context, types.NamespacedName{Name: storageClassName}, &storagev1.StorageClass{})
@@ -88,13 +131,14 @@ func (s *persistentVolumeClaimSyncer) translateSelector(ctx *synccontext.SyncCon
 func (s *persistentVolumeClaimSyncer) translateUpdate(ctx context.Context, pObj, vObj *corev1.PersistentVolumeClaim) (*corev1.PersistentVolumeClaim, error) {
 	var updated *corev1.PersistentVolumeClaim
 
-	// allow storage size to be increased
-	if pObj.Spec.Resources.Requests["storage"] != vObj.Spec.Resources.Requests["storage"] {
+	// allow storage size to be increased, shrinking a volume is not supported by kubernetes
+	vStorage, pStorage := vObj.Spec.Resources.Requests[corev1.ResourceStorage], pObj.Spec.Resources.Requests[corev1.ResourceStorage]
+	if vStorage.Cmp(pStorage) > 0 {
 		updated = translator.NewIfNil(updated, pObj)
 		if updated.Spec.Resources.Requests == nil {
 			updated.Spec.Resources.Requests = make(map[corev1.ResourceName]resource.Quantity)
 		}
-		updated.Spec.Resources.Requests["storage"] = vObj.Spec.Resources.Requests["storage"]
+		updated.Spec.Resources.Requests[corev1.ResourceStorage] = vStorage
 	}
 
 	changed, updatedAnnotations, updatedLabels := s.TranslateMetadataUpdate(ctx, vObj, pObj)
@@ -128,6 +172,16 @@ func (s *persistentVolumeClaimSyncer) translateUpdateBackwards(pObj, vObj *corev
 		}
 	}
 
+	// check if the volume was expanded in the host cluster
+	vStorage, pStorage := vObj.Spec.Resources.Requests[corev1.ResourceStorage], pObj.Spec.Resources.Requests[corev1.ResourceStorage]
+	if pStorage.Cmp(vStorage) > 0 {
+		updated = translator.NewIfNil(updated, vObj)
+		if updated.Spec.Resources.Requests == nil {
+			updated.Spec.Resources.Requests = make(map[corev1.ResourceName]resource.Quantity)
+		}
+		updated.Spec.Resources.Requests[corev1.ResourceStorage] = pStorage
+	}
+
 	return updated
 }
 
@@ -1,6 +1,7 @@
 package pods
 
 import (
+	podtranslate "github.com/loft-sh/vcluster/pkg/controllers/resources/pods/translate"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -76,6 +77,11 @@ func UpdateConditions(ctx *synccontext.SyncContext, physicalPod *corev1.Pod, vir
 
 // Check for custom condition
 func isCustomCondition(condition corev1.PodCondition) bool {
+	// the host mutations condition is owned by vcluster and only exists in the virtual cluster
+	if condition.Type == podtranslate.HostMutationsConditionType {
+		return false
+	}
+
 	// if not a default condition, we assume it's a custom condition
 	return !coreConditions[string(condition.Type)]
 }
@@ -3,8 +3,10 @@ package pods
 import (
 	"context"
 	"reflect"
+	"strings"
 	"time"
 
+	vclusterconfig "github.com/loft-sh/vcluster/config"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/util/wait"
 
@@ -14,7 +16,9 @@ import (
 
 	translatepods "github.com/loft-sh/vcluster/pkg/controllers/resources/pods/translate"
 	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	"github.com/loft-sh/vcluster/pkg/util/nodedebug"
 	"github.com/loft-sh/vcluster/pkg/util/toleration"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -66,6 +70,15 @@ func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
 		}
 	}
 
+	// node debug approvals are signed by the vCluster proxy
+	var nodeDebugKey []byte
+	if ctx.Config.Sync.ToHost.Pods.NodeDebug.Enabled {
+		nodeDebugKey, err = nodedebug.EnsureSigningKey(ctx.Context, physicalClusterClient, ctx.CurrentNamespace, translate.VClusterName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// create new namespaced translator
 	namespacedTranslator := translator.NewNamespacedTranslator(ctx, "pod", &corev1.Pod{})
 
@@ -89,6 +102,9 @@ func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
 		tolerations:           tolerations,
 
 		podSecurityStandard: ctx.Config.Policies.PodSecurityStandard,
+		hostMutations:       ctx.Config.Sync.ToHost.Pods.HostMutations,
+		nodeDebug:           ctx.Config.Sync.ToHost.Pods.NodeDebug,
+		nodeDebugKey:        nodeDebugKey,
 	}, nil
 }
 
@@ -106,6 +122,9 @@ type podSyncer struct {
 	tolerations           []*corev1.Toleration
 
 	podSecurityStandard string
+	hostMutations       vclusterconfig.SyncPodHostMutations
+	nodeDebug           vclusterconfig.SyncPodNodeDebug
+	nodeDebugKey        []byte
 }
 
 var _ syncer.IndicesRegisterer = &podSyncer{}
@@ -163,8 +182,20 @@ func (s *podSyncer) SyncToHost(ctx *synccontext.SyncContext, vObj client.Object)
 		return ctrl.Result{}, err
 	}
 
+	// node debug pods were signed by the vCluster proxy and need host access, so pod security standards don't apply.
+	// All other node debug pods are denied.
+	nodeDebugApproved := nodedebug.IsApproved(s.nodeDebug, s.nodeDebugKey, vPod)
+	if nodedebug.IsNodeDebugPod(vPod) && !nodeDebugApproved {
+		if nodedebug.IsAwaitingSignature(vPod) {
+			return ctrl.Result{RequeueAfter: time.Second}, nil
+		}
+
+		s.EventRecorder().Eventf(vPod, corev1.EventTypeWarning, "NodeDebugDenied", "Node debug pod was not approved by vCluster and won't be synced to the host cluster")
+		return ctrl.Result{}, nil
+	}
+
 	// validate virtual pod before syncing it to the host cluster
-	if s.podSecurityStandard != "" {
+	if s.podSecurityStandard != "" && !nodeDebugApproved {
 		valid, err := s.isPodSecurityStandardsValid(ctx.Context, vPod, ctx.Log)
 		if err != nil {
 			return ctrl.Result{}, err
@@ -215,7 +246,12 @@ func (s *podSyncer) SyncToHost(ctx *synccontext.SyncContext, vObj client.Object)
 		return ctrl.Result{}, nil
 	}
 
-	return s.SyncToHostCreate(ctx, vPod, pPod)
+	result, err := s.SyncToHostCreate(ctx, vPod, pPod)
+	if err == nil && result.IsZero() && nodeDebugApproved {
+		s.EventRecorder().Eventf(vPod, corev1.EventTypeNormal, "NodeDebug", "Started node debug session of user %s on host node %s", vPod.Annotations[nodedebug.UserAnnotation], pPod.Spec.NodeName)
+	}
+
+	return result, err
 }
 
 func (s *podSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj client.Object) (ctrl.Result, error) {
@@ -230,6 +266,11 @@ func (s *podSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj
 				gracePeriod = *vPod.Spec.TerminationGracePeriodSeconds
 			}
 
+			// tell the virtual cluster why the pod is going away if it was evicted or preempted in the host cluster
+			if condition := disruptionTargetCondition(pPod); condition != nil {
+				s.EventRecorder().Eventf(vPod, corev1.EventTypeWarning, "Evicted", "Pod was evicted in the host cluster (%s): %s", condition.Reason, condition.Message)
+			}
+
 			ctx.Log.Infof("delete virtual pod %s/%s, because the physical pod is being deleted", vPod.Namespace, vPod.Name)
 			if err := ctx.VirtualClient.Delete(ctx.Context, vPod, &client.DeleteOptions{GracePeriodSeconds: &gracePeriod}); err != nil {
 				return ctrl.Result{}, err
@@ -254,6 +295,23 @@ func (s *podSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj
 		return ctrl.Result{}, err
 	}
 
+	// reschedule pods the host kubelet rejected, because the virtual scheduler placed them on a node without enough room
+	if s.enableScheduler && vPod.Status.Phase != corev1.PodFailed && isHostRejected(pPod) {
+		s.EventRecorder().Eventf(vPod, corev1.EventTypeWarning, "HostRejected", "Pod was rejected by node %s in the host cluster (%s): %s", pPod.Spec.NodeName, pPod.Status.Reason, pPod.Status.Message)
+
+		// pods with a controller are deleted so that the controller recreates them and the virtual scheduler can pick
+		// another node, pods without a controller are marked as failed through the status sync below
+		if metav1.GetControllerOf(vPod) != nil {
+			ctx.Log.Infof("delete virtual pod %s/%s, because the host rejected it: %s", vPod.Namespace, vPod.Name, pPod.Status.Message)
+			err := ctx.VirtualClient.Delete(ctx.Context, vPod, &client.DeleteOptions{GracePeriodSeconds: &minimumGracePeriodInSeconds, Preconditions: metav1.NewUIDPreconditions(string(vPod.UID))})
+			if err != nil && !kerrors.IsNotFound(err) {
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, nil
+		}
+	}
+
 	// make sure node exists for pod
 	if pPod.Spec.NodeName != "" {
 		requeue, err := s.ensureNode(ctx, pPod, vPod)
@@ -286,6 +344,25 @@ func (s *podSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj
 		return ctrl.Result{}, err
 	}
 
+	// reflect host mutations into the virtual pod
+	if s.hostMutations.Enabled {
+		mutations := translatepods.CalcHostMutations(vPod, pPod, s.hostMutations.Annotations)
+		updatedAnnotations := translatepods.HostMutationsAnnotations(vPod, mutations)
+		if !equality.Semantic.DeepEqual(vPod.Annotations, updatedAnnotations) {
+			newPod := vPod.DeepCopy()
+			newPod.Annotations = updatedAnnotations
+			ctx.Log.Infof("update virtual pod %s/%s, because host pod was mutated", vPod.Namespace, vPod.Name)
+			translator.PrintChanges(vPod, newPod, ctx.Log)
+			err := ctx.VirtualClient.Update(ctx.Context, newPod)
+			if kerrors.IsConflict(err) {
+				return ctrl.Result{Requeue: true}, nil
+			}
+			return ctrl.Result{}, err
+		}
+
+		strippedPod.Status.Conditions = translatepods.SetHostMutationsCondition(strippedPod.Status.Conditions, vPod.Status.Conditions, mutations)
+	}
+
 	// update status physical -> virtual
 	if !equality.Semantic.DeepEqual(vPod.Status, strippedPod.Status) {
 		newPod := vPod.DeepCopy()
@@ -326,7 +403,7 @@ func (s *podSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, vObj
 	}
 
 	// validate virtual pod before syncing it to the host cluster
-	if s.podSecurityStandard != "" {
+	if s.podSecurityStandard != "" && !nodedebug.IsApproved(s.nodeDebug, s.nodeDebugKey, vPod) {
 		valid, err := s.isPodSecurityStandardsValid(ctx.Context, vPod, ctx.Log)
 		if err != nil {
 			return ctrl.Result{}, err
@@ -488,3 +565,23 @@ func stripInjectedSidecarContainers(vPod, pPod, strippedPod *corev1.Pod) *corev1
 
 	return strippedPod
 }
+
+// isHostRejected returns true if the host kubelet refused to admit the pod, e.g. because the node ran out of resources
+// or the node affinity didn't match anymore
+func isHostRejected(pod *corev1.Pod) bool {
+	if pod.Spec.NodeName == "" || pod.Status.Phase != corev1.PodFailed {
+		return false
+	}
+
+	return strings.HasPrefix(pod.Status.Message, "Pod was rejected:") || strings.HasPrefix(pod.Status.Reason, "OutOf")
+}
+
+func disruptionTargetCondition(pod *corev1.Pod) *corev1.PodCondition {
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == corev1.DisruptionTarget && pod.Status.Conditions[i].Status == corev1.ConditionTrue {
+			return &pod.Status.Conditions[i]
+		}
+	}
+
+	return nil
+}
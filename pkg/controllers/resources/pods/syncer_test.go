@@ -9,6 +9,7 @@ import (
 	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
 	"github.com/loft-sh/vcluster/pkg/specialservices"
 	"github.com/loft-sh/vcluster/pkg/util/maps"
+	"github.com/loft-sh/vcluster/pkg/util/nodedebug"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	"gotest.tools/assert"
 	corev1 "k8s.io/api/core/v1"
@@ -128,6 +129,21 @@ func TestSync(t *testing.T) {
 	pPodWithNodeName := pPodBase.DeepCopy()
 	pPodWithNodeName.Spec.NodeName = "test456"
 
+	vPodRejected := vPodWithNodeName.DeepCopy()
+	vPodRejected.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: "apps/v1",
+		Kind:       "ReplicaSet",
+		Name:       "test-rs",
+		Controller: ptr.To(true),
+	}}
+	pPodRejected := pPodBase.DeepCopy()
+	pPodRejected.Spec.NodeName = vPodRejected.Spec.NodeName
+	pPodRejected.Status = corev1.PodStatus{
+		Phase:   corev1.PodFailed,
+		Reason:  "OutOfcpu",
+		Message: "Pod was rejected: Node didn't have enough resource: cpu, requested: 1000, used: 3500, capacity: 4000",
+	}
+
 	vPodWithNodeSelector := &corev1.Pod{
 		ObjectMeta: vObjectMeta,
 		Spec: corev1.PodSpec{
@@ -422,6 +438,22 @@ func TestSync(t *testing.T) {
 	maps.Copy(pPodWithLabels.Labels, convertLabelKeyWithPrefix(testLabels))
 	pPodWithLabels.Annotations[podtranslate.VClusterLabelsAnnotation] = podtranslate.LabelsAnnotation(vPodWithLabels)
 
+	vNodeDebugPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "node-debugger-test123-abcde",
+			Namespace: vNamespace.Name,
+		},
+		Spec: corev1.PodSpec{
+			NodeName:    "test123",
+			HostNetwork: true,
+			HostPID:     true,
+			Containers:  []corev1.Container{{Name: "debugger", Image: "busybox:1.36"}},
+		},
+	}
+	vNodeDebugPodUnsigned := vNodeDebugPod.DeepCopy()
+	vNodeDebugPodUnsigned.CreationTimestamp = metav1.Now()
+	vNodeDebugPodUnsigned.Annotations = map[string]string{nodedebug.UserAnnotation: "alice"}
+
 	generictesting.RunTests(t, []*generictesting.SyncTest{
 		{
 			Name:                 "Delete virtual pod",
@@ -441,6 +473,25 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name:                 "Delete virtual pod rejected by host",
+			InitialVirtualState:  []runtime.Object{vPodRejected.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pPodRejected.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Pod"): {},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Pod"): {
+					pPodRejected.DeepCopy(),
+				},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Config.ControlPlane.Advanced.VirtualScheduler.Enabled = true
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*podSyncer).Sync(syncCtx, pPodRejected.DeepCopy(), vPodRejected.DeepCopy())
+				assert.NilError(t, err)
+			},
+		},
 		{
 			Name:                 "Sync and enforce NodeSelector",
 			InitialVirtualState:  []runtime.Object{vPodWithNodeSelector.DeepCopy(), vNamespace.DeepCopy()},
@@ -510,6 +561,42 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name:                 "Deny unapproved node debug pod",
+			InitialVirtualState:  []runtime.Object{vNodeDebugPod.DeepCopy(), vNamespace.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pVclusterService.DeepCopy(), pDNSService.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Pod"): {vNodeDebugPod.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Pod"): {},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				result, err := syncer.(*podSyncer).SyncToHost(syncCtx, vNodeDebugPod.DeepCopy())
+				assert.NilError(t, err)
+				assert.Assert(t, result.IsZero())
+			},
+		},
+		{
+			Name:                 "Requeue node debug pod that wasn't signed yet",
+			InitialVirtualState:  []runtime.Object{vNodeDebugPodUnsigned.DeepCopy(), vNamespace.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{pVclusterService.DeepCopy(), pDNSService.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Pod"): {vNodeDebugPodUnsigned.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Pod"): {},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				// the pod is requeued instead of being denied by the pod security standard
+				ctx.Config.Policies.PodSecurityStandard = string(api.LevelRestricted)
+				syncCtx, syncer := generictesting.FakeStartSyncer(t, ctx, New)
+				result, err := syncer.(*podSyncer).SyncToHost(syncCtx, vNodeDebugPodUnsigned.DeepCopy())
+				assert.NilError(t, err)
+				assert.Assert(t, result.RequeueAfter > 0)
+			},
+		},
 		{
 			Name:                 "Map hostpaths",
 			InitialVirtualState:  []runtime.Object{vHostPathPod, vHostpathNamespace},
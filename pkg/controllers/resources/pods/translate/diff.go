@@ -29,7 +29,9 @@ func (t *translator) Diff(ctx context.Context, vPod, pPod *corev1.Pod) (*corev1.
 	}
 
 	// check annotations
-	_, updatedAnnotations, updatedLabels := translate.Default.ApplyMetadataUpdate(vPod, pPod, t.syncedLabels, getExcludedAnnotations(pPod)...)
+	// annotations reflected from the host pod are never synced back
+	excludedAnnotations := append(getExcludedAnnotations(pPod), ReflectedHostAnnotations(vPod)...)
+	_, updatedAnnotations, updatedLabels := translate.Default.ApplyMetadataUpdate(vPod, pPod, t.syncedLabels, excludedAnnotations...)
 	if updatedAnnotations == nil {
 		updatedAnnotations = map[string]string{}
 	}
@@ -106,6 +108,8 @@ func getExcludedAnnotations(pPod *corev1.Pod) []string {
 // - spec.initContainers[*].image
 // - spec.activeDeadlineSeconds
 //
+// Containers that only exist in the host pod, e.g. injected by a mutating webhook, are kept as is.
+//
 // TODO: check for ephemereal containers
 func (t *translator) calcSpecDiff(pObj, vObj *corev1.Pod) *corev1.PodSpec {
 	var updatedPodSpec *corev1.PodSpec
@@ -162,8 +166,10 @@ func calcContainerImageDiff(pContainers, vContainers []corev1.Container, transla
 			continue
 		}
 
+		found := false
 		for _, v := range vContainers {
 			if p.Name == v.Name {
+				found = true
 				if p.Image != translateImages.Translate(v.Image) {
 					newContainer := *p.DeepCopy()
 					newContainer.Image = translateImages.Translate(v.Image)
@@ -176,6 +182,11 @@ func calcContainerImageDiff(pContainers, vContainers []corev1.Container, transla
 				break
 			}
 		}
+
+		// keep containers injected by the host, removing them would be rejected by the api server
+		if !found {
+			newContainers = append(newContainers, p)
+		}
 	}
 
 	if !changed {
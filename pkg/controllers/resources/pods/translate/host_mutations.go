@@ -0,0 +1,233 @@
+package translate
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	HostInjectedContainersAnnotation     = "vcluster.loft.sh/host-injected-containers"
+	HostInjectedInitContainersAnnotation = "vcluster.loft.sh/host-injected-init-containers"
+	HostInjectedVolumesAnnotation        = "vcluster.loft.sh/host-injected-volumes"
+	HostResourcesAnnotation              = "vcluster.loft.sh/host-resources"
+	HostAnnotationsAnnotation            = "vcluster.loft.sh/host-annotations"
+
+	HostMutationsConditionType corev1.PodConditionType = "vcluster.loft.sh/HostMutations"
+	HostMutationsReason                                = "HostMutated"
+)
+
+// HostMutations are the changes host mutating webhooks made to a synced pod
+type HostMutations struct {
+	Containers     []string
+	InitContainers []string
+	Volumes        []string
+
+	// Resources holds the host resources of containers whose resources differ from the virtual pod
+	Resources map[string]corev1.ResourceRequirements
+
+	// Annotations are the host annotations that should be copied to the virtual pod
+	Annotations map[string]string
+}
+
+// Empty returns true if the host did not mutate the pod spec
+func (h *HostMutations) Empty() bool {
+	return len(h.Containers) == 0 && len(h.InitContainers) == 0 && len(h.Volumes) == 0 && len(h.Resources) == 0
+}
+
+// CalcHostMutations compares the host pod with the virtual pod and returns everything that was added or changed
+// on the host side, ignoring the containers and volumes vCluster adds itself. Annotations are only reflected if they
+// are missing in the virtual pod or were reflected before.
+func CalcHostMutations(vPod, pPod *corev1.Pod, annotationPatterns []string) *HostMutations {
+	mutations := &HostMutations{
+		Resources:   map[string]corev1.ResourceRequirements{},
+		Annotations: map[string]string{},
+	}
+
+	vContainers := map[string]*corev1.Container{}
+	for i := range vPod.Spec.Containers {
+		vContainers[vPod.Spec.Containers[i].Name] = &vPod.Spec.Containers[i]
+	}
+	for i := range vPod.Spec.InitContainers {
+		vContainers[vPod.Spec.InitContainers[i].Name] = &vPod.Spec.InitContainers[i]
+	}
+
+	for _, pContainer := range pPod.Spec.Containers {
+		mutations.addContainer(pContainer, vContainers, &mutations.Containers)
+	}
+	for _, pContainer := range pPod.Spec.InitContainers {
+		if pContainer.Name == HostsRewriteContainerName {
+			continue
+		}
+
+		mutations.addContainer(pContainer, vContainers, &mutations.InitContainers)
+	}
+
+	vVolumes := map[string]bool{}
+	for _, vVolume := range vPod.Spec.Volumes {
+		vVolumes[vVolume.Name] = true
+	}
+	for _, pVolume := range pPod.Spec.Volumes {
+		if vVolumes[pVolume.Name] || pVolume.Name == HostsVolumeName || strings.HasSuffix(pVolume.Name, "-"+PhysicalVolumeNameSuffix) {
+			continue
+		}
+
+		mutations.Volumes = append(mutations.Volumes, pVolume.Name)
+	}
+
+	// annotations the user set on the virtual pod are synced to the host pod and must not be taken over, otherwise
+	// later changes of the user would be ignored
+	reflected := map[string]bool{}
+	for _, k := range ReflectedHostAnnotations(vPod) {
+		reflected[k] = true
+	}
+	for k, v := range pPod.Annotations {
+		if strings.HasPrefix(k, "vcluster.loft.sh/") || !matchesAnnotationPattern(k, annotationPatterns) {
+			continue
+		} else if _, ok := vPod.Annotations[k]; ok && !reflected[k] {
+			continue
+		}
+
+		mutations.Annotations[k] = v
+	}
+
+	return mutations
+}
+
+func (h *HostMutations) addContainer(pContainer corev1.Container, vContainers map[string]*corev1.Container, injected *[]string) {
+	vContainer, ok := vContainers[pContainer.Name]
+	if !ok {
+		*injected = append(*injected, pContainer.Name)
+		return
+	}
+
+	if !equality.Semantic.DeepEqual(vContainer.Resources, pContainer.Resources) {
+		h.Resources[pContainer.Name] = pContainer.Resources
+	}
+}
+
+func matchesAnnotationPattern(key string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if strings.HasSuffix(pattern, "*") {
+			if strings.HasPrefix(key, strings.TrimSuffix(pattern, "*")) {
+				return true
+			}
+		} else if key == pattern {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HostMutationsAnnotations returns the annotations of the virtual pod with the host mutations applied.
+// Annotations that were copied from the host before and are gone now are removed.
+func HostMutationsAnnotations(vPod *corev1.Pod, mutations *HostMutations) map[string]string {
+	annotations := map[string]string{}
+	for k, v := range vPod.Annotations {
+		annotations[k] = v
+	}
+	for _, k := range ReflectedHostAnnotations(vPod) {
+		delete(annotations, k)
+	}
+
+	setOrDelete(annotations, HostInjectedContainersAnnotation, strings.Join(mutations.Containers, ","))
+	setOrDelete(annotations, HostInjectedInitContainersAnnotation, strings.Join(mutations.InitContainers, ","))
+	setOrDelete(annotations, HostInjectedVolumesAnnotation, strings.Join(mutations.Volumes, ","))
+	resources := ""
+	if len(mutations.Resources) > 0 {
+		out, _ := json.Marshal(mutations.Resources)
+		resources = string(out)
+	}
+	setOrDelete(annotations, HostResourcesAnnotation, resources)
+
+	keys := make([]string, 0, len(mutations.Annotations))
+	for k, v := range mutations.Annotations {
+		annotations[k] = v
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	setOrDelete(annotations, HostAnnotationsAnnotation, strings.Join(keys, "\n"))
+
+	if len(annotations) == 0 {
+		return nil
+	}
+	return annotations
+}
+
+// ReflectedHostAnnotations returns all annotation keys of the virtual pod that were set from the host pod and
+// should therefore never be synced down again.
+func ReflectedHostAnnotations(vPod *corev1.Pod) []string {
+	keys := []string{HostInjectedContainersAnnotation, HostInjectedInitContainersAnnotation, HostInjectedVolumesAnnotation, HostResourcesAnnotation, HostAnnotationsAnnotation}
+	if vPod != nil && vPod.Annotations[HostAnnotationsAnnotation] != "" {
+		keys = append(keys, strings.Split(vPod.Annotations[HostAnnotationsAnnotation], "\n")...)
+	}
+
+	return keys
+}
+
+// SetHostMutationsCondition adds, updates or removes the host mutations condition. The transition time of an
+// existing condition is kept to avoid update loops.
+func SetHostMutationsCondition(conditions []corev1.PodCondition, vConditions []corev1.PodCondition, mutations *HostMutations) []corev1.PodCondition {
+	newConditions := []corev1.PodCondition{}
+	for _, condition := range conditions {
+		if condition.Type != HostMutationsConditionType {
+			newConditions = append(newConditions, condition)
+		}
+	}
+	if mutations.Empty() {
+		return newConditions
+	}
+
+	condition := corev1.PodCondition{
+		Type:               HostMutationsConditionType,
+		Status:             corev1.ConditionTrue,
+		Reason:             HostMutationsReason,
+		Message:            hostMutationsMessage(mutations),
+		LastTransitionTime: metav1.Now(),
+	}
+	for _, vCondition := range vConditions {
+		if vCondition.Type == HostMutationsConditionType && vCondition.Status == condition.Status {
+			condition.LastTransitionTime = vCondition.LastTransitionTime
+		}
+	}
+
+	return append(newConditions, condition)
+}
+
+func hostMutationsMessage(mutations *HostMutations) string {
+	parts := []string{}
+	if len(mutations.Containers) > 0 {
+		parts = append(parts, fmt.Sprintf("injected containers: %s", strings.Join(mutations.Containers, ", ")))
+	}
+	if len(mutations.InitContainers) > 0 {
+		parts = append(parts, fmt.Sprintf("injected init containers: %s", strings.Join(mutations.InitContainers, ", ")))
+	}
+	if len(mutations.Volumes) > 0 {
+		parts = append(parts, fmt.Sprintf("injected volumes: %s", strings.Join(mutations.Volumes, ", ")))
+	}
+	if len(mutations.Resources) > 0 {
+		names := make([]string, 0, len(mutations.Resources))
+		for name := range mutations.Resources {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		parts = append(parts, fmt.Sprintf("changed resources of: %s", strings.Join(names, ", ")))
+	}
+
+	return "Host pod was mutated, " + strings.Join(parts, "; ")
+}
+
+func setOrDelete(annotations map[string]string, key, value string) {
+	if value == "" {
+		delete(annotations, key)
+		return
+	}
+
+	annotations[key] = value
+}
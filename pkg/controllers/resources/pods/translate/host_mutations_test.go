@@ -0,0 +1,107 @@
+package translate
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestHostMutations(t *testing.T) {
+	vPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+			Annotations: map[string]string{
+				"user":                    "annotation",
+				"sidecar.istio.io/inject": "true",
+				"sidecar.istio.io/old":    "stale",
+				HostAnnotationsAnnotation: "sidecar.istio.io/old",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "app", Image: "nginx"}},
+			Volumes:    []corev1.Volume{{Name: "data"}},
+		},
+	}
+	pPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				"sidecar.istio.io/status": "injected",
+				"sidecar.istio.io/inject": "true",
+				"other":                   "annotation",
+				NameAnnotation:            "test",
+			},
+		},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: HostsRewriteContainerName}, {Name: "istio-init"}},
+			Containers: []corev1.Container{
+				{
+					Name:  "app",
+					Image: "nginx",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+					},
+				},
+				{Name: "istio-proxy"},
+			},
+			Volumes: []corev1.Volume{{Name: "data"}, {Name: HostsVolumeName}, {Name: "logs-" + PhysicalVolumeNameSuffix}, {Name: "istio-envoy"}},
+		},
+	}
+
+	mutations := CalcHostMutations(vPod, pPod, []string{"sidecar.istio.io/*"})
+	assert.DeepEqual(t, mutations.Containers, []string{"istio-proxy"})
+	assert.DeepEqual(t, mutations.InitContainers, []string{"istio-init"})
+	assert.DeepEqual(t, mutations.Volumes, []string{"istio-envoy"})
+	assert.Equal(t, len(mutations.Resources), 1)
+	assert.DeepEqual(t, mutations.Annotations, map[string]string{"sidecar.istio.io/status": "injected"})
+
+	annotations := HostMutationsAnnotations(vPod, mutations)
+	assert.DeepEqual(t, annotations, map[string]string{
+		"user":                               "annotation",
+		"sidecar.istio.io/inject":            "true",
+		"sidecar.istio.io/status":            "injected",
+		HostAnnotationsAnnotation:            "sidecar.istio.io/status",
+		HostInjectedContainersAnnotation:     "istio-proxy",
+		HostInjectedInitContainersAnnotation: "istio-init",
+		HostInjectedVolumesAnnotation:        "istio-envoy",
+		HostResourcesAnnotation:              `{"app":{"requests":{"cpu":"100m"}}}`,
+	})
+
+	// condition keeps its transition time
+	conditions := SetHostMutationsCondition(nil, nil, mutations)
+	assert.Equal(t, len(conditions), 1)
+	assert.Equal(t, conditions[0].Message, "Host pod was mutated, injected containers: istio-proxy; injected init containers: istio-init; injected volumes: istio-envoy; changed resources of: app")
+	oldConditions := []corev1.PodCondition{{Type: HostMutationsConditionType, Status: corev1.ConditionTrue, LastTransitionTime: metav1.Unix(10, 0)}}
+	conditions = SetHostMutationsCondition(conditions, oldConditions, mutations)
+	assert.Equal(t, len(conditions), 1)
+	assert.Equal(t, conditions[0].LastTransitionTime, metav1.Unix(10, 0))
+
+	// nothing mutated removes the condition and annotations
+	vPod.Annotations = annotations
+	mutations = CalcHostMutations(vPod, vPod, nil)
+	assert.Assert(t, mutations.Empty())
+	assert.Equal(t, len(SetHostMutationsCondition(conditions, conditions, mutations)), 0)
+	assert.DeepEqual(t, HostMutationsAnnotations(vPod, mutations), map[string]string{"user": "annotation", "sidecar.istio.io/inject": "true"})
+
+	// user annotations matching a pattern stay user owned, so later changes are still synced down
+	vPod.Annotations = annotations
+	pPod.Annotations["sidecar.istio.io/status"] = "updated"
+	mutations = CalcHostMutations(vPod, pPod, []string{"sidecar.istio.io/*"})
+	assert.DeepEqual(t, mutations.Annotations, map[string]string{"sidecar.istio.io/status": "updated"})
+	assert.Equal(t, HostMutationsAnnotations(vPod, mutations)[HostAnnotationsAnnotation], "sidecar.istio.io/status")
+}
+
+func TestCalcContainerImageDiffKeepsInjectedContainers(t *testing.T) {
+	imageTranslator, err := NewImageTranslator(nil)
+	assert.NilError(t, err)
+
+	pContainers := []corev1.Container{{Name: "app", Image: "nginx:1"}, {Name: "istio-proxy", Image: "proxyv2"}}
+	vContainers := []corev1.Container{{Name: "app", Image: "nginx:2"}}
+	assert.DeepEqual(t, calcContainerImageDiff(pContainers, vContainers, imageTranslator, nil), []corev1.Container{
+		{Name: "app", Image: "nginx:2"},
+		{Name: "istio-proxy", Image: "proxyv2"},
+	})
+}
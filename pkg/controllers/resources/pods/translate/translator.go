@@ -15,6 +15,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/priorityclasses"
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	"github.com/loft-sh/vcluster/pkg/util/noderules"
 	"github.com/loft-sh/vcluster/pkg/util/random"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	"github.com/pkg/errors"
@@ -68,6 +69,11 @@ func NewTranslator(ctx *synccontext.RegisterContext, eventRecorder record.EventR
 	virtualLogsPath := path.Join(virtualPath, "log")
 	virtualKubeletPath := path.Join(virtualPath, "kubelet")
 
+	nodeRules, err := noderules.New(ctx.Config.Sync.FromHost.Nodes)
+	if err != nil {
+		return nil, err
+	}
+
 	return &translator{
 		vClientConfig: ctx.VirtualManager.GetConfig(),
 		vClient:       ctx.VirtualManager.GetClient(),
@@ -88,6 +94,7 @@ func NewTranslator(ctx *synccontext.RegisterContext, eventRecorder record.EventR
 		priorityClassesEnabled:       ctx.Config.Sync.ToHost.PriorityClasses.Enabled,
 		enableScheduler:              ctx.Config.ControlPlane.Advanced.VirtualScheduler.Enabled,
 		syncedLabels:                 ctx.Config.Experimental.SyncSettings.SyncLabels,
+		nodeRules:                    nodeRules,
 
 		mountPhysicalHostPaths: ctx.Config.ControlPlane.HostPathMapper.Enabled && !ctx.Config.ControlPlane.HostPathMapper.Central,
 
@@ -119,6 +126,7 @@ type translator struct {
 	priorityClassesEnabled       bool
 	enableScheduler              bool
 	syncedLabels                 []string
+	nodeRules                    *noderules.Rules
 
 	virtualLogsPath       string
 	virtualPodLogsPath    string
@@ -322,6 +330,9 @@ func (t *translator) Translate(ctx context.Context, vPod *corev1.Pod, services [
 			}
 			pPod.Spec.NodeSelector[k] = v
 		}
+
+		// translate node selector and node affinity to the host node labels
+		pPod.Spec.NodeSelector, pPod.Spec.Affinity = t.nodeRules.TranslatePodNodeSelection(pPod.Spec.NodeSelector, pPod.Spec.Affinity)
 	}
 
 	return pPod, nil
@@ -61,7 +61,9 @@ func (s *serviceSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Object, v
 	newService := s.translateUpdateBackwards(pService, vService)
 	if newService != nil {
 		if vService.Spec.ClusterIP != pService.Spec.ClusterIP {
-			newService.Spec.ClusterIPs = nil
+			newService.Spec.ClusterIPs = pService.Spec.ClusterIPs
+			newService.Spec.IPFamilies = pService.Spec.IPFamilies
+			newService.Spec.IPFamilyPolicy = pService.Spec.IPFamilyPolicy
 			ctx.Log.Infof("recreating virtual service %s/%s, because cluster ip differs %s != %s", vService.Namespace, vService.Name, pService.Spec.ClusterIP, vService.Spec.ClusterIP)
 
 			// recreate the new service with the correct cluster ip
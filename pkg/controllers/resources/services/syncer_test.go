@@ -576,3 +576,43 @@ func TestSync(t *testing.T) {
 		},
 	})
 }
+
+func TestTranslateIPFamilies(t *testing.T) {
+	preferDualStack := corev1.IPFamilyPolicyPreferDualStack
+	requireDualStack := corev1.IPFamilyPolicyRequireDualStack
+	singleStack := corev1.IPFamilyPolicySingleStack
+
+	// single stack services get the host default
+	vService := &corev1.Service{Spec: corev1.ServiceSpec{IPFamilyPolicy: &singleStack, IPFamilies: []corev1.IPFamily{corev1.IPv4Protocol}}}
+	pService := &corev1.Service{}
+	translateIPFamilies(vService, pService)
+	assert.Assert(t, pService.Spec.IPFamilyPolicy == nil)
+	assert.Assert(t, pService.Spec.IPFamilies == nil)
+
+	// prefer dual stack lets the host decide about the families
+	vService = &corev1.Service{Spec: corev1.ServiceSpec{IPFamilyPolicy: &preferDualStack, IPFamilies: []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol}}}
+	pService = &corev1.Service{}
+	translateIPFamilies(vService, pService)
+	assert.Equal(t, *pService.Spec.IPFamilyPolicy, preferDualStack)
+	assert.Assert(t, pService.Spec.IPFamilies == nil)
+
+	// require dual stack keeps the family order
+	vService = &corev1.Service{Spec: corev1.ServiceSpec{IPFamilyPolicy: &requireDualStack, IPFamilies: []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol}}}
+	pService = &corev1.Service{}
+	translateIPFamilies(vService, pService)
+	assert.Equal(t, *pService.Spec.IPFamilyPolicy, requireDualStack)
+	assert.DeepEqual(t, pService.Spec.IPFamilies, []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol})
+
+	// secondary cluster ips are synced back
+	s := &serviceSyncer{}
+	vService = &corev1.Service{Spec: corev1.ServiceSpec{ClusterIP: "fd00::10", ClusterIPs: []string{"fd00::10"}, IPFamilyPolicy: &preferDualStack}}
+	pService = &corev1.Service{Spec: corev1.ServiceSpec{ClusterIP: "fd00::10", ClusterIPs: []string{"fd00::10", "10.96.0.10"}, IPFamilyPolicy: &preferDualStack, IPFamilies: []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol}}}
+	updated := s.translateUpdateBackwards(pService, vService)
+	assert.Assert(t, updated == nil, "single stack virtual clusters keep the primary cluster ip only")
+
+	vService.Spec.IPFamilyPolicy = &requireDualStack
+	updated = s.translateUpdateBackwards(pService, vService)
+	assert.Assert(t, updated != nil)
+	assert.DeepEqual(t, updated.Spec.ClusterIPs, []string{"fd00::10", "10.96.0.10"})
+	assert.DeepEqual(t, updated.Spec.IPFamilies, []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol})
+}
@@ -17,20 +17,42 @@ func (s *serviceSyncer) translate(ctx context.Context, vObj *corev1.Service) *co
 	}
 	newService.Spec.ClusterIPs = nil
 
-	// this rarely happens, but if services are created in the virtual
-	// cluster directly circumventing the vcluster proxy, this needs to
-	// be done as creating a service purely inside the
-	// virtual cluster can cause a RequireDualStack ipFamily that
-	// might not be supported in the host cluster, so we let the
-	// host cluster decide for itself what ip family and policy
-	// to use here.
-	newService.Spec.IPFamilies = nil
-	newService.Spec.IPFamilyPolicy = nil
+	translateIPFamilies(vObj, newService)
 
 	StripNodePorts(newService)
 	return newService
 }
 
+// translateIPFamilies only passes the ip family configuration to the host if the virtual service explicitly asks for
+// dual-stack. Single-stack services get the host default family, because creating a service purely inside the virtual
+// cluster, circumventing the vcluster proxy, defaults it to the first virtual service cidr, which might not be
+// supported in the host cluster.
+func translateIPFamilies(vObj, pObj *corev1.Service) {
+	if !isDualStack(vObj) {
+		pObj.Spec.IPFamilies = nil
+		pObj.Spec.IPFamilyPolicy = nil
+		return
+	}
+
+	pObj.Spec.IPFamilyPolicy = vObj.Spec.IPFamilyPolicy
+	pObj.Spec.IPFamilies = vObj.Spec.IPFamilies
+	if *vObj.Spec.IPFamilyPolicy == corev1.IPFamilyPolicyPreferDualStack {
+		// let the host decide which families it supports
+		pObj.Spec.IPFamilies = nil
+	}
+}
+
+// isVirtualSingleStack returns true if the virtual cluster itself only supports a single ip family. This is the case
+// if a service that prefers dual-stack only got a single cluster ip, while the host assigned more.
+func isVirtualSingleStack(pObj, vObj *corev1.Service) bool {
+	return vObj.Spec.IPFamilyPolicy != nil && *vObj.Spec.IPFamilyPolicy == corev1.IPFamilyPolicyPreferDualStack &&
+		len(vObj.Spec.ClusterIPs) == 1 && len(pObj.Spec.ClusterIPs) > 1 && vObj.Spec.ClusterIPs[0] == pObj.Spec.ClusterIPs[0]
+}
+
+func isDualStack(service *corev1.Service) bool {
+	return service.Spec.IPFamilyPolicy != nil && (*service.Spec.IPFamilyPolicy == corev1.IPFamilyPolicyPreferDualStack || *service.Spec.IPFamilyPolicy == corev1.IPFamilyPolicyRequireDualStack)
+}
+
 func StripNodePorts(vObj *corev1.Service) {
 	for i := range vObj.Spec.Ports {
 		vObj.Spec.Ports[i].NodePort = 0
@@ -57,6 +79,14 @@ func (s *serviceSyncer) translateUpdateBackwards(pObj, vObj *corev1.Service) *co
 		updated.Spec.ClusterIP = pObj.Spec.ClusterIP
 	}
 
+	// the host allocates the cluster ips of all families, so the virtual service has to use the same ones
+	if pObj.Spec.ClusterIP != corev1.ClusterIPNone && len(pObj.Spec.ClusterIPs) > 0 && !equality.Semantic.DeepEqual(vObj.Spec.ClusterIPs, pObj.Spec.ClusterIPs) && !isVirtualSingleStack(pObj, vObj) {
+		updated = translator.NewIfNil(updated, vObj)
+		updated.Spec.ClusterIPs = pObj.Spec.ClusterIPs
+		updated.Spec.IPFamilies = pObj.Spec.IPFamilies
+		updated.Spec.IPFamilyPolicy = pObj.Spec.IPFamilyPolicy
+	}
+
 	if !equality.Semantic.DeepEqual(vObj.Spec.ExternalIPs, pObj.Spec.ExternalIPs) {
 		updated = translator.NewIfNil(updated, vObj)
 		updated.Spec.ExternalIPs = pObj.Spec.ExternalIPs
@@ -124,6 +154,12 @@ func (s *serviceSyncer) translateUpdate(ctx context.Context, pObj, vObj *corev1.
 		updated.Spec.ExternalTrafficPolicy = vObj.Spec.ExternalTrafficPolicy
 	}
 
+	// ip family policy, the host might upgrade a service to dual-stack, but it won't downgrade it again
+	if isDualStack(vObj) && !equality.Semantic.DeepEqual(vObj.Spec.IPFamilyPolicy, pObj.Spec.IPFamilyPolicy) {
+		updated = translator.NewIfNil(updated, pObj)
+		updated.Spec.IPFamilyPolicy = vObj.Spec.IPFamilyPolicy
+	}
+
 	// session affinity
 	if vObj.Spec.SessionAffinity != pObj.Spec.SessionAffinity {
 		updated = translator.NewIfNil(updated, pObj)
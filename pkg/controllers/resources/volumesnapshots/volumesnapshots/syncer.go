@@ -1,6 +1,7 @@
 package volumesnapshots
 
 import (
+	vclusterconfig "github.com/loft-sh/vcluster/config"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 
 	"github.com/loft-sh/vcluster/pkg/controllers/syncer/translator"
@@ -27,12 +28,14 @@ func New(ctx *synccontext.RegisterContext) (syncer.Object, error) {
 	return &volumeSnapshotSyncer{
 		NamespacedTranslator:                translator.NewNamespacedTranslator(ctx, "volume-snapshot", &volumesnapshotv1.VolumeSnapshot{}),
 		volumeSnapshotContentNameTranslator: volumesnapshotcontents.NewVolumeSnapshotContentTranslator(),
+		volumeSnapshotClassMapping:          ctx.Config.Sync.ToHost.VolumeSnapshots.VolumeSnapshotClassMapping,
 	}, nil
 }
 
 type volumeSnapshotSyncer struct {
 	translator.NamespacedTranslator
 	volumeSnapshotContentNameTranslator translate.PhysicalNameTranslator
+	volumeSnapshotClassMapping          vclusterconfig.ClassMapping
 }
 
 var _ syncer.Initializer = &volumeSnapshotSyncer{}
@@ -57,6 +60,7 @@ func (s *volumeSnapshotSyncer) SyncToHost(ctx *synccontext.SyncContext, vObj cli
 
 	pObj, err := s.translate(ctx, vVS)
 	if err != nil {
+		s.EventRecorder().Event(vVS, "Warning", "SyncError", err.Error())
 		return ctrl.Result{}, err
 	}
 
@@ -148,8 +152,11 @@ func (s *volumeSnapshotSyncer) Sync(ctx *synccontext.SyncContext, pObj client.Ob
 	}
 
 	// forward update
-	updated = s.translateUpdate(ctx.Context, pVS, vVS)
-	if updated != nil {
+	updated, err := s.translateUpdate(ctx.Context, pVS, vVS)
+	if err != nil {
+		s.EventRecorder().Event(vVS, "Warning", "SyncError", err.Error())
+		return ctrl.Result{}, err
+	} else if updated != nil {
 		translator.PrintChanges(pVS, updated, ctx.Log)
 	}
 
@@ -33,17 +33,38 @@ func (s *volumeSnapshotSyncer) translate(ctx *synccontext.SyncContext, vVS *volu
 		}
 	}
 
-	pVS.Spec.VolumeSnapshotClassName = vVS.Spec.VolumeSnapshotClassName
+	volumeSnapshotClassName, err := s.translateVolumeSnapshotClassName(vVS)
+	if err != nil {
+		return nil, err
+	}
+
+	pVS.Spec.VolumeSnapshotClassName = volumeSnapshotClassName
 	return pVS, nil
 }
 
-func (s *volumeSnapshotSyncer) translateUpdate(ctx context.Context, pVS, vVS *volumesnapshotv1.VolumeSnapshot) *volumesnapshotv1.VolumeSnapshot {
+// translateVolumeSnapshotClassName maps the volume snapshot class with the configured mapping and passes it through otherwise
+func (s *volumeSnapshotSyncer) translateVolumeSnapshotClassName(vVS *volumesnapshotv1.VolumeSnapshot) (*string, error) {
+	mappedVolumeSnapshotClassName, mapped, err := translate.TranslateClassName(s.volumeSnapshotClassMapping, vVS.Spec.VolumeSnapshotClassName)
+	if err != nil {
+		return nil, fmt.Errorf("volume snapshot class: %w", err)
+	} else if mapped {
+		return &mappedVolumeSnapshotClassName, nil
+	}
+
+	return vVS.Spec.VolumeSnapshotClassName, nil
+}
+
+func (s *volumeSnapshotSyncer) translateUpdate(ctx context.Context, pVS, vVS *volumesnapshotv1.VolumeSnapshot) (*volumesnapshotv1.VolumeSnapshot, error) {
 	var updated *volumesnapshotv1.VolumeSnapshot
 
 	// snapshot class can be updated
-	if !equality.Semantic.DeepEqual(pVS.Spec.VolumeSnapshotClassName, vVS.Spec.VolumeSnapshotClassName) {
+	volumeSnapshotClassName, err := s.translateVolumeSnapshotClassName(vVS)
+	if err != nil {
+		return nil, err
+	}
+	if !equality.Semantic.DeepEqual(pVS.Spec.VolumeSnapshotClassName, volumeSnapshotClassName) {
 		updated = translator.NewIfNil(updated, pVS)
-		updated.Spec.VolumeSnapshotClassName = vVS.Spec.VolumeSnapshotClassName
+		updated.Spec.VolumeSnapshotClassName = volumeSnapshotClassName
 	}
 
 	// check if metadata changed
@@ -54,7 +75,7 @@ func (s *volumeSnapshotSyncer) translateUpdate(ctx context.Context, pVS, vVS *vo
 		updated.Labels = updatedLabels
 	}
 
-	return updated
+	return updated, nil
 }
 
 func (s *volumeSnapshotSyncer) translateUpdateBackwards(pObj, vObj *volumesnapshotv1.VolumeSnapshot) *volumesnapshotv1.VolumeSnapshot {
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/loft-sh/vcluster/pkg/config"
+	"github.com/loft-sh/vcluster/pkg/sharding"
 	"github.com/loft-sh/vcluster/pkg/util/loghelper"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -30,6 +31,9 @@ type RegisterContext struct {
 
 	VirtualManager  ctrl.Manager
 	PhysicalManager ctrl.Manager
+
+	// Sharding distributes the sync work across replicas, nil if sharding is disabled
+	Sharding *sharding.Manager
 }
 
 func ConvertContext(registerContext *RegisterContext, logName string) *SyncContext {
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/sharding"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
@@ -13,6 +14,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	controller2 "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 )
 
 func RegisterFakeSyncer(ctx *synccontext.RegisterContext, syncer syncertypes.FakeSyncer) error {
@@ -25,6 +27,7 @@ func RegisterFakeSyncer(ctx *synccontext.RegisterContext, syncer syncertypes.Fak
 		currentNamespaceClient: ctx.CurrentNamespaceClient,
 
 		virtualClient: ctx.VirtualManager.GetClient(),
+		sharding:      ctx.Sharding,
 	}
 
 	return controller.Register(ctx)
@@ -40,9 +43,15 @@ type fakeSyncer struct {
 	currentNamespaceClient client.Client
 
 	virtualClient client.Client
+	sharding      *sharding.Manager
 }
 
 func (r *fakeSyncer) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	// skip objects of shards that are owned by another replica
+	if !r.sharding.Owns(r.syncer.Name(), req.Namespace) {
+		return ctrl.Result{}, nil
+	}
+
 	log := loghelper.NewFromExisting(r.log.Base(), req.Name)
 	syncContext := &synccontext.SyncContext{
 		Context:                ctx,
@@ -91,6 +100,8 @@ func (r *fakeSyncer) Register(ctx *synccontext.RegisterContext) error {
 		}).
 		Named(r.syncer.Name()).
 		For(r.syncer.Resource())
+	controller = watchShards(ctx, controller, r.syncer, r.virtualClient, &handler.EnqueueRequestForObject{})
+
 	var err error
 	modifier, ok := r.syncer.(syncertypes.ControllerModifier)
 	if ok {
@@ -0,0 +1,91 @@
+package syncer
+
+import (
+	"fmt"
+
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	syncertypes "github.com/loft-sh/vcluster/pkg/types"
+	"github.com/loft-sh/vcluster/pkg/util/loghelper"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// watchShards requeues all virtual objects of a shard when this replica acquires it, as the events of these objects
+// were skipped while the shard was owned by another replica.
+func watchShards(ctx *synccontext.RegisterContext, controller *builder.Builder, syncer syncertypes.Object, virtualClient client.Client, eventHandler handler.EventHandler) *builder.Builder {
+	if ctx.Sharding == nil {
+		return controller
+	}
+
+	requeue := make(chan event.GenericEvent)
+	ctx.Sharding.OnAcquire(func(shard int) {
+		go requeueShard(ctx, syncer, virtualClient, requeue, shard)
+	})
+
+	return controller.WatchesRawSource(&source.Channel{Source: requeue}, eventHandler)
+}
+
+func requeueShard(ctx *synccontext.RegisterContext, syncer syncertypes.Object, virtualClient client.Client, requeue chan<- event.GenericEvent, shard int) {
+	list, err := newList(syncer.Resource(), virtualClient)
+	if err != nil {
+		loghelper.New(syncer.Name()).Errorf("error requeuing shard %d: %v", shard, err)
+		return
+	}
+
+	err = virtualClient.List(ctx.Context, list)
+	if err != nil {
+		loghelper.New(syncer.Name()).Errorf("error requeuing shard %d: list virtual objects: %v", shard, err)
+		return
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		loghelper.New(syncer.Name()).Errorf("error requeuing shard %d: %v", shard, err)
+		return
+	}
+
+	for _, item := range items {
+		obj, ok := item.(client.Object)
+		if !ok || ctx.Sharding.ShardFor(syncer.Name(), obj.GetNamespace()) != shard {
+			continue
+		}
+
+		select {
+		case requeue <- event.GenericEvent{Object: obj}:
+		case <-ctx.Context.Done():
+			return
+		}
+	}
+}
+
+func newList(obj client.Object, c client.Client) (client.ObjectList, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return nil, err
+	}
+
+	listGVK := gvk.GroupVersion().WithKind(gvk.Kind + "List")
+	if _, ok := obj.(*unstructured.Unstructured); ok {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(listGVK)
+		return list, nil
+	}
+
+	list, err := c.Scheme().New(listGVK)
+	if err != nil {
+		return nil, err
+	}
+
+	objList, ok := list.(client.ObjectList)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a list", listGVK.String())
+	}
+
+	return objList, nil
+}
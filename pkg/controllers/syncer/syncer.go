@@ -7,8 +7,11 @@ import (
 	"time"
 
 	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/sharding"
+	"github.com/loft-sh/vcluster/pkg/tracing"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	"github.com/moby/locker"
+	"go.opentelemetry.io/otel/attribute"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
@@ -39,13 +42,14 @@ func NewSyncController(ctx *synccontext.RegisterContext, syncer syncertypes.Sync
 		syncer:         syncer,
 		log:            loghelper.New(syncer.Name()),
 		vEventRecorder: ctx.VirtualManager.GetEventRecorderFor(syncer.Name() + "-syncer"),
-		physicalClient: ctx.PhysicalManager.GetClient(),
+		physicalClient: tracing.WrapClient(ctx.PhysicalManager.GetClient(), tracing.ClusterHost),
 
 		currentNamespace:       ctx.CurrentNamespace,
 		currentNamespaceClient: ctx.CurrentNamespaceClient,
 
-		virtualClient: ctx.VirtualManager.GetClient(),
+		virtualClient: tracing.WrapClient(ctx.VirtualManager.GetClient(), tracing.ClusterVirtual),
 		options:       options,
+		sharding:      ctx.Sharding,
 
 		locker: locker.New(),
 	}
@@ -68,11 +72,21 @@ type SyncController struct {
 
 	virtualClient client.Client
 	options       *syncertypes.Options
+	sharding      *sharding.Manager
 
 	locker *locker.Locker
 }
 
 func (r *SyncController) Reconcile(ctx context.Context, origReq ctrl.Request) (_ ctrl.Result, err error) {
+	// trace the complete reconcile
+	ctx, span := tracing.StartSpan(ctx, r.syncer.Name()+" Reconcile",
+		attribute.String("vcluster.syncer", r.syncer.Name()),
+		attribute.String("vcluster.request", origReq.String()),
+	)
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	// if host request we need to find the virtual object
 	vReq, pReq, err := r.extractRequest(ctx, origReq)
 	if err != nil {
@@ -81,6 +95,11 @@ func (r *SyncController) Reconcile(ctx context.Context, origReq ctrl.Request) (_
 		return ctrl.Result{}, nil
 	}
 
+	// skip objects of shards that are owned by another replica
+	if !r.sharding.Owns(r.syncer.Name(), vReq.Namespace) {
+		return ctrl.Result{}, nil
+	}
+
 	// block for virtual object here because we want to avoid
 	// reconciling on the same object in parallel as this could
 	// happen if a host event and virtual event are queued at the
@@ -119,7 +138,9 @@ func (r *SyncController) Reconcile(ctx context.Context, origReq ctrl.Request) (_
 
 	// check what function we should call
 	if vObj != nil && pObj == nil {
-		return r.syncer.SyncToHost(syncContext, vObj)
+		return r.traceSync(syncContext, "SyncToHost", func() (ctrl.Result, error) {
+			return r.syncer.SyncToHost(syncContext, vObj)
+		})
 	} else if vObj != nil && pObj != nil {
 		// make sure the object uid matches
 		pAnnotations := pObj.GetAnnotations()
@@ -133,7 +154,9 @@ func (r *SyncController) Reconcile(ctx context.Context, origReq ctrl.Request) (_
 			return DeleteObject(syncContext, pObj, "virtual object uid is different")
 		}
 
-		return r.syncer.Sync(syncContext, pObj, vObj)
+		return r.traceSync(syncContext, "Sync", func() (ctrl.Result, error) {
+			return r.syncer.Sync(syncContext, pObj, vObj)
+		})
 	} else if vObj == nil && pObj != nil {
 		if pObj.GetAnnotations() != nil {
 			if shouldSkip, ok := pObj.GetAnnotations()[translate.SkipBackSyncInMultiNamespaceMode]; ok && shouldSkip == "true" {
@@ -145,7 +168,9 @@ func (r *SyncController) Reconcile(ctx context.Context, origReq ctrl.Request) (_
 		// check if virtual syncer
 		toVirtual, ok := r.syncer.(syncertypes.ToVirtualSyncer)
 		if ok {
-			return toVirtual.SyncToVirtual(syncContext, pObj)
+			return r.traceSync(syncContext, "SyncToVirtual", func() (ctrl.Result, error) {
+				return toVirtual.SyncToVirtual(syncContext, pObj)
+			})
 		}
 
 		return DeleteObject(syncContext, pObj, "virtual object was deleted")
@@ -154,6 +179,37 @@ func (r *SyncController) Reconcile(ctx context.Context, origReq ctrl.Request) (_
 	return ctrl.Result{}, nil
 }
 
+// traceSync records a span for the given sync function of the syncer. The sync context will carry the
+// span so that translator and client calls within the sync function are recorded as its children.
+func (r *SyncController) traceSync(ctx *synccontext.SyncContext, name string, sync func() (ctrl.Result, error)) (result ctrl.Result, err error) {
+	spanCtx, span := tracing.StartSpan(ctx.Context, r.syncer.Name()+" "+name, attribute.String("vcluster.syncer", r.syncer.Name()))
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	originalCtx := ctx.Context
+	ctx.Context = spanCtx
+	defer func() {
+		ctx.Context = originalCtx
+	}()
+
+	return sync()
+}
+
+func (r *SyncController) hostToVirtual(ctx context.Context, req types.NamespacedName, pObj client.Object) types.NamespacedName {
+	ctx, span := tracing.StartSpan(ctx, r.syncer.Name()+" HostToVirtual", attribute.String("vcluster.syncer", r.syncer.Name()))
+	defer span.End()
+
+	return r.syncer.HostToVirtual(ctx, req, pObj)
+}
+
+func (r *SyncController) virtualToHost(ctx context.Context, req types.NamespacedName, vObj client.Object) types.NamespacedName {
+	ctx, span := tracing.StartSpan(ctx, r.syncer.Name()+" VirtualToHost", attribute.String("vcluster.syncer", r.syncer.Name()))
+	defer span.End()
+
+	return r.syncer.VirtualToHost(ctx, req, vObj)
+}
+
 func (r *SyncController) getObjects(ctx *synccontext.SyncContext, vReq, pReq ctrl.Request) (vObj client.Object, pObj client.Object, err error) {
 	// if we got a host request, we retrieve host object first
 	if pReq.Name != "" {
@@ -174,7 +230,7 @@ func (r *SyncController) getObjectsFromPhysical(ctx *synccontext.SyncContext, re
 	}
 
 	// get virtual object
-	exclude, vObj, err = r.getVirtualObject(ctx.Context, r.syncer.HostToVirtual(ctx.Context, req.NamespacedName, pObj))
+	exclude, vObj, err = r.getVirtualObject(ctx.Context, r.hostToVirtual(ctx.Context, req.NamespacedName, pObj))
 	if err != nil {
 		return nil, nil, err
 	} else if exclude {
@@ -194,7 +250,7 @@ func (r *SyncController) getObjectsFromVirtual(ctx *synccontext.SyncContext, req
 	}
 
 	// get physical object
-	exclude, pObj, err = r.getPhysicalObject(ctx.Context, r.syncer.VirtualToHost(ctx.Context, req.NamespacedName, vObj), vObj)
+	exclude, pObj, err = r.getPhysicalObject(ctx.Context, r.virtualToHost(ctx.Context, req.NamespacedName, vObj), vObj)
 	if err != nil {
 		return nil, nil, err
 	} else if exclude {
@@ -321,7 +377,7 @@ func (r *SyncController) extractRequest(ctx context.Context, req ctrl.Request) (
 		}
 
 		// try to get virtual name from physical
-		req.NamespacedName = r.syncer.HostToVirtual(ctx, pReq.NamespacedName, pObj)
+		req.NamespacedName = r.hostToVirtual(ctx, pReq.NamespacedName, pObj)
 	}
 
 	return req, pReq, nil
@@ -395,6 +451,9 @@ func (r *SyncController) Register(ctx *synccontext.RegisterContext) error {
 		Watches(r.syncer.Resource(), newEventHandler(r.enqueueVirtual)).
 		WatchesRawSource(source.Kind(ctx.PhysicalManager.GetCache(), r.syncer.Resource()), newEventHandler(r.enqueuePhysical))
 
+	// requeue all virtual objects of a shard when this replica acquires it
+	controller = watchShards(ctx, controller, r.syncer, r.virtualClient, newEventHandler(r.enqueueVirtual))
+
 	// should add extra stuff?
 	modifier, isControllerModifier := r.syncer.(syncertypes.ControllerModifier)
 	if isControllerModifier {
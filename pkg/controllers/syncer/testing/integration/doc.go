@@ -0,0 +1,15 @@
+// Package integration runs syncers against a real host and virtual api server.
+//
+// In contrast to the fake clients in the parent package, the syncers are registered through the real
+// SyncController, so watches, indices and ModifyController are exercised and objects are validated by
+// the api server. The api servers are started from envtest binaries, see `just test-integration`.
+// Tests are skipped if the binaries cannot be found.
+//
+//	env := integration.Start(t, nil)
+//	env.Register(t, mysyncer.New)
+//	env.StartManagers(t)
+//	env.CreateVirtualNamespace(t, "test")
+//
+//	// create a virtual object and wait until it was synced
+//	env.EventuallyConverged(t, vObj, pObj, func(vObj, pObj client.Object) bool { ... })
+package integration
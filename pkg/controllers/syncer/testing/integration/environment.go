@@ -0,0 +1,242 @@
+package integration
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/config"
+	"github.com/loft-sh/vcluster/pkg/controllers/syncer"
+	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
+	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
+	"github.com/loft-sh/vcluster/pkg/scheme"
+	syncertypes "github.com/loft-sh/vcluster/pkg/types"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+const (
+	// DefaultTimeout is the time the Eventually helpers wait for objects to converge
+	DefaultTimeout = time.Second * 30
+
+	kubebuilderAssetsEnv     = "KUBEBUILDER_ASSETS"
+	defaultKubebuilderAssets = "/usr/local/kubebuilder/bin"
+)
+
+// Options configure the integration environment
+type Options struct {
+	// Config is the vCluster config the syncers are created with, defaults to the chart values
+	Config *config.VirtualClusterConfig
+
+	// Scheme is used for the clients and managers, defaults to the vCluster scheme
+	Scheme *runtime.Scheme
+
+	// HostCRDDirectoryPaths are installed into the host api server
+	HostCRDDirectoryPaths []string
+	// VirtualCRDDirectoryPaths are installed into the virtual api server
+	VirtualCRDDirectoryPaths []string
+
+	// Timeout is used by the Eventually helpers, defaults to DefaultTimeout
+	Timeout time.Duration
+}
+
+// Environment runs a host and a virtual api server and registers syncers between them
+// through the real SyncController with cache backed managers.
+type Environment struct {
+	// HostClient and VirtualClient talk directly to the api servers without a cache
+	HostClient    client.Client
+	VirtualClient client.Client
+
+	HostConfig    *rest.Config
+	VirtualConfig *rest.Config
+
+	// RegisterContext is passed to the syncers
+	RegisterContext *synccontext.RegisterContext
+
+	timeout time.Duration
+	started bool
+}
+
+// Start boots the host and virtual api servers and creates the managers. The test is skipped if no
+// envtest binaries are available. Use KUBEBUILDER_ASSETS to point to a directory that contains them.
+// Everything is stopped when the test ends.
+func Start(t *testing.T, options *Options) *Environment {
+	t.Helper()
+	if options == nil {
+		options = &Options{}
+	}
+	if !assetsAvailable() {
+		t.Skipf("envtest binaries not found, set %s to run this test", kubebuilderAssetsEnv)
+	}
+
+	vConfig := options.Config
+	if vConfig == nil {
+		vConfig = generictesting.NewFakeConfig()
+	}
+	s := options.Scheme
+	if s == nil {
+		s = scheme.Scheme
+	}
+	timeout := options.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	hostConfig := startAPIServer(t, "host", s, options.HostCRDDirectoryPaths)
+	virtualConfig := startAPIServer(t, "virtual", s, options.VirtualCRDDirectoryPaths)
+
+	hostClient, err := client.New(hostConfig, client.Options{Scheme: s})
+	assert.NilError(t, err)
+	virtualClient, err := client.New(virtualConfig, client.Options{Scheme: s})
+	assert.NilError(t, err)
+
+	// prepare host namespaces the same way the syncer setup does
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	var defaultNamespaces map[string]cache.Config
+	if vConfig.Experimental.MultiNamespaceMode.Enabled {
+		translate.Default, err = translate.NewMultiNamespaceTranslatorWithOptions(generictesting.DefaultTestCurrentNamespace, vConfig.Experimental.MultiNamespaceMode)
+		assert.NilError(t, err)
+	} else {
+		translate.Default = translate.NewSingleNamespaceTranslator(vConfig.TargetNamespace)
+		defaultNamespaces = map[string]cache.Config{vConfig.TargetNamespace: {}}
+		ensureNamespace(ctx, t, hostClient, vConfig.TargetNamespace)
+	}
+	ensureNamespace(ctx, t, hostClient, generictesting.DefaultTestCurrentNamespace)
+
+	hostManager, err := ctrl.NewManager(hostConfig, ctrl.Options{
+		Scheme:         s,
+		Metrics:        metricsserver.Options{BindAddress: "0"},
+		LeaderElection: false,
+		Cache:          cache.Options{DefaultNamespaces: defaultNamespaces},
+	})
+	assert.NilError(t, err)
+	virtualManager, err := ctrl.NewManager(virtualConfig, ctrl.Options{
+		Scheme:         s,
+		Metrics:        metricsserver.Options{BindAddress: "0"},
+		LeaderElection: false,
+	})
+	assert.NilError(t, err)
+
+	return &Environment{
+		HostClient:    hostClient,
+		VirtualClient: virtualClient,
+		HostConfig:    hostConfig,
+		VirtualConfig: virtualConfig,
+		RegisterContext: &synccontext.RegisterContext{
+			Context:                ctx,
+			Config:                 vConfig,
+			CurrentNamespace:       generictesting.DefaultTestCurrentNamespace,
+			CurrentNamespaceClient: hostClient,
+			VirtualManager:         virtualManager,
+			PhysicalManager:        hostManager,
+		},
+		timeout: timeout,
+	}
+}
+
+// Register creates the syncer, registers its indices and starts it through the SyncController.
+// Syncers need to be registered before the environment is started.
+func (e *Environment) Register(t *testing.T, create func(ctx *synccontext.RegisterContext) (syncertypes.Object, error)) syncertypes.Object {
+	t.Helper()
+	if e.started {
+		t.Fatal("syncers need to be registered before the environment is started")
+	}
+
+	object, err := create(e.RegisterContext)
+	assert.NilError(t, err)
+
+	registerer, ok := object.(syncertypes.IndicesRegisterer)
+	if ok {
+		assert.NilError(t, registerer.RegisterIndices(e.RegisterContext))
+	}
+
+	switch s := object.(type) {
+	case syncertypes.FakeSyncer:
+		assert.NilError(t, syncer.RegisterFakeSyncer(e.RegisterContext, s))
+	case syncertypes.Syncer:
+		assert.NilError(t, syncer.RegisterSyncer(e.RegisterContext, s))
+	default:
+		t.Fatalf("syncer %s does not implement fake syncer or syncer interface", object.Name())
+	}
+
+	return object
+}
+
+// StartManagers starts the host and virtual managers and waits for their caches to sync
+func (e *Environment) StartManagers(t *testing.T) {
+	t.Helper()
+	e.started = true
+	for _, m := range []ctrl.Manager{e.RegisterContext.PhysicalManager, e.RegisterContext.VirtualManager} {
+		m := m
+		go func() {
+			err := m.Start(e.RegisterContext.Context)
+			if err != nil {
+				t.Errorf("start manager: %v", err)
+			}
+		}()
+	}
+
+	waitCtx, cancel := context.WithTimeout(e.RegisterContext.Context, e.timeout)
+	defer cancel()
+	assert.Assert(t, e.RegisterContext.PhysicalManager.GetCache().WaitForCacheSync(waitCtx), "host cache did not sync")
+	assert.Assert(t, e.RegisterContext.VirtualManager.GetCache().WaitForCacheSync(waitCtx), "virtual cache did not sync")
+}
+
+// CreateVirtualNamespace creates a namespace in the virtual cluster
+func (e *Environment) CreateVirtualNamespace(t *testing.T, name string) {
+	t.Helper()
+	ensureNamespace(e.RegisterContext.Context, t, e.VirtualClient, name)
+}
+
+func ensureNamespace(ctx context.Context, t *testing.T, c client.Client, name string) {
+	t.Helper()
+	err := c.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		t.Fatalf("create namespace %s: %v", name, err)
+	}
+}
+
+func startAPIServer(t *testing.T, name string, s *runtime.Scheme, crdPaths []string) *rest.Config {
+	t.Helper()
+	testEnv := &envtest.Environment{
+		Scheme:                s,
+		CRDDirectoryPaths:     crdPaths,
+		ErrorIfCRDPathMissing: len(crdPaths) > 0,
+	}
+
+	restConfig, err := testEnv.Start()
+	if err != nil {
+		t.Fatalf("start %s api server: %v", name, err)
+	}
+	t.Cleanup(func() {
+		err := testEnv.Stop()
+		if err != nil {
+			t.Logf("stop %s api server: %v", name, err)
+		}
+	})
+
+	return restConfig
+}
+
+func assetsAvailable() bool {
+	assets := os.Getenv(kubebuilderAssetsEnv)
+	if assets == "" {
+		assets = defaultKubebuilderAssets
+	}
+
+	_, err := os.Stat(filepath.Join(assets, "kube-apiserver"))
+	return err == nil
+}
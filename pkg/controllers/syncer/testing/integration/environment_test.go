@@ -0,0 +1,64 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/configmaps"
+	generictesting "github.com/loft-sh/vcluster/pkg/controllers/syncer/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestConfigMapRoundTrip(t *testing.T) {
+	vConfig := generictesting.NewFakeConfig()
+	vConfig.Sync.ToHost.ConfigMaps.All = true
+
+	env := Start(t, &Options{Config: vConfig})
+	env.Register(t, configmaps.New)
+	env.StartManagers(t)
+	env.CreateVirtualNamespace(t, "round-trip")
+
+	ctx := env.RegisterContext.Context
+	vConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "round-trip",
+			Labels:    map[string]string{"app": "test"},
+		},
+		Data: map[string]string{"key": "value"},
+	}
+	assert.NilError(t, env.VirtualClient.Create(ctx, vConfigMap))
+
+	// create is synced to the host
+	pConfigMap := &corev1.ConfigMap{}
+	env.EventuallyConverged(t, vConfigMap.DeepCopy(), pConfigMap, func(_, pObj client.Object) bool {
+		return pObj.(*corev1.ConfigMap).Data["key"] == "value"
+	})
+	assert.Equal(t, pConfigMap.Annotations[translate.NameAnnotation], "test")
+	assert.Assert(t, translate.Default.IsManaged(pConfigMap), "host config map should be managed by vcluster")
+
+	// update is synced to the host
+	vConfigMap.Data["key"] = "changed"
+	assert.NilError(t, env.VirtualClient.Update(ctx, vConfigMap))
+	env.EventuallyHost(t, HostName(vConfigMap), &corev1.ConfigMap{}, func(obj client.Object) bool {
+		return obj.(*corev1.ConfigMap).Data["key"] == "changed"
+	})
+
+	// changes on the host are reverted
+	pConfigMap = &corev1.ConfigMap{}
+	assert.NilError(t, env.HostClient.Get(ctx, HostName(vConfigMap), pConfigMap))
+	pConfigMap.Data["key"] = "host"
+	assert.NilError(t, env.HostClient.Update(ctx, pConfigMap))
+	env.EventuallyHost(t, HostName(vConfigMap), &corev1.ConfigMap{}, func(obj client.Object) bool {
+		return obj.(*corev1.ConfigMap).Data["key"] == "changed"
+	})
+
+	// delete is synced to the host
+	assert.NilError(t, env.VirtualClient.Delete(ctx, vConfigMap))
+	env.EventuallyHostGone(t, HostName(vConfigMap), &corev1.ConfigMap{})
+	env.EventuallyVirtualGone(t, types.NamespacedName{Namespace: "round-trip", Name: "test"}, &corev1.ConfigMap{})
+}
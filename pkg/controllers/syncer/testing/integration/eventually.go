@@ -0,0 +1,113 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Condition is checked against the latest state of an object
+type Condition func(obj client.Object) bool
+
+// ConvergedCondition is checked against the latest state of a virtual and its host object
+type ConvergedCondition func(vObj, pObj client.Object) bool
+
+// HostName returns the name the given virtual object is synced to
+func HostName(vObj client.Object) types.NamespacedName {
+	return types.NamespacedName{
+		Name:      translate.Default.PhysicalName(vObj.GetName(), vObj.GetNamespace()),
+		Namespace: translate.Default.PhysicalNamespace(vObj.GetNamespace()),
+	}
+}
+
+// EventuallyHost waits until the host object with the given name exists and matches the condition.
+// The latest state is written into obj.
+func (e *Environment) EventuallyHost(t *testing.T, name types.NamespacedName, obj client.Object, condition Condition) {
+	t.Helper()
+	e.eventually(t, "host", e.HostClient, name, obj, condition)
+}
+
+// EventuallyVirtual waits until the virtual object with the given name exists and matches the condition.
+// The latest state is written into obj.
+func (e *Environment) EventuallyVirtual(t *testing.T, name types.NamespacedName, obj client.Object, condition Condition) {
+	t.Helper()
+	e.eventually(t, "virtual", e.VirtualClient, name, obj, condition)
+}
+
+// EventuallyHostGone waits until the host object with the given name was deleted
+func (e *Environment) EventuallyHostGone(t *testing.T, name types.NamespacedName, obj client.Object) {
+	t.Helper()
+	e.eventuallyGone(t, "host", e.HostClient, name, obj)
+}
+
+// EventuallyVirtualGone waits until the virtual object with the given name was deleted
+func (e *Environment) EventuallyVirtualGone(t *testing.T, name types.NamespacedName, obj client.Object) {
+	t.Helper()
+	e.eventuallyGone(t, "virtual", e.VirtualClient, name, obj)
+}
+
+// EventuallyConverged waits until the virtual object and the host object it is synced to both exist
+// and match the condition. The latest states are written into vObj and pObj.
+func (e *Environment) EventuallyConverged(t *testing.T, vObj, pObj client.Object, condition ConvergedCondition) {
+	t.Helper()
+	vName := types.NamespacedName{Namespace: vObj.GetNamespace(), Name: vObj.GetName()}
+	pName := HostName(vObj)
+
+	var lastErr error
+	err := e.poll(func(ctx context.Context) (bool, error) {
+		lastErr = e.VirtualClient.Get(ctx, vName, vObj)
+		if lastErr != nil {
+			return false, nil
+		}
+		lastErr = e.HostClient.Get(ctx, pName, pObj)
+		if lastErr != nil {
+			return false, nil
+		}
+
+		return condition == nil || condition(vObj, pObj), nil
+	})
+	if err != nil {
+		t.Fatalf("virtual object %s and host object %s did not converge: %v (last error: %v)", vName, pName, err, lastErr)
+	}
+}
+
+func (e *Environment) eventually(t *testing.T, cluster string, c client.Client, name types.NamespacedName, obj client.Object, condition Condition) {
+	t.Helper()
+	var lastErr error
+	err := e.poll(func(ctx context.Context) (bool, error) {
+		lastErr = c.Get(ctx, name, obj)
+		if lastErr != nil {
+			return false, nil
+		}
+
+		return condition == nil || condition(obj), nil
+	})
+	if err != nil {
+		t.Fatalf("%s object %s did not reach expected state: %v (last error: %v)", cluster, name, err, lastErr)
+	}
+}
+
+func (e *Environment) eventuallyGone(t *testing.T, cluster string, c client.Client, name types.NamespacedName, obj client.Object) {
+	t.Helper()
+	err := e.poll(func(ctx context.Context) (bool, error) {
+		err := c.Get(ctx, name, obj)
+		if kerrors.IsNotFound(err) {
+			return true, nil
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		t.Fatalf("%s object %s was not deleted: %v", cluster, name, err)
+	}
+}
+
+func (e *Environment) poll(condition wait.ConditionWithContextFunc) error {
+	return wait.PollUntilContextTimeout(e.RegisterContext.Context, time.Millisecond*250, e.timeout, true, condition)
+}
@@ -15,10 +15,14 @@ import (
 	"github.com/loft-sh/vcluster/pkg/config"
 	"github.com/loft-sh/vcluster/pkg/util/commandwriter"
 	"k8s.io/klog/v2"
+	utilnet "k8s.io/utils/net"
 )
 
 const runDir = "/run/k0s"
-const cidrPlaceholder = "CIDR_PLACEHOLDER"
+const (
+	cidrPlaceholder      = "CIDR_PLACEHOLDER"
+	dualStackPlaceholder = "DUAL_STACK_PLACEHOLDER"
+)
 
 var k0sConfig = `apiVersion: k0s.k0sproject.io/v1beta1
 kind: Cluster
@@ -38,6 +42,7 @@ spec:
     {{- else }}
     # Will be replaced automatically by the syncer container on first startup
     serviceCIDR: CIDR_PLACEHOLDER
+    dualStack: DUAL_STACK_PLACEHOLDER
     {{- end }}
     provider: custom
     {{- if .Values.networking.advanced.clusterDomain }}
@@ -119,6 +124,17 @@ func StartK0S(ctx context.Context, cancel context.CancelFunc, vConfig *config.Vi
 	return nil
 }
 
+// SupportedServiceCIDR returns the service cidrs k0s can be configured with. k0s only supports dual-stack with
+// IPv4 as primary family, so IPv6 first clusters fall back to the IPv6 cidr only.
+func SupportedServiceCIDR(serviceCIDR string) string {
+	serviceCIDRs := strings.Split(serviceCIDR, ",")
+	if len(serviceCIDRs) > 1 && utilnet.IsIPv4CIDRString(serviceCIDRs[0]) && utilnet.IsIPv6CIDRString(serviceCIDRs[1]) {
+		return serviceCIDRs[0] + "," + serviceCIDRs[1]
+	}
+
+	return serviceCIDRs[0]
+}
+
 func WriteK0sConfig(
 	serviceCIDR string,
 	vConfig *config.VirtualClusterConfig,
@@ -136,7 +152,12 @@ func WriteK0sConfig(
 	}
 
 	// apply changes
-	updatedConfig := []byte(strings.ReplaceAll(string(outBytes), cidrPlaceholder, serviceCIDR))
+	serviceCIDRs := strings.Split(serviceCIDR, ",")
+	dualStack := `{"enabled": false}`
+	if len(serviceCIDRs) > 1 {
+		dualStack = fmt.Sprintf(`{"enabled": true, "IPv6serviceCIDR": %q}`, serviceCIDRs[1])
+	}
+	updatedConfig := []byte(strings.ReplaceAll(strings.ReplaceAll(string(outBytes), cidrPlaceholder, serviceCIDRs[0]), dualStackPlaceholder, dualStack))
 
 	// write the config to file
 	err = os.WriteFile("/tmp/k0s-config.yaml", updatedConfig, 0640)
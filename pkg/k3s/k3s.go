@@ -38,6 +38,11 @@ func StartK3S(ctx context.Context, vConfig *config.VirtualClusterConfig, service
 		args = append(args, "--egress-selector-mode=disabled")
 		args = append(args, "--flannel-backend=none")
 		args = append(args, "--kube-apiserver-arg=bind-address=127.0.0.1")
+		if issuer := vConfig.ControlPlane.Advanced.ServiceAccountIssuerDiscovery.Issuer; vConfig.ControlPlane.Advanced.ServiceAccountIssuerDiscovery.Enabled && issuer != "" {
+			// the first issuer is used to sign new tokens, the default issuer of k3s is kept so existing tokens stay valid
+			args = append(args, "--kube-apiserver-arg=service-account-issuer="+issuer)
+			args = append(args, "--kube-apiserver-arg=service-account-issuer=https://kubernetes.default.svc.cluster.local")
+		}
 		if vConfig.ControlPlane.Advanced.VirtualScheduler.Enabled {
 			args = append(args, "--kube-controller-manager-arg=controllers=*,-nodeipam,-persistentvolume-binder,-attachdetach,-persistentvolume-expander,-cloud-node-lifecycle,-ttl")
 			args = append(args, "--kube-apiserver-arg=endpoint-reconciler-type=none")
@@ -61,6 +61,10 @@ func StartK8S(
 				args = append(args, "--requestheader-group-headers=X-Remote-Group")
 				args = append(args, "--requestheader-username-headers=X-Remote-User")
 				args = append(args, "--secure-port=6443")
+				if issuer := vConfig.ControlPlane.Advanced.ServiceAccountIssuerDiscovery.Issuer; vConfig.ControlPlane.Advanced.ServiceAccountIssuerDiscovery.Enabled && issuer != "" {
+					// the first issuer is used to sign new tokens, the default issuer is kept so existing tokens stay valid
+					args = append(args, "--service-account-issuer="+issuer)
+				}
 				args = append(args, "--service-account-issuer=https://kubernetes.default.svc.cluster.local")
 				args = append(args, "--service-account-key-file=/pki/sa.pub")
 				args = append(args, "--service-account-signing-key-file=/pki/sa.key")
@@ -2,17 +2,23 @@ package lifecycle
 
 import (
 	"context"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/loft-sh/log"
 	"github.com/loft-sh/vcluster/pkg/constants"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -93,10 +99,15 @@ func DeleteMultiNamespaceVclusterWorkloads(ctx context.Context, client *kubernet
 	for _, ns := range namespaces.Items {
 		podList, podListErr := client.CoreV1().Pods(ns.Name).List(ctx, metav1.ListOptions{})
 		if podListErr != nil {
-			return errors.Wrapf(err, "error listing pods in namespace %s", ns.Name)
+			return errors.Wrapf(podListErr, "error listing pods in namespace %s", ns.Name)
 		}
 
 		for _, pod := range podList.Items {
+			// adopted namespaces might contain pods that were not created by vCluster
+			if IsAdoptedNamespace(&ns) && pod.Annotations[translate.NameAnnotation] == "" {
+				continue
+			}
+
 			err := client.CoreV1().Pods(ns.Name).Delete(ctx, pod.Name, metav1.DeleteOptions{})
 			if err != nil {
 				return errors.Wrapf(err, "error deleting pod %s/%s", ns.Name, pod.Name)
@@ -107,6 +118,76 @@ func DeleteMultiNamespaceVclusterWorkloads(ctx context.Context, client *kubernet
 	return nil
 }
 
+// IsAdoptedNamespace returns true if the host namespace existed before and was adopted by a multi namespace mode vcluster
+func IsAdoptedNamespace(namespace *corev1.Namespace) bool {
+	return namespace.Labels[translate.AdoptedLabel] == "true"
+}
+
+// ReleaseAdoptedNamespace deletes the objects vCluster synced into an adopted host namespace and removes the
+// vCluster labels and annotations from it, so that the namespace itself can be kept when the vcluster is deleted
+func ReleaseAdoptedNamespace(ctx context.Context, kubeClient kubernetes.Interface, hostClient client.Client, namespace *corev1.Namespace) error {
+	// objects that were synced by vCluster either have the name annotation or the managed-by label of the namespace
+	managedBy := namespace.Labels[translate.MarkerLabel]
+	synced := func(obj *metav1.PartialObjectMetadata) bool {
+		return obj.Annotations[translate.NameAnnotation] != "" || (managedBy != "" && obj.Labels[translate.MarkerLabel] == managedBy)
+	}
+
+	// delete the synced objects of every namespaced kind, as vCluster can sync arbitrary kinds
+	_, resourceLists, err := kubeClient.Discovery().ServerGroupsAndResources()
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return errors.Wrap(err, "discover resources")
+	}
+	seen := map[schema.GroupResource]bool{}
+	for _, resourceList := range resourceLists {
+		groupVersion, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			continue
+		}
+
+		for _, resource := range resourceList.APIResources {
+			groupResource := schema.GroupResource{Group: groupVersion.Group, Resource: resource.Name}
+			if !resource.Namespaced || strings.Contains(resource.Name, "/") || seen[groupResource] || !slices.Contains(resource.Verbs, "list") || !slices.Contains(resource.Verbs, "delete") {
+				continue
+			}
+			seen[groupResource] = true
+
+			list := &metav1.PartialObjectMetadataList{}
+			list.SetGroupVersionKind(groupVersion.WithKind(resource.Kind + "List"))
+			err = hostClient.List(ctx, list, client.InNamespace(namespace.Name))
+			if err != nil {
+				if kerrors.IsNotFound(err) || kerrors.IsMethodNotSupported(err) {
+					continue
+				}
+
+				return errors.Wrapf(err, "list %s", groupResource.String())
+			}
+
+			for i := range list.Items {
+				item := &list.Items[i]
+				if !synced(item) {
+					continue
+				}
+
+				item.SetGroupVersionKind(groupVersion.WithKind(resource.Kind))
+				err = hostClient.Delete(ctx, item, client.PropagationPolicy(metav1.DeletePropagationBackground))
+				if err != nil && !kerrors.IsNotFound(err) {
+					return errors.Wrapf(err, "delete %s %s/%s", groupResource.String(), namespace.Name, item.Name)
+				}
+			}
+		}
+	}
+
+	// remove vcluster metadata from the namespace
+	patch := []byte(`{"metadata":{"labels":{"` + translate.MarkerLabel + `":null,"` + translate.AdoptedLabel + `":null},"annotations":{"` +
+		translate.NameAnnotation + `":null,"` + translate.UIDAnnotation + `":null,"` + translate.SkipBackSyncInMultiNamespaceMode + `":null}}}`)
+	_, err = kubeClient.CoreV1().Namespaces().Patch(ctx, namespace.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrapf(err, "remove vcluster metadata from namespace %s", namespace.Name)
+	}
+
+	return nil
+}
+
 func scaleDownDeployment(ctx context.Context, kubeClient kubernetes.Interface, labelSelector, namespace string, log log.BaseLogger) (bool, error) {
 	list, err := kubeClient.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
 	if err != nil {
@@ -0,0 +1,81 @@
+package lifecycle
+
+import (
+	"context"
+	"testing"
+
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReleaseAdoptedNamespace(t *testing.T) {
+	managedBy := translate.SafeConcatName("vcluster", "x", "my-vcluster")
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "adopted",
+		Labels:      map[string]string{translate.MarkerLabel: managedBy, translate.AdoptedLabel: "true"},
+		Annotations: map[string]string{translate.NameAnnotation: "adopted"},
+	}}
+	syncedAnnotations := map[string]string{translate.NameAnnotation: "synced"}
+	objects := []client.Object{
+		namespace,
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "synced", Namespace: "adopted", Annotations: syncedAnnotations}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "adopted"}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "synced", Namespace: "adopted", Annotations: syncedAnnotations}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "labeled", Namespace: "adopted", Labels: map[string]string{translate.MarkerLabel: managedBy}}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "other-vcluster", Namespace: "adopted", Labels: map[string]string{translate.MarkerLabel: "other"}}},
+	}
+	hostClient := fakeclient.NewClientBuilder().WithScheme(testingutil.NewScheme()).WithObjects(objects...).Build()
+
+	kubeClient := fake.NewSimpleClientset(namespace)
+	kubeClient.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "configmaps", Kind: "ConfigMap", Namespaced: true, Verbs: metav1.Verbs{"list", "delete"}},
+				{Name: "serviceaccounts", Kind: "ServiceAccount", Namespaced: true, Verbs: metav1.Verbs{"list", "delete"}},
+				{Name: "serviceaccounts/token", Kind: "TokenRequest", Namespaced: true, Verbs: metav1.Verbs{"create"}},
+				{Name: "namespaces", Kind: "Namespace", Verbs: metav1.Verbs{"list", "delete"}},
+			},
+		},
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "deployments", Kind: "Deployment", Namespaced: true, Verbs: metav1.Verbs{"list", "delete"}},
+			},
+		},
+	}
+
+	err := ReleaseAdoptedNamespace(context.TODO(), kubeClient, hostClient, namespace)
+	assert.NilError(t, err)
+
+	expectExists := func(obj client.Object, exists bool) {
+		err := hostClient.Get(context.TODO(), client.ObjectKeyFromObject(obj), obj)
+		if exists {
+			assert.NilError(t, err, "expected %s to exist", client.ObjectKeyFromObject(obj))
+		} else {
+			assert.Assert(t, kerrors.IsNotFound(err), "expected %s to be deleted", client.ObjectKeyFromObject(obj))
+		}
+	}
+	expectExists(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "synced", Namespace: "adopted"}}, false)
+	expectExists(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "adopted"}}, true)
+	expectExists(&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "synced", Namespace: "adopted"}}, false)
+	expectExists(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "labeled", Namespace: "adopted"}}, false)
+	expectExists(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "other-vcluster", Namespace: "adopted"}}, true)
+	expectExists(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "adopted"}}, true)
+
+	// the vCluster metadata is removed from the namespace
+	released, err := kubeClient.CoreV1().Namespaces().Get(context.TODO(), "adopted", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, released.Labels[translate.MarkerLabel], "")
+	assert.Equal(t, released.Labels[translate.AdoptedLabel], "")
+	assert.Equal(t, released.Annotations[translate.NameAnnotation], "")
+}
@@ -0,0 +1,126 @@
+package metrics
+
+import (
+	"sort"
+
+	dto "github.com/prometheus/client_model/go"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+var podPhases = []corev1.PodPhase{corev1.PodPending, corev1.PodRunning, corev1.PodSucceeded, corev1.PodFailed, corev1.PodUnknown}
+
+// PodStateMetrics creates kube-state-metrics compatible metrics for the given virtual pods
+func PodStateMetrics(pods []corev1.Pod) []*dto.MetricFamily {
+	podInfo := newFamily("kube_pod_info", "Information about pod.", dto.MetricType_GAUGE)
+	podPhase := newFamily("kube_pod_status_phase", "The pods current phase.", dto.MetricType_GAUGE)
+	containerRestarts := newFamily("kube_pod_container_status_restarts_total", "The number of container restarts per container.", dto.MetricType_COUNTER)
+	containerReady := newFamily("kube_pod_container_status_ready", "Describes whether the containers readiness check succeeded.", dto.MetricType_GAUGE)
+	containerRequests := newFamily("kube_pod_container_resource_requests", "The number of requested request resource by a container.", dto.MetricType_GAUGE)
+	containerLimits := newFamily("kube_pod_container_resource_limits", "The number of requested limit resource by a container.", dto.MetricType_GAUGE)
+	for _, pod := range pods {
+		podLabels := []string{"namespace", pod.Namespace, "pod", pod.Name, "uid", string(pod.UID)}
+		podInfo.Metric = append(podInfo.Metric, newGauge(1, append(podLabels, "node", pod.Spec.NodeName, "pod_ip", pod.Status.PodIP)...))
+		for _, phase := range podPhases {
+			value := 0.0
+			if pod.Status.Phase == phase {
+				value = 1
+			}
+
+			podPhase.Metric = append(podPhase.Metric, newGauge(value, append(podLabels, "phase", string(phase))...))
+		}
+
+		for _, status := range pod.Status.ContainerStatuses {
+			containerLabels := append(podLabels, "container", status.Name)
+			containerRestarts.Metric = append(containerRestarts.Metric, &dto.Metric{
+				Label:   labelPairs(containerLabels...),
+				Counter: &dto.Counter{Value: ptr.To(float64(status.RestartCount))},
+			})
+
+			ready := 0.0
+			if status.Ready {
+				ready = 1
+			}
+			containerReady.Metric = append(containerReady.Metric, newGauge(ready, containerLabels...))
+		}
+
+		for _, container := range pod.Spec.Containers {
+			containerLabels := append(podLabels, "container", container.Name, "node", pod.Spec.NodeName)
+			containerRequests.Metric = append(containerRequests.Metric, resourceMetrics(container.Resources.Requests, containerLabels)...)
+			containerLimits.Metric = append(containerLimits.Metric, resourceMetrics(container.Resources.Limits, containerLabels)...)
+		}
+	}
+
+	return []*dto.MetricFamily{podInfo, podPhase, containerRestarts, containerReady, containerRequests, containerLimits}
+}
+
+// Merge merges metric families with the same name and sorts them alphabetically
+func Merge(metricsFamilies ...[]*dto.MetricFamily) []*dto.MetricFamily {
+	byName := map[string]*dto.MetricFamily{}
+	for _, families := range metricsFamilies {
+		for _, fam := range families {
+			existing, ok := byName[fam.GetName()]
+			if !ok {
+				byName[fam.GetName()] = fam
+				continue
+			}
+
+			existing.Metric = append(existing.Metric, fam.Metric...)
+		}
+	}
+
+	merged := make([]*dto.MetricFamily, 0, len(byName))
+	for _, fam := range byName {
+		merged = append(merged, fam)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].GetName() < merged[j].GetName()
+	})
+
+	return merged
+}
+
+func resourceMetrics(resources corev1.ResourceList, labels []string) []*dto.Metric {
+	names := make([]string, 0, len(resources))
+	for name := range resources {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	metrics := []*dto.Metric{}
+	for _, name := range names {
+		quantity := resources[corev1.ResourceName(name)]
+		unit := "byte"
+		if name == string(corev1.ResourceCPU) {
+			unit = "core"
+		}
+
+		metrics = append(metrics, newGauge(quantity.AsApproximateFloat64(), append(labels, "resource", name, "unit", unit)...))
+	}
+
+	return metrics
+}
+
+func newFamily(name, help string, metricType dto.MetricType) *dto.MetricFamily {
+	return &dto.MetricFamily{
+		Name: ptr.To(name),
+		Help: ptr.To(help),
+		Type: metricType.Enum(),
+	}
+}
+
+func newGauge(value float64, labels ...string) *dto.Metric {
+	return &dto.Metric{
+		Label: labelPairs(labels...),
+		Gauge: &dto.Gauge{Value: ptr.To(value)},
+	}
+}
+
+func labelPairs(labels ...string) []*dto.LabelPair {
+	pairs := make([]*dto.LabelPair, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, &dto.LabelPair{Name: ptr.To(labels[i]), Value: ptr.To(labels[i+1])})
+	}
+
+	return pairs
+}
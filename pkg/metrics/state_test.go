@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestPodStateMetrics(t *testing.T) {
+	pods := []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default", UID: "123"},
+			Spec: corev1.PodSpec{
+				NodeName: "node-1",
+				Containers: []corev1.Container{
+					{
+						Name: "nginx",
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+							Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Ki")},
+						},
+					},
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				PodIP: "10.0.0.1",
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "nginx", Ready: true, RestartCount: 2},
+				},
+			},
+		},
+	}
+
+	out, err := Encode(PodStateMetrics(pods), expfmt.FmtText)
+	assert.NilError(t, err)
+	assert.Equal(t, string(out), `# HELP kube_pod_info Information about pod.
+# TYPE kube_pod_info gauge
+kube_pod_info{namespace="default",pod="nginx",uid="123",node="node-1",pod_ip="10.0.0.1"} 1
+# HELP kube_pod_status_phase The pods current phase.
+# TYPE kube_pod_status_phase gauge
+kube_pod_status_phase{namespace="default",pod="nginx",uid="123",phase="Pending"} 0
+kube_pod_status_phase{namespace="default",pod="nginx",uid="123",phase="Running"} 1
+kube_pod_status_phase{namespace="default",pod="nginx",uid="123",phase="Succeeded"} 0
+kube_pod_status_phase{namespace="default",pod="nginx",uid="123",phase="Failed"} 0
+kube_pod_status_phase{namespace="default",pod="nginx",uid="123",phase="Unknown"} 0
+# HELP kube_pod_container_status_restarts_total The number of container restarts per container.
+# TYPE kube_pod_container_status_restarts_total counter
+kube_pod_container_status_restarts_total{namespace="default",pod="nginx",uid="123",container="nginx"} 2
+# HELP kube_pod_container_status_ready Describes whether the containers readiness check succeeded.
+# TYPE kube_pod_container_status_ready gauge
+kube_pod_container_status_ready{namespace="default",pod="nginx",uid="123",container="nginx"} 1
+# HELP kube_pod_container_resource_requests The number of requested request resource by a container.
+# TYPE kube_pod_container_resource_requests gauge
+kube_pod_container_resource_requests{namespace="default",pod="nginx",uid="123",container="nginx",node="node-1",resource="cpu",unit="core"} 0.1
+# HELP kube_pod_container_resource_limits The number of requested limit resource by a container.
+# TYPE kube_pod_container_resource_limits gauge
+kube_pod_container_resource_limits{namespace="default",pod="nginx",uid="123",container="nginx",node="node-1",resource="memory",unit="byte"} 1024
+`)
+}
+
+func TestMerge(t *testing.T) {
+	merged := Merge(
+		[]*dto.MetricFamily{{Name: ptr.To("b"), Metric: []*dto.Metric{newGauge(1)}}},
+		[]*dto.MetricFamily{{Name: ptr.To("a"), Metric: []*dto.Metric{newGauge(2)}}, {Name: ptr.To("b"), Metric: []*dto.Metric{newGauge(3)}}},
+	)
+
+	assert.Equal(t, len(merged), 2)
+	assert.Equal(t, merged[0].GetName(), "a")
+	assert.Equal(t, merged[1].GetName(), "b")
+	assert.Equal(t, len(merged[1].Metric), 2)
+}
@@ -23,12 +23,37 @@ import (
 const (
 	MetricsVersion        = "v1beta1"
 	MetricsAPIServiceName = MetricsVersion + "." + metrics.GroupName // "v1beta1.metrics.k8s.io"
+
+	CustomMetricsGroupName   = "custom.metrics.k8s.io"
+	ExternalMetricsGroupName = "external.metrics.k8s.io"
+
+	metricsServiceName      = "metrics-service"
+	metricsServiceNamespace = "kube-system"
 )
 
-func checkExistingAPIService(ctx context.Context, client client.Client) bool {
+type apiService struct {
+	group           string
+	version         string
+	versionPriority int32
+}
+
+func (a apiService) name() string {
+	return a.version + "." + a.group
+}
+
+var (
+	metricsAPIServices         = []apiService{{group: metrics.GroupName, version: MetricsVersion, versionPriority: 100}}
+	customMetricsAPIServices   = []apiService{{group: CustomMetricsGroupName, version: "v1beta1", versionPriority: 100}, {group: CustomMetricsGroupName, version: "v1beta2", versionPriority: 200}}
+	externalMetricsAPIServices = []apiService{{group: ExternalMetricsGroupName, version: "v1beta1", versionPriority: 100}}
+)
+
+// checkExistingAPIService returns true if the api service exists and points to the vCluster syncer. Api services of
+// metrics adapters that were installed within the virtual cluster are ignored.
+func checkExistingAPIService(ctx context.Context, client client.Client, name string) bool {
 	var exists bool
 	_ = applyOperation(ctx, func(ctx context.Context) (bool, error) {
-		err := client.Get(ctx, types.NamespacedName{Name: MetricsAPIServiceName}, &apiregistrationv1.APIService{})
+		existing := &apiregistrationv1.APIService{}
+		err := client.Get(ctx, types.NamespacedName{Name: name}, existing)
 		if err != nil {
 			if kerrors.IsNotFound(err) {
 				return true, nil
@@ -37,7 +62,7 @@ func checkExistingAPIService(ctx context.Context, client client.Client) bool {
 			return false, err
 		}
 
-		exists = true
+		exists = existing.Spec.Service != nil && existing.Spec.Service.Namespace == metricsServiceNamespace && existing.Spec.Service.Name == metricsServiceName
 		return true, nil
 	})
 
@@ -53,11 +78,11 @@ func applyOperation(ctx context.Context, operationFunc wait.ConditionWithContext
 	}, operationFunc)
 }
 
-func deleteOperation(ctrlCtx *config.ControllerContext) wait.ConditionWithContextFunc {
+func deleteOperation(ctrlCtx *config.ControllerContext, service apiService) wait.ConditionWithContextFunc {
 	return func(ctx context.Context) (bool, error) {
 		err := ctrlCtx.VirtualManager.GetClient().Delete(ctx, &apiregistrationv1.APIService{
 			ObjectMeta: metav1.ObjectMeta{
-				Name: MetricsAPIServiceName,
+				Name: service.name(),
 			},
 		})
 		if err != nil {
@@ -73,12 +98,12 @@ func deleteOperation(ctrlCtx *config.ControllerContext) wait.ConditionWithContex
 	}
 }
 
-func createOperation(ctrlCtx *config.ControllerContext) wait.ConditionWithContextFunc {
+func createOperation(ctrlCtx *config.ControllerContext, apiServiceConfig apiService) wait.ConditionWithContextFunc {
 	return func(ctx context.Context) (bool, error) {
 		service := &corev1.Service{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      "metrics-service",
-				Namespace: "kube-system",
+				Name:      metricsServiceName,
+				Namespace: metricsServiceNamespace,
 			},
 		}
 		_, err := controllerutil.CreateOrUpdate(ctx, ctrlCtx.VirtualManager.GetClient(), service, func() error {
@@ -102,19 +127,19 @@ func createOperation(ctrlCtx *config.ControllerContext) wait.ConditionWithContex
 
 		apiServiceSpec := apiregistrationv1.APIServiceSpec{
 			Service: &apiregistrationv1.ServiceReference{
-				Namespace: "kube-system",
-				Name:      "metrics-service",
+				Namespace: metricsServiceNamespace,
+				Name:      metricsServiceName,
 				Port:      ptr.To(int32(8443)),
 			},
 			InsecureSkipTLSVerify: true,
-			Group:                 metrics.GroupName,
+			Group:                 apiServiceConfig.group,
 			GroupPriorityMinimum:  100,
-			Version:               MetricsVersion,
-			VersionPriority:       100,
+			Version:               apiServiceConfig.version,
+			VersionPriority:       apiServiceConfig.versionPriority,
 		}
 		apiService := &apiregistrationv1.APIService{
 			ObjectMeta: metav1.ObjectMeta{
-				Name: MetricsAPIServiceName,
+				Name: apiServiceConfig.name(),
 			},
 		}
 		_, err = controllerutil.CreateOrUpdate(ctx, ctrlCtx.VirtualManager.GetClient(), apiService, func() error {
@@ -135,12 +160,35 @@ func createOperation(ctrlCtx *config.ControllerContext) wait.ConditionWithContex
 }
 
 func RegisterOrDeregisterAPIService(ctx *config.ControllerContext) error {
-	// check if the api service should get created
-	exists := checkExistingAPIService(ctx.Context, ctx.VirtualManager.GetClient())
-	if ctx.Config.Observability.Metrics.Proxy.Nodes || ctx.Config.Observability.Metrics.Proxy.Pods {
-		return applyOperation(ctx.Context, createOperation(ctx))
-	} else if exists {
-		return applyOperation(ctx.Context, deleteOperation(ctx))
+	metricsProxy := ctx.Config.Observability.Metrics.Proxy
+	err := registerOrDeregister(ctx, metricsAPIServices, metricsProxy.Nodes || metricsProxy.Pods)
+	if err != nil {
+		return err
+	}
+
+	err = registerOrDeregister(ctx, customMetricsAPIServices, metricsProxy.CustomMetrics)
+	if err != nil {
+		return err
+	}
+
+	return registerOrDeregister(ctx, externalMetricsAPIServices, metricsProxy.ExternalMetrics)
+}
+
+func registerOrDeregister(ctx *config.ControllerContext, apiServices []apiService, enabled bool) error {
+	for _, service := range apiServices {
+		// check if the api service should get created
+		exists := checkExistingAPIService(ctx.Context, ctx.VirtualManager.GetClient(), service.name())
+		if enabled {
+			err := applyOperation(ctx.Context, createOperation(ctx, service))
+			if err != nil {
+				return err
+			}
+		} else if exists {
+			err := applyOperation(ctx.Context, deleteOperation(ctx, service))
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
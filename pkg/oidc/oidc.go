@@ -0,0 +1,121 @@
+package oidc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// DiscoveryPath is the path of the OpenID discovery document
+	DiscoveryPath = "/.well-known/openid-configuration"
+	// JWKSPath is the path of the JSON web key set
+	JWKSPath = "/openid/v1/jwks"
+
+	// DefaultIssuer is the issuer used by the virtual cluster if no other issuer is configured
+	DefaultIssuer = "https://kubernetes.default.svc.cluster.local"
+
+	DiscoveryConfigMapKey  = "openid-configuration"
+	JWKSConfigMapKey       = "jwks"
+	DefaultConfigMapPrefix = "vc-oidc-"
+
+	cacheDuration = time.Minute
+)
+
+// Document holds the discovery document and the JWKS of the virtual cluster service account issuer
+type Document struct {
+	Discovery []byte
+	JWKS      []byte
+}
+
+// Provider retrieves the discovery document and JWKS from the virtual cluster api server and caches them
+type Provider struct {
+	restClient rest.Interface
+
+	m         sync.Mutex
+	document  *Document
+	expiresAt time.Time
+}
+
+// NewProvider creates a new provider for the given virtual cluster config
+func NewProvider(virtualConfig *rest.Config) (*Provider, error) {
+	virtualClient, err := kubernetes.NewForConfig(virtualConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Provider{
+		restClient: virtualClient.Discovery().RESTClient(),
+	}, nil
+}
+
+// Get returns the cached document or retrieves it from the virtual cluster api server
+func (p *Provider) Get(ctx context.Context) (*Document, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if p.document != nil && time.Now().Before(p.expiresAt) {
+		return p.document, nil
+	}
+
+	discovery, err := p.restClient.Get().AbsPath(DiscoveryPath).DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get openid configuration: %w", err)
+	}
+
+	jwks, err := p.restClient.Get().AbsPath(JWKSPath).DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get jwks: %w", err)
+	}
+
+	p.document = &Document{
+		Discovery: discovery,
+		JWKS:      jwks,
+	}
+	p.expiresAt = time.Now().Add(cacheDuration)
+	return p.document, nil
+}
+
+// GetDefaultConfigMapName returns the default name of the host ConfigMap the document is published to
+func GetDefaultConfigMapName(vClusterName string) string {
+	return DefaultConfigMapPrefix + vClusterName
+}
+
+// PublishConfigMap writes the discovery document and JWKS into the given host ConfigMap
+func PublishConfigMap(ctx context.Context, provider *Provider, hostClient client.Client, namespace, name string) error {
+	document, err := provider.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+	result, err := controllerutil.CreateOrPatch(ctx, hostClient, configMap, func() error {
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+		configMap.Data[DiscoveryConfigMapKey] = string(document.Discovery)
+		configMap.Data[JWKSConfigMapKey] = string(document.JWKS)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("publish service account issuer discovery to config map %s/%s: %w", namespace, name, err)
+	} else if result != controllerutil.OperationResultNone {
+		klog.Infof("Published service account issuer discovery to config map %s/%s", namespace, name)
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"k8s.io/client-go/rest"
+)
+
+func TestProviderGet(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		switch req.URL.Path {
+		case DiscoveryPath:
+			_, _ = w.Write([]byte(`{"issuer":"https://oidc.example.com","jwks_uri":"https://oidc.example.com/openid/v1/jwks"}`))
+		case JWKSPath:
+			_, _ = w.Write([]byte(`{"keys":[]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider(&rest.Config{Host: server.URL})
+	assert.NilError(t, err)
+
+	document, err := provider.Get(context.Background())
+	assert.NilError(t, err)
+	assert.Equal(t, string(document.Discovery), `{"issuer":"https://oidc.example.com","jwks_uri":"https://oidc.example.com/openid/v1/jwks"}`)
+	assert.Equal(t, string(document.JWKS), `{"keys":[]}`)
+	assert.Equal(t, requests, 2)
+
+	// second call should be served from the cache
+	_, err = provider.Get(context.Background())
+	assert.NilError(t, err)
+	assert.Equal(t, requests, 2)
+}
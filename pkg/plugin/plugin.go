@@ -3,6 +3,7 @@ package plugin
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/loft-sh/vcluster/pkg/config"
 	plugintypes "github.com/loft-sh/vcluster/pkg/plugin/types"
@@ -81,10 +82,34 @@ func (m *manager) HasClientHooksForType(versionKindType plugintypes.VersionKindT
 	return m.legacyManager.HasClientHooksForType(versionKindType) || m.pluginManager.HasClientHooksForType(versionKindType)
 }
 
+func (m *manager) HasValidatingHooksForType(versionKindType plugintypes.VersionKindType) bool {
+	return m.pluginManager.HasValidatingHooksForType(versionKindType)
+}
+
+func (m *manager) ValidateRequest(ctx context.Context, request *plugintypes.ValidationRequest) error {
+	return m.pluginManager.ValidateRequest(ctx, request)
+}
+
+func (m *manager) HasHTTPHandlers() bool {
+	return m.pluginManager.HasHTTPHandlers()
+}
+
+func (m *manager) WithHTTPHandlers(next http.Handler, virtualClient client.Client) http.Handler {
+	return m.pluginManager.WithHTTPHandlers(next, virtualClient)
+}
+
 func (m *manager) HasPlugins() bool {
 	return m.legacyManager.HasPlugins() || m.pluginManager.HasPlugins()
 }
 
+func (m *manager) PluginStatus() []plugintypes.PluginStatus {
+	return m.pluginManager.PluginStatus()
+}
+
+func (m *manager) StatusHandler() http.Handler {
+	return m.pluginManager.StatusHandler()
+}
+
 func (m *manager) SetProFeatures(proFeatures map[string]bool) {
 	m.pluginManager.ProFeatures = proFeatures
 }
@@ -0,0 +1,103 @@
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+//go:embed all:templates
+var templates embed.FS
+
+var validName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// Options are the options to scaffold a new plugin
+type Options struct {
+	// Name of the plugin, this is also the key in the plugins section of the vCluster config
+	Name string
+
+	// Module is the go module path of the plugin
+	Module string
+
+	// Image is the container image the plugin is pushed to
+	Image string
+}
+
+// Generate writes a new plugin v2 project to the given directory. Existing files are never overwritten.
+func Generate(dir string, options Options) ([]string, error) {
+	if !validName.MatchString(options.Name) {
+		return nil, fmt.Errorf("invalid plugin name %q, the name must consist of lower case alphanumeric characters or '-'", options.Name)
+	}
+	if options.Module == "" {
+		options.Module = options.Name
+	}
+	if options.Image == "" {
+		options.Image = options.Name + ":latest"
+	}
+
+	// render all files first, so nothing is written if a file exists already
+	rendered := map[string][]byte{}
+	files := []string{}
+	err := fs.WalkDir(templates, "templates", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		content, err := templates.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		relativePath := strings.TrimSuffix(strings.TrimPrefix(path, "templates/"), ".tmpl")
+		target := filepath.Join(dir, filepath.FromSlash(relativePath))
+		if _, err := os.Stat(target); err == nil {
+			return fmt.Errorf("file %s already exists", target)
+		}
+
+		rendered[target], err = render(relativePath, string(content), options)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, target)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, target := range files {
+		err = os.MkdirAll(filepath.Dir(target), 0755)
+		if err != nil {
+			return nil, err
+		}
+
+		err = os.WriteFile(target, rendered[target], 0644)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+func render(name, content string, options Options) ([]byte, error) {
+	t, err := template.New(name).Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("parse template %s: %w", name, err)
+	}
+
+	buffer := &bytes.Buffer{}
+	err = t.Execute(buffer, options)
+	if err != nil {
+		return nil, fmt.Errorf("render template %s: %w", name, err)
+	}
+
+	return buffer.Bytes(), nil
+}
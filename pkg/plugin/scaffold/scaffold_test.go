@@ -0,0 +1,46 @@
+package scaffold
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	files, err := Generate(dir, Options{Name: "my-plugin", Module: "github.com/my-org/my-plugin"})
+	assert.NilError(t, err)
+	assert.Assert(t, len(files) > 0)
+
+	goMod, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	assert.NilError(t, err)
+	assert.Assert(t, strings.HasPrefix(string(goMod), "module github.com/my-org/my-plugin\n"))
+
+	pluginYAML, err := os.ReadFile(filepath.Join(dir, "plugin.yaml"))
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(string(pluginYAML), "image: my-plugin:latest"))
+
+	// generated go code has to be valid
+	for _, file := range files {
+		if filepath.Ext(file) != ".go" {
+			continue
+		}
+
+		_, err = parser.ParseFile(token.NewFileSet(), file, nil, parser.AllErrors)
+		assert.NilError(t, err, "parse %s", file)
+	}
+
+	// existing files are not overwritten
+	_, err = Generate(dir, Options{Name: "my-plugin"})
+	assert.ErrorContains(t, err, "already exists")
+}
+
+func TestGenerateInvalidName(t *testing.T) {
+	_, err := Generate(t.TempDir(), Options{Name: "My_Plugin"})
+	assert.ErrorContains(t, err, "invalid plugin name")
+}
@@ -2,9 +2,12 @@ package types
 
 import (
 	"context"
+	"net/http"
+	"time"
 
 	"github.com/loft-sh/vcluster/pkg/config"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/client-go/rest"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -33,9 +36,28 @@ type Manager interface {
 	// HasClientHooksForType returns if there are any plugin client hooks for the given type
 	HasClientHooksForType(VersionKindType) bool
 
+	// HasValidatingHooksForType returns if there are any plugin validating hooks for the given type, where type is the operation
+	HasValidatingHooksForType(VersionKindType) bool
+
+	// ValidateRequest calls the validating hooks for the request and returns a forbidden error if a plugin denies it
+	ValidateRequest(ctx context.Context, request *ValidationRequest) error
+
+	// HasHTTPHandlers returns if there are any plugin http handlers
+	HasHTTPHandlers() bool
+
+	// WithHTTPHandlers routes requests with a plugin registered path prefix to the plugin and all others to next.
+	// The virtual client is used to authorize requests to plugin paths.
+	WithHTTPHandlers(next http.Handler, virtualClient client.Client) http.Handler
+
 	// HasPlugins returns if there are any plugins to start
 	HasPlugins() bool
 
+	// PluginStatus returns the supervision status of the plugins
+	PluginStatus() []PluginStatus
+
+	// StatusHandler serves the plugin status and metrics
+	StatusHandler() http.Handler
+
 	// SetProFeatures is used by vCluster.Pro to signal what pro features are enabled
 	SetProFeatures(proFeatures map[string]bool)
 }
@@ -45,3 +67,49 @@ type VersionKindType struct {
 	Kind       string
 	Type       string
 }
+
+// ValidationRequest is a request to the virtual cluster api server that is validated by plugins
+type ValidationRequest struct {
+	APIVersion  string
+	Kind        string
+	Resource    string
+	SubResource string
+	Operation   string
+	Namespace   string
+	Name        string
+	DryRun      bool
+
+	// Object is the json encoded object after the operation
+	Object []byte
+	// OldObject is the json encoded object before the operation
+	OldObject []byte
+
+	UserInfo user.Info
+}
+
+// PluginStatus is the supervision status of a single plugin
+type PluginStatus struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Optional bool   `json:"optional"`
+
+	// Phase is either Running, Restarting or Failed
+	Phase              string    `json:"phase"`
+	Restarts           int       `json:"restarts"`
+	LastError          string    `json:"lastError,omitempty"`
+	LastTransitionTime time.Time `json:"lastTransitionTime,omitempty"`
+
+	// Hooks are the hooks and handlers the plugin registered
+	Hooks []PluginHook `json:"hooks,omitempty"`
+}
+
+// PluginHook is a hook or http handler registered by a plugin
+type PluginHook struct {
+	// Type is either Client, Validating or HTTP
+	Type string `json:"type"`
+
+	APIVersion string   `json:"apiVersion,omitempty"`
+	Kind       string   `json:"kind,omitempty"`
+	Types      []string `json:"types,omitempty"`
+	PathPrefix string   `json:"pathPrefix,omitempty"`
+}
@@ -23,7 +23,9 @@ type InitConfigPro struct {
 
 // PluginConfig is the config the plugin sends back to the syncer
 type PluginConfig struct {
-	ClientHooks []*ClientHook `json:"clientHooks,omitempty"`
+	ClientHooks     []*ClientHook     `json:"clientHooks,omitempty"`
+	ValidatingHooks []*ValidatingHook `json:"validatingHooks,omitempty"`
+	HTTPHandlers    []*HTTPHandler    `json:"httpHandlers,omitempty"`
 }
 
 type ClientHook struct {
@@ -32,6 +34,18 @@ type ClientHook struct {
 	Types      []string `json:"types,omitempty"`
 }
 
+// ValidatingHook registers the plugin to validate requests for the given kind and operations
+type ValidatingHook struct {
+	APIVersion string   `json:"apiVersion,omitempty"`
+	Kind       string   `json:"kind,omitempty"`
+	Operations []string `json:"operations,omitempty"`
+}
+
+// HTTPHandler registers the plugin to serve all requests below the given path prefix
+type HTTPHandler struct {
+	PathPrefix string `json:"pathPrefix,omitempty"`
+}
+
 func parsePluginConfig(config string) (*PluginConfig, error) {
 	pluginConfig := &PluginConfig{}
 	err := json.Unmarshal([]byte(config), pluginConfig)
@@ -0,0 +1,228 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/plugin/v2/pluginv2"
+	"github.com/loft-sh/vcluster/pkg/util/clienthelper"
+	requestpkg "github.com/loft-sh/vcluster/pkg/util/request"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// bodyChunkSize is the maximum size of a body chunk sent in a single message
+const bodyChunkSize = 32 * 1024
+
+// reservedPaths are served by the virtual cluster api server or vCluster itself and can't be taken over by plugins
+var reservedPaths = []string{"/api", "/apis", "/openapi", "/version", "/healthz", "/livez", "/readyz", "/metrics", "/.well-known", "/openid", "/vcluster"}
+
+func (m *Manager) HasHTTPHandlers() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return len(m.HTTPHandlers) > 0
+}
+
+// WithHTTPHandlers routes requests that match a registered path prefix to the plugin. If multiple prefixes match,
+// the longest one wins. Requests to plugin paths never reach the virtual cluster api server, so the user is
+// authorized for the path against the virtual cluster RBAC before the request is passed to the plugin.
+func (m *Manager) WithHTTPHandlers(next http.Handler, virtualClient client.Client) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		vClusterPlugin := m.findHTTPHandler(req.URL.Path)
+		if vClusterPlugin == nil {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		allowed, reason, err := authorizeHTTP(req, virtualClient)
+		if err != nil {
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
+			return
+		} else if !allowed {
+			requestpkg.FailWithStatus(w, req, http.StatusForbidden, fmt.Errorf("forbidden: user is not allowed to %s path %s: %s", requestVerb(req), req.URL.Path, reason))
+			return
+		} else if !vClusterPlugin.isRunning() {
+			requestpkg.FailWithStatus(w, req, http.StatusServiceUnavailable, fmt.Errorf("plugin %s is not running", vClusterPlugin.Path))
+			return
+		}
+
+		m.serveHTTP(w, req, vClusterPlugin)
+	})
+}
+
+// authorizeHTTP checks with the virtual cluster RBAC if the user is allowed to access the non resource url
+func authorizeHTTP(req *http.Request, virtualClient client.Client) (bool, string, error) {
+	userInfo, ok := request.UserFrom(req.Context())
+	if !ok {
+		return false, "", fmt.Errorf("user info is missing")
+	}
+
+	return clienthelper.AuthorizeNonResource(req.Context(), virtualClient, userInfo, req.URL.Path, requestVerb(req))
+}
+
+// requestVerb returns the verb of a non resource request, which is the lower case http method
+func requestVerb(req *http.Request) string {
+	info, ok := request.RequestInfoFrom(req.Context())
+	if ok && info.Verb != "" {
+		return info.Verb
+	}
+
+	return strings.ToLower(req.Method)
+}
+
+func (m *Manager) findHTTPHandler(path string) *vClusterPlugin {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	longestPrefix := ""
+	for prefix := range m.HTTPHandlers {
+		if len(prefix) > len(longestPrefix) && matchesPathPrefix(path, prefix) {
+			longestPrefix = prefix
+		}
+	}
+	if longestPrefix == "" {
+		return nil
+	}
+
+	return m.HTTPHandlers[longestPrefix]
+}
+
+// serveHTTP streams the request to the plugin and the response of the plugin back to the client
+func (m *Manager) serveHTTP(w http.ResponseWriter, req *http.Request, plugin *vClusterPlugin) {
+	logger := klog.FromContext(req.Context()).WithValues("plugin", plugin.Path, "path", req.URL.Path)
+	userInfo, _ := request.UserFrom(req.Context())
+	encodedUserInfo, err := encodeUserInfo(userInfo)
+	if err != nil {
+		requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	// the stream is cancelled on return, so the body goroutine below can't outlive the request
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+
+	stream, err := plugin.grpcClient().ServeHTTP(ctx)
+	if err != nil {
+		requestpkg.FailWithStatus(w, req, http.StatusBadGateway, fmt.Errorf("call plugin %s: %w", plugin.Path, err))
+		return
+	}
+
+	err = stream.Send(&pluginv2.ServeHTTP_Request{
+		Method:   req.Method,
+		Url:      req.URL.RequestURI(),
+		Headers:  toHeaders(req.Header),
+		UserInfo: encodedUserInfo,
+	})
+	if err != nil {
+		requestpkg.FailWithStatus(w, req, http.StatusBadGateway, fmt.Errorf("send request to plugin %s: %w", plugin.Path, err))
+		return
+	}
+
+	// stream the body to the plugin, this is done in parallel so plugins can start responding before the body
+	// was fully received, e.g. for streaming uploads
+	done := make(chan struct{})
+	defer func() {
+		// unblock a pending body read and wait for the goroutine, the body must not be used after returning
+		cancel()
+		_ = http.NewResponseController(w).SetReadDeadline(time.Now())
+		<-done
+	}()
+	go func() {
+		defer close(done)
+		defer func() {
+			_ = stream.CloseSend()
+		}()
+
+		buffer := make([]byte, bodyChunkSize)
+		for {
+			n, err := req.Body.Read(buffer)
+			if n > 0 {
+				if sendErr := stream.Send(&pluginv2.ServeHTTP_Request{Body: buffer[:n]}); sendErr != nil {
+					logger.Error(sendErr, "error sending request body to plugin")
+					return
+				}
+			}
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
+					logger.Error(err, "error reading request body")
+				}
+				return
+			}
+		}
+	}()
+
+	// the first response message contains status code and headers
+	response, err := stream.Recv()
+	if err != nil {
+		requestpkg.FailWithStatus(w, req, http.StatusBadGateway, fmt.Errorf("receive response from plugin %s: %w", plugin.Path, err))
+		return
+	}
+	for key, header := range response.Headers {
+		for _, value := range header.Values {
+			w.Header().Add(key, value)
+		}
+	}
+	statusCode := int(response.StatusCode)
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	w.WriteHeader(statusCode)
+
+	flusher, _ := w.(http.Flusher)
+	for {
+		if len(response.Body) > 0 {
+			_, err = w.Write(response.Body)
+			if err != nil {
+				logger.V(1).Info("error writing response body", "error", err)
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+
+		response, err = stream.Recv()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				logger.Error(err, "error receiving response body from plugin")
+			}
+			return
+		}
+	}
+}
+
+func toHeaders(header http.Header) map[string]*pluginv2.ServeHTTP_Header {
+	out := map[string]*pluginv2.ServeHTTP_Header{}
+	for key, values := range header {
+		// never forward credentials to plugins, the authenticated user is passed instead
+		if strings.EqualFold(key, "Authorization") || strings.EqualFold(key, "Cookie") {
+			continue
+		}
+
+		out[key] = &pluginv2.ServeHTTP_Header{Values: values}
+	}
+
+	return out
+}
+
+func matchesPathPrefix(path, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+func isReservedPath(pathPrefix string) bool {
+	for _, reservedPath := range reservedPaths {
+		if matchesPathPrefix(pathPrefix, reservedPath) || matchesPathPrefix(reservedPath, pathPrefix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -8,6 +8,9 @@ import (
 	"os/exec"
 	"path"
 	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ghodss/yaml"
@@ -16,8 +19,13 @@ import (
 	"github.com/loft-sh/vcluster/pkg/config"
 	plugintypes "github.com/loft-sh/vcluster/pkg/plugin/types"
 	"github.com/loft-sh/vcluster/pkg/plugin/v2/pluginv2"
+	"github.com/loft-sh/vcluster/pkg/tracing"
 	"github.com/loft-sh/vcluster/pkg/util/kubeconfig"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
@@ -35,8 +43,10 @@ func NewManager() *Manager {
 	}
 
 	return &Manager{
-		PluginFolder: pluginFolder,
-		ClientHooks:  map[plugintypes.VersionKindType][]*vClusterPlugin{},
+		PluginFolder:    pluginFolder,
+		ClientHooks:     map[plugintypes.VersionKindType][]*vClusterPlugin{},
+		ValidatingHooks: map[plugintypes.VersionKindType][]*vClusterPlugin{},
+		HTTPHandlers:    map[string]*vClusterPlugin{},
 	}
 }
 
@@ -50,19 +60,59 @@ type Manager struct {
 	// ClientHooks that were loaded
 	ClientHooks map[plugintypes.VersionKindType][]*vClusterPlugin
 
+	// ValidatingHooks that were loaded, the type is the operation to validate
+	ValidatingHooks map[plugintypes.VersionKindType][]*vClusterPlugin
+
+	// HTTPHandlers that were loaded by path prefix
+	HTTPHandlers map[string]*vClusterPlugin
+
 	// ProFeatures are pro features to hand-over to the plugin
 	ProFeatures map[string]bool
+
+	// lock protects the registered hooks and handlers, as plugins might register them after a restart
+	lock sync.RWMutex
+
+	// leader signals if plugins were told to start their controllers
+	leader atomic.Bool
+
+	vConfig *config.VirtualClusterConfig
 }
 
 type vClusterPlugin struct {
 	// Path is the path where the plugin was loaded from
 	Path string
 
+	// Name is the name of the plugin
+	Name string
+
+	// Optional plugins are allowed to fail without stopping vCluster
+	Optional bool
+
+	// lock protects the clients, which are replaced on restarts
+	lock sync.RWMutex
+
 	// Client is the plugin client
 	Client *plugin.Client
 
 	// GRPCClient is the direct grpc client
 	GRPCClient pluginv2.PluginClient
+
+	protocol    plugin.ClientProtocol
+	initRequest *pluginv2.Initialize_Request
+
+	// pluginConfig is the config the plugin returned on its first start
+	pluginConfig string
+	registered   bool
+	hooks        []plugintypes.PluginHook
+
+	state pluginState
+}
+
+func (p *vClusterPlugin) grpcClient() pluginv2.PluginClient {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return p.GRPCClient
 }
 
 func (m *Manager) Start(
@@ -80,49 +130,96 @@ func (m *Manager) Start(
 		return nil
 	}
 
-	// loop over plugins and load them
+	// loop over plugins and start them
+	m.vConfig = vConfig
 	for _, pluginPath := range plugins {
-		err = m.loadPlugin(pluginPath, vConfig)
-		if err != nil {
-			return fmt.Errorf("start plugin %s: %w", pluginPath, err)
+		vClusterPlugin := &vClusterPlugin{
+			Path:     pluginPath,
+			Name:     filepath.Base(filepath.Dir(pluginPath)),
+			Optional: isOptional(filepath.Base(filepath.Dir(pluginPath)), vConfig),
 		}
-	}
 
-	// after loading all plugins we start them
-	for _, vClusterPlugin := range m.Plugins {
 		// build the start request
-		initRequest, err := m.buildInitRequest(filepath.Dir(vClusterPlugin.Path), currentNamespace, physicalKubeConfig, syncerConfig, vConfig)
+		vClusterPlugin.initRequest, err = m.buildInitRequest(filepath.Dir(vClusterPlugin.Path), currentNamespace, physicalKubeConfig, syncerConfig, vConfig)
 		if err != nil {
 			return fmt.Errorf("build start request: %w", err)
 		}
 
-		// start the plugin
-		_, err = vClusterPlugin.GRPCClient.Initialize(ctx, initRequest)
+		// start the plugin, optional plugins are restarted in the background if they fail
+		m.Plugins = append(m.Plugins, vClusterPlugin)
+		err = m.startPlugin(ctx, vClusterPlugin)
 		if err != nil {
-			return fmt.Errorf("error starting plugin %s: %w", vClusterPlugin.Path, err)
-		}
+			if !vClusterPlugin.Optional {
+				return fmt.Errorf("start plugin %s: %w", pluginPath, err)
+			}
 
-		// get plugin config
-		pluginConfigResponse, err := vClusterPlugin.GRPCClient.GetPluginConfig(ctx, &pluginv2.GetPluginConfig_Request{})
-		if err != nil {
-			return fmt.Errorf("error retrieving client hooks for plugin %s: %w", vClusterPlugin.Path, err)
+			klog.FromContext(ctx).Error(err, "error starting optional plugin, will retry in the background", "plugin", pluginPath)
+			continue
 		}
 
-		// parse plugin config
-		pluginConfig, err := parsePluginConfig(pluginConfigResponse.Config)
-		if err != nil {
-			return fmt.Errorf("error parsing plugin config: %w", err)
-		}
+		klog.FromContext(ctx).Info("Successfully loaded plugin", "plugin", vClusterPlugin.Path)
+	}
 
-		// register client hooks
-		err = m.registerClientHooks(vClusterPlugin, pluginConfig.ClientHooks)
-		if err != nil {
-			return fmt.Errorf("error adding client hook for plugin %s: %w", vClusterPlugin.Path, err)
+	// supervise the plugins from now on
+	for _, vClusterPlugin := range m.Plugins {
+		go m.supervise(ctx, vClusterPlugin)
+	}
+
+	return nil
+}
+
+// initializePlugin initializes the plugin and registers its hooks and handlers on the first start
+func (m *Manager) initializePlugin(ctx context.Context, vClusterPlugin *vClusterPlugin) error {
+	grpcClient := vClusterPlugin.grpcClient()
+	_, err := grpcClient.Initialize(ctx, vClusterPlugin.initRequest)
+	if err != nil {
+		return fmt.Errorf("error starting plugin %s: %w", vClusterPlugin.Path, err)
+	}
+
+	// get plugin config
+	pluginConfigResponse, err := grpcClient.GetPluginConfig(ctx, &pluginv2.GetPluginConfig_Request{})
+	if err != nil {
+		return fmt.Errorf("error retrieving client hooks for plugin %s: %w", vClusterPlugin.Path, err)
+	}
+
+	// hooks and handlers stay registered across restarts
+	if vClusterPlugin.registered {
+		if pluginConfigResponse.Config != vClusterPlugin.pluginConfig {
+			klog.FromContext(ctx).Info("Plugin config changed after restart, changes to hooks or handlers are only applied after vCluster was restarted", "plugin", vClusterPlugin.Path)
 		}
 
-		klog.FromContext(ctx).Info("Successfully loaded plugin", "plugin", vClusterPlugin.Path)
+		return nil
+	}
+
+	// parse plugin config
+	pluginConfig, err := parsePluginConfig(pluginConfigResponse.Config)
+	if err != nil {
+		return fmt.Errorf("error parsing plugin config: %w", err)
 	}
 
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	// register client hooks
+	err = m.registerClientHooks(vClusterPlugin, pluginConfig.ClientHooks)
+	if err != nil {
+		return fmt.Errorf("error adding client hook for plugin %s: %w", vClusterPlugin.Path, err)
+	}
+
+	// register validating hooks
+	err = m.registerValidatingHooks(vClusterPlugin, pluginConfig.ValidatingHooks)
+	if err != nil {
+		return fmt.Errorf("error adding validating hook for plugin %s: %w", vClusterPlugin.Path, err)
+	}
+
+	// register http handlers
+	err = m.registerHTTPHandlers(vClusterPlugin, pluginConfig.HTTPHandlers)
+	if err != nil {
+		return fmt.Errorf("error adding http handler for plugin %s: %w", vClusterPlugin.Path, err)
+	}
+
+	vClusterPlugin.pluginConfig = pluginConfigResponse.Config
+	vClusterPlugin.registered = true
 	return nil
 }
 
@@ -138,7 +235,9 @@ func (m *Manager) MutateObject(ctx context.Context, obj client.Object, hookType
 		Kind:       kind,
 		Type:       hookType,
 	}
+	m.lock.RLock()
 	clientHooks := m.ClientHooks[versionKindType]
+	m.lock.RUnlock()
 	if len(clientHooks) == 0 {
 		return nil
 	}
@@ -149,6 +248,13 @@ func (m *Manager) MutateObject(ctx context.Context, obj client.Object, hookType
 	}
 
 	for _, clientHook := range clientHooks {
+		available, err := clientHook.checkAvailable()
+		if err != nil {
+			return err
+		} else if !available {
+			continue
+		}
+
 		encodedObj, err = m.mutateObject(ctx, versionKindType, encodedObj, clientHook)
 		if err != nil {
 			return err
@@ -163,12 +269,22 @@ func (m *Manager) MutateObject(ctx context.Context, obj client.Object, hookType
 	return nil
 }
 
-func (m *Manager) mutateObject(ctx context.Context, versionKindType plugintypes.VersionKindType, obj []byte, plugin *vClusterPlugin) ([]byte, error) {
+func (m *Manager) mutateObject(ctx context.Context, versionKindType plugintypes.VersionKindType, obj []byte, plugin *vClusterPlugin) (_ []byte, err error) {
 	ctx, cancel := context.WithTimeout(ctx, time.Second*10)
 	defer cancel()
 
+	ctx, span := tracing.StartSpan(ctx, "plugin Mutate",
+		attribute.String("vcluster.plugin", plugin.Path),
+		attribute.String("k8s.apiVersion", versionKindType.APIVersion),
+		attribute.String("k8s.kind", versionKindType.Kind),
+		attribute.String("vcluster.hookType", versionKindType.Type),
+	)
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	klog.FromContext(ctx).V(1).Info("calling plugin to mutate object", "plugin", plugin.Path, "apiVersion", versionKindType.APIVersion, "kind", versionKindType.Kind)
-	mutateResult, err := plugin.GRPCClient.Mutate(ctx, &pluginv2.Mutate_Request{
+	mutateResult, err := plugin.grpcClient().Mutate(ctx, &pluginv2.Mutate_Request{
 		ApiVersion: versionKindType.APIVersion,
 		Kind:       versionKindType.Kind,
 		Object:     string(obj),
@@ -185,8 +301,14 @@ func (m *Manager) mutateObject(ctx context.Context, versionKindType plugintypes.
 }
 
 func (m *Manager) SetLeader(ctx context.Context) error {
+	m.leader.Store(true)
 	for _, vClusterPlugin := range m.Plugins {
-		_, err := vClusterPlugin.GRPCClient.SetLeader(ctx, &pluginv2.SetLeader_Request{})
+		// plugins that are not running will be told after they were restarted
+		if !vClusterPlugin.isRunning() {
+			continue
+		}
+
+		_, err := vClusterPlugin.grpcClient().SetLeader(ctx, &pluginv2.SetLeader_Request{})
 		if err != nil {
 			return fmt.Errorf("error setting leader in plugin %s: %w", vClusterPlugin.Path, err)
 		}
@@ -195,11 +317,21 @@ func (m *Manager) SetLeader(ctx context.Context) error {
 	return nil
 }
 
+func (m *Manager) isLeader() bool {
+	return m.leader.Load()
+}
+
 func (m *Manager) HasClientHooksForType(versionKindType plugintypes.VersionKindType) bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
 	return len(m.ClientHooks[versionKindType]) > 0
 }
 
 func (m *Manager) HasClientHooks() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
 	return len(m.ClientHooks) > 0
 }
 
@@ -229,12 +361,76 @@ func (m *Manager) registerClientHooks(vClusterPlugin *vClusterPlugin, clientHook
 			m.ClientHooks[versionKindType] = append(m.ClientHooks[versionKindType], vClusterPlugin)
 		}
 
+		vClusterPlugin.hooks = append(vClusterPlugin.hooks, plugintypes.PluginHook{
+			Type:       "Client",
+			APIVersion: clientHookInfo.APIVersion,
+			Kind:       clientHookInfo.Kind,
+			Types:      clientHookInfo.Types,
+		})
+
 		klog.Infof("Register client hook for %s %s in plugin %s", clientHookInfo.APIVersion, clientHookInfo.Kind, vClusterPlugin.Path)
 	}
 
 	return nil
 }
 
+func (m *Manager) registerValidatingHooks(vClusterPlugin *vClusterPlugin, validatingHooks []*ValidatingHook) error {
+	for _, validatingHookInfo := range validatingHooks {
+		if validatingHookInfo.APIVersion == "" {
+			return fmt.Errorf("api version is empty in plugin %s validating hook", vClusterPlugin.Path)
+		} else if validatingHookInfo.Kind == "" {
+			return fmt.Errorf("kind is empty in plugin %s validating hook", vClusterPlugin.Path)
+		}
+
+		for _, operation := range validatingHookInfo.Operations {
+			operation = strings.ToUpper(operation)
+			if !validOperations.Has(operation) {
+				return fmt.Errorf("unknown operation %s in plugin %s validating hook, expected one of %v", operation, vClusterPlugin.Path, sets.List(validOperations))
+			}
+
+			versionKindType := plugintypes.VersionKindType{
+				APIVersion: validatingHookInfo.APIVersion,
+				Kind:       validatingHookInfo.Kind,
+				Type:       operation,
+			}
+
+			m.ValidatingHooks[versionKindType] = append(m.ValidatingHooks[versionKindType], vClusterPlugin)
+		}
+
+		vClusterPlugin.hooks = append(vClusterPlugin.hooks, plugintypes.PluginHook{
+			Type:       "Validating",
+			APIVersion: validatingHookInfo.APIVersion,
+			Kind:       validatingHookInfo.Kind,
+			Types:      validatingHookInfo.Operations,
+		})
+
+		klog.Infof("Register validating hook for %s %s in plugin %s", validatingHookInfo.APIVersion, validatingHookInfo.Kind, vClusterPlugin.Path)
+	}
+
+	return nil
+}
+
+func (m *Manager) registerHTTPHandlers(vClusterPlugin *vClusterPlugin, httpHandlers []*HTTPHandler) error {
+	for _, httpHandlerInfo := range httpHandlers {
+		if !strings.HasPrefix(httpHandlerInfo.PathPrefix, "/") {
+			return fmt.Errorf("path prefix %q in plugin %s http handler has to start with a /", httpHandlerInfo.PathPrefix, vClusterPlugin.Path)
+		} else if isReservedPath(httpHandlerInfo.PathPrefix) {
+			return fmt.Errorf("path prefix %s in plugin %s http handler is reserved for the kubernetes api", httpHandlerInfo.PathPrefix, vClusterPlugin.Path)
+		} else if other, ok := m.HTTPHandlers[httpHandlerInfo.PathPrefix]; ok {
+			return fmt.Errorf("path prefix %s in plugin %s http handler is already registered by plugin %s", httpHandlerInfo.PathPrefix, vClusterPlugin.Path, other.Path)
+		}
+
+		m.HTTPHandlers[httpHandlerInfo.PathPrefix] = vClusterPlugin
+		vClusterPlugin.hooks = append(vClusterPlugin.hooks, plugintypes.PluginHook{
+			Type:       "HTTP",
+			PathPrefix: httpHandlerInfo.PathPrefix,
+		})
+		klog.Infof("Register http handler for %s in plugin %s", httpHandlerInfo.PathPrefix, vClusterPlugin.Path)
+	}
+
+	return nil
+}
+
 func (m *Manager) buildInitRequest(
 	workingDir,
 	currentNamespace string,
@@ -302,7 +498,8 @@ func (m *Manager) buildInitRequest(
 	}, nil
 }
 
-func (m *Manager) loadPlugin(pluginPath string, vConfig *config.VirtualClusterConfig) error {
+// loadPlugin starts the plugin process and connects to it, an already running process is killed before
+func (m *Manager) loadPlugin(vClusterPlugin *vClusterPlugin) error {
 	// Create an hclog.Logger
 	logger := hclog.New(&hclog.LoggerOptions{
 		Name:   "plugin",
@@ -311,7 +508,7 @@ func (m *Manager) loadPlugin(pluginPath string, vConfig *config.VirtualClusterCo
 	})
 
 	// build command
-	cmd, err := buildCommand(pluginPath, vConfig)
+	cmd, err := buildCommand(vClusterPlugin.Path, m.vConfig)
 	if err != nil {
 		return err
 	}
@@ -328,6 +525,10 @@ func (m *Manager) loadPlugin(pluginPath string, vConfig *config.VirtualClusterCo
 		SyncStderr:       os.Stderr,
 		SkipHostEnv:      true,
 		AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
+		GRPCDialOptions: []grpc.DialOption{
+			// propagate the trace context to the plugin
+			grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		},
 	})
 
 	// Connect via RPC
@@ -344,13 +545,15 @@ func (m *Manager) loadPlugin(pluginPath string, vConfig *config.VirtualClusterCo
 		return err
 	}
 
-	// add to loaded plugins
-	m.Plugins = append(m.Plugins, &vClusterPlugin{
-		Path:       pluginPath,
-		Client:     pluginClient,
-		GRPCClient: raw.(pluginv2.PluginClient),
-	})
-
+	// replace the clients of the plugin
+	vClusterPlugin.lock.Lock()
+	defer vClusterPlugin.lock.Unlock()
+	if vClusterPlugin.Client != nil {
+		vClusterPlugin.Client.Kill()
+	}
+	vClusterPlugin.Client = pluginClient
+	vClusterPlugin.GRPCClient = raw.(pluginv2.PluginClient)
+	vClusterPlugin.protocol = rpcClient
 	return nil
 }
 
@@ -388,6 +591,16 @@ func (m *Manager) findPlugins(ctx context.Context) ([]string, error) {
 	return pluginPaths, nil
 }
 
+func isOptional(pluginName string, vConfig *config.VirtualClusterConfig) bool {
+	if newPlugin, ok := vConfig.Plugins[pluginName]; ok {
+		return newPlugin.Optional
+	} else if legacyPlugin, ok := vConfig.Plugin[pluginName]; ok {
+		return legacyPlugin.Optional
+	}
+
+	return false
+}
+
 func buildCommand(pluginPath string, vConfig *config.VirtualClusterConfig) (*exec.Cmd, error) {
 	pluginName := filepath.Base(filepath.Dir(pluginPath))
 	pluginConfig := ""
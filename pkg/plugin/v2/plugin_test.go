@@ -0,0 +1,211 @@
+package v2
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	plugintypes "github.com/loft-sh/vcluster/pkg/plugin/types"
+	"github.com/loft-sh/vcluster/pkg/plugin/v2/pluginv2"
+	"google.golang.org/grpc"
+	"gotest.tools/v3/assert"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+type fakeValidateClient struct {
+	pluginv2.PluginClient
+
+	response *pluginv2.Validate_Response
+}
+
+func (f *fakeValidateClient) Validate(_ context.Context, _ *pluginv2.Validate_Request, _ ...grpc.CallOption) (*pluginv2.Validate_Response, error) {
+	return f.response, nil
+}
+
+func TestRegisterHTTPHandlers(t *testing.T) {
+	testCases := []struct {
+		name        string
+		prefixes    []string
+		expectedErr bool
+	}{
+		{name: "valid", prefixes: []string{"/my-plugin", "/other/path"}},
+		{name: "missing slash", prefixes: []string{"my-plugin"}, expectedErr: true},
+		{name: "reserved", prefixes: []string{"/apis/my-plugin"}, expectedErr: true},
+		{name: "root", prefixes: []string{"/"}, expectedErr: true},
+		{name: "duplicate", prefixes: []string{"/my-plugin", "/my-plugin"}, expectedErr: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			m := NewManager()
+			httpHandlers := []*HTTPHandler{}
+			for _, prefix := range testCase.prefixes {
+				httpHandlers = append(httpHandlers, &HTTPHandler{PathPrefix: prefix})
+			}
+
+			err := m.registerHTTPHandlers(&vClusterPlugin{Path: "test"}, httpHandlers)
+			assert.Equal(t, err != nil, testCase.expectedErr, "unexpected error %v", err)
+		})
+	}
+}
+
+func TestWithHTTPHandlersPassthrough(t *testing.T) {
+	m := NewManager()
+	m.HTTPHandlers["/my-plugin"] = &vClusterPlugin{Path: "test"}
+
+	called := false
+	h := m.WithHTTPHandlers(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	}), fake.NewClientBuilder().Build())
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/my-plugin-other/test", nil))
+	assert.Assert(t, called, "expected request to be passed to the next handler")
+}
+
+func TestWithHTTPHandlersAuthorization(t *testing.T) {
+	m := NewManager()
+	m.HTTPHandlers["/my-plugin"] = &vClusterPlugin{Path: "test"}
+
+	// only admins are allowed to post to the plugin path
+	var accessReviews []authorizationv1.SubjectAccessReviewSpec
+	virtualClient := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(_ context.Context, _ client.WithWatch, obj client.Object, _ ...client.CreateOption) error {
+			accessReview := obj.(*authorizationv1.SubjectAccessReview)
+			accessReviews = append(accessReviews, accessReview.Spec)
+			accessReview.Status.Allowed = accessReview.Spec.User == "admin" && accessReview.Spec.NonResourceAttributes.Path == "/my-plugin/action" && accessReview.Spec.NonResourceAttributes.Verb == "post"
+			return nil
+		},
+	}).Build()
+	h := m.WithHTTPHandlers(http.NotFoundHandler(), virtualClient)
+
+	testCases := []struct {
+		name         string
+		user         string
+		expectedCode int
+	}{
+		{name: "denied", user: "developer", expectedCode: http.StatusForbidden},
+		// the plugin isn't running, so authorized users reach the plugin status check
+		{name: "allowed", user: "admin", expectedCode: http.StatusServiceUnavailable},
+	}
+
+	for _, testCase := range testCases {
+		req := httptest.NewRequest(http.MethodPost, "/my-plugin/action", nil)
+		req = req.WithContext(request.WithUser(req.Context(), &user.DefaultInfo{Name: testCase.user, Groups: []string{"system:authenticated"}}))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		assert.Equal(t, w.Code, testCase.expectedCode, testCase.name)
+	}
+	assert.Equal(t, len(accessReviews), len(testCases))
+}
+
+// fakeServeHTTPClient is a plugin that responds right away and never reads the request body
+type fakeServeHTTPClient struct {
+	pluginv2.PluginClient
+	grpc.ClientStream
+
+	ctx        context.Context
+	responded  bool
+	closedSend bool
+}
+
+func (f *fakeServeHTTPClient) ServeHTTP(ctx context.Context, _ ...grpc.CallOption) (pluginv2.Plugin_ServeHTTPClient, error) {
+	f.ctx = ctx
+	return f, nil
+}
+
+func (f *fakeServeHTTPClient) Send(request *pluginv2.ServeHTTP_Request) error {
+	if request.Method != "" {
+		return nil
+	}
+
+	<-f.ctx.Done()
+	return f.ctx.Err()
+}
+
+func (f *fakeServeHTTPClient) Recv() (*pluginv2.ServeHTTP_Response, error) {
+	if f.responded {
+		return nil, io.EOF
+	}
+
+	f.responded = true
+	return &pluginv2.ServeHTTP_Response{StatusCode: http.StatusAccepted, Body: []byte("ok")}, nil
+}
+
+func (f *fakeServeHTTPClient) CloseSend() error {
+	f.closedSend = true
+	return nil
+}
+
+func TestServeHTTPWaitsForBody(t *testing.T) {
+	m := NewManager()
+	pluginClient := &fakeServeHTTPClient{}
+
+	req := httptest.NewRequest(http.MethodPost, "/my-plugin/upload", bytes.NewReader(make([]byte, 4*bodyChunkSize)))
+	w := httptest.NewRecorder()
+	m.serveHTTP(w, req, newRunningPlugin("test", false, pluginClient))
+	assert.Equal(t, w.Code, http.StatusAccepted)
+	assert.Equal(t, w.Body.String(), "ok")
+
+	// the body goroutine was cancelled and has finished before serveHTTP returned
+	assert.Assert(t, pluginClient.closedSend)
+}
+
+func newRunningPlugin(path string, optional bool, grpcClient pluginv2.PluginClient) *vClusterPlugin {
+	p := &vClusterPlugin{Path: path, Name: path, Optional: optional, GRPCClient: grpcClient}
+	p.setPhase(PhaseRunning, nil)
+	return p
+}
+
+func TestValidateRequest(t *testing.T) {
+	m := NewManager()
+	err := m.registerValidatingHooks(newRunningPlugin("allow", false, &fakeValidateClient{response: &pluginv2.Validate_Response{Allowed: true}}), []*ValidatingHook{{APIVersion: "v1", Kind: "Pod", Operations: []string{"create"}}})
+	assert.NilError(t, err)
+	deny := newRunningPlugin("deny", true, &fakeValidateClient{response: &pluginv2.Validate_Response{Reason: "no pods allowed"}})
+	err = m.registerValidatingHooks(deny, []*ValidatingHook{{APIVersion: "v1", Kind: "Pod", Operations: []string{"CREATE"}}})
+	assert.NilError(t, err)
+	err = m.registerValidatingHooks(&vClusterPlugin{Path: "invalid"}, []*ValidatingHook{{APIVersion: "v1", Kind: "Pod", Operations: []string{"GET"}}})
+	assert.ErrorContains(t, err, "unknown operation GET")
+
+	assert.Assert(t, m.HasValidatingHooksForType(plugintypes.VersionKindType{APIVersion: "v1", Kind: "Pod", Type: "CREATE"}))
+	assert.Assert(t, !m.HasValidatingHooksForType(plugintypes.VersionKindType{APIVersion: "v1", Kind: "Pod", Type: "DELETE"}))
+
+	err = m.ValidateRequest(context.Background(), &plugintypes.ValidationRequest{APIVersion: "v1", Kind: "Pod", Resource: "pods", Operation: "CREATE", Name: "test"})
+	assert.Assert(t, kerrors.IsForbidden(err), "expected forbidden error, got %v", err)
+	assert.ErrorContains(t, err, "no pods allowed")
+
+	err = m.ValidateRequest(context.Background(), &plugintypes.ValidationRequest{APIVersion: "v1", Kind: "Pod", Resource: "pods", Operation: "DELETE", Name: "test"})
+	assert.NilError(t, err)
+
+	// optional plugins that are not running are skipped
+	deny.setPhase(PhaseRestarting, nil)
+	err = m.ValidateRequest(context.Background(), &plugintypes.ValidationRequest{APIVersion: "v1", Kind: "Pod", Resource: "pods", Operation: "CREATE", Name: "test"})
+	assert.NilError(t, err)
+}
+
+func TestCheckAvailable(t *testing.T) {
+	required := &vClusterPlugin{Path: "required", Name: "required"}
+	required.setPhase(PhaseFailed, nil)
+	_, err := required.checkAvailable()
+	assert.ErrorContains(t, err, "plugin required is not running")
+
+	optional := &vClusterPlugin{Path: "optional", Name: "optional", Optional: true}
+	optional.setPhase(PhaseFailed, nil)
+	available, err := optional.checkAvailable()
+	assert.NilError(t, err)
+	assert.Assert(t, !available)
+
+	optional.setPhase(PhaseRunning, nil)
+	available, err = optional.checkAvailable()
+	assert.NilError(t, err)
+	assert.Assert(t, available)
+}
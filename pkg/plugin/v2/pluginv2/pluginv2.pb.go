@@ -2,7 +2,7 @@
 
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.28.0
+// 	protoc-gen-go v1.32.0
 // 	protoc        v3.19.3
 // source: pluginv2.proto
 
@@ -136,6 +136,82 @@ func (*Mutate) Descriptor() ([]byte, []int) {
 	return file_pluginv2_proto_rawDescGZIP(), []int{2}
 }
 
+type Validate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *Validate) Reset() {
+	*x = Validate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pluginv2_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Validate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Validate) ProtoMessage() {}
+
+func (x *Validate) ProtoReflect() protoreflect.Message {
+	mi := &file_pluginv2_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Validate.ProtoReflect.Descriptor instead.
+func (*Validate) Descriptor() ([]byte, []int) {
+	return file_pluginv2_proto_rawDescGZIP(), []int{3}
+}
+
+type ServeHTTP struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ServeHTTP) Reset() {
+	*x = ServeHTTP{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pluginv2_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServeHTTP) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServeHTTP) ProtoMessage() {}
+
+func (x *ServeHTTP) ProtoReflect() protoreflect.Message {
+	mi := &file_pluginv2_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServeHTTP.ProtoReflect.Descriptor instead.
+func (*ServeHTTP) Descriptor() ([]byte, []int) {
+	return file_pluginv2_proto_rawDescGZIP(), []int{4}
+}
+
 type SetLeader struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -145,7 +221,7 @@ type SetLeader struct {
 func (x *SetLeader) Reset() {
 	*x = SetLeader{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pluginv2_proto_msgTypes[3]
+		mi := &file_pluginv2_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -158,7 +234,7 @@ func (x *SetLeader) String() string {
 func (*SetLeader) ProtoMessage() {}
 
 func (x *SetLeader) ProtoReflect() protoreflect.Message {
-	mi := &file_pluginv2_proto_msgTypes[3]
+	mi := &file_pluginv2_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -171,7 +247,7 @@ func (x *SetLeader) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetLeader.ProtoReflect.Descriptor instead.
 func (*SetLeader) Descriptor() ([]byte, []int) {
-	return file_pluginv2_proto_rawDescGZIP(), []int{3}
+	return file_pluginv2_proto_rawDescGZIP(), []int{5}
 }
 
 type Initialize_Request struct {
@@ -185,7 +261,7 @@ type Initialize_Request struct {
 func (x *Initialize_Request) Reset() {
 	*x = Initialize_Request{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pluginv2_proto_msgTypes[4]
+		mi := &file_pluginv2_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -198,7 +274,7 @@ func (x *Initialize_Request) String() string {
 func (*Initialize_Request) ProtoMessage() {}
 
 func (x *Initialize_Request) ProtoReflect() protoreflect.Message {
-	mi := &file_pluginv2_proto_msgTypes[4]
+	mi := &file_pluginv2_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -230,7 +306,7 @@ type Initialize_Response struct {
 func (x *Initialize_Response) Reset() {
 	*x = Initialize_Response{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pluginv2_proto_msgTypes[5]
+		mi := &file_pluginv2_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -243,7 +319,7 @@ func (x *Initialize_Response) String() string {
 func (*Initialize_Response) ProtoMessage() {}
 
 func (x *Initialize_Response) ProtoReflect() protoreflect.Message {
-	mi := &file_pluginv2_proto_msgTypes[5]
+	mi := &file_pluginv2_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -268,7 +344,7 @@ type GetPluginConfig_Request struct {
 func (x *GetPluginConfig_Request) Reset() {
 	*x = GetPluginConfig_Request{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pluginv2_proto_msgTypes[6]
+		mi := &file_pluginv2_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -281,7 +357,7 @@ func (x *GetPluginConfig_Request) String() string {
 func (*GetPluginConfig_Request) ProtoMessage() {}
 
 func (x *GetPluginConfig_Request) ProtoReflect() protoreflect.Message {
-	mi := &file_pluginv2_proto_msgTypes[6]
+	mi := &file_pluginv2_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -308,7 +384,7 @@ type GetPluginConfig_Response struct {
 func (x *GetPluginConfig_Response) Reset() {
 	*x = GetPluginConfig_Response{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pluginv2_proto_msgTypes[7]
+		mi := &file_pluginv2_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -321,7 +397,7 @@ func (x *GetPluginConfig_Response) String() string {
 func (*GetPluginConfig_Response) ProtoMessage() {}
 
 func (x *GetPluginConfig_Response) ProtoReflect() protoreflect.Message {
-	mi := &file_pluginv2_proto_msgTypes[7]
+	mi := &file_pluginv2_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -358,7 +434,7 @@ type Mutate_Request struct {
 func (x *Mutate_Request) Reset() {
 	*x = Mutate_Request{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pluginv2_proto_msgTypes[8]
+		mi := &file_pluginv2_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -371,7 +447,7 @@ func (x *Mutate_Request) String() string {
 func (*Mutate_Request) ProtoMessage() {}
 
 func (x *Mutate_Request) ProtoReflect() protoreflect.Message {
-	mi := &file_pluginv2_proto_msgTypes[8]
+	mi := &file_pluginv2_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -427,7 +503,7 @@ type Mutate_Response struct {
 func (x *Mutate_Response) Reset() {
 	*x = Mutate_Response{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pluginv2_proto_msgTypes[9]
+		mi := &file_pluginv2_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -440,7 +516,7 @@ func (x *Mutate_Response) String() string {
 func (*Mutate_Response) ProtoMessage() {}
 
 func (x *Mutate_Response) ProtoReflect() protoreflect.Message {
-	mi := &file_pluginv2_proto_msgTypes[9]
+	mi := &file_pluginv2_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -470,6 +546,389 @@ func (x *Mutate_Response) GetMutated() bool {
 	return false
 }
 
+type Validate_Request struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ApiVersion  string `protobuf:"bytes,1,opt,name=apiVersion,proto3" json:"apiVersion,omitempty"`
+	Kind        string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Resource    string `protobuf:"bytes,3,opt,name=resource,proto3" json:"resource,omitempty"`
+	SubResource string `protobuf:"bytes,4,opt,name=subResource,proto3" json:"subResource,omitempty"`
+	Operation   string `protobuf:"bytes,5,opt,name=operation,proto3" json:"operation,omitempty"`
+	Namespace   string `protobuf:"bytes,6,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Name        string `protobuf:"bytes,7,opt,name=name,proto3" json:"name,omitempty"`
+	Object      string `protobuf:"bytes,8,opt,name=object,proto3" json:"object,omitempty"`
+	OldObject   string `protobuf:"bytes,9,opt,name=oldObject,proto3" json:"oldObject,omitempty"`
+	UserInfo    string `protobuf:"bytes,10,opt,name=userInfo,proto3" json:"userInfo,omitempty"`
+	DryRun      bool   `protobuf:"varint,11,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
+}
+
+func (x *Validate_Request) Reset() {
+	*x = Validate_Request{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pluginv2_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Validate_Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Validate_Request) ProtoMessage() {}
+
+func (x *Validate_Request) ProtoReflect() protoreflect.Message {
+	mi := &file_pluginv2_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Validate_Request.ProtoReflect.Descriptor instead.
+func (*Validate_Request) Descriptor() ([]byte, []int) {
+	return file_pluginv2_proto_rawDescGZIP(), []int{3, 0}
+}
+
+func (x *Validate_Request) GetApiVersion() string {
+	if x != nil {
+		return x.ApiVersion
+	}
+	return ""
+}
+
+func (x *Validate_Request) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Validate_Request) GetResource() string {
+	if x != nil {
+		return x.Resource
+	}
+	return ""
+}
+
+func (x *Validate_Request) GetSubResource() string {
+	if x != nil {
+		return x.SubResource
+	}
+	return ""
+}
+
+func (x *Validate_Request) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *Validate_Request) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *Validate_Request) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Validate_Request) GetObject() string {
+	if x != nil {
+		return x.Object
+	}
+	return ""
+}
+
+func (x *Validate_Request) GetOldObject() string {
+	if x != nil {
+		return x.OldObject
+	}
+	return ""
+}
+
+func (x *Validate_Request) GetUserInfo() string {
+	if x != nil {
+		return x.UserInfo
+	}
+	return ""
+}
+
+func (x *Validate_Request) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type Validate_Response struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Allowed bool   `protobuf:"varint,1,opt,name=allowed,proto3" json:"allowed,omitempty"`
+	Reason  string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	Code    int32  `protobuf:"varint,3,opt,name=code,proto3" json:"code,omitempty"`
+}
+
+func (x *Validate_Response) Reset() {
+	*x = Validate_Response{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pluginv2_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Validate_Response) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Validate_Response) ProtoMessage() {}
+
+func (x *Validate_Response) ProtoReflect() protoreflect.Message {
+	mi := &file_pluginv2_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Validate_Response.ProtoReflect.Descriptor instead.
+func (*Validate_Response) Descriptor() ([]byte, []int) {
+	return file_pluginv2_proto_rawDescGZIP(), []int{3, 1}
+}
+
+func (x *Validate_Response) GetAllowed() bool {
+	if x != nil {
+		return x.Allowed
+	}
+	return false
+}
+
+func (x *Validate_Response) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Validate_Response) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+type ServeHTTP_Header struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Values []string `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+}
+
+func (x *ServeHTTP_Header) Reset() {
+	*x = ServeHTTP_Header{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pluginv2_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServeHTTP_Header) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServeHTTP_Header) ProtoMessage() {}
+
+func (x *ServeHTTP_Header) ProtoReflect() protoreflect.Message {
+	mi := &file_pluginv2_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServeHTTP_Header.ProtoReflect.Descriptor instead.
+func (*ServeHTTP_Header) Descriptor() ([]byte, []int) {
+	return file_pluginv2_proto_rawDescGZIP(), []int{4, 0}
+}
+
+func (x *ServeHTTP_Header) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+// Request is streamed from the syncer to the plugin. The first message contains
+// the request line and headers, all following messages only contain body chunks.
+type ServeHTTP_Request struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Method   string                       `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	Url      string                       `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Headers  map[string]*ServeHTTP_Header `protobuf:"bytes,3,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	UserInfo string                       `protobuf:"bytes,4,opt,name=userInfo,proto3" json:"userInfo,omitempty"`
+	Body     []byte                       `protobuf:"bytes,5,opt,name=body,proto3" json:"body,omitempty"`
+}
+
+func (x *ServeHTTP_Request) Reset() {
+	*x = ServeHTTP_Request{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pluginv2_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServeHTTP_Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServeHTTP_Request) ProtoMessage() {}
+
+func (x *ServeHTTP_Request) ProtoReflect() protoreflect.Message {
+	mi := &file_pluginv2_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServeHTTP_Request.ProtoReflect.Descriptor instead.
+func (*ServeHTTP_Request) Descriptor() ([]byte, []int) {
+	return file_pluginv2_proto_rawDescGZIP(), []int{4, 1}
+}
+
+func (x *ServeHTTP_Request) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *ServeHTTP_Request) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *ServeHTTP_Request) GetHeaders() map[string]*ServeHTTP_Header {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *ServeHTTP_Request) GetUserInfo() string {
+	if x != nil {
+		return x.UserInfo
+	}
+	return ""
+}
+
+func (x *ServeHTTP_Request) GetBody() []byte {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+// Response is streamed from the plugin to the syncer. The first message contains
+// the status code and headers, all following messages only contain body chunks.
+type ServeHTTP_Response struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StatusCode int32                        `protobuf:"varint,1,opt,name=statusCode,proto3" json:"statusCode,omitempty"`
+	Headers    map[string]*ServeHTTP_Header `protobuf:"bytes,2,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Body       []byte                       `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+}
+
+func (x *ServeHTTP_Response) Reset() {
+	*x = ServeHTTP_Response{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pluginv2_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServeHTTP_Response) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServeHTTP_Response) ProtoMessage() {}
+
+func (x *ServeHTTP_Response) ProtoReflect() protoreflect.Message {
+	mi := &file_pluginv2_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServeHTTP_Response.ProtoReflect.Descriptor instead.
+func (*ServeHTTP_Response) Descriptor() ([]byte, []int) {
+	return file_pluginv2_proto_rawDescGZIP(), []int{4, 2}
+}
+
+func (x *ServeHTTP_Response) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *ServeHTTP_Response) GetHeaders() map[string]*ServeHTTP_Header {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *ServeHTTP_Response) GetBody() []byte {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
 type SetLeader_Request struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -479,7 +938,7 @@ type SetLeader_Request struct {
 func (x *SetLeader_Request) Reset() {
 	*x = SetLeader_Request{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pluginv2_proto_msgTypes[10]
+		mi := &file_pluginv2_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -492,7 +951,7 @@ func (x *SetLeader_Request) String() string {
 func (*SetLeader_Request) ProtoMessage() {}
 
 func (x *SetLeader_Request) ProtoReflect() protoreflect.Message {
-	mi := &file_pluginv2_proto_msgTypes[10]
+	mi := &file_pluginv2_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -505,7 +964,7 @@ func (x *SetLeader_Request) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetLeader_Request.ProtoReflect.Descriptor instead.
 func (*SetLeader_Request) Descriptor() ([]byte, []int) {
-	return file_pluginv2_proto_rawDescGZIP(), []int{3, 0}
+	return file_pluginv2_proto_rawDescGZIP(), []int{5, 0}
 }
 
 type SetLeader_Response struct {
@@ -517,7 +976,7 @@ type SetLeader_Response struct {
 func (x *SetLeader_Response) Reset() {
 	*x = SetLeader_Response{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pluginv2_proto_msgTypes[11]
+		mi := &file_pluginv2_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -530,7 +989,7 @@ func (x *SetLeader_Response) String() string {
 func (*SetLeader_Response) ProtoMessage() {}
 
 func (x *SetLeader_Response) ProtoReflect() protoreflect.Message {
-	mi := &file_pluginv2_proto_msgTypes[11]
+	mi := &file_pluginv2_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -543,7 +1002,7 @@ func (x *SetLeader_Response) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetLeader_Response.ProtoReflect.Descriptor instead.
 func (*SetLeader_Response) Descriptor() ([]byte, []int) {
-	return file_pluginv2_proto_rawDescGZIP(), []int{3, 1}
+	return file_pluginv2_proto_rawDescGZIP(), []int{5, 1}
 }
 
 var File_pluginv2_proto protoreflect.FileDescriptor
@@ -569,33 +1028,100 @@ var file_pluginv2_proto_rawDesc = []byte{
 	0x3c, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6f,
 	0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6f, 0x62, 0x6a,
 	0x65, 0x63, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x75, 0x74, 0x61, 0x74, 0x65, 0x64, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x6d, 0x75, 0x74, 0x61, 0x74, 0x65, 0x64, 0x22, 0x22, 0x0a,
-	0x09, 0x53, 0x65, 0x74, 0x4c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x1a, 0x09, 0x0a, 0x07, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0a, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x32, 0xb4, 0x02, 0x0a, 0x06, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x12, 0x49, 0x0a, 0x0a,
-	0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x12, 0x1c, 0x2e, 0x70, 0x6c, 0x75,
-	0x67, 0x69, 0x6e, 0x76, 0x32, 0x2e, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x65,
-	0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x70, 0x6c, 0x75, 0x67, 0x69,
-	0x6e, 0x76, 0x32, 0x2e, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x2e, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46, 0x0a, 0x09, 0x53, 0x65, 0x74, 0x4c, 0x65,
-	0x61, 0x64, 0x65, 0x72, 0x12, 0x1b, 0x2e, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x76, 0x32, 0x2e,
-	0x53, 0x65, 0x74, 0x4c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x1c, 0x2e, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x76, 0x32, 0x2e, 0x53, 0x65, 0x74,
-	0x4c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x58, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x43, 0x6f, 0x6e, 0x66,
-	0x69, 0x67, 0x12, 0x21, 0x2e, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x76, 0x32, 0x2e, 0x47, 0x65,
-	0x74, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x76, 0x32,
-	0x2e, 0x47, 0x65, 0x74, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
-	0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a, 0x06, 0x4d, 0x75, 0x74,
-	0x61, 0x74, 0x65, 0x12, 0x18, 0x2e, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x76, 0x32, 0x2e, 0x4d,
-	0x75, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e,
-	0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x76, 0x32, 0x2e, 0x4d, 0x75, 0x74, 0x61, 0x74, 0x65, 0x2e,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x34, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x68,
-	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6c, 0x6f, 0x66, 0x74, 0x2d, 0x73, 0x68, 0x2f, 0x76,
-	0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x6c, 0x75, 0x67,
-	0x69, 0x6e, 0x2f, 0x76, 0x32, 0x2f, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x76, 0x32, 0x62, 0x06,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x6d, 0x75, 0x74, 0x61, 0x74, 0x65, 0x64, 0x22, 0x94, 0x03,
+	0x0a, 0x08, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x1a, 0xb5, 0x02, 0x0a, 0x07, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x61, 0x70, 0x69, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x70, 0x69, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x73, 0x75, 0x62, 0x52, 0x65, 0x73,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x73, 0x75, 0x62,
+	0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6f, 0x70, 0x65, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6f, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70,
+	0x61, 0x63, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x62, 0x6a, 0x65,
+	0x63, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x12, 0x1c, 0x0a, 0x09, 0x6f, 0x6c, 0x64, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x6f, 0x6c, 0x64, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x1a,
+	0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x72,
+	0x79, 0x52, 0x75, 0x6e, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x64, 0x72, 0x79, 0x52,
+	0x75, 0x6e, 0x1a, 0x50, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x07, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04,
+	0x63, 0x6f, 0x64, 0x65, 0x22, 0x8d, 0x04, 0x0a, 0x09, 0x53, 0x65, 0x72, 0x76, 0x65, 0x48, 0x54,
+	0x54, 0x50, 0x1a, 0x20, 0x0a, 0x06, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x73, 0x1a, 0xff, 0x01, 0x0a, 0x07, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x42, 0x0a, 0x07, 0x68, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x70, 0x6c,
+	0x75, 0x67, 0x69, 0x6e, 0x76, 0x32, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x48, 0x54, 0x54, 0x50,
+	0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x12, 0x1a,
+	0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f,
+	0x64, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x1a, 0x56,
+	0x0a, 0x0c, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x12, 0x30, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x76, 0x32, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65,
+	0x48, 0x54, 0x54, 0x50, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0xdb, 0x01, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x6f, 0x64,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43,
+	0x6f, 0x64, 0x65, 0x12, 0x43, 0x0a, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x76, 0x32, 0x2e,
+	0x53, 0x65, 0x72, 0x76, 0x65, 0x48, 0x54, 0x54, 0x50, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x1a, 0x56, 0x0a, 0x0c,
+	0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x30,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x76, 0x32, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x48, 0x54,
+	0x54, 0x50, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x3a, 0x02, 0x38, 0x01, 0x22, 0x22, 0x0a, 0x09, 0x53, 0x65, 0x74, 0x4c, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x1a, 0x09, 0x0a, 0x07, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0a, 0x0a, 0x08,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0xc5, 0x03, 0x0a, 0x06, 0x50, 0x6c, 0x75,
+	0x67, 0x69, 0x6e, 0x12, 0x49, 0x0a, 0x0a, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x69, 0x7a,
+	0x65, 0x12, 0x1c, 0x2e, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x76, 0x32, 0x2e, 0x49, 0x6e, 0x69,
+	0x74, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1d, 0x2e, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x76, 0x32, 0x2e, 0x49, 0x6e, 0x69, 0x74, 0x69,
+	0x61, 0x6c, 0x69, 0x7a, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46,
+	0x0a, 0x09, 0x53, 0x65, 0x74, 0x4c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x1b, 0x2e, 0x70, 0x6c,
+	0x75, 0x67, 0x69, 0x6e, 0x76, 0x32, 0x2e, 0x53, 0x65, 0x74, 0x4c, 0x65, 0x61, 0x64, 0x65, 0x72,
+	0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x70, 0x6c, 0x75, 0x67, 0x69,
+	0x6e, 0x76, 0x32, 0x2e, 0x53, 0x65, 0x74, 0x4c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x2e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x58, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x50, 0x6c, 0x75,
+	0x67, 0x69, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x21, 0x2e, 0x70, 0x6c, 0x75, 0x67,
+	0x69, 0x6e, 0x76, 0x32, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x70,
+	0x6c, 0x75, 0x67, 0x69, 0x6e, 0x76, 0x32, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x6c, 0x75, 0x67, 0x69,
+	0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x3d, 0x0a, 0x06, 0x4d, 0x75, 0x74, 0x61, 0x74, 0x65, 0x12, 0x18, 0x2e, 0x70, 0x6c, 0x75,
+	0x67, 0x69, 0x6e, 0x76, 0x32, 0x2e, 0x4d, 0x75, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x76, 0x32, 0x2e,
+	0x4d, 0x75, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x43, 0x0a, 0x08, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x12, 0x1a, 0x2e, 0x70, 0x6c,
+	0x75, 0x67, 0x69, 0x6e, 0x76, 0x32, 0x2e, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2e,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e,
+	0x76, 0x32, 0x2e, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4a, 0x0a, 0x09, 0x53, 0x65, 0x72, 0x76, 0x65, 0x48, 0x54, 0x54,
+	0x50, 0x12, 0x1b, 0x2e, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x76, 0x32, 0x2e, 0x53, 0x65, 0x72,
+	0x76, 0x65, 0x48, 0x54, 0x54, 0x50, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c,
+	0x2e, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x76, 0x32, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x48,
+	0x54, 0x54, 0x50, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x30, 0x01,
+	0x42, 0x34, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6c,
+	0x6f, 0x66, 0x74, 0x2d, 0x73, 0x68, 0x2f, 0x76, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x2f,
+	0x70, 0x6b, 0x67, 0x2f, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2f, 0x76, 0x32, 0x2f, 0x70, 0x6c,
+	0x75, 0x67, 0x69, 0x6e, 0x76, 0x32, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -610,35 +1136,52 @@ func file_pluginv2_proto_rawDescGZIP() []byte {
 	return file_pluginv2_proto_rawDescData
 }
 
-var file_pluginv2_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_pluginv2_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
 var file_pluginv2_proto_goTypes = []interface{}{
 	(*Initialize)(nil),               // 0: pluginv2.Initialize
 	(*GetPluginConfig)(nil),          // 1: pluginv2.GetPluginConfig
 	(*Mutate)(nil),                   // 2: pluginv2.Mutate
-	(*SetLeader)(nil),                // 3: pluginv2.SetLeader
-	(*Initialize_Request)(nil),       // 4: pluginv2.Initialize.Request
-	(*Initialize_Response)(nil),      // 5: pluginv2.Initialize.Response
-	(*GetPluginConfig_Request)(nil),  // 6: pluginv2.GetPluginConfig.Request
-	(*GetPluginConfig_Response)(nil), // 7: pluginv2.GetPluginConfig.Response
-	(*Mutate_Request)(nil),           // 8: pluginv2.Mutate.Request
-	(*Mutate_Response)(nil),          // 9: pluginv2.Mutate.Response
-	(*SetLeader_Request)(nil),        // 10: pluginv2.SetLeader.Request
-	(*SetLeader_Response)(nil),       // 11: pluginv2.SetLeader.Response
+	(*Validate)(nil),                 // 3: pluginv2.Validate
+	(*ServeHTTP)(nil),                // 4: pluginv2.ServeHTTP
+	(*SetLeader)(nil),                // 5: pluginv2.SetLeader
+	(*Initialize_Request)(nil),       // 6: pluginv2.Initialize.Request
+	(*Initialize_Response)(nil),      // 7: pluginv2.Initialize.Response
+	(*GetPluginConfig_Request)(nil),  // 8: pluginv2.GetPluginConfig.Request
+	(*GetPluginConfig_Response)(nil), // 9: pluginv2.GetPluginConfig.Response
+	(*Mutate_Request)(nil),           // 10: pluginv2.Mutate.Request
+	(*Mutate_Response)(nil),          // 11: pluginv2.Mutate.Response
+	(*Validate_Request)(nil),         // 12: pluginv2.Validate.Request
+	(*Validate_Response)(nil),        // 13: pluginv2.Validate.Response
+	(*ServeHTTP_Header)(nil),         // 14: pluginv2.ServeHTTP.Header
+	(*ServeHTTP_Request)(nil),        // 15: pluginv2.ServeHTTP.Request
+	(*ServeHTTP_Response)(nil),       // 16: pluginv2.ServeHTTP.Response
+	nil,                              // 17: pluginv2.ServeHTTP.Request.HeadersEntry
+	nil,                              // 18: pluginv2.ServeHTTP.Response.HeadersEntry
+	(*SetLeader_Request)(nil),        // 19: pluginv2.SetLeader.Request
+	(*SetLeader_Response)(nil),       // 20: pluginv2.SetLeader.Response
 }
 var file_pluginv2_proto_depIdxs = []int32{
-	4,  // 0: pluginv2.Plugin.Initialize:input_type -> pluginv2.Initialize.Request
-	10, // 1: pluginv2.Plugin.SetLeader:input_type -> pluginv2.SetLeader.Request
-	6,  // 2: pluginv2.Plugin.GetPluginConfig:input_type -> pluginv2.GetPluginConfig.Request
-	8,  // 3: pluginv2.Plugin.Mutate:input_type -> pluginv2.Mutate.Request
-	5,  // 4: pluginv2.Plugin.Initialize:output_type -> pluginv2.Initialize.Response
-	11, // 5: pluginv2.Plugin.SetLeader:output_type -> pluginv2.SetLeader.Response
-	7,  // 6: pluginv2.Plugin.GetPluginConfig:output_type -> pluginv2.GetPluginConfig.Response
-	9,  // 7: pluginv2.Plugin.Mutate:output_type -> pluginv2.Mutate.Response
-	4,  // [4:8] is the sub-list for method output_type
-	0,  // [0:4] is the sub-list for method input_type
-	0,  // [0:0] is the sub-list for extension type_name
-	0,  // [0:0] is the sub-list for extension extendee
-	0,  // [0:0] is the sub-list for field type_name
+	17, // 0: pluginv2.ServeHTTP.Request.headers:type_name -> pluginv2.ServeHTTP.Request.HeadersEntry
+	18, // 1: pluginv2.ServeHTTP.Response.headers:type_name -> pluginv2.ServeHTTP.Response.HeadersEntry
+	14, // 2: pluginv2.ServeHTTP.Request.HeadersEntry.value:type_name -> pluginv2.ServeHTTP.Header
+	14, // 3: pluginv2.ServeHTTP.Response.HeadersEntry.value:type_name -> pluginv2.ServeHTTP.Header
+	6,  // 4: pluginv2.Plugin.Initialize:input_type -> pluginv2.Initialize.Request
+	19, // 5: pluginv2.Plugin.SetLeader:input_type -> pluginv2.SetLeader.Request
+	8,  // 6: pluginv2.Plugin.GetPluginConfig:input_type -> pluginv2.GetPluginConfig.Request
+	10, // 7: pluginv2.Plugin.Mutate:input_type -> pluginv2.Mutate.Request
+	12, // 8: pluginv2.Plugin.Validate:input_type -> pluginv2.Validate.Request
+	15, // 9: pluginv2.Plugin.ServeHTTP:input_type -> pluginv2.ServeHTTP.Request
+	7,  // 10: pluginv2.Plugin.Initialize:output_type -> pluginv2.Initialize.Response
+	20, // 11: pluginv2.Plugin.SetLeader:output_type -> pluginv2.SetLeader.Response
+	9,  // 12: pluginv2.Plugin.GetPluginConfig:output_type -> pluginv2.GetPluginConfig.Response
+	11, // 13: pluginv2.Plugin.Mutate:output_type -> pluginv2.Mutate.Response
+	13, // 14: pluginv2.Plugin.Validate:output_type -> pluginv2.Validate.Response
+	16, // 15: pluginv2.Plugin.ServeHTTP:output_type -> pluginv2.ServeHTTP.Response
+	10, // [10:16] is the sub-list for method output_type
+	4,  // [4:10] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_pluginv2_proto_init() }
@@ -684,7 +1227,7 @@ func file_pluginv2_proto_init() {
 			}
 		}
 		file_pluginv2_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SetLeader); i {
+			switch v := v.(*Validate); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -696,7 +1239,7 @@ func file_pluginv2_proto_init() {
 			}
 		}
 		file_pluginv2_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Initialize_Request); i {
+			switch v := v.(*ServeHTTP); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -708,7 +1251,7 @@ func file_pluginv2_proto_init() {
 			}
 		}
 		file_pluginv2_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Initialize_Response); i {
+			switch v := v.(*SetLeader); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -720,7 +1263,7 @@ func file_pluginv2_proto_init() {
 			}
 		}
 		file_pluginv2_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetPluginConfig_Request); i {
+			switch v := v.(*Initialize_Request); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -732,7 +1275,7 @@ func file_pluginv2_proto_init() {
 			}
 		}
 		file_pluginv2_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetPluginConfig_Response); i {
+			switch v := v.(*Initialize_Response); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -744,7 +1287,7 @@ func file_pluginv2_proto_init() {
 			}
 		}
 		file_pluginv2_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Mutate_Request); i {
+			switch v := v.(*GetPluginConfig_Request); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -756,7 +1299,7 @@ func file_pluginv2_proto_init() {
 			}
 		}
 		file_pluginv2_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Mutate_Response); i {
+			switch v := v.(*GetPluginConfig_Response); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -768,7 +1311,7 @@ func file_pluginv2_proto_init() {
 			}
 		}
 		file_pluginv2_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SetLeader_Request); i {
+			switch v := v.(*Mutate_Request); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -780,6 +1323,90 @@ func file_pluginv2_proto_init() {
 			}
 		}
 		file_pluginv2_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Mutate_Response); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pluginv2_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Validate_Request); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pluginv2_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Validate_Response); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pluginv2_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ServeHTTP_Header); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pluginv2_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ServeHTTP_Request); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pluginv2_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ServeHTTP_Response); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pluginv2_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetLeader_Request); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pluginv2_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SetLeader_Response); i {
 			case 0:
 				return &v.state
@@ -798,7 +1425,7 @@ func file_pluginv2_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pluginv2_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   12,
+			NumMessages:   21,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -26,6 +26,8 @@ type PluginClient interface {
 	SetLeader(ctx context.Context, in *SetLeader_Request, opts ...grpc.CallOption) (*SetLeader_Response, error)
 	GetPluginConfig(ctx context.Context, in *GetPluginConfig_Request, opts ...grpc.CallOption) (*GetPluginConfig_Response, error)
 	Mutate(ctx context.Context, in *Mutate_Request, opts ...grpc.CallOption) (*Mutate_Response, error)
+	Validate(ctx context.Context, in *Validate_Request, opts ...grpc.CallOption) (*Validate_Response, error)
+	ServeHTTP(ctx context.Context, opts ...grpc.CallOption) (Plugin_ServeHTTPClient, error)
 }
 
 type pluginClient struct {
@@ -72,6 +74,46 @@ func (c *pluginClient) Mutate(ctx context.Context, in *Mutate_Request, opts ...g
 	return out, nil
 }
 
+func (c *pluginClient) Validate(ctx context.Context, in *Validate_Request, opts ...grpc.CallOption) (*Validate_Response, error) {
+	out := new(Validate_Response)
+	err := c.cc.Invoke(ctx, "/pluginv2.Plugin/Validate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginClient) ServeHTTP(ctx context.Context, opts ...grpc.CallOption) (Plugin_ServeHTTPClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Plugin_ServiceDesc.Streams[0], "/pluginv2.Plugin/ServeHTTP", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &pluginServeHTTPClient{stream}
+	return x, nil
+}
+
+type Plugin_ServeHTTPClient interface {
+	Send(*ServeHTTP_Request) error
+	Recv() (*ServeHTTP_Response, error)
+	grpc.ClientStream
+}
+
+type pluginServeHTTPClient struct {
+	grpc.ClientStream
+}
+
+func (x *pluginServeHTTPClient) Send(m *ServeHTTP_Request) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *pluginServeHTTPClient) Recv() (*ServeHTTP_Response, error) {
+	m := new(ServeHTTP_Response)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // PluginServer is the server API for Plugin service.
 // All implementations must embed UnimplementedPluginServer
 // for forward compatibility
@@ -80,6 +122,8 @@ type PluginServer interface {
 	SetLeader(context.Context, *SetLeader_Request) (*SetLeader_Response, error)
 	GetPluginConfig(context.Context, *GetPluginConfig_Request) (*GetPluginConfig_Response, error)
 	Mutate(context.Context, *Mutate_Request) (*Mutate_Response, error)
+	Validate(context.Context, *Validate_Request) (*Validate_Response, error)
+	ServeHTTP(Plugin_ServeHTTPServer) error
 	mustEmbedUnimplementedPluginServer()
 }
 
@@ -99,6 +143,12 @@ func (UnimplementedPluginServer) GetPluginConfig(context.Context, *GetPluginConf
 func (UnimplementedPluginServer) Mutate(context.Context, *Mutate_Request) (*Mutate_Response, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Mutate not implemented")
 }
+func (UnimplementedPluginServer) Validate(context.Context, *Validate_Request) (*Validate_Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Validate not implemented")
+}
+func (UnimplementedPluginServer) ServeHTTP(Plugin_ServeHTTPServer) error {
+	return status.Errorf(codes.Unimplemented, "method ServeHTTP not implemented")
+}
 func (UnimplementedPluginServer) mustEmbedUnimplementedPluginServer() {}
 
 // UnsafePluginServer may be embedded to opt out of forward compatibility for this service.
@@ -184,6 +234,50 @@ func _Plugin_Mutate_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Plugin_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Validate_Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServer).Validate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pluginv2.Plugin/Validate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServer).Validate(ctx, req.(*Validate_Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Plugin_ServeHTTP_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PluginServer).ServeHTTP(&pluginServeHTTPServer{stream})
+}
+
+type Plugin_ServeHTTPServer interface {
+	Send(*ServeHTTP_Response) error
+	Recv() (*ServeHTTP_Request, error)
+	grpc.ServerStream
+}
+
+type pluginServeHTTPServer struct {
+	grpc.ServerStream
+}
+
+func (x *pluginServeHTTPServer) Send(m *ServeHTTP_Response) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *pluginServeHTTPServer) Recv() (*ServeHTTP_Request, error) {
+	m := new(ServeHTTP_Request)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Plugin_ServiceDesc is the grpc.ServiceDesc for Plugin service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -207,7 +301,18 @@ var Plugin_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Mutate",
 			Handler:    _Plugin_Mutate_Handler,
 		},
+		{
+			MethodName: "Validate",
+			Handler:    _Plugin_Validate_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ServeHTTP",
+			Handler:       _Plugin_ServeHTTP_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "pluginv2.proto",
 }
@@ -0,0 +1,243 @@
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	plugintypes "github.com/loft-sh/vcluster/pkg/plugin/types"
+	"github.com/loft-sh/vcluster/pkg/plugin/v2/pluginv2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+const (
+	PhaseRunning    = "Running"
+	PhaseRestarting = "Restarting"
+	PhaseFailed     = "Failed"
+)
+
+var (
+	// HealthCheckInterval is the interval in which plugins are checked
+	HealthCheckInterval = 10 * time.Second
+
+	// InitialRestartBackoff is the time to wait before the first restart attempt of a plugin, every failed attempt
+	// doubles the time up to MaxRestartBackoff
+	InitialRestartBackoff = 5 * time.Second
+	MaxRestartBackoff     = 5 * time.Minute
+)
+
+var (
+	metricsRegistry = prometheus.NewRegistry()
+
+	pluginUp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "vcluster_plugin_up",
+		Help: "Whether the plugin is running and healthy (1) or not (0).",
+	}, []string{"plugin", "optional"})
+	pluginRestarts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "vcluster_plugin_restarts_total",
+		Help: "Number of times the plugin was restarted.",
+	}, []string{"plugin"})
+	pluginHealthCheckFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "vcluster_plugin_health_check_failures_total",
+		Help: "Number of failed plugin health checks.",
+	}, []string{"plugin"})
+)
+
+func init() {
+	metricsRegistry.MustRegister(pluginUp, pluginRestarts, pluginHealthCheckFailures)
+}
+
+// pluginState is the supervision state of a plugin
+type pluginState struct {
+	lock sync.RWMutex
+
+	phase              string
+	restarts           int
+	lastError          string
+	lastTransitionTime time.Time
+
+	// nextRestart is the earliest time the next restart is attempted
+	nextRestart time.Time
+	backoff     time.Duration
+}
+
+func (p *vClusterPlugin) setPhase(phase string, err error) {
+	p.state.lock.Lock()
+	defer p.state.lock.Unlock()
+
+	if p.state.phase != phase {
+		p.state.lastTransitionTime = time.Now()
+	}
+	p.state.phase = phase
+	if err != nil {
+		p.state.lastError = err.Error()
+	}
+
+	up := 0.0
+	if phase == PhaseRunning {
+		up = 1
+	}
+	pluginUp.WithLabelValues(p.Name, fmt.Sprint(p.Optional)).Set(up)
+}
+
+func (p *vClusterPlugin) isRunning() bool {
+	p.state.lock.RLock()
+	defer p.state.lock.RUnlock()
+
+	return p.state.phase == PhaseRunning
+}
+
+// checkAvailable returns if the plugin should be called. Optional plugins that are not running are skipped,
+// required plugins that are not running return an error.
+func (p *vClusterPlugin) checkAvailable() (bool, error) {
+	if p.isRunning() {
+		return true, nil
+	} else if p.Optional {
+		return false, nil
+	}
+
+	return false, fmt.Errorf("plugin %s is not running", p.Path)
+}
+
+func (p *vClusterPlugin) status() plugintypes.PluginStatus {
+	p.state.lock.RLock()
+	defer p.state.lock.RUnlock()
+
+	return plugintypes.PluginStatus{
+		Name:               p.Name,
+		Path:               p.Path,
+		Optional:           p.Optional,
+		Phase:              p.state.phase,
+		Restarts:           p.state.restarts,
+		LastError:          p.state.lastError,
+		LastTransitionTime: p.state.lastTransitionTime,
+		Hooks:              p.hooks,
+	}
+}
+
+// healthCheck checks that the plugin process is alive and its grpc server responds
+func (p *vClusterPlugin) healthCheck() error {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if p.Client == nil || p.protocol == nil {
+		return fmt.Errorf("plugin was not started")
+	} else if p.Client.Exited() {
+		return fmt.Errorf("plugin process exited")
+	}
+
+	return p.protocol.Ping()
+}
+
+// supervise checks the health of the plugin periodically and restarts it if needed
+func (m *Manager) supervise(ctx context.Context, p *vClusterPlugin) {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if p.isRunning() {
+			err := p.healthCheck()
+			if err == nil {
+				return
+			}
+
+			klog.FromContext(ctx).Error(err, "plugin health check failed, restarting plugin", "plugin", p.Path)
+			pluginHealthCheckFailures.WithLabelValues(p.Name).Inc()
+			p.setPhase(PhaseRestarting, err)
+		}
+
+		// wait for the backoff to pass
+		p.state.lock.RLock()
+		nextRestart := p.state.nextRestart
+		p.state.lock.RUnlock()
+		if time.Now().Before(nextRestart) {
+			return
+		}
+
+		m.restartPlugin(ctx, p)
+	}, HealthCheckInterval)
+}
+
+func (m *Manager) restartPlugin(ctx context.Context, p *vClusterPlugin) {
+	p.state.lock.Lock()
+	p.state.restarts++
+	p.state.lock.Unlock()
+	pluginRestarts.WithLabelValues(p.Name).Inc()
+
+	err := m.startPlugin(ctx, p)
+	if err == nil {
+		klog.FromContext(ctx).Info("Successfully restarted plugin", "plugin", p.Path)
+		return
+	}
+
+	// backoff the next restart
+	p.state.lock.Lock()
+	if p.state.backoff == 0 {
+		p.state.backoff = InitialRestartBackoff
+	} else {
+		p.state.backoff = min(p.state.backoff*2, MaxRestartBackoff)
+	}
+	p.state.nextRestart = time.Now().Add(p.state.backoff)
+	backoff := p.state.backoff
+	p.state.lock.Unlock()
+
+	klog.FromContext(ctx).Error(err, "error restarting plugin", "plugin", p.Path, "retryIn", backoff.String())
+}
+
+// startPlugin (re-)starts the plugin process, initializes it and signals leadership if needed
+func (m *Manager) startPlugin(ctx context.Context, p *vClusterPlugin) error {
+	err := m.loadPlugin(p)
+	if err != nil {
+		p.setPhase(PhaseFailed, err)
+		return fmt.Errorf("load plugin: %w", err)
+	}
+
+	err = m.initializePlugin(ctx, p)
+	if err != nil {
+		p.setPhase(PhaseFailed, err)
+		return err
+	}
+
+	if m.isLeader() {
+		_, err = p.grpcClient().SetLeader(ctx, &pluginv2.SetLeader_Request{})
+		if err != nil {
+			err = fmt.Errorf("error setting leader in plugin %s: %w", p.Path, err)
+			p.setPhase(PhaseFailed, err)
+			return err
+		}
+	}
+
+	p.state.lock.Lock()
+	p.state.backoff = 0
+	p.state.nextRestart = time.Time{}
+	p.state.lock.Unlock()
+	p.setPhase(PhaseRunning, nil)
+	return nil
+}
+
+func (m *Manager) PluginStatus() []plugintypes.PluginStatus {
+	// hooks are registered under the manager lock
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	statuses := make([]plugintypes.PluginStatus, 0, len(m.Plugins))
+	for _, p := range m.Plugins {
+		statuses = append(statuses, p.status())
+	}
+
+	return statuses
+}
+
+// StatusHandler serves the plugin status as json at /status and the plugin metrics at /metrics
+func (m *Manager) StatusHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(m.PluginStatus())
+	})
+	mux.Handle("/metrics", promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{}))
+	return mux
+}
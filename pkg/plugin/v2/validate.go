@@ -0,0 +1,162 @@
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	plugintypes "github.com/loft-sh/vcluster/pkg/plugin/types"
+	"github.com/loft-sh/vcluster/pkg/plugin/v2/pluginv2"
+	"github.com/loft-sh/vcluster/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/klog/v2"
+)
+
+var validOperations = sets.New("CREATE", "UPDATE", "DELETE", "CONNECT")
+
+func (m *Manager) HasValidatingHooksForType(versionKindType plugintypes.VersionKindType) bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return len(m.ValidatingHooks[versionKindType]) > 0
+}
+
+// ValidateRequest calls all plugins that registered a validating hook for the request. The first plugin that denies
+// the request wins, in which case a status error with the reason of the plugin is returned.
+func (m *Manager) ValidateRequest(ctx context.Context, request *plugintypes.ValidationRequest) error {
+	m.lock.RLock()
+	validatingHooks := m.ValidatingHooks[plugintypes.VersionKindType{
+		APIVersion: request.APIVersion,
+		Kind:       request.Kind,
+		Type:       request.Operation,
+	}]
+	m.lock.RUnlock()
+	if len(validatingHooks) == 0 {
+		return nil
+	}
+
+	validateRequest, err := buildValidateRequest(request)
+	if err != nil {
+		return err
+	}
+
+	for _, validatingHook := range validatingHooks {
+		available, err := validatingHook.checkAvailable()
+		if err != nil {
+			return err
+		} else if !available {
+			continue
+		}
+
+		response, err := m.validateRequest(ctx, validateRequest, validatingHook)
+		if err != nil {
+			return err
+		} else if !response.Allowed {
+			return denied(request, validatingHook, response)
+		}
+	}
+
+	return nil
+}
+
+func (m *Manager) validateRequest(ctx context.Context, request *pluginv2.Validate_Request, plugin *vClusterPlugin) (_ *pluginv2.Validate_Response, err error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*10)
+	defer cancel()
+
+	ctx, span := tracing.StartSpan(ctx, "plugin Validate",
+		attribute.String("vcluster.plugin", plugin.Path),
+		attribute.String("k8s.apiVersion", request.ApiVersion),
+		attribute.String("k8s.kind", request.Kind),
+		attribute.String("vcluster.operation", request.Operation),
+	)
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	klog.FromContext(ctx).V(1).Info("calling plugin to validate request", "plugin", plugin.Path, "apiVersion", request.ApiVersion, "kind", request.Kind, "operation", request.Operation)
+	response, err := plugin.grpcClient().Validate(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("call plugin validate %s: %w", plugin.Path, err)
+	}
+
+	return response, nil
+}
+
+func buildValidateRequest(request *plugintypes.ValidationRequest) (*pluginv2.Validate_Request, error) {
+	userInfo, err := encodeUserInfo(request.UserInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pluginv2.Validate_Request{
+		ApiVersion:  request.APIVersion,
+		Kind:        request.Kind,
+		Resource:    request.Resource,
+		SubResource: request.SubResource,
+		Operation:   request.Operation,
+		Namespace:   request.Namespace,
+		Name:        request.Name,
+		Object:      string(request.Object),
+		OldObject:   string(request.OldObject),
+		UserInfo:    userInfo,
+		DryRun:      request.DryRun,
+	}, nil
+}
+
+func denied(request *plugintypes.ValidationRequest, plugin *vClusterPlugin, response *pluginv2.Validate_Response) error {
+	code := response.Code
+	if code == 0 {
+		code = http.StatusForbidden
+	}
+
+	reason := response.Reason
+	if reason == "" {
+		reason = fmt.Sprintf("denied by plugin %s", plugin.Path)
+	}
+
+	gv, _ := schema.ParseGroupVersion(request.APIVersion)
+	return &kerrors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    code,
+		Reason:  metav1.StatusReasonForbidden,
+		Message: fmt.Sprintf("%s %q is forbidden: %s", request.Resource, request.Name, reason),
+		Details: &metav1.StatusDetails{
+			Name:  request.Name,
+			Group: gv.Group,
+			Kind:  request.Resource,
+		},
+	}}
+}
+
+func encodeUserInfo(userInfo user.Info) (string, error) {
+	if userInfo == nil {
+		return "", nil
+	}
+
+	out := authenticationv1.UserInfo{
+		Username: userInfo.GetName(),
+		UID:      userInfo.GetUID(),
+		Groups:   userInfo.GetGroups(),
+	}
+	if len(userInfo.GetExtra()) > 0 {
+		out.Extra = map[string]authenticationv1.ExtraValue{}
+		for k, v := range userInfo.GetExtra() {
+			out.Extra[k] = v
+		}
+	}
+
+	encoded, err := json.Marshal(out)
+	if err != nil {
+		return "", fmt.Errorf("encode user info: %w", err)
+	}
+
+	return string(encoded), nil
+}
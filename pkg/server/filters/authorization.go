@@ -0,0 +1,46 @@
+package filters
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/util/clienthelper"
+	requestpkg "github.com/loft-sh/vcluster/pkg/util/request"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// WithNonResourceAuthorization authorizes requests to paths that are served by vCluster itself against the virtual
+// cluster RBAC. These paths never reach the virtual cluster api server, which would otherwise authorize them.
+func WithNonResourceAuthorization(handler http.Handler, uncachedVirtualClient client.Client) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		userInfo, ok := request.UserFrom(req.Context())
+		if !ok {
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, fmt.Errorf("user info is missing"))
+			return
+		}
+
+		// the request info holds the full path, the url might have been stripped by the router already
+		path, verb := req.URL.Path, strings.ToLower(req.Method)
+		if info, ok := request.RequestInfoFrom(req.Context()); ok {
+			if info.Path != "" {
+				path = info.Path
+			}
+			if info.Verb != "" {
+				verb = info.Verb
+			}
+		}
+
+		allowed, reason, err := clienthelper.AuthorizeNonResource(req.Context(), uncachedVirtualClient, userInfo, path, verb)
+		if err != nil {
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
+			return
+		} else if !allowed {
+			requestpkg.FailWithStatus(w, req, http.StatusForbidden, fmt.Errorf("forbidden: user is not allowed to %s path %s: %s", verb, path, reason))
+			return
+		}
+
+		handler.ServeHTTP(w, req)
+	})
+}
@@ -0,0 +1,47 @@
+package filters
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestWithNonResourceAuthorization(t *testing.T) {
+	// only admins are allowed to get the full path, even if the router stripped the url
+	virtualClient := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(_ context.Context, _ client.WithWatch, obj client.Object, _ ...client.CreateOption) error {
+			accessReview := obj.(*authorizationv1.SubjectAccessReview)
+			accessReview.Status.Allowed = accessReview.Spec.User == "admin" && accessReview.Spec.NonResourceAttributes.Path == "/vcluster/plugins/status" && accessReview.Spec.NonResourceAttributes.Verb == "get"
+			return nil
+		},
+	}).Build()
+	h := WithNonResourceAuthorization(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), virtualClient)
+
+	testCases := []struct {
+		name         string
+		user         string
+		expectedCode int
+	}{
+		{name: "denied", user: "developer", expectedCode: http.StatusForbidden},
+		{name: "allowed", user: "admin", expectedCode: http.StatusOK},
+	}
+	for _, testCase := range testCases {
+		req := httptest.NewRequest(http.MethodGet, "/status", nil)
+		ctx := request.WithRequestInfo(req.Context(), &request.RequestInfo{Path: "/vcluster/plugins/status", Verb: "get"})
+		req = req.WithContext(request.WithUser(ctx, &user.DefaultInfo{Name: testCase.user, Groups: []string{"system:authenticated"}}))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		assert.Equal(t, w.Code, testCase.expectedCode, testCase.name)
+	}
+}
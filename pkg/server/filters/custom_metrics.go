@@ -0,0 +1,247 @@
+package filters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/metricsapiservice"
+	"github.com/loft-sh/vcluster/pkg/server/handler"
+	requestpkg "github.com/loft-sh/vcluster/pkg/util/request"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// customMetricsRequest is a parsed request to the custom or external metrics api
+type customMetricsRequest struct {
+	group string
+
+	// namespace is the virtual namespace of the request
+	namespace string
+
+	// resource is the group resource of the described objects, empty for external metrics and metrics of the
+	// namespace itself
+	resource string
+
+	// name is the name of the described object or * for all objects
+	name string
+}
+
+// WithCustomMetricsProxy proxies the custom.metrics.k8s.io and external.metrics.k8s.io apis to the metrics adapters
+// of the host cluster. Namespaces, object names and label selectors are translated to the host cluster and the
+// described objects of the response are translated back to the virtual objects.
+func WithCustomMetricsProxy(h http.Handler, hostConfig *rest.Config, cachedVirtualClient client.Client, customMetrics, externalMetrics bool) http.Handler {
+	s := serializer.NewCodecFactory(cachedVirtualClient.Scheme())
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		group, parts, ok := splitMetricsAPIPath(req.URL.Path)
+		if !ok || (group == metricsapiservice.CustomMetricsGroupName && !customMetrics) || (group == metricsapiservice.ExternalMetricsGroupName && !externalMetrics) {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		proxyHandler, err := handler.Handler("", hostConfig, nil)
+		if err != nil {
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
+			return
+		}
+		req.Header.Del("Authorization")
+
+		// discovery requests are passed through
+		if len(parts) <= 3 {
+			code, header, data, err := executeRequest(req, proxyHandler)
+			if err != nil {
+				responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
+				return
+			}
+
+			writeWithHeader(w, code, header, data)
+			return
+		}
+
+		metricsRequest, err := parseCustomMetricsRequest(group, parts[3:])
+		if err != nil {
+			responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
+			return
+		}
+
+		// translate the request to the host cluster
+		hostParts := append([]string{}, parts...)
+		hostParts[4] = translate.Default.PhysicalNamespace(metricsRequest.namespace)
+		if metricsRequest.resource != "" {
+			if metricsRequest.name != "*" {
+				hostParts[6] = translate.Default.PhysicalName(metricsRequest.name, metricsRequest.namespace)
+			}
+
+			// the label selector of custom metrics selects the described objects, while external metrics use it for metric labels
+			err = translateLabelSelectors(req)
+			if err != nil {
+				requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
+				return
+			}
+		}
+		req.URL.Path = "/" + strings.Join(hostParts, "/")
+		req.Header.Set("Accept", "application/json")
+
+		code, header, data, err := executeRequest(req, proxyHandler)
+		if err != nil {
+			responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
+			return
+		} else if code != http.StatusOK || group == metricsapiservice.ExternalMetricsGroupName {
+			// external metrics don't describe any objects, so there is nothing to rewrite
+			writeWithHeader(w, code, header, data)
+			return
+		}
+
+		data, err = rewriteCustomMetrics(req.Context(), cachedVirtualClient, metricsRequest, data)
+		if err != nil {
+			klog.Infof("error rewriting custom metrics %v", err)
+			responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(data)
+	})
+}
+
+// splitMetricsAPIPath returns the group and the path parts if the path belongs to the custom or external metrics api
+func splitMetricsAPIPath(path string) (string, []string, bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) < 2 || parts[0] != "apis" {
+		return "", nil, false
+	} else if parts[1] != metricsapiservice.CustomMetricsGroupName && parts[1] != metricsapiservice.ExternalMetricsGroupName {
+		return "", nil, false
+	}
+
+	return parts[1], parts, true
+}
+
+// parseCustomMetricsRequest parses the path parts after /apis/GROUP/VERSION. Supported paths are:
+// custom metrics:   namespaces/NAMESPACE/metrics/METRIC (multi namespace mode only) and namespaces/NAMESPACE/RESOURCE/NAME/METRIC
+// external metrics: namespaces/NAMESPACE/METRIC
+func parseCustomMetricsRequest(group string, parts []string) (*customMetricsRequest, error) {
+	if len(parts) < 3 || parts[0] != "namespaces" {
+		return nil, kerrors.NewForbidden(schema.GroupResource{Group: group}, "", fmt.Errorf("only namespaced metrics are supported within the virtual cluster"))
+	}
+
+	metricsRequest := &customMetricsRequest{
+		group:     group,
+		namespace: parts[1],
+	}
+	switch {
+	case group == metricsapiservice.ExternalMetricsGroupName && len(parts) == 3:
+	case group == metricsapiservice.CustomMetricsGroupName && len(parts) == 4 && parts[2] == "metrics":
+		// metrics of the namespace itself would aggregate the workloads of all virtual namespaces that share the
+		// host namespace, so they are only supported if every virtual namespace has its own host namespace
+		if translate.Default.SingleNamespaceTarget() {
+			return nil, kerrors.NewForbidden(schema.GroupResource{Group: group}, parts[3], fmt.Errorf("namespace metrics are only supported in multi namespace mode"))
+		}
+	case group == metricsapiservice.CustomMetricsGroupName && len(parts) == 5:
+		metricsRequest.resource = parts[2]
+		metricsRequest.name = parts[3]
+	default:
+		return nil, kerrors.NewNotFound(schema.GroupResource{Group: group}, strings.Join(parts, "/"))
+	}
+
+	return metricsRequest, nil
+}
+
+// rewriteCustomMetrics translates the described objects of a MetricValueList back to the virtual objects and removes
+// all values of host objects that don't belong to the virtual namespace
+func rewriteCustomMetrics(ctx context.Context, vClient client.Client, metricsRequest *customMetricsRequest, data []byte) ([]byte, error) {
+	metricValueList := map[string]interface{}{}
+	err := json.Unmarshal(data, &metricValueList)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal metric value list: %w", err)
+	}
+
+	items, _, err := unstructured.NestedSlice(metricValueList, "items")
+	if err != nil {
+		return nil, err
+	}
+
+	// build a map of host names to virtual names
+	hostToVirtual := map[string]string{}
+	if metricsRequest.resource == "" {
+		hostToVirtual[translate.Default.PhysicalNamespace(metricsRequest.namespace)] = metricsRequest.namespace
+	} else if metricsRequest.name != "*" {
+		hostToVirtual[translate.Default.PhysicalName(metricsRequest.name, metricsRequest.namespace)] = metricsRequest.name
+	} else {
+		vNames, err := listVirtualNames(ctx, vClient, metricsRequest.resource, metricsRequest.namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, vName := range vNames {
+			hostToVirtual[translate.Default.PhysicalName(vName, metricsRequest.namespace)] = vName
+		}
+	}
+
+	filteredItems := []interface{}{}
+	for _, item := range items {
+		itemMap, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		hostName, _, _ := unstructured.NestedString(itemMap, "describedObject", "name")
+		vName, ok := hostToVirtual[hostName]
+		if !ok {
+			continue
+		}
+
+		err = unstructured.SetNestedField(itemMap, vName, "describedObject", "name")
+		if err != nil {
+			return nil, err
+		}
+		if metricsRequest.resource != "" {
+			err = unstructured.SetNestedField(itemMap, metricsRequest.namespace, "describedObject", "namespace")
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		filteredItems = append(filteredItems, itemMap)
+	}
+
+	err = unstructured.SetNestedSlice(metricValueList, filteredItems, "items")
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(metricValueList)
+}
+
+// listVirtualNames returns the names of all virtual objects of the given resource in the given namespace
+func listVirtualNames(ctx context.Context, vClient client.Client, resource, namespace string) ([]string, error) {
+	gvk, err := vClient.RESTMapper().KindFor(schema.ParseGroupResource(resource).WithVersion(""))
+	if err != nil {
+		return nil, kerrors.NewBadRequest(fmt.Sprintf("unknown resource %s: %v", resource, err))
+	}
+
+	list := &metav1.PartialObjectMetadataList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	err = vClient.List(ctx, list, client.InNamespace(namespace))
+	if err != nil {
+		return nil, fmt.Errorf("list virtual %s: %w", resource, err)
+	}
+
+	names := make([]string, 0, len(list.Items))
+	for _, item := range list.Items {
+		names = append(names, item.Name)
+	}
+
+	return names, nil
+}
@@ -0,0 +1,113 @@
+package filters
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/metricsapiservice"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestParseCustomMetricsRequest(t *testing.T) {
+	testCases := []struct {
+		name     string
+		path     string
+		expected *customMetricsRequest
+		err      bool
+	}{
+		{
+			name:     "object metrics",
+			path:     "/apis/custom.metrics.k8s.io/v1beta2/namespaces/test/pods/*/http_requests",
+			expected: &customMetricsRequest{group: metricsapiservice.CustomMetricsGroupName, namespace: "test", resource: "pods", name: "*"},
+		},
+		{
+			name: "namespace metrics in single namespace mode",
+			path: "/apis/custom.metrics.k8s.io/v1beta2/namespaces/test/metrics/queue_length",
+			err:  true,
+		},
+		{
+			name:     "external metrics",
+			path:     "/apis/external.metrics.k8s.io/v1beta1/namespaces/test/queue_length",
+			expected: &customMetricsRequest{group: metricsapiservice.ExternalMetricsGroupName, namespace: "test"},
+		},
+		{
+			name: "root scoped metrics",
+			path: "/apis/custom.metrics.k8s.io/v1beta2/nodes/node-1/cpu",
+			err:  true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		group, parts, ok := splitMetricsAPIPath(testCase.path)
+		assert.Assert(t, ok, testCase.name)
+
+		metricsRequest, err := parseCustomMetricsRequest(group, parts[3:])
+		if testCase.err {
+			assert.Assert(t, err != nil, testCase.name)
+			continue
+		}
+
+		assert.NilError(t, err, testCase.name)
+		assert.Equal(t, *metricsRequest, *testCase.expected, testCase.name)
+	}
+
+	_, _, ok := splitMetricsAPIPath("/apis/metrics.k8s.io/v1beta1/pods")
+	assert.Assert(t, !ok)
+
+	// every virtual namespace has its own host namespace in multi namespace mode
+	defaultTranslator := translate.Default
+	defer func() { translate.Default = defaultTranslator }()
+	translate.Default = translate.NewMultiNamespaceTranslator("vcluster")
+
+	group, parts, _ := splitMetricsAPIPath("/apis/custom.metrics.k8s.io/v1beta2/namespaces/test/metrics/queue_length")
+	metricsRequest, err := parseCustomMetricsRequest(group, parts[3:])
+	assert.NilError(t, err)
+	assert.Equal(t, *metricsRequest, customMetricsRequest{group: metricsapiservice.CustomMetricsGroupName, namespace: "test"})
+}
+
+func TestRewriteCustomMetrics(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Pod"), meta.RESTScopeNamespace)
+	vClient := fake.NewClientBuilder().WithScheme(testingutil.NewScheme()).WithRESTMapper(mapper).WithObjects(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "test"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "other"}},
+	).Build()
+
+	hostNamespace := translate.Default.PhysicalNamespace("test")
+	data, err := json.Marshal(map[string]interface{}{
+		"kind":       "MetricValueList",
+		"apiVersion": "custom.metrics.k8s.io/v1beta2",
+		"items": []interface{}{
+			map[string]interface{}{
+				"describedObject": map[string]interface{}{"kind": "Pod", "namespace": hostNamespace, "name": translate.Default.PhysicalName("a", "test")},
+				"value":           "10",
+			},
+			map[string]interface{}{
+				"describedObject": map[string]interface{}{"kind": "Pod", "namespace": hostNamespace, "name": translate.Default.PhysicalName("b", "other")},
+				"value":           "20",
+			},
+		},
+	})
+	assert.NilError(t, err)
+
+	out, err := rewriteCustomMetrics(context.TODO(), vClient, &customMetricsRequest{
+		group:     metricsapiservice.CustomMetricsGroupName,
+		namespace: "test",
+		resource:  "pods",
+		name:      "*",
+	}, data)
+	assert.NilError(t, err)
+
+	result := map[string]interface{}{}
+	assert.NilError(t, json.Unmarshal(out, &result))
+	items := result["items"].([]interface{})
+	assert.Equal(t, len(items), 1)
+	assert.DeepEqual(t, items[0].(map[string]interface{})["describedObject"], map[string]interface{}{"kind": "Pod", "namespace": "test", "name": "a"})
+}
@@ -0,0 +1,267 @@
+package filters
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/loft-sh/vcluster/pkg/util/clienthelper"
+	"github.com/loft-sh/vcluster/pkg/util/encoding"
+	requestpkg "github.com/loft-sh/vcluster/pkg/util/request"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/handlers/negotiation"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// WithEvictionPassthrough translates evictions of virtual pods to their host pods, so that host pod disruption
+// budgets are respected as well. The eviction is first tried as dry run in the virtual cluster to check permissions,
+// then the virtual pod disruption budget is consumed like the apiserver would before the host pod is evicted.
+func WithEvictionPassthrough(handler http.Handler, uncachedLocalClient, uncachedVirtualClient client.Client, virtualConfig *rest.Config, eventRecorder record.EventRecorder) http.Handler {
+	decoder := encoding.NewDecoder(uncachedVirtualClient.Scheme(), false)
+	s := serializer.NewCodecFactory(uncachedVirtualClient.Scheme())
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		info, ok := request.RequestInfoFrom(req.Context())
+		if !ok {
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, fmt.Errorf("request info is missing"))
+			return
+		}
+
+		if info.APIGroup != corev1.SchemeGroupVersion.Group || info.Resource != "pods" || info.Subresource != "eviction" || info.Verb != "create" {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		userInfo, ok := request.UserFrom(req.Context())
+		if !ok {
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, fmt.Errorf("user info is missing"))
+			return
+		}
+
+		rawObj, err := io.ReadAll(req.Body)
+		if err != nil {
+			responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
+			return
+		}
+
+		eviction, err := decodeEviction(decoder, rawObj)
+		if err != nil {
+			responsewriters.ErrorNegotiated(kerrors.NewBadRequest(err.Error()), s, corev1.SchemeGroupVersion, w, req)
+			return
+		}
+
+		// dry run evictions only concern the virtual cluster. Like the apiserver, dry run can be requested through the
+		// query or the delete options.
+		if isDryRun(req) || (eviction.DeleteOptions != nil && len(eviction.DeleteOptions.DryRun) > 0) {
+			serveWithBody(handler, w, req, rawObj)
+			return
+		}
+
+		// find the host pod, if there is none, the virtual cluster can handle the eviction itself
+		vPod := &corev1.Pod{}
+		err = uncachedVirtualClient.Get(req.Context(), types.NamespacedName{Namespace: info.Namespace, Name: info.Name}, vPod)
+		if err != nil {
+			serveWithBody(handler, w, req, rawObj)
+			return
+		}
+		pPod := &corev1.Pod{}
+		err = uncachedLocalClient.Get(req.Context(), types.NamespacedName{Namespace: translate.Default.PhysicalNamespace(vPod.Namespace), Name: translate.Default.PhysicalName(vPod.Name, vPod.Namespace)}, pPod)
+		if err != nil || pPod.DeletionTimestamp != nil {
+			serveWithBody(handler, w, req, rawObj)
+			return
+		}
+
+		status, err := evictHostPod(req.Context(), uncachedLocalClient, uncachedVirtualClient, virtualConfig, userInfo, eventRecorder, vPod, pPod, eviction)
+		if err != nil {
+			responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
+			return
+		}
+
+		responsewriters.WriteObjectNegotiated(s, negotiation.DefaultEndpointRestrictions, metav1.SchemeGroupVersion, w, req, http.StatusCreated, status, false)
+	})
+}
+
+func evictHostPod(
+	ctx context.Context,
+	localClient, virtualClient client.Client,
+	virtualConfig *rest.Config,
+	userInfo user.Info,
+	eventRecorder record.EventRecorder,
+	vPod, pPod *corev1.Pod,
+	eviction *policyv1.Eviction,
+) (*metav1.Status, error) {
+	// check permissions and virtual pod disruption budgets by evicting with dry run as the user
+	impersonatingClient, err := clienthelper.NewImpersonatingClient(virtualConfig, virtualClient.RESTMapper(), userInfo, virtualClient.Scheme())
+	if err != nil {
+		return nil, err
+	}
+
+	deleteOptions := &metav1.DeleteOptions{}
+	if eviction.DeleteOptions != nil {
+		deleteOptions = eviction.DeleteOptions.DeepCopy()
+	}
+	dryRunOptions := deleteOptions.DeepCopy()
+	dryRunOptions.DryRun = []string{metav1.DryRunAll}
+	err = impersonatingClient.SubResource("eviction").Create(ctx, vPod, &policyv1.Eviction{
+		ObjectMeta:    metav1.ObjectMeta{Name: vPod.Name, Namespace: vPod.Namespace},
+		DeleteOptions: dryRunOptions,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the dry run doesn't consume the budget, so concurrent evictions would all pass it
+	err = consumeDisruptionBudget(ctx, virtualClient, vPod)
+	if err != nil {
+		return nil, err
+	}
+
+	// evict the host pod, preconditions refer to the virtual pod and were checked by the dry run already
+	deleteOptions.Preconditions = metav1.NewUIDPreconditions(string(pPod.UID))
+	err = localClient.SubResource("eviction").Create(ctx, pPod, &policyv1.Eviction{
+		ObjectMeta:    metav1.ObjectMeta{Name: pPod.Name, Namespace: pPod.Namespace},
+		DeleteOptions: deleteOptions,
+	})
+	if err != nil {
+		if kerrors.IsTooManyRequests(err) {
+			klog.FromContext(ctx).Info("Eviction of host pod was blocked", "pod", client.ObjectKeyFromObject(vPod), "error", err)
+			eventRecorder.Eventf(vPod, corev1.EventTypeWarning, "EvictionBlocked", "Eviction was blocked in the host cluster: %v", err)
+			return nil, translateEvictionError(err)
+		} else if kerrors.IsNotFound(err) {
+			return nil, kerrors.NewNotFound(corev1.Resource("pods"), vPod.Name)
+		}
+
+		return nil, err
+	}
+
+	// the pod syncer records the eviction on the virtual pod as soon as the host pod is terminating
+	return &metav1.Status{
+		Status: metav1.StatusSuccess,
+		Code:   http.StatusCreated,
+	}, nil
+}
+
+// consumeDisruptionBudget records the pod as disrupted in its virtual pod disruption budget and decrements the allowed
+// disruptions, which is what the apiserver does for evictions. The update is rejected on conflicts, so concurrent
+// evictions can't exceed the budget.
+func consumeDisruptionBudget(ctx context.Context, virtualClient client.Client, vPod *corev1.Pod) error {
+	// pods that aren't running don't count against the budget
+	if vPod.DeletionTimestamp != nil || vPod.Status.Phase == corev1.PodSucceeded || vPod.Status.Phase == corev1.PodFailed || vPod.Status.Phase == corev1.PodPending {
+		return nil
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pdbList := &policyv1.PodDisruptionBudgetList{}
+		err := virtualClient.List(ctx, pdbList, client.InNamespace(vPod.Namespace))
+		if err != nil {
+			return err
+		}
+
+		var pdbs []policyv1.PodDisruptionBudget
+		for _, pdb := range pdbList.Items {
+			if pdb.Spec.Selector == nil {
+				continue
+			}
+			selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+			if err != nil || !selector.Matches(labels.Set(vPod.Labels)) {
+				continue
+			}
+
+			pdbs = append(pdbs, pdb)
+		}
+		if len(pdbs) == 0 {
+			return nil
+		} else if len(pdbs) > 1 {
+			return kerrors.NewInternalError(fmt.Errorf("this pod has more than one PodDisruptionBudget, which the eviction subresource does not support"))
+		}
+
+		pdb := &pdbs[0]
+		if !isPodReady(vPod) && (pdb.Spec.UnhealthyPodEvictionPolicy != nil && *pdb.Spec.UnhealthyPodEvictionPolicy == policyv1.AlwaysAllow || pdb.Status.CurrentHealthy >= pdb.Status.DesiredHealthy && pdb.Status.DesiredHealthy > 0) {
+			return nil
+		} else if pdb.Status.ObservedGeneration < pdb.Generation {
+			return kerrors.NewTooManyRequests(fmt.Sprintf("Cannot evict pod as it would violate the pod's disruption budget. The disruption budget %s is still being processed by the server.", pdb.Name), 10)
+		} else if pdb.Status.DisruptionsAllowed <= 0 {
+			return kerrors.NewTooManyRequests(fmt.Sprintf("Cannot evict pod as it would violate the pod's disruption budget. The disruption budget %s needs %d healthy pods and has %d currently", pdb.Name, pdb.Status.DesiredHealthy, pdb.Status.CurrentHealthy), 10)
+		}
+
+		pdb.Status.DisruptionsAllowed--
+		if pdb.Status.DisruptedPods == nil {
+			pdb.Status.DisruptedPods = map[string]metav1.Time{}
+		}
+		pdb.Status.DisruptedPods[vPod.Name] = metav1.Now()
+		return virtualClient.Status().Update(ctx, pdb)
+	})
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+// translateEvictionError removes host object names from a pod disruption budget error, but keeps the retry after
+// information so clients like kubectl drain retry as usual.
+func translateEvictionError(err error) error {
+	statusErr, ok := err.(kerrors.APIStatus)
+	if !ok {
+		return err
+	}
+
+	status := statusErr.Status()
+	retryAfter := 0
+	if status.Details != nil {
+		retryAfter = int(status.Details.RetryAfterSeconds)
+	}
+	if retryAfter == 0 {
+		retryAfter = 10
+	}
+
+	return kerrors.NewTooManyRequests(fmt.Sprintf("Cannot evict pod as it would violate the pod's disruption budget in the host cluster: %s", status.Message), retryAfter)
+}
+
+func decodeEviction(decoder encoding.Decoder, rawObj []byte) (*policyv1.Eviction, error) {
+	evictionGVK := policyv1.SchemeGroupVersion.WithKind("Eviction")
+	obj, err := decoder.Decode(rawObj, &evictionGVK)
+	if err != nil {
+		return nil, err
+	}
+
+	switch eviction := obj.(type) {
+	case *policyv1.Eviction:
+		return eviction, nil
+	case *policyv1beta1.Eviction:
+		return &policyv1.Eviction{
+			ObjectMeta:    eviction.ObjectMeta,
+			DeleteOptions: eviction.DeleteOptions,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unexpected object %T, expected an eviction", obj)
+}
+
+// serveWithBody passes the request on to the next handler after its body was consumed already
+func serveWithBody(handler http.Handler, w http.ResponseWriter, req *http.Request, rawObj []byte) {
+	req.Body = io.NopCloser(bytes.NewReader(rawObj))
+	req.ContentLength = int64(len(rawObj))
+	handler.ServeHTTP(w, req)
+}
@@ -0,0 +1,176 @@
+package filters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestEvictionPassthrough(t *testing.T) {
+	pdbError := kerrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 30)
+
+	testCases := []struct {
+		name  string
+		query string
+		body  *policyv1.Eviction
+		// hostErr is returned by the host cluster when evicting the host pod
+		hostErr error
+
+		expectedCode       int
+		expectedRetryAfter string
+		expectedHostPod    bool
+		expectedDownstream bool
+	}{
+		{
+			name:         "passthrough",
+			body:         &policyv1.Eviction{},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:               "blocked by host pod disruption budget",
+			body:               &policyv1.Eviction{},
+			hostErr:            pdbError,
+			expectedCode:       http.StatusTooManyRequests,
+			expectedRetryAfter: "30",
+			expectedHostPod:    true,
+		},
+		{
+			name:               "dry run query",
+			query:              "?dryRun=All",
+			body:               &policyv1.Eviction{},
+			expectedCode:       http.StatusCreated,
+			expectedHostPod:    true,
+			expectedDownstream: true,
+		},
+		{
+			name:               "dry run delete options",
+			body:               &policyv1.Eviction{DeleteOptions: &metav1.DeleteOptions{DryRun: []string{metav1.DryRunAll}}},
+			expectedCode:       http.StatusCreated,
+			expectedHostPod:    true,
+			expectedDownstream: true,
+		},
+	}
+
+	// the virtual apiserver accepts the dry run eviction of the user
+	virtualServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write(mustMarshal(&metav1.Status{TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}, Status: metav1.StatusSuccess, Code: http.StatusCreated}))
+	}))
+	defer virtualServer.Close()
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Pod"), meta.RESTScopeNamespace)
+	for _, testCase := range testCases {
+		vPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", UID: "virtual"}}
+		pPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: translate.Default.PhysicalName("test", "default"), Namespace: translate.Default.PhysicalNamespace("default"), UID: "host"}}
+		vClient := fake.NewClientBuilder().WithScheme(testingutil.NewScheme()).WithRESTMapper(mapper).WithObjects(vPod).Build()
+		pClient := fake.NewClientBuilder().WithScheme(testingutil.NewScheme()).WithObjects(pPod).WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceCreate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+				if testCase.hostErr != nil {
+					return testCase.hostErr
+				}
+
+				return c.SubResource(subResourceName).Create(ctx, obj, subResource, opts...)
+			},
+		}).Build()
+
+		downstreamCalled := false
+		downstream := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			downstreamCalled = true
+			w.WriteHeader(http.StatusCreated)
+		})
+		h := WithEvictionPassthrough(downstream, pClient, vClient, &rest.Config{Host: virtualServer.URL}, record.NewFakeRecorder(10))
+
+		body, err := json.Marshal(testCase.body)
+		assert.NilError(t, err, testCase.name)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/namespaces/default/pods/test/eviction"+testCase.query, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		ctx := request.WithRequestInfo(req.Context(), &request.RequestInfo{
+			IsResourceRequest: true,
+			Verb:              "create",
+			APIVersion:        "v1",
+			Resource:          "pods",
+			Subresource:       "eviction",
+			Namespace:         "default",
+			Name:              "test",
+		})
+		req = req.WithContext(request.WithUser(ctx, nodeDebugDeveloper))
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		assert.Equal(t, w.Code, testCase.expectedCode, testCase.name+": "+w.Body.String())
+		assert.Equal(t, w.Header().Get("Retry-After"), testCase.expectedRetryAfter, testCase.name)
+		assert.Equal(t, downstreamCalled, testCase.expectedDownstream, testCase.name)
+
+		err = pClient.Get(context.TODO(), types.NamespacedName{Namespace: pPod.Namespace, Name: pPod.Name}, &corev1.Pod{})
+		if testCase.expectedHostPod {
+			assert.NilError(t, err, testCase.name)
+		} else {
+			assert.Assert(t, kerrors.IsNotFound(err), testCase.name)
+		}
+	}
+}
+
+func TestTranslateEvictionError(t *testing.T) {
+	err := translateEvictionError(kerrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0))
+	status := err.(kerrors.APIStatus).Status()
+	assert.Equal(t, status.Code, int32(http.StatusTooManyRequests))
+	assert.Equal(t, status.Details.RetryAfterSeconds, int32(10))
+}
+
+func TestConsumeDisruptionBudget(t *testing.T) {
+	vPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", Labels: map[string]string{"app": "test"}},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}}},
+		Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 1, CurrentHealthy: 2, DesiredHealthy: 1},
+	}
+	vClient := fake.NewClientBuilder().WithScheme(testingutil.NewScheme()).WithObjects(vPod, pdb).WithStatusSubresource(pdb).Build()
+
+	// the first eviction consumes the budget
+	err := consumeDisruptionBudget(context.TODO(), vClient, vPod)
+	assert.NilError(t, err)
+	updated := &policyv1.PodDisruptionBudget{}
+	assert.NilError(t, vClient.Get(context.TODO(), client.ObjectKeyFromObject(pdb), updated))
+	assert.Equal(t, updated.Status.DisruptionsAllowed, int32(0))
+	_, ok := updated.Status.DisruptedPods["test"]
+	assert.Assert(t, ok)
+
+	// the budget is exhausted for the next one
+	err = consumeDisruptionBudget(context.TODO(), vClient, vPod)
+	assert.Assert(t, kerrors.IsTooManyRequests(err), err)
+
+	// pods that aren't running or are selected by no budget are not limited
+	pending := vPod.DeepCopy()
+	pending.Status.Phase = corev1.PodPending
+	assert.NilError(t, consumeDisruptionBudget(context.TODO(), vClient, pending))
+	unselected := vPod.DeepCopy()
+	unselected.Labels = nil
+	assert.NilError(t, consumeDisruptionBudget(context.TODO(), vClient, unselected))
+}
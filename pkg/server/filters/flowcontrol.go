@@ -0,0 +1,282 @@
+package filters
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/loft-sh/vcluster/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+const (
+	DefaultPriorityLevel = "default"
+
+	rejectReasonRateLimit   = "rate-limit"
+	rejectReasonConcurrency = "concurrency-limit"
+
+	// limiterIdleTimeout is the time after which rate limiters of users that did not send any requests are removed
+	limiterIdleTimeout = 10 * time.Minute
+)
+
+var (
+	flowControlMetricsRegistry = prometheus.NewRegistry()
+
+	flowControlRejectedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "vcluster_proxy_rejected_requests_total",
+		Help: "Number of requests rejected by the proxy flow control.",
+	}, []string{"priority_level", "reason"})
+	flowControlInFlightRequests = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "vcluster_proxy_inflight_requests",
+		Help: "Number of requests currently served by the proxy per priority level, long running requests are not counted.",
+	}, []string{"priority_level"})
+)
+
+func init() {
+	flowControlMetricsRegistry.MustRegister(flowControlRejectedRequests, flowControlInFlightRequests)
+}
+
+// FlowControlMetricsHandler serves the proxy flow control metrics at /metrics
+func FlowControlMetricsHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(flowControlMetricsRegistry, promhttp.HandlerOpts{}))
+	return mux
+}
+
+// WithFlowControl limits the requests of users and groups with the configured rate limits and priority levels. Rejected
+// requests are answered with 429 Too Many Requests and a Retry-After header.
+func WithFlowControl(h http.Handler, options config.ControlPlaneProxyFlowControl, longRunning request.LongRunningRequestCheck) http.Handler {
+	controller := NewFlowController(options)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		userInfo, ok := request.UserFrom(req.Context())
+		if !ok {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		isLongRunning := false
+		if requestInfo, ok := request.RequestInfoFrom(req.Context()); ok && longRunning != nil {
+			isLongRunning = longRunning(req, requestInfo)
+		}
+
+		release, retryAfter, err := controller.Admit(userInfo, !isLongRunning)
+		if err != nil {
+			err := kerrors.NewTooManyRequests(err.Error(), int(math.Ceil(retryAfter.Seconds())))
+			responsewriters.ErrorNegotiated(err, scheme.Codecs, corev1.SchemeGroupVersion, w, req)
+			return
+		}
+		defer release()
+
+		h.ServeHTTP(w, req)
+	})
+}
+
+// FlowController assigns requests to priority levels and enforces their rate and concurrency limits
+type FlowController struct {
+	levels       []*priorityLevel
+	defaultLevel *priorityLevel
+	groupLimits  map[string]config.ProxyRateLimit
+
+	limitersLock sync.Mutex
+	limiters     map[string]*trackedLimiter
+	lastCleanup  time.Time
+
+	now func() time.Time
+}
+
+type priorityLevel struct {
+	config.ProxyPriorityLevel
+
+	// inFlight is used as semaphore to limit the concurrent requests of the priority level
+	inFlight chan struct{}
+}
+
+type trackedLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewFlowController creates a new flow controller for the given options
+func NewFlowController(options config.ControlPlaneProxyFlowControl) *FlowController {
+	controller := &FlowController{
+		defaultLevel: newPriorityLevel(config.ProxyPriorityLevel{
+			Name:        DefaultPriorityLevel,
+			MaxInFlight: options.MaxInFlight,
+			PerUser:     &options.PerUser,
+		}),
+		groupLimits: map[string]config.ProxyRateLimit{},
+		limiters:    map[string]*trackedLimiter{},
+		now:         time.Now,
+	}
+	for _, level := range options.PriorityLevels {
+		if level.PerUser == nil {
+			level.PerUser = &options.PerUser
+		}
+
+		controller.levels = append(controller.levels, newPriorityLevel(level))
+	}
+	for _, groupLimit := range options.PerGroup {
+		controller.groupLimits[groupLimit.Group] = groupLimit.ProxyRateLimit
+	}
+
+	return controller
+}
+
+func newPriorityLevel(level config.ProxyPriorityLevel) *priorityLevel {
+	p := &priorityLevel{ProxyPriorityLevel: level}
+	if level.MaxInFlight > 0 {
+		p.inFlight = make(chan struct{}, level.MaxInFlight)
+	}
+
+	return p
+}
+
+// Admit checks if a request of the given user can be served. If the request is admitted, release needs to be called
+// after the request was served. Otherwise, the returned duration is the time the client should wait before retrying.
+func (f *FlowController) Admit(userInfo user.Info, countInFlight bool) (func(), time.Duration, error) {
+	level := f.priorityLevelFor(userInfo)
+	if level == nil || level.Exempt {
+		return func() {}, 0, nil
+	}
+
+	// check the rate limits first, so that rejected requests don't occupy a seat
+	retryAfter := f.reserve(level, userInfo)
+	if retryAfter > 0 {
+		flowControlRejectedRequests.WithLabelValues(level.Name, rejectReasonRateLimit).Inc()
+		return nil, retryAfter, fmt.Errorf("rate limit of user %s exceeded, please try again later", userInfo.GetName())
+	}
+
+	if !countInFlight || level.inFlight == nil {
+		return func() {}, 0, nil
+	}
+
+	select {
+	case level.inFlight <- struct{}{}:
+		flowControlInFlightRequests.WithLabelValues(level.Name).Inc()
+		return func() {
+			<-level.inFlight
+			flowControlInFlightRequests.WithLabelValues(level.Name).Dec()
+		}, 0, nil
+	default:
+		flowControlRejectedRequests.WithLabelValues(level.Name, rejectReasonConcurrency).Inc()
+		return nil, time.Second, fmt.Errorf("too many requests in priority level %s, please try again later", level.Name)
+	}
+}
+
+// priorityLevelFor returns the priority level of the user or nil if the user is always exempt
+func (f *FlowController) priorityLevelFor(userInfo user.Info) *priorityLevel {
+	if isSystemUser(userInfo) {
+		return nil
+	}
+
+	for _, level := range f.levels {
+		if slices.Contains(level.Users, userInfo.GetName()) {
+			return level
+		}
+		for _, group := range userInfo.GetGroups() {
+			if slices.Contains(level.Groups, group) {
+				return level
+			}
+		}
+	}
+
+	return f.defaultLevel
+}
+
+// reserve takes a token from the user and group rate limiters and returns the time to wait if any of them is exhausted
+func (f *FlowController) reserve(level *priorityLevel, userInfo user.Info) time.Duration {
+	f.limitersLock.Lock()
+	defer f.limitersLock.Unlock()
+
+	now := f.now()
+	f.cleanupLimiters(now)
+
+	limiters := []*rate.Limiter{}
+	if limiter := f.limiter("user/"+level.Name+"/"+userInfo.GetName(), *level.PerUser, now); limiter != nil {
+		limiters = append(limiters, limiter)
+	}
+	for _, group := range userInfo.GetGroups() {
+		groupLimit, ok := f.groupLimits[group]
+		if !ok {
+			continue
+		}
+
+		if limiter := f.limiter("group/"+group, groupLimit, now); limiter != nil {
+			limiters = append(limiters, limiter)
+		}
+	}
+
+	// reserve from all limiters and give back the tokens if one of them would need to wait
+	var retryAfter time.Duration
+	reservations := make([]*rate.Reservation, 0, len(limiters))
+	for _, limiter := range limiters {
+		reservation := limiter.ReserveN(now, 1)
+		reservations = append(reservations, reservation)
+		if delay := reservation.DelayFrom(now); delay > retryAfter {
+			retryAfter = delay
+		}
+	}
+	if retryAfter > 0 {
+		for _, reservation := range reservations {
+			reservation.CancelAt(now)
+		}
+	}
+
+	return retryAfter
+}
+
+func (f *FlowController) limiter(key string, limit config.ProxyRateLimit, now time.Time) *rate.Limiter {
+	if limit.QPS <= 0 {
+		return nil
+	}
+
+	tracked, ok := f.limiters[key]
+	if !ok {
+		burst := limit.Burst
+		if burst <= 0 {
+			burst = limit.QPS
+		}
+
+		tracked = &trackedLimiter{limiter: rate.NewLimiter(rate.Limit(limit.QPS), burst)}
+		f.limiters[key] = tracked
+	}
+
+	tracked.lastSeen = now
+	return tracked.limiter
+}
+
+func (f *FlowController) cleanupLimiters(now time.Time) {
+	if now.Sub(f.lastCleanup) < time.Minute {
+		return
+	}
+
+	for key, tracked := range f.limiters {
+		if now.Sub(tracked.lastSeen) > limiterIdleTimeout {
+			delete(f.limiters, key)
+		}
+	}
+	f.lastCleanup = now
+}
+
+// isSystemUser returns true for the syncer, cluster admins and kubernetes system components. Service accounts and
+// anonymous users are not treated as system users.
+func isSystemUser(userInfo user.Info) bool {
+	if slices.Contains(userInfo.GetGroups(), user.SystemPrivilegedGroup) {
+		return true
+	}
+
+	name := userInfo.GetName()
+	return strings.HasPrefix(name, "system:") && !strings.HasPrefix(name, "system:serviceaccount:") && name != user.Anonymous
+}
@@ -0,0 +1,113 @@
+package filters
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/vcluster/config"
+	"gotest.tools/v3/assert"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func TestFlowControllerRateLimit(t *testing.T) {
+	now := time.Now()
+	controller := NewFlowController(config.ControlPlaneProxyFlowControl{
+		PerUser: config.ProxyRateLimit{QPS: 1, Burst: 2},
+		PerGroup: []config.ProxyGroupRateLimit{
+			{Group: "team-a", ProxyRateLimit: config.ProxyRateLimit{QPS: 1, Burst: 3}},
+		},
+	})
+	controller.now = func() time.Time { return now }
+
+	alice := &user.DefaultInfo{Name: "alice", Groups: []string{"team-a"}}
+	bob := &user.DefaultInfo{Name: "bob", Groups: []string{"team-a"}}
+	for i := 0; i < 2; i++ {
+		_, _, err := controller.Admit(alice, true)
+		assert.NilError(t, err)
+	}
+
+	// alice exceeded her own burst
+	_, retryAfter, err := controller.Admit(alice, true)
+	assert.ErrorContains(t, err, "rate limit of user alice exceeded")
+	assert.Assert(t, retryAfter > 0)
+
+	// bob can use the remaining token of the group, afterwards the group is exhausted
+	_, _, err = controller.Admit(bob, true)
+	assert.NilError(t, err)
+	_, _, err = controller.Admit(bob, true)
+	assert.ErrorContains(t, err, "rate limit of user bob exceeded")
+
+	// system users and the syncer are never limited
+	for i := 0; i < 10; i++ {
+		_, _, err = controller.Admit(&user.DefaultInfo{Name: "system:admin", Groups: []string{user.SystemPrivilegedGroup, "team-a"}}, true)
+		assert.NilError(t, err)
+		_, _, err = controller.Admit(&user.DefaultInfo{Name: "system:kube-controller-manager"}, true)
+		assert.NilError(t, err)
+	}
+
+	// tokens are refilled over time
+	now = now.Add(3 * time.Second)
+	_, _, err = controller.Admit(alice, true)
+	assert.NilError(t, err)
+}
+
+func TestFlowControllerPriorityLevels(t *testing.T) {
+	controller := NewFlowController(config.ControlPlaneProxyFlowControl{
+		MaxInFlight: 1,
+		PriorityLevels: []config.ProxyPriorityLevel{
+			{Name: "controllers", Groups: []string{"system:serviceaccounts:controllers"}, MaxInFlight: 2},
+			{Name: "ci", Users: []string{"ci"}, Exempt: true},
+		},
+	})
+
+	serviceAccount := &user.DefaultInfo{Name: "system:serviceaccount:controllers:noisy", Groups: []string{"system:serviceaccounts:controllers"}}
+	release, _, err := controller.Admit(serviceAccount, true)
+	assert.NilError(t, err)
+	_, _, err = controller.Admit(serviceAccount, true)
+	assert.NilError(t, err)
+	_, retryAfter, err := controller.Admit(serviceAccount, true)
+	assert.ErrorContains(t, err, "too many requests in priority level controllers")
+	assert.Equal(t, retryAfter, time.Second)
+
+	// long running requests don't occupy a seat
+	_, _, err = controller.Admit(serviceAccount, false)
+	assert.NilError(t, err)
+
+	// the controllers don't starve users of the default priority level
+	_, _, err = controller.Admit(&user.DefaultInfo{Name: "alice"}, true)
+	assert.NilError(t, err)
+	_, _, err = controller.Admit(&user.DefaultInfo{Name: "bob"}, true)
+	assert.ErrorContains(t, err, "too many requests in priority level default")
+
+	// exempt priority levels are never limited
+	_, _, err = controller.Admit(&user.DefaultInfo{Name: "ci"}, true)
+	assert.NilError(t, err)
+
+	release()
+	_, _, err = controller.Admit(serviceAccount, true)
+	assert.NilError(t, err)
+}
+
+func TestWithFlowControl(t *testing.T) {
+	handler := WithFlowControl(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), config.ControlPlaneProxyFlowControl{
+		Enabled: true,
+		PerUser: config.ProxyRateLimit{QPS: 1},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/pods", nil)
+	req = req.WithContext(request.WithUser(req.Context(), &user.DefaultInfo{Name: "alice"}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	assert.Equal(t, recorder.Code, http.StatusOK)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	assert.Equal(t, recorder.Code, http.StatusTooManyRequests)
+	assert.Equal(t, recorder.Header().Get("Retry-After"), "1")
+}
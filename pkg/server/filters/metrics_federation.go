@@ -0,0 +1,272 @@
+package filters
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/loft-sh/vcluster/pkg/metrics"
+	"github.com/loft-sh/vcluster/pkg/util/clienthelper"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	MetricsFederationPath = "/vcluster/metrics/federate"
+
+	// federationConcurrency is the number of kubelets that are scraped in parallel
+	federationConcurrency = 10
+)
+
+// federatedKubeletPaths are the kubelet endpoints that are scraped for every node a virtual pod is running on
+var federatedKubeletPaths = []string{"/metrics/cadvisor", "/metrics/resource"}
+
+// WithMetricsFederation serves the kubelet and cAdvisor metrics of all virtual pods together with pod state metrics at
+// MetricsFederationPath. Metrics are relabeled to virtual names and filtered by the namespaces the user is allowed to
+// list pods in.
+func WithMetricsFederation(h http.Handler, localConfig *rest.Config, cachedVirtualClient, uncachedVirtualClient client.Client) http.Handler {
+	s := serializer.NewCodecFactory(cachedVirtualClient.Scheme())
+	hostClient, err := kubernetes.NewForConfig(localConfig)
+	if err != nil {
+		klog.Errorf("Error creating metrics federation client: %v", err)
+		return h
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != MetricsFederationPath {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		if req.Method != http.MethodGet {
+			responsewriters.ErrorNegotiated(kerrors.NewMethodNotSupported(corev1.Resource("metrics"), req.Method), s, corev1.SchemeGroupVersion, w, req)
+			return
+		}
+
+		userInfo, ok := request.UserFrom(req.Context())
+		if !ok {
+			responsewriters.ErrorNegotiated(kerrors.NewUnauthorized("user info is missing"), s, corev1.SchemeGroupVersion, w, req)
+			return
+		}
+
+		metricsFamilies, err := federateMetrics(req.Context(), hostClient, cachedVirtualClient, &namespaceAuthorizer{client: uncachedVirtualClient, user: userInfo})
+		if err != nil {
+			responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
+			return
+		}
+
+		format := expfmt.Negotiate(req.Header)
+		out, err := metrics.Encode(metricsFamilies, format)
+		if err != nil {
+			responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
+			return
+		}
+
+		w.Header().Set("Content-Type", string(format))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(out)
+	})
+}
+
+func federateMetrics(ctx context.Context, hostClient kubernetes.Interface, cachedVirtualClient client.Client, authorizer *namespaceAuthorizer) ([]*dto.MetricFamily, error) {
+	podList := &corev1.PodList{}
+	err := cachedVirtualClient.List(ctx, podList)
+	if err != nil {
+		return nil, fmt.Errorf("list virtual pods: %w", err)
+	}
+
+	// only keep the pods the user is allowed to see
+	pods := []corev1.Pod{}
+	nodes := map[string]bool{}
+	for _, pod := range podList.Items {
+		allowed, err := authorizer.Allowed(ctx, pod.Namespace)
+		if err != nil {
+			return nil, err
+		} else if !allowed {
+			continue
+		}
+
+		pods = append(pods, pod)
+		if pod.Spec.NodeName != "" {
+			nodes[pod.Spec.NodeName] = true
+		}
+	}
+
+	// scrape the kubelets in parallel
+	var (
+		lock     sync.Mutex
+		wg       sync.WaitGroup
+		scraped  = [][]*dto.MetricFamily{metrics.PodStateMetrics(pods)}
+		limiter  = make(chan struct{}, federationConcurrency)
+		nodeList = make([]string, 0, len(nodes))
+	)
+	for node := range nodes {
+		nodeList = append(nodeList, node)
+	}
+	sort.Strings(nodeList)
+	for _, node := range nodeList {
+		for _, path := range federatedKubeletPaths {
+			wg.Add(1)
+			limiter <- struct{}{}
+			go func(node, path string) {
+				defer func() {
+					<-limiter
+					wg.Done()
+				}()
+
+				metricsFamilies, err := scrapeKubelet(ctx, hostClient, cachedVirtualClient, authorizer, node, path)
+				if err != nil {
+					// a single unavailable kubelet shouldn't break the whole endpoint
+					klog.Errorf("Error scraping %s of node %s: %v", path, node, err)
+					return
+				}
+
+				lock.Lock()
+				defer lock.Unlock()
+				scraped = append(scraped, metricsFamilies)
+			}(node, path)
+		}
+	}
+	wg.Wait()
+
+	return metrics.Merge(scraped...), nil
+}
+
+func scrapeKubelet(ctx context.Context, hostClient kubernetes.Interface, cachedVirtualClient client.Client, authorizer *namespaceAuthorizer, node, path string) ([]*dto.MetricFamily, error) {
+	data, err := hostClient.CoreV1().RESTClient().Get().AbsPath("/api/v1/nodes", node, "proxy", path).DoRaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	metricsFamilies, err := metrics.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// rewrite the host pod names to virtual ones, this drops all metrics of pods that don't belong to this vCluster
+	metricsFamilies, err = metrics.Rewrite(ctx, metricsFamilies, cachedVirtualClient)
+	if err != nil {
+		return nil, err
+	}
+
+	// only keep pod metrics of allowed namespaces, as node wide metrics would leak information about the host
+	result := []*dto.MetricFamily{}
+	for _, fam := range metricsFamilies {
+		filtered := []*dto.Metric{}
+		for _, m := range fam.Metric {
+			var pod, namespace string
+			for _, l := range m.Label {
+				if l.GetName() == "pod" {
+					pod = l.GetValue()
+				} else if l.GetName() == "namespace" {
+					namespace = l.GetValue()
+				}
+			}
+			if pod == "" || namespace == "" {
+				continue
+			}
+
+			allowed, err := authorizer.Allowed(ctx, namespace)
+			if err != nil {
+				return nil, err
+			} else if !allowed {
+				continue
+			}
+
+			m.Label = append(m.Label, &dto.LabelPair{Name: ptr.To("node"), Value: ptr.To(node)})
+			filtered = append(filtered, m)
+		}
+
+		if len(filtered) > 0 {
+			fam.Metric = filtered
+			result = append(result, fam)
+		}
+	}
+
+	return result, nil
+}
+
+// namespaceAuthorizer checks with the virtual cluster RBAC if a user is allowed to list pods in a namespace and
+// caches the decisions for a single request
+type namespaceAuthorizer struct {
+	client client.Client
+	user   user.Info
+
+	lock      sync.Mutex
+	decisions map[string]bool
+}
+
+func (n *namespaceAuthorizer) Allowed(ctx context.Context, namespace string) (bool, error) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.decisions == nil {
+		n.decisions = map[string]bool{}
+	}
+	if allowed, ok := n.decisions[namespace]; ok {
+		return allowed, nil
+	}
+
+	// a namespace of "" checks if the user is allowed to list pods in all namespaces
+	if namespace != "" {
+		allowed, ok := n.decisions[""]
+		if !ok {
+			var err error
+			allowed, err = n.check(ctx, "")
+			if err != nil {
+				return false, err
+			}
+
+			n.decisions[""] = allowed
+		}
+		if allowed {
+			return true, nil
+		}
+	}
+
+	allowed, err := n.check(ctx, namespace)
+	if err != nil {
+		return false, err
+	}
+
+	n.decisions[namespace] = allowed
+	return allowed, nil
+}
+
+func (n *namespaceAuthorizer) check(ctx context.Context, namespace string) (bool, error) {
+	accessReview := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   n.user.GetName(),
+			UID:    n.user.GetUID(),
+			Groups: n.user.GetGroups(),
+			Extra:  clienthelper.ConvertExtra(n.user.GetExtra()),
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "list",
+				Group:     corev1.SchemeGroupVersion.Group,
+				Version:   corev1.SchemeGroupVersion.Version,
+				Resource:  "pods",
+			},
+		},
+	}
+	err := n.client.Create(ctx, accessReview)
+	if err != nil {
+		return false, fmt.Errorf("create subject access review: %w", err)
+	}
+
+	return accessReview.Status.Allowed && !accessReview.Status.Denied, nil
+}
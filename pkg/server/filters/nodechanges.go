@@ -9,6 +9,7 @@ import (
 
 	"github.com/loft-sh/vcluster/pkg/server/handler"
 	"github.com/loft-sh/vcluster/pkg/util/encoding"
+	"github.com/loft-sh/vcluster/pkg/util/noderules"
 	requestpkg "github.com/loft-sh/vcluster/pkg/util/request"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -24,7 +25,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func WithNodeChanges(ctx context.Context, h http.Handler, uncachedLocalClient, uncachedVirtualClient client.Client, virtualConfig *rest.Config) http.Handler {
+func WithNodeChanges(ctx context.Context, h http.Handler, uncachedLocalClient, uncachedVirtualClient client.Client, virtualConfig *rest.Config, rules *noderules.Rules) http.Handler {
 	decoder := encoding.NewDecoder(uncachedLocalClient.Scheme(), false)
 	s := serializer.NewCodecFactory(uncachedVirtualClient.Scheme())
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
@@ -50,7 +51,7 @@ func WithNodeChanges(ctx context.Context, h http.Handler, uncachedLocalClient, u
 						return
 					}
 
-					updatedNode, err := updateNode(ctx, decoder, uncachedLocalClient, uncachedVirtualClient, rawObj, info.Subresource == "status")
+					updatedNode, err := updateNode(ctx, decoder, uncachedLocalClient, uncachedVirtualClient, rules, rawObj, info.Subresource == "status")
 					if err != nil {
 						responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
 						return
@@ -67,7 +68,7 @@ func WithNodeChanges(ctx context.Context, h http.Handler, uncachedLocalClient, u
 				}
 
 				if len(options.DryRun) == 0 {
-					patchNode(ctx, w, req, s, decoder, uncachedLocalClient, uncachedVirtualClient, virtualConfig, info.Subresource == "status")
+					patchNode(ctx, w, req, s, decoder, uncachedLocalClient, uncachedVirtualClient, virtualConfig, rules, info.Subresource == "status")
 					return
 				}
 			}
@@ -77,7 +78,7 @@ func WithNodeChanges(ctx context.Context, h http.Handler, uncachedLocalClient, u
 	})
 }
 
-func patchNode(ctx context.Context, w http.ResponseWriter, req *http.Request, s runtime.NegotiatedSerializer, decoder encoding.Decoder, localClient client.Client, virtualClient client.Client, virtualConfig *rest.Config, status bool) {
+func patchNode(ctx context.Context, w http.ResponseWriter, req *http.Request, s runtime.NegotiatedSerializer, decoder encoding.Decoder, localClient client.Client, virtualClient client.Client, virtualConfig *rest.Config, rules *noderules.Rules, status bool) {
 	h, err := handler.Handler("", virtualConfig, nil)
 	if err != nil {
 		responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
@@ -97,7 +98,7 @@ func patchNode(ctx context.Context, w http.ResponseWriter, req *http.Request, s
 		return
 	}
 
-	vObj, err := updateNode(ctx, decoder, localClient, virtualClient, data, status)
+	vObj, err := updateNode(ctx, decoder, localClient, virtualClient, rules, data, status)
 	if err != nil {
 		responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
 		return
@@ -106,7 +107,7 @@ func patchNode(ctx context.Context, w http.ResponseWriter, req *http.Request, s
 	responsewriters.WriteObjectNegotiated(s, negotiation.DefaultEndpointRestrictions, corev1.SchemeGroupVersion, w, req, http.StatusOK, vObj, false)
 }
 
-func updateNode(ctx context.Context, decoder encoding.Decoder, localClient client.Client, virtualClient client.Client, rawObj []byte, status bool) (runtime.Object, error) {
+func updateNode(ctx context.Context, decoder encoding.Decoder, localClient client.Client, virtualClient client.Client, rules *noderules.Rules, rawObj []byte, status bool) (runtime.Object, error) {
 	nodeGVK := corev1.SchemeGroupVersion.WithKind("Node")
 	vObj, err := decoder.Decode(rawObj, &nodeGVK)
 	if err != nil {
@@ -140,8 +141,7 @@ func updateNode(ctx context.Context, decoder encoding.Decoder, localClient clien
 
 	// apply the changes to from the vNode
 	newNode := pNode.DeepCopy()
-	newNode.Labels = vNode.Labels
-	newNode.Spec.Taints = vNode.Spec.Taints
+	newNode.Labels, newNode.Spec.Taints = rules.TranslateNodeBackwards(pNode, vNode)
 	newNode.Status.Capacity = vNode.Status.Capacity
 
 	// if there are no changes, just return the provided object
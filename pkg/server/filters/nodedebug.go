@@ -0,0 +1,210 @@
+package filters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/util/encoding"
+	"github.com/loft-sh/vcluster/pkg/util/nodedebug"
+	requestpkg "github.com/loft-sh/vcluster/pkg/util/request"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// WithNodeDebug approves pods created by `kubectl debug node/NAME` for the configured groups and images. Approved pods
+// are marked with the creating user and signed with the node debug key after they were created, which tells the pod
+// syncer to run them on the host node. Every session is recorded as an event on the virtual node. The approval
+// annotations can't be set or changed by users directly.
+func WithNodeDebug(handler http.Handler, nodeDebug config.SyncPodNodeDebug, key []byte, uncachedVirtualClient client.Client, eventRecorder record.EventRecorder) http.Handler {
+	decoder := encoding.NewDecoder(uncachedVirtualClient.Scheme(), false)
+	s := serializer.NewCodecFactory(uncachedVirtualClient.Scheme())
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		info, ok := request.RequestInfoFrom(req.Context())
+		if !ok {
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, fmt.Errorf("request info is missing"))
+			return
+		} else if info.APIGroup != corev1.SchemeGroupVersion.Group || info.Resource != "pods" || info.Subresource != "" || (info.Verb != "create" && info.Verb != "update" && info.Verb != "patch") {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		userInfo, ok := request.UserFrom(req.Context())
+		if !ok {
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, fmt.Errorf("user info is missing"))
+			return
+		}
+
+		rawObj, err := io.ReadAll(req.Body)
+		if err != nil {
+			responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
+			return
+		}
+
+		// updates and patches can't change the approval annotations. Patches can address annotations in many ways,
+		// so the result of the request is always checked.
+		if info.Verb != "create" {
+			err = checkNodeDebugAnnotationUnchanged(req, handler, uncachedVirtualClient, info, rawObj)
+			if err != nil {
+				responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
+				return
+			}
+
+			serveWithBody(handler, w, req, rawObj)
+			return
+		}
+
+		podGVK := corev1.SchemeGroupVersion.WithKind("Pod")
+		obj, err := decoder.Decode(rawObj, &podGVK)
+		if err != nil {
+			responsewriters.ErrorNegotiated(kerrors.NewBadRequest(err.Error()), s, corev1.SchemeGroupVersion, w, req)
+			return
+		}
+		pod, ok := obj.(*corev1.Pod)
+		if !ok {
+			responsewriters.ErrorNegotiated(kerrors.NewBadRequest(fmt.Sprintf("unexpected object %T, expected a pod", obj)), s, corev1.SchemeGroupVersion, w, req)
+			return
+		}
+		if pod.Namespace == "" {
+			pod.Namespace = info.Namespace
+		}
+
+		if !nodedebug.IsNodeDebugPod(pod) {
+			for _, annotation := range []string{nodedebug.UserAnnotation, nodedebug.SignatureAnnotation} {
+				if _, ok := pod.Annotations[annotation]; ok {
+					responsewriters.ErrorNegotiated(kerrors.NewForbidden(corev1.Resource("pods"), pod.Name, fmt.Errorf("annotation %s is reserved for node debug pods", annotation)), s, corev1.SchemeGroupVersion, w, req)
+					return
+				}
+			}
+
+			serveWithBody(handler, w, req, rawObj)
+			return
+		}
+
+		// check if the user is allowed to debug the node
+		vNode := &corev1.Node{}
+		err = uncachedVirtualClient.Get(req.Context(), types.NamespacedName{Name: pod.Spec.NodeName}, vNode)
+		if err != nil {
+			responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
+			return
+		}
+		err = nodedebug.Validate(nodeDebug, userInfo, pod)
+		if err != nil {
+			eventRecorder.Eventf(vNode, corev1.EventTypeWarning, "NodeDebugDenied", "Node debug pod %s/%s of user %s was denied: %v", pod.Namespace, pod.Name, userInfo.GetName(), err)
+			responsewriters.ErrorNegotiated(kerrors.NewForbidden(corev1.Resource("pods"), pod.Name, err), s, corev1.SchemeGroupVersion, w, req)
+			return
+		}
+
+		// mark the pod as approved
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		pod.Annotations[nodedebug.UserAnnotation] = userInfo.GetName()
+		delete(pod.Annotations, nodedebug.SignatureAnnotation)
+		rawObj, err = json.Marshal(pod)
+		if err != nil {
+			responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+		req.Header.Del("Accept-Encoding")
+		req.Body = io.NopCloser(bytes.NewReader(rawObj))
+		req.ContentLength = int64(len(rawObj))
+
+		code, header, data, err := executeRequest(req, handler)
+		if err != nil {
+			responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
+			return
+		} else if code == http.StatusCreated && !isDryRun(req) {
+			// the signature covers the uid, so the pod can only be signed after it was created
+			data, err = signNodeDebugPod(req.Context(), uncachedVirtualClient, key, data)
+			if err != nil {
+				responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
+				return
+			}
+
+			eventRecorder.Eventf(vNode, corev1.EventTypeNormal, "NodeDebug", "User %s started node debug pod %s/%s with image %s", userInfo.GetName(), pod.Namespace, pod.Name, strings.Join(podImages(pod), ", "))
+		}
+
+		writeWithHeader(w, code, header, data)
+	})
+}
+
+// signNodeDebugPod signs the created node debug pod and returns the signed pod as json
+func signNodeDebugPod(ctx context.Context, uncachedVirtualClient client.Client, key []byte, data []byte) ([]byte, error) {
+	createdPod := &corev1.Pod{}
+	err := json.Unmarshal(data, createdPod)
+	if err != nil {
+		return nil, fmt.Errorf("decode pod: %w", err)
+	}
+
+	signedPod := createdPod.DeepCopy()
+	signedPod.Annotations[nodedebug.SignatureAnnotation] = nodedebug.Sign(key, createdPod)
+	err = uncachedVirtualClient.Patch(ctx, signedPod, client.MergeFrom(createdPod))
+	if err != nil {
+		return nil, fmt.Errorf("sign node debug pod: %w", err)
+	}
+
+	signedPod.TypeMeta = createdPod.TypeMeta
+	return json.Marshal(signedPod)
+}
+
+// checkNodeDebugAnnotationUnchanged makes sure that an update or patch of a pod doesn't set or change the node debug
+// approval annotations
+func checkNodeDebugAnnotationUnchanged(req *http.Request, handler http.Handler, uncachedVirtualClient client.Client, info *request.RequestInfo, rawObj []byte) error {
+	code, _, data, err := dryRunRequest(req, handler, rawObj)
+	if err != nil {
+		return err
+	} else if code < 200 || code >= 300 {
+		// let the actual request fail with the same error
+		return nil
+	}
+
+	updatedPod := &corev1.Pod{}
+	err = json.Unmarshal(data, updatedPod)
+	if err != nil {
+		return fmt.Errorf("decode pod: %w", err)
+	}
+
+	currentPod := &corev1.Pod{}
+	err = uncachedVirtualClient.Get(req.Context(), types.NamespacedName{Namespace: info.Namespace, Name: info.Name}, currentPod)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+
+	for _, annotation := range []string{nodedebug.UserAnnotation, nodedebug.SignatureAnnotation} {
+		if updatedPod.Annotations[annotation] != currentPod.Annotations[annotation] {
+			return kerrors.NewForbidden(corev1.Resource("pods"), info.Name, fmt.Errorf("annotation %s is reserved for node debug pods", annotation))
+		}
+	}
+
+	return nil
+}
+
+func isDryRun(req *http.Request) bool {
+	return len(req.URL.Query()["dryRun"]) > 0
+}
+
+func podImages(pod *corev1.Pod) []string {
+	images := []string{}
+	for _, container := range pod.Spec.InitContainers {
+		images = append(images, container.Image)
+	}
+	for _, container := range pod.Spec.Containers {
+		images = append(images, container.Image)
+	}
+
+	return images
+}
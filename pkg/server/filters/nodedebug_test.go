@@ -0,0 +1,294 @@
+package filters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/util/nodedebug"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var (
+	nodeDebugAdmin     = &user.DefaultInfo{Name: "alice", Groups: []string{"system:authenticated", "node-admins"}}
+	nodeDebugDeveloper = &user.DefaultInfo{Name: "bob", Groups: []string{"system:authenticated"}}
+)
+
+func TestNodeDebugCreate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		user         user.Info
+		pod          *corev1.Pod
+		expectedCode int
+		approved     bool
+	}{
+		{
+			name:         "approved",
+			user:         nodeDebugAdmin,
+			pod:          newNodeDebugPod("node-debugger-node1-abcde", "busybox:1.36"),
+			expectedCode: http.StatusCreated,
+			approved:     true,
+		},
+		{
+			name:         "group not allowed",
+			user:         nodeDebugDeveloper,
+			pod:          newNodeDebugPod("node-debugger-node1-abcde", "busybox:1.36"),
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "image not approved",
+			user:         nodeDebugAdmin,
+			pod:          newNodeDebugPod("node-debugger-node1-abcde", "ubuntu"),
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name: "signature set by user",
+			user: nodeDebugDeveloper,
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", Annotations: map[string]string{nodedebug.SignatureAnnotation: "abc"}},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "test", Image: "nginx"}}},
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name: "regular pod",
+			user: nodeDebugDeveloper,
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "test", Image: "nginx"}}},
+			},
+			expectedCode: http.StatusCreated,
+		},
+	}
+
+	for _, testCase := range testCases {
+		vClient, h := newNodeDebugTestHandler()
+
+		rawObj, err := json.Marshal(testCase.pod)
+		assert.NilError(t, err, testCase.name)
+		req := newPodRequest("create", http.MethodPost, "", "application/json", rawObj, testCase.user)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		assert.Equal(t, w.Code, testCase.expectedCode, testCase.name+": "+w.Body.String())
+
+		pod := &corev1.Pod{}
+		err = vClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: testCase.pod.Name}, pod)
+		if testCase.expectedCode != http.StatusCreated {
+			assert.Assert(t, kerrors.IsNotFound(err), testCase.name)
+			continue
+		}
+
+		assert.NilError(t, err, testCase.name)
+		assert.Equal(t, nodedebug.IsApproved(nodeDebugTestConfig, nodeDebugTestKey, pod), testCase.approved, testCase.name)
+		if testCase.approved {
+			assert.Equal(t, pod.Annotations[nodedebug.UserAnnotation], testCase.user.GetName(), testCase.name)
+
+			// the response contains the signed pod
+			responsePod := &corev1.Pod{}
+			assert.NilError(t, json.Unmarshal(w.Body.Bytes(), responsePod), testCase.name)
+			assert.Equal(t, responsePod.Annotations[nodedebug.SignatureAnnotation], pod.Annotations[nodedebug.SignatureAnnotation], testCase.name)
+		}
+	}
+}
+
+func TestNodeDebugAnnotationTampering(t *testing.T) {
+	testCases := []struct {
+		name         string
+		verb         string
+		method       string
+		contentType  string
+		body         func(pod *corev1.Pod) []byte
+		expectedCode int
+	}{
+		{
+			name:        "update",
+			verb:        "update",
+			method:      http.MethodPut,
+			contentType: "application/json",
+			body: func(pod *corev1.Pod) []byte {
+				pod = pod.DeepCopy()
+				pod.Annotations = map[string]string{nodedebug.UserAnnotation: "alice"}
+				return mustMarshal(pod)
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "json patch with escaped path",
+			verb:         "patch",
+			method:       http.MethodPatch,
+			contentType:  string(types.JSONPatchType),
+			body:         staticBody(`[{"op":"add","path":"/metadata/annotations","value":{}},{"op":"add","path":"/metadata/annotations/vcluster.loft.sh~1node-debug-user","value":"alice"}]`),
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "merge patch with unicode escaped key",
+			verb:         "patch",
+			method:       http.MethodPatch,
+			contentType:  string(types.MergePatchType),
+			body:         staticBody(`{"metadata":{"annotations":{"vcluster.loft.sh\u002fnode-debug-signature":"abc"}}}`),
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "strategic merge patch",
+			verb:         "patch",
+			method:       http.MethodPatch,
+			contentType:  string(types.StrategicMergePatchType),
+			body:         staticBody(`{"metadata":{"annotations":{"vcluster.loft.sh/node-debug-user":"alice"}}}`),
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "unrelated patch",
+			verb:         "patch",
+			method:       http.MethodPatch,
+			contentType:  string(types.MergePatchType),
+			body:         staticBody(`{"metadata":{"annotations":{"other":"value"}}}`),
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, testCase := range testCases {
+		vClient, h := newNodeDebugTestHandler()
+
+		// a node debug pod that was created through a pod template and never passed the proxy
+		pod := newNodeDebugPod("node-debugger-node1-abcde", "busybox:1.36")
+		pod.UID = "uid-1"
+		pod.Labels = map[string]string{"app.kubernetes.io/managed-by": "kubectl-debug"}
+		assert.NilError(t, vClient.Create(context.TODO(), pod), testCase.name)
+
+		req := newPodRequest(testCase.verb, testCase.method, pod.Name, testCase.contentType, testCase.body(pod), nodeDebugDeveloper)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		assert.Equal(t, w.Code, testCase.expectedCode, testCase.name+": "+w.Body.String())
+
+		updatedPod := &corev1.Pod{}
+		assert.NilError(t, vClient.Get(context.TODO(), client.ObjectKeyFromObject(pod), updatedPod), testCase.name)
+		assert.Equal(t, updatedPod.Annotations[nodedebug.UserAnnotation], "", testCase.name)
+		assert.Equal(t, updatedPod.Annotations[nodedebug.SignatureAnnotation], "", testCase.name)
+		assert.Assert(t, !nodedebug.IsApproved(nodeDebugTestConfig, nodeDebugTestKey, updatedPod), testCase.name)
+	}
+}
+
+var (
+	nodeDebugTestKey    = []byte("test-key")
+	nodeDebugTestConfig = config.SyncPodNodeDebug{
+		Enabled: true,
+		Groups:  []string{"node-admins"},
+		Images:  []string{"busybox:*"},
+	}
+)
+
+func newNodeDebugTestHandler() (client.Client, http.Handler) {
+	vClient := fake.NewClientBuilder().WithScheme(testingutil.NewScheme()).WithObjects(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+	).Build()
+
+	return vClient, WithNodeDebug(fakePodAPIServer(vClient), nodeDebugTestConfig, nodeDebugTestKey, vClient, record.NewFakeRecorder(10))
+}
+
+func newNodeDebugPod(name, image string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: corev1.PodSpec{
+			NodeName:   "node1",
+			Containers: []corev1.Container{{Name: "debugger", Image: image}},
+		},
+	}
+}
+
+func newPodRequest(verb, method, name, contentType string, body []byte, userInfo user.Info) *http.Request {
+	path := "/api/v1/namespaces/default/pods"
+	if name != "" {
+		path += "/" + name
+	}
+
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	ctx := request.WithRequestInfo(req.Context(), &request.RequestInfo{
+		IsResourceRequest: true,
+		Verb:              verb,
+		APIVersion:        "v1",
+		Resource:          "pods",
+		Namespace:         "default",
+		Name:              name,
+	})
+	return req.WithContext(request.WithUser(ctx, userInfo))
+}
+
+// fakePodAPIServer serves pod creates, updates and patches from the fake client like the virtual apiserver
+func fakePodAPIServer(vClient client.Client) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		info, _ := request.RequestInfoFrom(req.Context())
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		dryRun := isDryRun(req)
+		pod := &corev1.Pod{}
+		code := http.StatusOK
+		switch info.Verb {
+		case "create":
+			code = http.StatusCreated
+			err = json.Unmarshal(body, pod)
+			if err == nil && !dryRun {
+				pod.UID = types.UID("uid-" + pod.Name)
+				err = vClient.Create(req.Context(), pod)
+			}
+		case "update":
+			err = json.Unmarshal(body, pod)
+			if err == nil && !dryRun {
+				err = vClient.Update(req.Context(), pod)
+			}
+		case "patch":
+			err = vClient.Get(req.Context(), types.NamespacedName{Namespace: info.Namespace, Name: info.Name}, pod)
+			if err == nil {
+				// the fake client doesn't apply dry run patches, so apply them to a copy instead
+				target := vClient
+				if dryRun {
+					target = fake.NewClientBuilder().WithScheme(vClient.Scheme()).WithObjects(pod.DeepCopy()).Build()
+				}
+
+				err = target.Patch(req.Context(), pod, client.RawPatch(types.PatchType(req.Header.Get("Content-Type")), body))
+			}
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		_, _ = w.Write(mustMarshal(pod))
+	})
+}
+
+func staticBody(body string) func(pod *corev1.Pod) []byte {
+	return func(*corev1.Pod) []byte {
+		return []byte(body)
+	}
+}
+
+func mustMarshal(obj interface{}) []byte {
+	out, err := json.Marshal(obj)
+	if err != nil {
+		panic(err)
+	}
+
+	return out
+}
@@ -0,0 +1,44 @@
+package filters
+
+import (
+	"net/http"
+
+	"github.com/loft-sh/vcluster/pkg/oidc"
+	"k8s.io/klog/v2"
+)
+
+// WithServiceAccountIssuerDiscovery serves the OpenID discovery document and JWKS of the virtual cluster
+// service account issuer without authentication, so that external systems can verify service account tokens.
+func WithServiceAccountIssuerDiscovery(h http.Handler, provider *oidc.Provider) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != oidc.DiscoveryPath && req.URL.Path != oidc.JWKSPath {
+			h.ServeHTTP(w, req)
+			return
+		} else if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		document, err := provider.Get(req.Context())
+		if err != nil {
+			klog.Errorf("Error retrieving service account issuer discovery: %v", err)
+			http.Error(w, "service account issuer discovery is currently unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		body := document.Discovery
+		contentType := "application/json"
+		if req.URL.Path == oidc.JWKSPath {
+			body = document.JWKS
+			contentType = "application/jwk-set+json"
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		w.WriteHeader(http.StatusOK)
+		if req.Method == http.MethodGet {
+			_, _ = w.Write(body)
+		}
+	})
+}
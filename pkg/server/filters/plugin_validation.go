@@ -0,0 +1,166 @@
+package filters
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/loft-sh/vcluster/pkg/plugin"
+	plugintypes "github.com/loft-sh/vcluster/pkg/plugin/types"
+	requestpkg "github.com/loft-sh/vcluster/pkg/util/request"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// connectSubResources are the sub resources that are validated with the CONNECT operation
+var connectSubResources = sets.New("exec", "attach", "portforward", "proxy")
+
+// WithPluginValidation lets plugins that registered a validating hook deny requests before they reach the
+// virtual cluster. The object passed to the plugins is the result of a dry run of the request, so patches and
+// defaulting are already applied.
+func WithPluginValidation(handler http.Handler, uncachedVirtualClient client.Client) http.Handler {
+	s := serializer.NewCodecFactory(uncachedVirtualClient.Scheme())
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		info, ok := request.RequestInfoFrom(req.Context())
+		if !ok {
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, fmt.Errorf("request info is missing"))
+			return
+		} else if !info.IsResourceRequest {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		operation := operationFromRequestInfo(info)
+		if operation == "" {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		gvk, err := uncachedVirtualClient.RESTMapper().KindFor(schema.GroupVersionResource{Group: info.APIGroup, Version: info.APIVersion, Resource: info.Resource})
+		if err != nil {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		apiVersion, kind := gvk.ToAPIVersionAndKind()
+		if !plugin.DefaultManager.HasValidatingHooksForType(plugintypes.VersionKindType{APIVersion: apiVersion, Kind: kind, Type: string(operation)}) {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		userInfo, _ := request.UserFrom(req.Context())
+		validationRequest := &plugintypes.ValidationRequest{
+			APIVersion:  apiVersion,
+			Kind:        kind,
+			Resource:    info.Resource,
+			SubResource: info.Subresource,
+			Operation:   string(operation),
+			Namespace:   info.Namespace,
+			Name:        info.Name,
+			DryRun:      len(req.URL.Query()["dryRun"]) > 0,
+			UserInfo:    userInfo,
+		}
+
+		// find out how the object would look like after the request
+		var rawObj []byte
+		if operation == admission.Create || operation == admission.Update {
+			rawObj, err = io.ReadAll(req.Body)
+			if err != nil {
+				responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
+				return
+			}
+
+			code, header, data, err := dryRunRequest(req, handler, rawObj)
+			if err != nil {
+				responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
+				return
+			} else if code < 200 || code >= 300 {
+				writeWithHeader(w, code, header, data)
+				return
+			}
+
+			validationRequest.Object = data
+		}
+
+		// retrieve the current object
+		if info.Name != "" && (operation == admission.Update || operation == admission.Delete) {
+			validationRequest.OldObject, err = getCurrentObject(req, uncachedVirtualClient, gvk, info.Namespace, info.Name)
+			if err != nil {
+				responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
+				return
+			}
+		}
+
+		err = plugin.DefaultManager.ValidateRequest(req.Context(), validationRequest)
+		if err != nil {
+			responsewriters.ErrorNegotiated(err, s, corev1.SchemeGroupVersion, w, req)
+			return
+		}
+
+		if rawObj != nil {
+			serveWithBody(handler, w, req, rawObj)
+			return
+		}
+
+		handler.ServeHTTP(w, req)
+	})
+}
+
+func operationFromRequestInfo(info *request.RequestInfo) admission.Operation {
+	if connectSubResources.Has(info.Subresource) {
+		return admission.Connect
+	}
+
+	switch info.Verb {
+	case "create":
+		return admission.Create
+	case "update", "patch":
+		return admission.Update
+	case "delete":
+		return admission.Delete
+	}
+
+	return ""
+}
+
+// dryRunRequest executes the request as dry run and returns the resulting object as json. The request passes through
+// the downstream filters, so every filter that changes objects outside the virtual cluster api server has to honor
+// the dryRun query, e.g. evictions of host pods or services created in the host cluster.
+func dryRunRequest(req *http.Request, handler http.Handler, rawObj []byte) (int, http.Header, []byte, error) {
+	dryRunReq := req.Clone(req.Context())
+	q := dryRunReq.URL.Query()
+	q.Set("dryRun", metav1.DryRunAll)
+	dryRunReq.URL.RawQuery = q.Encode()
+	dryRunReq.Header.Set("Accept", "application/json")
+	dryRunReq.Header.Del("Accept-Encoding")
+	dryRunReq.Body = io.NopCloser(bytes.NewReader(rawObj))
+	dryRunReq.ContentLength = int64(len(rawObj))
+	return executeRequest(dryRunReq, handler)
+}
+
+func getCurrentObject(req *http.Request, uncachedVirtualClient client.Client, gvk schema.GroupVersionKind, namespace, name string) ([]byte, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	err := uncachedVirtualClient.Get(req.Context(), types.NamespacedName{Namespace: namespace, Name: name}, obj)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return json.Marshal(obj)
+}
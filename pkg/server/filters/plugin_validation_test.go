@@ -0,0 +1,55 @@
+package filters
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDryRunRequestEviction(t *testing.T) {
+	vPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+	pPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: translate.Default.PhysicalName("test", "default"), Namespace: translate.Default.PhysicalNamespace("default")}}
+	vClient := fake.NewClientBuilder().WithScheme(testingutil.NewScheme()).WithObjects(vPod).Build()
+	pClient := fake.NewClientBuilder().WithScheme(testingutil.NewScheme()).WithObjects(pPod).Build()
+
+	// the dry run of a validated eviction must not evict the host pod
+	var downstreamQuery string
+	downstream := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		downstreamQuery = req.URL.RawQuery
+		w.WriteHeader(http.StatusCreated)
+	})
+	h := WithEvictionPassthrough(downstream, pClient, vClient, &rest.Config{Host: "http://127.0.0.1:0"}, record.NewFakeRecorder(10))
+
+	rawObj := mustMarshal(map[string]interface{}{"apiVersion": "policy/v1", "kind": "Eviction", "metadata": map[string]interface{}{"name": "test", "namespace": "default"}})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/namespaces/default/pods/test/eviction", bytes.NewReader(rawObj))
+	req.Header.Set("Content-Type", "application/json")
+	ctx := request.WithRequestInfo(req.Context(), &request.RequestInfo{
+		IsResourceRequest: true,
+		Verb:              "create",
+		APIVersion:        "v1",
+		Resource:          "pods",
+		Subresource:       "eviction",
+		Namespace:         "default",
+		Name:              "test",
+	})
+	req = req.WithContext(request.WithUser(ctx, nodeDebugDeveloper))
+
+	code, _, _, err := dryRunRequest(req, h, rawObj)
+	assert.NilError(t, err)
+	assert.Equal(t, code, http.StatusCreated)
+	assert.Equal(t, downstreamQuery, "dryRun=All")
+	assert.NilError(t, pClient.Get(context.TODO(), types.NamespacedName{Namespace: pPod.Namespace, Name: pPod.Name}, &corev1.Pod{}))
+}
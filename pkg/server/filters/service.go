@@ -160,6 +160,9 @@ func updateService(req *http.Request, decoder encoding.Decoder, localClient clie
 
 	// now we have the cluster ip that we can apply to the new service
 	newVService.Spec.ClusterIP = pService.Spec.ClusterIP
+	newVService.Spec.ClusterIPs = pService.Spec.ClusterIPs
+	newVService.Spec.IPFamilies = pService.Spec.IPFamilies
+	newVService.Spec.IPFamilyPolicy = pService.Spec.IPFamilyPolicy
 	// also we need to apply newly allocated node ports
 	newVService.Spec.HealthCheckNodePort = pService.Spec.HealthCheckNodePort
 	newVService.Spec.Ports = pService.Spec.Ports
@@ -224,6 +227,8 @@ func createService(req *http.Request, decoder encoding.Decoder, localClient clie
 
 	vService.Spec.ClusterIP = newService.Spec.ClusterIP
 	vService.Spec.ClusterIPs = newService.Spec.ClusterIPs
+	vService.Spec.IPFamilies = newService.Spec.IPFamilies
+	vService.Spec.IPFamilyPolicy = newService.Spec.IPFamilyPolicy
 	vService.Spec.Ports = newService.Spec.Ports
 	vService.Spec.HealthCheckNodePort = newService.Spec.HealthCheckNodePort
 	vService.Status = newService.Status
@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"time"
 
+	vclusterconfig "github.com/loft-sh/vcluster/config"
 	"github.com/loft-sh/vcluster/pkg/authentication/delegatingauthenticator"
 	"github.com/loft-sh/vcluster/pkg/authorization/allowall"
 	"github.com/loft-sh/vcluster/pkg/authorization/delegatingauthorizer"
@@ -18,11 +19,16 @@ import (
 	"github.com/loft-sh/vcluster/pkg/constants"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/nodes"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/nodes/nodeservice"
+	"github.com/loft-sh/vcluster/pkg/oidc"
+	"github.com/loft-sh/vcluster/pkg/plugin"
 	"github.com/loft-sh/vcluster/pkg/server/cert"
 	"github.com/loft-sh/vcluster/pkg/server/filters"
 	"github.com/loft-sh/vcluster/pkg/server/handler"
 	servertypes "github.com/loft-sh/vcluster/pkg/server/types"
+	"github.com/loft-sh/vcluster/pkg/tracing"
 	"github.com/loft-sh/vcluster/pkg/util/blockingcacheclient"
+	"github.com/loft-sh/vcluster/pkg/util/nodedebug"
+	"github.com/loft-sh/vcluster/pkg/util/noderules"
 	"github.com/loft-sh/vcluster/pkg/util/pluginhookclient"
 	"github.com/loft-sh/vcluster/pkg/util/serverhelper"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
@@ -70,6 +76,9 @@ type Server struct {
 	clientCaFile           string
 	redirectResources      []delegatingauthorizer.GroupVersionResourceVerb
 	fakeKubeletIPs         bool
+	flowControl            vclusterconfig.ControlPlaneProxyFlowControl
+
+	serviceAccountIssuerDiscovery *oidc.Provider
 }
 
 // NewServer creates and installs a new Server.
@@ -157,6 +166,7 @@ func NewServer(ctx *config.ControllerContext, requestHeaderCaFile, clientCaFile
 		handler:               http.NewServeMux(),
 
 		fakeKubeletIPs: ctx.Config.Networking.Advanced.ProxyKubelets.ByIP,
+		flowControl:    ctx.Config.ControlPlane.Proxy.FlowControl,
 
 		currentNamespace:       ctx.CurrentNamespace,
 		currentNamespaceClient: cachedLocalClient,
@@ -192,6 +202,14 @@ func NewServer(ctx *config.ControllerContext, requestHeaderCaFile, clientCaFile
 		},
 	}
 
+	// serve the service account issuer discovery without authentication
+	if ctx.Config.ControlPlane.Advanced.ServiceAccountIssuerDiscovery.Enabled {
+		s.serviceAccountIssuerDiscovery, err = oidc.NewProvider(virtualConfig)
+		if err != nil {
+			return nil, errors.Wrap(err, "create service account issuer discovery provider")
+		}
+	}
+
 	// init plugins
 	admissionHandler, err := initAdmission(ctx.Context, virtualConfig)
 	if err != nil {
@@ -199,9 +217,40 @@ func NewServer(ctx *config.ControllerContext, requestHeaderCaFile, clientCaFile
 	}
 
 	h := handler.ImpersonatingHandler("", virtualConfig)
+	h = tracing.WithSpan(h, "proxy")
 	h = filters.WithServiceCreateRedirect(h, uncachedLocalClient, uncachedVirtualClient, virtualConfig, ctx.Config.Experimental.SyncSettings.SyncLabels)
+	h = tracing.WithSpan(h, "filter serviceCreateRedirect")
+	h = filters.WithEvictionPassthrough(h, uncachedLocalClient, uncachedVirtualClient, virtualConfig, ctx.VirtualManager.GetEventRecorderFor("vcluster-eviction"))
+	h = tracing.WithSpan(h, "filter evictionPassthrough")
+	if ctx.Config.Sync.ToHost.Pods.NodeDebug.Enabled {
+		localKubeClient, err := kubernetes.NewForConfig(localConfig)
+		if err != nil {
+			return nil, err
+		}
+		nodeDebugKey, err := nodedebug.EnsureSigningKey(ctx.Context, localKubeClient, ctx.CurrentNamespace, translate.VClusterName)
+		if err != nil {
+			return nil, errors.Wrap(err, "ensure node debug signing key")
+		}
+
+		h = filters.WithNodeDebug(h, ctx.Config.Sync.ToHost.Pods.NodeDebug, nodeDebugKey, uncachedVirtualClient, ctx.VirtualManager.GetEventRecorderFor("vcluster-node-debug"))
+		h = tracing.WithSpan(h, "filter nodeDebug")
+	}
 	h = filters.WithRedirect(h, localConfig, uncachedLocalClient.Scheme(), uncachedVirtualClient, admissionHandler, s.redirectResources)
+	h = tracing.WithSpan(h, "filter redirect")
 	h = filters.WithMetricsProxy(h, localConfig, cachedVirtualClient)
+	h = tracing.WithSpan(h, "filter metricsProxy")
+
+	// is custom or external metrics proxy enabled?
+	if ctx.Config.Observability.Metrics.Proxy.CustomMetrics || ctx.Config.Observability.Metrics.Proxy.ExternalMetrics {
+		h = filters.WithCustomMetricsProxy(h, localConfig, cachedVirtualClient, ctx.Config.Observability.Metrics.Proxy.CustomMetrics, ctx.Config.Observability.Metrics.Proxy.ExternalMetrics)
+		h = tracing.WithSpan(h, "filter customMetricsProxy")
+	}
+
+	// is metrics federation enabled?
+	if ctx.Config.Observability.Metrics.Federation.Enabled {
+		h = filters.WithMetricsFederation(h, localConfig, cachedVirtualClient, uncachedVirtualClient)
+		h = tracing.WithSpan(h, "filter metricsFederation")
+	}
 
 	// is metrics proxy enabled?
 	if ctx.Config.Observability.Metrics.Proxy.Nodes || ctx.Config.Observability.Metrics.Proxy.Pods {
@@ -214,18 +263,31 @@ func NewServer(ctx *config.ControllerContext, requestHeaderCaFile, clientCaFile
 			virtualConfig,
 			ctx.Config.Experimental.MultiNamespaceMode.Enabled,
 		)
+		h = tracing.WithSpan(h, "filter metricsServerProxy")
 	}
 
 	if ctx.Config.Sync.FromHost.Nodes.Enabled && ctx.Config.Sync.FromHost.Nodes.SyncBackChanges {
-		h = filters.WithNodeChanges(ctx.Context, h, uncachedLocalClient, uncachedVirtualClient, virtualConfig)
+		nodeRules, err := noderules.New(ctx.Config.Sync.FromHost.Nodes)
+		if err != nil {
+			return nil, errors.Wrap(err, "create node rules")
+		}
+
+		h = filters.WithNodeChanges(ctx.Context, h, uncachedLocalClient, uncachedVirtualClient, virtualConfig, nodeRules)
+		h = tracing.WithSpan(h, "filter nodeChanges")
 	}
 	h = filters.WithFakeKubelet(h, localConfig, cachedVirtualClient)
+	h = tracing.WithSpan(h, "filter fakeKubelet")
 	h = filters.WithK3sConnect(h)
 
 	if os.Getenv("DEBUG") == "true" {
 		h = filters.WithPprof(h)
 	}
 
+	if plugin.DefaultManager.HasPlugins() {
+		h = filters.WithPluginValidation(h, uncachedVirtualClient)
+		h = tracing.WithSpan(h, "filter pluginValidation")
+	}
+
 	for _, f := range ctx.AdditionalServerFilters {
 		h = f(h)
 	}
@@ -234,6 +296,16 @@ func NewServer(ctx *config.ControllerContext, requestHeaderCaFile, clientCaFile
 		h = handler(h)
 	}
 
+	// serve the plugin status and metrics
+	if plugin.DefaultManager.HasPlugins() {
+		serverhelper.HandleRoute(s.handler, "/vcluster/plugins/", filters.WithNonResourceAuthorization(plugin.DefaultManager.StatusHandler(), uncachedVirtualClient))
+	}
+
+	// serve the flow control metrics
+	if s.flowControl.Enabled {
+		serverhelper.HandleRoute(s.handler, "/vcluster/flowcontrol/", filters.WithNonResourceAuthorization(filters.FlowControlMetricsHandler(), uncachedVirtualClient))
+	}
+
 	serverhelper.HandleRoute(s.handler, "/", h)
 
 	return s, nil
@@ -243,6 +315,7 @@ func NewServer(ctx *config.ControllerContext, requestHeaderCaFile, clientCaFile
 func (s *Server) ServeOnListenerTLS(address string, port int, stopChan <-chan struct{}) error {
 	// kubernetes build handler configuration
 	serverConfig := server.NewConfig(serializer.NewCodecFactory(s.uncachedVirtualClient.Scheme()))
+	serverConfig.TracerProvider = tracing.TracerProvider()
 	serverConfig.RequestInfoResolver = &request.RequestInfoFactory{
 		APIPrefixes:          sets.NewString("api", "apis"),
 		GrouplessAPIPrefixes: sets.NewString("api"),
@@ -350,13 +423,16 @@ func createCachedClient(ctx context.Context, config *rest.Config, namespace stri
 }
 
 func (s *Server) buildHandlerChain(serverConfig *server.Config) http.Handler {
-	defaultHandler := DefaultBuildHandlerChain(s.handler, serverConfig)
+	defaultHandler := DefaultBuildHandlerChain(s.handler, serverConfig, s.flowControl)
 	defaultHandler = filters.WithNodeName(defaultHandler, s.currentNamespace, s.fakeKubeletIPs, s.cachedVirtualClient, s.currentNamespaceClient)
+	if s.serviceAccountIssuerDiscovery != nil {
+		defaultHandler = filters.WithServiceAccountIssuerDiscovery(defaultHandler, s.serviceAccountIssuerDiscovery)
+	}
 	return defaultHandler
 }
 
 // Copied from "k8s.io/apiserver/pkg/server" package
-func DefaultBuildHandlerChain(apiHandler http.Handler, c *server.Config) http.Handler {
+func DefaultBuildHandlerChain(apiHandler http.Handler, c *server.Config, flowControl vclusterconfig.ControlPlaneProxyFlowControl) http.Handler {
 	handler := filterlatency.TrackCompleted(apiHandler)
 	handler = genericapifilters.WithAuthorization(handler, c.Authorization.Authorizer, c.Serializer)
 	handler = filterlatency.TrackStarted(handler, c.TracerProvider, "authorization")
@@ -370,6 +446,13 @@ func DefaultBuildHandlerChain(apiHandler http.Handler, c *server.Config) http.Ha
 		handler = filterlatency.TrackStarted(handler, c.TracerProvider, "priorityandfairness")
 	} else {
 		handler = genericfilters.WithMaxInFlightLimit(handler, c.MaxRequestsInFlight, c.MaxMutatingRequestsInFlight, c.LongRunningFunc)
+
+		// limit users and groups before they can occupy the global in flight limit
+		if flowControl.Enabled {
+			handler = filterlatency.TrackCompleted(handler)
+			handler = filters.WithFlowControl(handler, flowControl, c.LongRunningFunc)
+			handler = filterlatency.TrackStarted(handler, c.TracerProvider, "flowcontrol")
+		}
 	}
 
 	handler = filterlatency.TrackCompleted(handler)
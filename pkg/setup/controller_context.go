@@ -3,6 +3,7 @@ package setup
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"time"
 
@@ -51,6 +52,13 @@ func NewControllerContext(
 		return nil, err
 	}
 
+	// serve plugin http handlers through the proxy, optional plugins might register them after a restart
+	if plugin.DefaultManager.HasPlugins() {
+		controllerContext.ExtraHandlers = append(controllerContext.ExtraHandlers, func(h http.Handler) http.Handler {
+			return plugin.DefaultManager.WithHTTPHandlers(h, controllerContext.VirtualManager.GetClient())
+		})
+	}
+
 	// init pro controller context
 	err = pro.InitProControllerContext(controllerContext)
 	if err != nil {
@@ -80,7 +88,10 @@ func InitManagers(
 	if options.Experimental.MultiNamespaceMode.Enabled {
 		// set options.TargetNamespace to empty because it will later be used in Manager
 		options.TargetNamespace = ""
-		translate.Default = translate.NewMultiNamespaceTranslator(currentNamespace)
+		translate.Default, err = translate.NewMultiNamespaceTranslatorWithOptions(currentNamespace, options.Experimental.MultiNamespaceMode)
+		if err != nil {
+			return nil, err
+		}
 	} else {
 		// ensure target namespace
 		if options.TargetNamespace == "" {
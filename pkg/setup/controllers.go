@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"os"
 	"time"
 
 	"github.com/loft-sh/vcluster/pkg/config"
@@ -12,8 +13,10 @@ import (
 	synccontext "github.com/loft-sh/vcluster/pkg/controllers/syncer/context"
 	"github.com/loft-sh/vcluster/pkg/coredns"
 	"github.com/loft-sh/vcluster/pkg/metricsapiservice"
+	"github.com/loft-sh/vcluster/pkg/oidc"
 	"github.com/loft-sh/vcluster/pkg/plugin"
 	"github.com/loft-sh/vcluster/pkg/pro"
+	"github.com/loft-sh/vcluster/pkg/sharding"
 	"github.com/loft-sh/vcluster/pkg/specialservices"
 	syncertypes "github.com/loft-sh/vcluster/pkg/types"
 	"github.com/loft-sh/vcluster/pkg/util/kubeconfig"
@@ -26,6 +29,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"k8s.io/klog/v2"
@@ -37,6 +41,88 @@ func StartControllers(
 	controlPlaneNamespace,
 	controlPlaneService string,
 	controlPlaneConfig *rest.Config,
+) error {
+	syncers, err := createSyncersAndStartManagers(controllerContext)
+	if err != nil {
+		return err
+	}
+
+	return startLeaderControllers(controllerContext, controlPlaneNamespace, controlPlaneService, controlPlaneConfig, syncers)
+}
+
+// StartShardedControllers starts the resource syncers on every replica, where each replica only syncs the objects of
+// the shards it owns. All other controllers are started by startLeaderElection once this replica becomes the leader.
+func StartShardedControllers(
+	controllerContext *config.ControllerContext,
+	controlPlaneNamespace,
+	controlPlaneService string,
+	controlPlaneConfig *rest.Config,
+	startLeaderElection func(startLeading func() error) error,
+) error {
+	// create the sharding manager, the shard leases are stored next to the leader election lease
+	identity, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+	shardingClient, err := kubernetes.NewForConfig(rest.AddUserAgent(controllerContext.LocalManager.GetConfig(), "sharding"))
+	if err != nil {
+		return errors.Wrap(err, "create sharding client")
+	}
+	highAvailability := controllerContext.Config.ControlPlane.StatefulSet.HighAvailability
+	controllerContext.Sharding = sharding.NewManager(shardingClient, sharding.Options{
+		Identity:      identity,
+		Namespace:     controllerContext.CurrentNamespace,
+		Mode:          highAvailability.Sharding.Mode,
+		Shards:        highAvailability.Sharding.Shards,
+		LeaseDuration: time.Duration(highAvailability.LeaseDuration) * time.Second,
+		RenewDeadline: time.Duration(highAvailability.RenewDeadline) * time.Second,
+		RetryPeriod:   time.Duration(highAvailability.RetryPeriod) * time.Second,
+	})
+
+	syncers, err := createSyncersAndStartManagers(controllerContext)
+	if err != nil {
+		return err
+	}
+
+	// register the resource syncers and start acquiring shards
+	err = controllers.RegisterSyncers(controllerContext, syncers)
+	if err != nil {
+		return err
+	}
+	go controllerContext.Sharding.Start(controllerContext.Context)
+
+	return startLeaderElection(func() error {
+		return startLeaderControllers(controllerContext, controlPlaneNamespace, controlPlaneService, controlPlaneConfig, nil)
+	})
+}
+
+func createSyncersAndStartManagers(controllerContext *config.ControllerContext) ([]syncertypes.Object, error) {
+	// init syncers
+	var syncers []syncertypes.Object
+	if !controllerContext.Config.Experimental.SyncSettings.DisableSync {
+		var err error
+		syncers, err = controllers.Create(controllerContext)
+		if err != nil {
+			return nil, errors.Wrap(err, "instantiate controllers")
+		}
+	}
+
+	// start managers
+	err := StartManagers(controllerContext, syncers)
+	if err != nil {
+		return nil, err
+	}
+
+	return syncers, nil
+}
+
+// startLeaderControllers starts everything that should only run on the leader and registers the given syncers
+func startLeaderControllers(
+	controllerContext *config.ControllerContext,
+	controlPlaneNamespace,
+	controlPlaneService string,
+	controlPlaneConfig *rest.Config,
+	syncers []syncertypes.Object,
 ) error {
 	// exchange control plane client
 	controlPlaneClient, err := pro.ExchangeControlPlaneClient(controllerContext, controlPlaneNamespace, controlPlaneConfig)
@@ -44,8 +130,7 @@ func StartControllers(
 		return err
 	}
 
-	// start coredns & create syncers
-	var syncers []syncertypes.Object
+	// start coredns
 	if !controllerContext.Config.Experimental.SyncSettings.DisableSync {
 		// setup CoreDNS according to the manifest file
 		// skip this if both integrated and dedicated coredns
@@ -67,18 +152,6 @@ func StartControllers(
 				}
 			}
 		}()
-
-		// init syncers
-		syncers, err = controllers.Create(controllerContext)
-		if err != nil {
-			return errors.Wrap(err, "instantiate controllers")
-		}
-	}
-
-	// start managers
-	err = StartManagers(controllerContext, syncers)
-	if err != nil {
-		return err
 	}
 
 	// sync remote Endpoints
@@ -143,9 +216,36 @@ func StartControllers(
 			if err != nil {
 				klog.Errorf("Error writing kube config to secret: %v", err)
 			}
+
+			err = WriteAdditionalKubeConfigsToSecrets(controllerContext.Context, controlPlaneNamespace, controlPlaneClient, controllerContext.VirtualManager.GetClient(), controllerContext.Config, controllerContext.VirtualRawConfig)
+			if err != nil {
+				klog.Errorf("Error writing additional kube configs to secrets: %v", err)
+			}
 		}, time.Minute, controllerContext.StopChan)
 	}()
 
+	// publish the service account issuer discovery to a host config map
+	if controllerContext.Config.ControlPlane.Advanced.ServiceAccountIssuerDiscovery.Enabled && controllerContext.Config.ControlPlane.Advanced.ServiceAccountIssuerDiscovery.ConfigMap.Enabled {
+		provider, err := oidc.NewProvider(controllerContext.VirtualManager.GetConfig())
+		if err != nil {
+			return fmt.Errorf("create service account issuer discovery provider: %w", err)
+		}
+
+		configMapName := controllerContext.Config.ControlPlane.Advanced.ServiceAccountIssuerDiscovery.ConfigMap.Name
+		if configMapName == "" {
+			configMapName = oidc.GetDefaultConfigMapName(translate.VClusterName)
+		}
+
+		go func() {
+			wait.Until(func() {
+				err := oidc.PublishConfigMap(controllerContext.Context, provider, controlPlaneClient, controlPlaneNamespace, configMapName)
+				if err != nil {
+					klog.Errorf("Error publishing service account issuer discovery: %v", err)
+				}
+			}, time.Minute, controllerContext.StopChan)
+		}()
+	}
+
 	// set leader
 	err = plugin.DefaultManager.SetLeader(controllerContext.Context)
 	if err != nil {
@@ -8,7 +8,6 @@ import (
 	"path/filepath"
 	"reflect"
 	"strconv"
-	"strings"
 	"time"
 
 	vclusterconfig "github.com/loft-sh/vcluster/config"
@@ -89,8 +88,8 @@ func initialize(
 	// check what distro are we running
 	switch distro {
 	case vclusterconfig.K0SDistro:
-		// only return the first cidr, because k0s don't accept coma separated ones
-		serviceCIDR = strings.Split(serviceCIDR, ",")[0]
+		// k0s only supports dual-stack with IPv4 as primary family
+		serviceCIDR = k0s.SupportedServiceCIDR(serviceCIDR)
 
 		// ensure service cidr
 		err := k0s.WriteK0sConfig(serviceCIDR, options)
@@ -0,0 +1,503 @@
+package setup
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/certs"
+	"github.com/loft-sh/vcluster/pkg/config"
+	"github.com/loft-sh/vcluster/pkg/util/kubeconfig"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// KubeConfigIssuedAtAnnotation holds the time the identity within the kubeconfig secret was issued
+	KubeConfigIssuedAtAnnotation = "vcluster.loft.sh/kubeconfig-issued-at"
+	// KubeConfigExpiresAtAnnotation holds the time the identity within the kubeconfig secret expires
+	KubeConfigExpiresAtAnnotation = "vcluster.loft.sh/kubeconfig-expires-at"
+	// KubeConfigHashAnnotation holds the hash of the configuration the kubeconfig secret was generated from
+	KubeConfigHashAnnotation = "vcluster.loft.sh/kubeconfig-hash"
+	// KubeConfigExportLabel marks the secrets, service accounts and bindings that were created for exported
+	// kubeconfigs, so they can be deleted once they are no longer configured. On secrets it holds the vCluster name.
+	KubeConfigExportLabel = "vcluster.loft.sh/kubeconfig-export"
+
+	DefaultExportCertificateExpiration = 365 * 24 * time.Hour
+	DefaultExportTokenExpiration       = 24 * time.Hour
+
+	// exportBindingPrefix is the prefix of the role bindings created for exported service accounts
+	exportBindingPrefix = "vcluster-export-"
+)
+
+// WriteAdditionalKubeConfigsToSecrets writes the kubeconfigs defined in exportKubeConfig.additionalSecrets and rotates
+// their identities once two thirds of their lifetime have passed. Secrets, service accounts and bindings of exports
+// that were removed or narrowed are deleted.
+func WriteAdditionalKubeConfigsToSecrets(ctx context.Context, currentNamespace string, currentNamespaceClient, virtualClient client.Client, options *config.VirtualClusterConfig, syncerConfig *clientcmdapi.Config) error {
+	if len(options.ExportKubeConfig.AdditionalSecrets) > 0 {
+		err := writeAdditionalKubeConfigsToSecrets(ctx, currentNamespace, currentNamespaceClient, virtualClient, options, syncerConfig)
+		if err != nil {
+			return err
+		}
+	}
+
+	return cleanupKubeConfigExports(ctx, currentNamespace, currentNamespaceClient, virtualClient, options.ExportKubeConfig.AdditionalSecrets)
+}
+
+func writeAdditionalKubeConfigsToSecrets(ctx context.Context, currentNamespace string, currentNamespaceClient, virtualClient client.Client, options *config.VirtualClusterConfig, syncerConfig *clientcmdapi.Config) error {
+	baseConfig, err := CreateVClusterKubeConfig(syncerConfig, options)
+	if err != nil {
+		return err
+	}
+
+	// get the cluster of the current context
+	var cluster *clientcmdapi.Cluster
+	if currentContext, ok := baseConfig.Contexts[baseConfig.CurrentContext]; ok {
+		cluster = baseConfig.Clusters[currentContext.Cluster]
+	}
+	if cluster == nil {
+		for _, c := range baseConfig.Clusters {
+			cluster = c
+			break
+		}
+	}
+	if cluster == nil {
+		return fmt.Errorf("no cluster found in virtual cluster kube config")
+	}
+
+	for _, secretConfig := range options.ExportKubeConfig.AdditionalSecrets {
+		err = writeAdditionalKubeConfigToSecret(ctx, currentNamespace, currentNamespaceClient, virtualClient, options, cluster, secretConfig)
+		if err != nil {
+			return fmt.Errorf("write kube config secret %s: %w", secretConfig.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func writeAdditionalKubeConfigToSecret(ctx context.Context, currentNamespace string, currentNamespaceClient, virtualClient client.Client, options *config.VirtualClusterConfig, cluster *clientcmdapi.Cluster, secretConfig vclusterconfig.ExportKubeConfigAdditionalSecret) error {
+	secretNamespace := secretConfig.Namespace
+	if secretNamespace == "" {
+		secretNamespace = currentNamespace
+	}
+	server := secretConfig.Server
+	if server == "" {
+		server = cluster.Server
+	}
+	contextName := secretConfig.Context
+	if contextName == "" {
+		contextName = secretConfig.Name
+	}
+
+	// check if the existing secret is still valid
+	hash, err := hashAdditionalKubeConfig(secretConfig, server, cluster.CertificateAuthorityData)
+	if err != nil {
+		return err
+	}
+	kubeConfigSecret := &corev1.Secret{}
+	err = currentNamespaceClient.Get(ctx, client.ObjectKey{Namespace: secretNamespace, Name: secretConfig.Name}, kubeConfigSecret)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	} else if err == nil && !needsRotation(kubeConfigSecret, hash, time.Now()) {
+		return nil
+	}
+
+	// issue a new identity
+	issuedAt := time.Now()
+	authInfo := &clientcmdapi.AuthInfo{}
+	var expiresAt time.Time
+	if secretConfig.Certificate.User != "" {
+		expiresAt, err = issueClientCertificate(options, secretConfig.Certificate, issuedAt, authInfo)
+	} else {
+		expiresAt, err = issueServiceAccountToken(ctx, virtualClient, secretConfig.ServiceAccount, authInfo)
+	}
+	if err != nil {
+		return err
+	}
+
+	out, err := clientcmd.Write(clientcmdapi.Config{
+		Clusters: map[string]*clientcmdapi.Cluster{
+			contextName: {
+				Server:                   server,
+				CertificateAuthorityData: cluster.CertificateAuthorityData,
+			},
+		},
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{
+			contextName: authInfo,
+		},
+		Contexts: map[string]*clientcmdapi.Context{
+			contextName: {
+				Cluster:  contextName,
+				AuthInfo: contextName,
+			},
+		},
+		CurrentContext: contextName,
+	})
+	if err != nil {
+		return err
+	}
+
+	kubeConfigSecret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretConfig.Name,
+			Namespace: secretNamespace,
+		},
+	}
+	result, err := controllerutil.CreateOrPatch(ctx, currentNamespaceClient, kubeConfigSecret, func() error {
+		kubeConfigSecret.Type = corev1.SecretTypeOpaque
+		kubeConfigSecret.Data = map[string][]byte{
+			kubeconfig.KubeconfigSecretKey: out,
+			kubeconfig.CADataSecretKey:     cluster.CertificateAuthorityData,
+		}
+		if len(authInfo.ClientCertificateData) > 0 {
+			kubeConfigSecret.Data[kubeconfig.CertificateSecretKey] = authInfo.ClientCertificateData
+			kubeConfigSecret.Data[kubeconfig.CertificateKeySecretKey] = authInfo.ClientKeyData
+		}
+		if kubeConfigSecret.Annotations == nil {
+			kubeConfigSecret.Annotations = map[string]string{}
+		}
+		kubeConfigSecret.Annotations[KubeConfigIssuedAtAnnotation] = issuedAt.UTC().Format(time.RFC3339)
+		kubeConfigSecret.Annotations[KubeConfigExpiresAtAnnotation] = expiresAt.UTC().Format(time.RFC3339)
+		kubeConfigSecret.Annotations[KubeConfigHashAnnotation] = hash
+		if kubeConfigSecret.Labels == nil {
+			kubeConfigSecret.Labels = map[string]string{}
+		}
+		kubeConfigSecret.Labels[KubeConfigExportLabel] = translate.VClusterName
+
+		// set owner reference
+		if options.Experimental.IsolatedControlPlane.KubeConfig == "" && translate.Owner != nil && translate.Owner.GetNamespace() == kubeConfigSecret.Namespace {
+			kubeConfigSecret.OwnerReferences = translate.GetOwnerReference(nil)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("apply kube config secret: %w", err)
+	} else if result != controllerutil.OperationResultNone {
+		klog.Infof("Applied kube config secret %s/%s, expires at %s", secretNamespace, secretConfig.Name, expiresAt.UTC().Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// needsRotation checks if the secret was generated from a different configuration or if two thirds of the lifetime
+// of its identity have passed
+func needsRotation(secret *corev1.Secret, hash string, now time.Time) bool {
+	if secret.Annotations[KubeConfigHashAnnotation] != hash {
+		return true
+	}
+
+	issuedAt, err := time.Parse(time.RFC3339, secret.Annotations[KubeConfigIssuedAtAnnotation])
+	if err != nil {
+		return true
+	}
+	expiresAt, err := time.Parse(time.RFC3339, secret.Annotations[KubeConfigExpiresAtAnnotation])
+	if err != nil {
+		return true
+	}
+
+	rotateAt := issuedAt.Add(expiresAt.Sub(issuedAt) * 2 / 3)
+	return !now.Before(rotateAt)
+}
+
+func hashAdditionalKubeConfig(secretConfig vclusterconfig.ExportKubeConfigAdditionalSecret, server string, caData []byte) (string, error) {
+	rawConfig, err := json.Marshal(secretConfig)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	hash.Write(rawConfig)
+	hash.Write([]byte(server))
+	hash.Write(caData)
+	return hex.EncodeToString(hash.Sum(nil))[0:16], nil
+}
+
+func issueClientCertificate(options *config.VirtualClusterConfig, certificate vclusterconfig.ExportKubeConfigCertificate, issuedAt time.Time, authInfo *clientcmdapi.AuthInfo) (time.Time, error) {
+	expiration := DefaultExportCertificateExpiration
+	if certificate.Expiration != "" {
+		var err error
+		expiration, err = time.ParseDuration(certificate.Expiration)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("parse certificate expiration: %w", err)
+		}
+	}
+
+	// load the client ca
+	clientCACert := options.VirtualClusterKubeConfig().ClientCACert
+	caCert, caKey, err := certs.TryLoadCertAndKeyFromDisk(filepath.Dir(clientCACert), strings.TrimSuffix(filepath.Base(clientCACert), ".crt"))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("load client ca: %w", err)
+	}
+
+	// the certificate cannot outlive the ca
+	notAfter := issuedAt.Add(expiration).UTC()
+	if notAfter.After(caCert.NotAfter) {
+		notAfter = caCert.NotAfter
+	}
+
+	clientCert, clientKey, err := certs.NewCertAndKey(caCert, caKey, &certs.CertConfig{
+		Config: certutil.Config{
+			CommonName:   certificate.User,
+			Organization: certificate.Groups,
+			Usages:       []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		},
+		NotAfter: &notAfter,
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("create client certificate: %w", err)
+	}
+
+	encodedClientKey, err := keyutil.MarshalPrivateKeyToPEM(clientKey)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("marshal private key: %w", err)
+	}
+
+	authInfo.ClientCertificateData = certs.EncodeCertPEM(clientCert)
+	authInfo.ClientKeyData = encodedClientKey
+	return notAfter, nil
+}
+
+func issueServiceAccountToken(ctx context.Context, virtualClient client.Client, serviceAccountConfig vclusterconfig.ExportKubeConfigServiceAccount, authInfo *clientcmdapi.AuthInfo) (time.Time, error) {
+	expiration := DefaultExportTokenExpiration
+	if serviceAccountConfig.Expiration != "" {
+		var err error
+		expiration, err = time.ParseDuration(serviceAccountConfig.Expiration)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("parse service account expiration: %w", err)
+		}
+	}
+
+	namespace := serviceAccountConfig.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	// ensure the service account exists
+	serviceAccount := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      serviceAccountConfig.Name,
+			Namespace: namespace,
+		},
+	}
+	_, err := controllerutil.CreateOrPatch(ctx, virtualClient, serviceAccount, func() error {
+		// only service accounts created by vCluster are deleted again
+		if serviceAccount.CreationTimestamp.IsZero() {
+			serviceAccount.Labels = map[string]string{KubeConfigExportLabel: "true"}
+		}
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("ensure service account %s/%s: %w", namespace, serviceAccountConfig.Name, err)
+	}
+
+	// ensure the role bindings exist
+	err = ensureServiceAccountBindings(ctx, virtualClient, serviceAccount, serviceAccountConfig)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// request a new token
+	tokenRequest := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			ExpirationSeconds: ptr.To(int64(expiration.Seconds())),
+		},
+	}
+	err = virtualClient.SubResource("token").Create(ctx, serviceAccount, tokenRequest)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("request token for service account %s/%s: %w", namespace, serviceAccountConfig.Name, err)
+	}
+
+	authInfo.Token = tokenRequest.Status.Token
+	return tokenRequest.Status.ExpirationTimestamp.Time, nil
+}
+
+func ensureServiceAccountBindings(ctx context.Context, virtualClient client.Client, serviceAccount *corev1.ServiceAccount, serviceAccountConfig vclusterconfig.ExportKubeConfigServiceAccount) error {
+	if serviceAccountConfig.ClusterRole == "" {
+		return nil
+	}
+
+	bindingName := exportBindingName(serviceAccount.Namespace, serviceAccount.Name)
+	subjects := []rbacv1.Subject{
+		{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      serviceAccount.Name,
+			Namespace: serviceAccount.Namespace,
+		},
+	}
+	roleRef := rbacv1.RoleRef{
+		APIGroup: rbacv1.GroupName,
+		Kind:     "ClusterRole",
+		Name:     serviceAccountConfig.ClusterRole,
+	}
+
+	// bind cluster wide
+	if len(serviceAccountConfig.BindingNamespaces) == 0 {
+		clusterRoleBinding := &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: bindingName}}
+		err := recreateOnRoleRefChange(ctx, virtualClient, clusterRoleBinding, roleRef, func() *rbacv1.RoleRef { return &clusterRoleBinding.RoleRef })
+		if err != nil {
+			return err
+		}
+
+		_, err = controllerutil.CreateOrPatch(ctx, virtualClient, clusterRoleBinding, func() error {
+			clusterRoleBinding.Labels = map[string]string{KubeConfigExportLabel: "true"}
+			clusterRoleBinding.Subjects = subjects
+			clusterRoleBinding.RoleRef = roleRef
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("ensure cluster role binding %s: %w", bindingName, err)
+		}
+
+		return nil
+	}
+
+	// bind within the given namespaces
+	for _, namespace := range serviceAccountConfig.BindingNamespaces {
+		roleBinding := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: bindingName, Namespace: namespace}}
+		err := recreateOnRoleRefChange(ctx, virtualClient, roleBinding, roleRef, func() *rbacv1.RoleRef { return &roleBinding.RoleRef })
+		if err != nil {
+			return err
+		}
+
+		_, err = controllerutil.CreateOrPatch(ctx, virtualClient, roleBinding, func() error {
+			roleBinding.Labels = map[string]string{KubeConfigExportLabel: "true"}
+			roleBinding.Subjects = subjects
+			roleBinding.RoleRef = roleRef
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("ensure role binding %s/%s: %w", namespace, bindingName, err)
+		}
+	}
+
+	return nil
+}
+
+// recreateOnRoleRefChange deletes the binding if its role ref has changed, because role refs are immutable
+func recreateOnRoleRefChange(ctx context.Context, virtualClient client.Client, binding client.Object, roleRef rbacv1.RoleRef, getRoleRef func() *rbacv1.RoleRef) error {
+	err := virtualClient.Get(ctx, client.ObjectKeyFromObject(binding), binding)
+	if kerrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	} else if *getRoleRef() == roleRef {
+		return nil
+	}
+
+	err = virtualClient.Delete(ctx, binding)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("delete binding %s with outdated role ref: %w", binding.GetName(), err)
+	}
+
+	binding.SetResourceVersion("")
+	binding.SetUID("")
+	*getRoleRef() = rbacv1.RoleRef{}
+	return nil
+}
+
+func exportBindingName(serviceAccountNamespace, serviceAccountName string) string {
+	return exportBindingPrefix + serviceAccountNamespace + "-" + serviceAccountName
+}
+
+// cleanupKubeConfigExports deletes the labeled secrets, service accounts and bindings that don't belong to a
+// configured export anymore, so access that was removed from the config is revoked. Secrets are looked up in the
+// current namespace and the namespaces of configured exports.
+func cleanupKubeConfigExports(ctx context.Context, currentNamespace string, currentNamespaceClient, virtualClient client.Client, additionalSecrets []vclusterconfig.ExportKubeConfigAdditionalSecret) error {
+	secretNamespaces := sets.New(currentNamespace)
+	desiredSecrets := sets.New[string]()
+	desiredServiceAccounts := sets.New[string]()
+	desiredClusterRoleBindings := sets.New[string]()
+	desiredRoleBindings := sets.New[string]()
+	for _, secretConfig := range additionalSecrets {
+		secretNamespace := secretConfig.Namespace
+		if secretNamespace == "" {
+			secretNamespace = currentNamespace
+		}
+		secretNamespaces.Insert(secretNamespace)
+		desiredSecrets.Insert(client.ObjectKey{Namespace: secretNamespace, Name: secretConfig.Name}.String())
+		if secretConfig.Certificate.User != "" {
+			continue
+		}
+
+		serviceAccountNamespace := secretConfig.ServiceAccount.Namespace
+		if serviceAccountNamespace == "" {
+			serviceAccountNamespace = "default"
+		}
+		desiredServiceAccounts.Insert(client.ObjectKey{Namespace: serviceAccountNamespace, Name: secretConfig.ServiceAccount.Name}.String())
+		if secretConfig.ServiceAccount.ClusterRole == "" {
+			continue
+		}
+
+		bindingName := exportBindingName(serviceAccountNamespace, secretConfig.ServiceAccount.Name)
+		if len(secretConfig.ServiceAccount.BindingNamespaces) == 0 {
+			desiredClusterRoleBindings.Insert(client.ObjectKey{Name: bindingName}.String())
+		}
+		for _, namespace := range secretConfig.ServiceAccount.BindingNamespaces {
+			desiredRoleBindings.Insert(client.ObjectKey{Namespace: namespace, Name: bindingName}.String())
+		}
+	}
+
+	for _, namespace := range sets.List(secretNamespaces) {
+		err := deleteUndesiredExports(ctx, currentNamespaceClient, &corev1.SecretList{}, desiredSecrets, client.InNamespace(namespace), client.MatchingLabels{KubeConfigExportLabel: translate.VClusterName})
+		if err != nil {
+			return err
+		}
+	}
+	err := deleteUndesiredExports(ctx, virtualClient, &rbacv1.ClusterRoleBindingList{}, desiredClusterRoleBindings, client.MatchingLabels{KubeConfigExportLabel: "true"})
+	if err != nil {
+		return err
+	}
+	err = deleteUndesiredExports(ctx, virtualClient, &rbacv1.RoleBindingList{}, desiredRoleBindings, client.MatchingLabels{KubeConfigExportLabel: "true"})
+	if err != nil {
+		return err
+	}
+
+	return deleteUndesiredExports(ctx, virtualClient, &corev1.ServiceAccountList{}, desiredServiceAccounts, client.MatchingLabels{KubeConfigExportLabel: "true"})
+}
+
+func deleteUndesiredExports(ctx context.Context, c client.Client, list client.ObjectList, desired sets.Set[string], opts ...client.ListOption) error {
+	err := c.List(ctx, list, opts...)
+	if err != nil {
+		return fmt.Errorf("list %T: %w", list, err)
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		obj, ok := item.(client.Object)
+		if !ok || desired.Has(client.ObjectKeyFromObject(obj).String()) {
+			continue
+		}
+
+		klog.Infof("Delete %T %s of removed kube config export", obj, client.ObjectKeyFromObject(obj).String())
+		err = c.Delete(ctx, obj)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return fmt.Errorf("delete %s: %w", client.ObjectKeyFromObject(obj).String(), err)
+		}
+	}
+
+	return nil
+}
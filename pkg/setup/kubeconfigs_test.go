@@ -0,0 +1,77 @@
+package setup
+
+import (
+	"context"
+	"testing"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCleanupKubeConfigExports(t *testing.T) {
+	exportLabels := map[string]string{KubeConfigExportLabel: "true"}
+	secretLabels := map[string]string{KubeConfigExportLabel: translate.VClusterName}
+	hostObjects := []client.Object{
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "kept", Namespace: "vcluster", Labels: secretLabels}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "removed", Namespace: "vcluster", Labels: secretLabels}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "unlabeled", Namespace: "vcluster"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other-vcluster", Namespace: "vcluster", Labels: map[string]string{KubeConfigExportLabel: "other"}}},
+	}
+	virtualObjects := []client.Object{
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "ci", Namespace: "default", Labels: exportLabels}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "removed", Namespace: "default", Labels: exportLabels}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "default"}},
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: exportBindingName("default", "ci"), Labels: exportLabels}},
+		&rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: exportBindingName("default", "ci"), Namespace: "team-a", Labels: exportLabels}},
+		&rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: exportBindingName("default", "ci"), Namespace: "team-b", Labels: exportLabels}},
+	}
+	hostClient := fake.NewClientBuilder().WithScheme(testingutil.NewScheme()).WithObjects(hostObjects...).Build()
+	virtualClient := fake.NewClientBuilder().WithScheme(testingutil.NewScheme()).WithObjects(virtualObjects...).Build()
+
+	// the ci export was narrowed from a cluster wide binding to the namespace team-a
+	err := cleanupKubeConfigExports(context.TODO(), "vcluster", hostClient, virtualClient, []vclusterconfig.ExportKubeConfigAdditionalSecret{
+		{
+			Name: "kept",
+			ServiceAccount: vclusterconfig.ExportKubeConfigServiceAccount{
+				Name:              "ci",
+				ClusterRole:       "view",
+				BindingNamespaces: []string{"team-a"},
+			},
+		},
+	})
+	assert.NilError(t, err)
+
+	expectExists := func(c client.Client, obj client.Object, exists bool) {
+		err := c.Get(context.TODO(), client.ObjectKeyFromObject(obj), obj)
+		if exists {
+			assert.NilError(t, err, "expected %s to exist", client.ObjectKeyFromObject(obj))
+		} else {
+			assert.Assert(t, kerrors.IsNotFound(err), "expected %s to be deleted", client.ObjectKeyFromObject(obj))
+		}
+	}
+	expectExists(hostClient, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "kept", Namespace: "vcluster"}}, true)
+	expectExists(hostClient, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "removed", Namespace: "vcluster"}}, false)
+	expectExists(hostClient, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "unlabeled", Namespace: "vcluster"}}, true)
+	expectExists(hostClient, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other-vcluster", Namespace: "vcluster"}}, true)
+	expectExists(virtualClient, &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "ci", Namespace: "default"}}, true)
+	expectExists(virtualClient, &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "removed", Namespace: "default"}}, false)
+	expectExists(virtualClient, &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "default"}}, true)
+	expectExists(virtualClient, &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: exportBindingName("default", "ci")}}, false)
+	expectExists(virtualClient, &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: exportBindingName("default", "ci"), Namespace: "team-a"}}, true)
+	expectExists(virtualClient, &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: exportBindingName("default", "ci"), Namespace: "team-b"}}, false)
+
+	// removing all exports removes everything that was created for them
+	err = cleanupKubeConfigExports(context.TODO(), "vcluster", hostClient, virtualClient, nil)
+	assert.NilError(t, err)
+	expectExists(hostClient, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "kept", Namespace: "vcluster"}}, false)
+	expectExists(virtualClient, &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "ci", Namespace: "default"}}, false)
+	expectExists(virtualClient, &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: exportBindingName("default", "ci"), Namespace: "team-a"}}, false)
+}
@@ -0,0 +1,356 @@
+package sharding
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	ModeNamespace = "namespace"
+	ModeResource  = "resource"
+
+	// LabelVCluster is the label on the shard and member leases that holds the vCluster name
+	LabelVCluster = "vcluster.loft.sh/sharding"
+	// LabelRole is the label on the leases that defines if the lease is a shard or a member lease
+	LabelRole = "vcluster.loft.sh/sharding-role"
+
+	roleMember = "member"
+	roleShard  = "shard"
+)
+
+// Options configure the sharding manager
+type Options struct {
+	// Identity is the unique identity of this replica
+	Identity string
+
+	// Namespace is the namespace the leases are created in
+	Namespace string
+
+	// Mode is either ModeNamespace or ModeResource
+	Mode string
+
+	// Shards is the number of shards
+	Shards int
+
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+}
+
+// Manager distributes shards across all live replicas with leases. Every replica keeps a member lease alive and
+// computes the desired owner of each shard by rendezvous hashing over the live members, so only the shards of a
+// replica that joins or leaves are moved. A shard is only processed while the replica holds its lease.
+type Manager struct {
+	client  kubernetes.Interface
+	options Options
+
+	lock      sync.RWMutex
+	owned     map[int]time.Time
+	listeners []func(shard int)
+
+	now func() time.Time
+}
+
+// NewManager creates a new sharding manager
+func NewManager(client kubernetes.Interface, options Options) *Manager {
+	return &Manager{
+		client:  client,
+		options: options,
+		owned:   map[int]time.Time{},
+		now:     time.Now,
+	}
+}
+
+// Start renews the member lease and acquires or releases shards until the context is done
+func (m *Manager) Start(ctx context.Context) {
+	klog.Infof("Start sharding with %d shards by %s as %s", m.options.Shards, m.options.Mode, m.options.Identity)
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		err := m.reconcile(ctx)
+		if err != nil {
+			klog.Errorf("Error reconciling shards: %v", err)
+		}
+	}, m.options.RetryPeriod)
+
+	// give up all shards on shutdown so that other replicas can take them over right away
+	releaseCtx, cancel := context.WithTimeout(context.Background(), m.options.RetryPeriod)
+	defer cancel()
+	for shard := range m.ownedShards() {
+		m.release(releaseCtx, shard)
+	}
+	err := m.client.CoordinationV1().Leases(m.options.Namespace).Delete(releaseCtx, m.memberLeaseName(), metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		klog.Errorf("Error deleting member lease: %v", err)
+	}
+}
+
+// OnAcquire registers a function that is called every time this replica acquires a shard
+func (m *Manager) OnAcquire(listener func(shard int)) {
+	if m == nil {
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.listeners = append(m.listeners, listener)
+}
+
+// Owns returns true if this replica should sync the object of the given syncer in the given virtual namespace.
+// A nil manager owns every object.
+func (m *Manager) Owns(syncerName, namespace string) bool {
+	if m == nil {
+		return true
+	}
+
+	return m.OwnsShard(m.ShardFor(syncerName, namespace))
+}
+
+// OwnsShard returns true if this replica currently holds the lease of the shard
+func (m *Manager) OwnsShard(shard int) bool {
+	if m == nil {
+		return true
+	}
+
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	// stop processing before the lease could expire if it wasn't renewed
+	renewTime, ok := m.owned[shard]
+	return ok && m.now().Sub(renewTime) < m.options.RenewDeadline
+}
+
+// ShardFor returns the shard of the object of the given syncer in the given virtual namespace
+func (m *Manager) ShardFor(syncerName, namespace string) int {
+	key := namespace
+	if m.options.Mode == ModeResource {
+		key = syncerName
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(m.options.Shards))
+}
+
+func (m *Manager) reconcile(ctx context.Context) error {
+	err := m.renewMember(ctx)
+	if err != nil {
+		return fmt.Errorf("renew member lease: %w", err)
+	}
+
+	members, err := m.liveMembers(ctx)
+	if err != nil {
+		return fmt.Errorf("list members: %w", err)
+	}
+
+	for shard := 0; shard < m.options.Shards; shard++ {
+		if desiredOwner(members, shard) != m.options.Identity {
+			m.release(ctx, shard)
+			continue
+		}
+
+		err := m.acquireOrRenew(ctx, shard)
+		if err != nil {
+			klog.Errorf("Error acquiring shard %d: %v", shard, err)
+		}
+	}
+
+	return nil
+}
+
+func (m *Manager) renewMember(ctx context.Context) error {
+	lease, err := m.client.CoordinationV1().Leases(m.options.Namespace).Get(ctx, m.memberLeaseName(), metav1.GetOptions{})
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return err
+		}
+
+		_, err = m.client.CoordinationV1().Leases(m.options.Namespace).Create(ctx, m.newLease(m.memberLeaseName(), roleMember), metav1.CreateOptions{})
+		return err
+	}
+
+	lease.Spec.HolderIdentity = ptr.To(m.options.Identity)
+	lease.Spec.LeaseDurationSeconds = ptr.To(int32(m.options.LeaseDuration.Seconds()))
+	lease.Spec.RenewTime = &metav1.MicroTime{Time: m.now()}
+	_, err = m.client.CoordinationV1().Leases(m.options.Namespace).Update(ctx, lease, metav1.UpdateOptions{})
+	return err
+}
+
+func (m *Manager) liveMembers(ctx context.Context) ([]string, error) {
+	leases, err := m.client.CoordinationV1().Leases(m.options.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: LabelVCluster + "=" + translate.VClusterName + "," + LabelRole + "=" + roleMember,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	members := []string{m.options.Identity}
+	for _, lease := range leases.Items {
+		if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity == m.options.Identity || m.isExpired(&lease) {
+			continue
+		}
+
+		members = append(members, *lease.Spec.HolderIdentity)
+	}
+
+	sort.Strings(members)
+	return members, nil
+}
+
+func (m *Manager) acquireOrRenew(ctx context.Context, shard int) error {
+	leaseName := m.shardLeaseName(shard)
+	lease, err := m.client.CoordinationV1().Leases(m.options.Namespace).Get(ctx, leaseName, metav1.GetOptions{})
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return err
+		}
+
+		_, err = m.client.CoordinationV1().Leases(m.options.Namespace).Create(ctx, m.newLease(leaseName, roleShard), metav1.CreateOptions{})
+		if err != nil {
+			return err
+		}
+
+		m.setOwned(shard)
+		return nil
+	}
+
+	holder := ptr.Deref(lease.Spec.HolderIdentity, "")
+	if holder != "" && holder != m.options.Identity && !m.isExpired(lease) {
+		// wait until the previous owner released the shard or its lease expired
+		return nil
+	}
+
+	now := m.now()
+	if holder != m.options.Identity {
+		lease.Spec.AcquireTime = &metav1.MicroTime{Time: now}
+		lease.Spec.LeaseTransitions = ptr.To(ptr.Deref(lease.Spec.LeaseTransitions, 0) + 1)
+	}
+	lease.Spec.HolderIdentity = ptr.To(m.options.Identity)
+	lease.Spec.LeaseDurationSeconds = ptr.To(int32(m.options.LeaseDuration.Seconds()))
+	lease.Spec.RenewTime = &metav1.MicroTime{Time: now}
+	_, err = m.client.CoordinationV1().Leases(m.options.Namespace).Update(ctx, lease, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+
+	m.setOwned(shard)
+	return nil
+}
+
+func (m *Manager) release(ctx context.Context, shard int) {
+	// stop processing the shard first
+	m.lock.Lock()
+	_, wasOwned := m.owned[shard]
+	delete(m.owned, shard)
+	m.lock.Unlock()
+	if !wasOwned {
+		return
+	}
+
+	klog.Infof("Release shard %d", shard)
+	lease, err := m.client.CoordinationV1().Leases(m.options.Namespace).Get(ctx, m.shardLeaseName(shard), metav1.GetOptions{})
+	if err != nil || ptr.Deref(lease.Spec.HolderIdentity, "") != m.options.Identity {
+		return
+	}
+
+	lease.Spec.HolderIdentity = nil
+	_, err = m.client.CoordinationV1().Leases(m.options.Namespace).Update(ctx, lease, metav1.UpdateOptions{})
+	if err != nil {
+		klog.Errorf("Error releasing shard %d: %v", shard, err)
+	}
+}
+
+func (m *Manager) setOwned(shard int) {
+	m.lock.Lock()
+	_, wasOwned := m.owned[shard]
+	m.owned[shard] = m.now()
+	listeners := m.listeners
+	m.lock.Unlock()
+	if wasOwned {
+		return
+	}
+
+	klog.Infof("Acquired shard %d", shard)
+	for _, listener := range listeners {
+		listener(shard)
+	}
+}
+
+func (m *Manager) ownedShards() map[int]time.Time {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	owned := make(map[int]time.Time, len(m.owned))
+	for shard, renewTime := range m.owned {
+		owned[shard] = renewTime
+	}
+
+	return owned
+}
+
+func (m *Manager) isExpired(lease *coordinationv1.Lease) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+
+	return lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second).Before(m.now())
+}
+
+func (m *Manager) newLease(name, role string) *coordinationv1.Lease {
+	now := m.now()
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: m.options.Namespace,
+			Labels: map[string]string{
+				LabelVCluster: translate.VClusterName,
+				LabelRole:     role,
+			},
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       ptr.To(m.options.Identity),
+			LeaseDurationSeconds: ptr.To(int32(m.options.LeaseDuration.Seconds())),
+			AcquireTime:          &metav1.MicroTime{Time: now},
+			RenewTime:            &metav1.MicroTime{Time: now},
+		},
+	}
+}
+
+func (m *Manager) memberLeaseName() string {
+	return translate.SafeConcatName("vcluster", translate.VClusterName, "member", m.options.Identity)
+}
+
+func (m *Manager) shardLeaseName(shard int) string {
+	return translate.SafeConcatName("vcluster", translate.VClusterName, "shard", strconv.Itoa(shard))
+}
+
+// desiredOwner returns the member with the highest hash for the shard, so that a change of members only moves the
+// shards of the member that joined or left
+func desiredOwner(members []string, shard int) string {
+	owner := ""
+	var ownerHash uint64
+	for _, member := range members {
+		sum := sha256.Sum256([]byte(member + "/" + strconv.Itoa(shard)))
+		if hash := binary.BigEndian.Uint64(sum[:8]); owner == "" || hash > ownerHash {
+			owner = member
+			ownerHash = hash
+		}
+	}
+
+	return owner
+}
@@ -0,0 +1,97 @@
+package sharding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestManager(client *fake.Clientset, identity string, now *time.Time) *Manager {
+	m := NewManager(client, Options{
+		Identity:      identity,
+		Namespace:     "test",
+		Mode:          ModeNamespace,
+		Shards:        8,
+		LeaseDuration: 60 * time.Second,
+		RenewDeadline: 40 * time.Second,
+		RetryPeriod:   15 * time.Second,
+	})
+	m.now = func() time.Time { return *now }
+	return m
+}
+
+func ownedShards(managers ...*Manager) map[int][]string {
+	owners := map[int][]string{}
+	for _, m := range managers {
+		for shard := 0; shard < m.options.Shards; shard++ {
+			if m.OwnsShard(shard) {
+				owners[shard] = append(owners[shard], m.options.Identity)
+			}
+		}
+	}
+
+	return owners
+}
+
+func TestShardRebalancing(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	now := time.Now()
+
+	// a single replica owns all shards
+	a := newTestManager(client, "replica-a", &now)
+	acquired := []int{}
+	a.OnAcquire(func(shard int) {
+		acquired = append(acquired, shard)
+	})
+	assert.NilError(t, a.reconcile(ctx))
+	owners := ownedShards(a)
+	assert.Equal(t, len(owners), 8)
+	assert.Equal(t, len(acquired), 8)
+
+	// a second replica joins, replica-a releases the shards of replica-b first
+	b := newTestManager(client, "replica-b", &now)
+	assert.NilError(t, b.reconcile(ctx))
+	assert.Equal(t, len(ownedShards(b)), 0)
+	assert.NilError(t, a.reconcile(ctx))
+	assert.NilError(t, b.reconcile(ctx))
+
+	owners = ownedShards(a, b)
+	assert.Equal(t, len(owners), 8)
+	ownedByB := 0
+	for shard, shardOwners := range owners {
+		assert.Equal(t, len(shardOwners), 1, "shard %d is owned by %v", shard, shardOwners)
+		assert.Equal(t, shardOwners[0], desiredOwner([]string{"replica-a", "replica-b"}, shard))
+		if shardOwners[0] == "replica-b" {
+			ownedByB++
+		}
+	}
+	assert.Assert(t, ownedByB > 0)
+
+	// replica-b stops renewing, so its shards are taken over after the leases expired
+	now = now.Add(30 * time.Second)
+	assert.NilError(t, a.reconcile(ctx))
+	assert.Equal(t, len(ownedShards(a)), 8-ownedByB)
+	assert.Equal(t, len(ownedShards(b)), ownedByB)
+
+	now = now.Add(45 * time.Second)
+	assert.NilError(t, a.reconcile(ctx))
+	assert.Equal(t, len(ownedShards(a)), 8)
+	assert.Equal(t, len(ownedShards(b)), 0)
+}
+
+func TestShardFor(t *testing.T) {
+	now := time.Now()
+	m := newTestManager(fake.NewSimpleClientset(), "replica-a", &now)
+	assert.Equal(t, m.ShardFor("pods", "default"), m.ShardFor("services", "default"))
+
+	m.options.Mode = ModeResource
+	assert.Equal(t, m.ShardFor("pods", "default"), m.ShardFor("pods", "kube-system"))
+
+	// without a manager every object is owned
+	var nilManager *Manager
+	assert.Assert(t, nilManager.Owns("pods", "default"))
+}
@@ -56,17 +56,18 @@ func SyncKubernetesService(
 	}
 
 	// detect if cluster ips changed
-	clusterIPsChanged := vObj.Spec.ClusterIP != pObj.Spec.ClusterIP || !slices.Equal(vObj.Spec.ClusterIPs, pObj.Spec.ClusterIPs)
+	clusterIPsChanged := vObj.Spec.ClusterIP != pObj.Spec.ClusterIP || (!slices.Equal(vObj.Spec.ClusterIPs, pObj.Spec.ClusterIPs) && !isSingleStackFallback(vObj, pObj))
 
 	translatedPorts := svcPortTranslator(pObj.Spec.Ports)
 	if clusterIPsChanged || !equality.Semantic.DeepEqual(vObj.Spec.Ports, translatedPorts) {
 		newService := vObj.DeepCopy()
-		newService.Spec.ClusterIP = pObj.Spec.ClusterIP
-		newService.Spec.ClusterIPs = pObj.Spec.ClusterIPs
-		newService.Spec.IPFamilies = pObj.Spec.IPFamilies
-		newService.Spec.IPFamilyPolicy = pObj.Spec.IPFamilyPolicy
 		newService.Spec.Ports = translatedPorts
 		if clusterIPsChanged {
+			newService.Spec.ClusterIP = pObj.Spec.ClusterIP
+			newService.Spec.ClusterIPs = pObj.Spec.ClusterIPs
+			newService.Spec.IPFamilies = pObj.Spec.IPFamilies
+			newService.Spec.IPFamilyPolicy = pObj.Spec.IPFamilyPolicy
+
 			// delete & create with correct ClusterIP
 			err = ctx.VirtualClient.Delete(ctx.Context, vObj)
 			if err != nil {
@@ -78,6 +79,15 @@ func SyncKubernetesService(
 
 			// create the new service with the correct cluster ip
 			err = ctx.VirtualClient.Create(ctx.Context, newService)
+			if kerrors.IsInvalid(err) && len(newService.Spec.ClusterIPs) > 1 {
+				// the virtual cluster might not be dual-stack, so fallback to the primary ip family
+				ctx.Log.Infof("error creating dual-stack kubernetes service, falling back to single-stack: %v", err)
+				singleStack := corev1.IPFamilyPolicySingleStack
+				newService.Spec.ClusterIPs = newService.Spec.ClusterIPs[:1]
+				newService.Spec.IPFamilies = newService.Spec.IPFamilies[:1]
+				newService.Spec.IPFamilyPolicy = &singleStack
+				err = ctx.VirtualClient.Create(ctx.Context, newService)
+			}
 			if err != nil {
 				return err
 			}
@@ -92,3 +102,10 @@ func SyncKubernetesService(
 
 	return nil
 }
+
+// isSingleStackFallback returns true if the virtual service only got the primary cluster ip of the dual-stack host
+// service, because the virtual cluster itself is not dual-stack.
+func isSingleStackFallback(vObj, pObj *corev1.Service) bool {
+	return len(vObj.Spec.ClusterIPs) == 1 && len(pObj.Spec.ClusterIPs) > 1 && vObj.Spec.ClusterIPs[0] == pObj.Spec.ClusterIPs[0] &&
+		vObj.Spec.IPFamilyPolicy != nil && *vObj.Spec.IPFamilyPolicy == corev1.IPFamilyPolicySingleStack
+}
@@ -0,0 +1,126 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+const (
+	ClusterHost    = "host"
+	ClusterVirtual = "virtual"
+)
+
+// WrapClient returns a client that records a span for every call against the given cluster
+func WrapClient(delegate client.Client, cluster string) client.Client {
+	return &Client{
+		Client:  delegate,
+		cluster: cluster,
+		scheme:  delegate.Scheme(),
+	}
+}
+
+// Client records a span for every Get/List/Create/Update/Patch/Delete call
+type Client struct {
+	client.Client
+
+	cluster string
+	scheme  *runtime.Scheme
+}
+
+func (c *Client) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) (err error) {
+	ctx, span := c.startSpan(ctx, "Get", obj, key)
+	defer func() { EndSpan(span, err) }()
+
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c *Client) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) (err error) {
+	ctx, span := c.startSpan(ctx, "List", list, client.ObjectKey{})
+	defer func() { EndSpan(span, err) }()
+
+	return c.Client.List(ctx, list, opts...)
+}
+
+func (c *Client) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) (err error) {
+	ctx, span := c.startSpan(ctx, "Create", obj, client.ObjectKeyFromObject(obj))
+	defer func() { EndSpan(span, err) }()
+
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *Client) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) (err error) {
+	ctx, span := c.startSpan(ctx, "Update", obj, client.ObjectKeyFromObject(obj))
+	defer func() { EndSpan(span, err) }()
+
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *Client) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) (err error) {
+	ctx, span := c.startSpan(ctx, "Patch", obj, client.ObjectKeyFromObject(obj))
+	defer func() { EndSpan(span, err) }()
+
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *Client) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) (err error) {
+	ctx, span := c.startSpan(ctx, "Delete", obj, client.ObjectKeyFromObject(obj))
+	defer func() { EndSpan(span, err) }()
+
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *Client) Status() client.StatusWriter {
+	return &StatusClient{
+		client: c,
+	}
+}
+
+func (c *Client) startSpan(ctx context.Context, verb string, obj runtime.Object, key client.ObjectKey) (context.Context, trace.Span) {
+	kind := ""
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err == nil {
+		kind = gvk.Kind
+	}
+
+	return StartSpan(
+		ctx,
+		fmt.Sprintf("%s %s %s", c.cluster, verb, kind),
+		attribute.String("k8s.cluster", c.cluster),
+		attribute.String("k8s.verb", verb),
+		attribute.String("k8s.kind", kind),
+		attribute.String("k8s.namespace", key.Namespace),
+		attribute.String("k8s.name", key.Name),
+	)
+}
+
+// StatusClient records a span for every status Create/Update/Patch call
+type StatusClient struct {
+	client *Client
+}
+
+func (c *StatusClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) (err error) {
+	ctx, span := c.client.startSpan(ctx, "CreateStatus", obj, client.ObjectKeyFromObject(obj))
+	defer func() { EndSpan(span, err) }()
+
+	return c.client.Client.Status().Create(ctx, obj, subResource, opts...)
+}
+
+func (c *StatusClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) (err error) {
+	ctx, span := c.client.startSpan(ctx, "UpdateStatus", obj, client.ObjectKeyFromObject(obj))
+	defer func() { EndSpan(span, err) }()
+
+	return c.client.Client.Status().Update(ctx, obj, opts...)
+}
+
+func (c *StatusClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) (err error) {
+	ctx, span := c.client.startSpan(ctx, "PatchStatus", obj, client.ObjectKeyFromObject(obj))
+	defer func() { EndSpan(span, err) }()
+
+	return c.client.Client.Status().Patch(ctx, obj, patch, opts...)
+}
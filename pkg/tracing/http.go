@@ -0,0 +1,33 @@
+package tracing
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// WithSpan wraps the given handler and records a span with the given name for every request that
+// passes through it. The span is a child of the span that is already part of the request context.
+func WithSpan(h http.Handler, name string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		attributes := []attribute.KeyValue{
+			attribute.String("http.method", req.Method),
+			attribute.String("http.target", req.URL.Path),
+		}
+		if info, ok := request.RequestInfoFrom(req.Context()); ok && info.IsResourceRequest {
+			attributes = append(attributes,
+				attribute.String("k8s.verb", info.Verb),
+				attribute.String("k8s.resource", info.Resource),
+				attribute.String("k8s.subresource", info.Subresource),
+				attribute.String("k8s.namespace", info.Namespace),
+				attribute.String("k8s.name", info.Name),
+			)
+		}
+
+		ctx, span := StartSpan(req.Context(), name, attributes...)
+		defer span.End()
+
+		h.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
@@ -0,0 +1,121 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/loft-sh/vcluster/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	componenttracing "k8s.io/component-base/tracing"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// InstrumentationName is the name of the tracer used throughout vCluster
+	InstrumentationName = "github.com/loft-sh/vcluster"
+
+	// ServiceName is the service name reported to the tracing backend
+	ServiceName = "vcluster"
+)
+
+// tracerProvider is the configured sdk tracer provider, nil if tracing is disabled
+var tracerProvider *sdktrace.TracerProvider
+
+// Start configures the global OpenTelemetry tracer provider and propagator with an OTLP exporter. If tracing
+// is disabled, the global noop tracer provider is kept and the returned shutdown function does nothing.
+func Start(ctx context.Context, vClusterName string, tracingConfig config.ObservabilityTracing) (func(context.Context) error, error) {
+	if !tracingConfig.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	// build exporter options
+	options := []otlptracegrpc.Option{}
+	if tracingConfig.Endpoint != "" {
+		options = append(options, otlptracegrpc.WithEndpoint(tracingConfig.Endpoint))
+	}
+	if tracingConfig.Insecure {
+		options = append(options, otlptracegrpc.WithInsecure())
+	}
+	if len(tracingConfig.Headers) > 0 {
+		options = append(options, otlptracegrpc.WithHeaders(tracingConfig.Headers))
+	}
+
+	// create the exporter, this does not block until the collector is reachable
+	exporter, err := otlptracegrpc.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("create otlp trace exporter: %w", err)
+	}
+
+	// create the resource that describes this vCluster
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(ServiceName),
+		attribute.String("vcluster.name", vClusterName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("create trace resource: %w", err)
+	}
+
+	sampler, samplingRatio := newSampler(tracingConfig.SamplingRatio)
+	tracerProvider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sampler),
+	)
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		klog.V(1).Infof("opentelemetry error: %v", err)
+	}))
+
+	klog.Infof("Exporting OpenTelemetry traces with sampling ratio %v", samplingRatio)
+	return tracerProvider.Shutdown, nil
+}
+
+// newSampler returns the sampler for the configured sampling ratio and the ratio it samples with. Everything is sampled
+// if no ratio is configured, spans with a sampled parent are always sampled.
+func newSampler(samplingRatio *float64) (sdktrace.Sampler, float64) {
+	ratio := 1.0
+	if samplingRatio != nil {
+		ratio = *samplingRatio
+	}
+
+	return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio)), ratio
+}
+
+// Tracer returns the vCluster tracer from the global tracer provider
+func Tracer() trace.Tracer {
+	return otel.Tracer(InstrumentationName)
+}
+
+// TracerProvider returns the configured tracer provider in a form that can be used by the Kubernetes api server filters
+func TracerProvider() componenttracing.TracerProvider {
+	if tracerProvider == nil {
+		return componenttracing.NewNoopTracerProvider()
+	}
+
+	return tracerProvider
+}
+
+// StartSpan starts a new span with the given name and attributes as a child of the span within ctx
+func StartSpan(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// EndSpan records the given error on the span if there is any and ends the span
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,131 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"gotest.tools/v3/assert"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/utils/ptr"
+)
+
+func TestNewSampler(t *testing.T) {
+	parent := trace.ContextWithSpanContext(context.TODO(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	testCases := []struct {
+		name                   string
+		samplingRatio          *float64
+		expectedRatio          float64
+		expectedRootDecision   sdktrace.SamplingDecision
+		expectedParentDecision sdktrace.SamplingDecision
+	}{
+		{
+			name:                   "unset",
+			expectedRatio:          1,
+			expectedRootDecision:   sdktrace.RecordAndSample,
+			expectedParentDecision: sdktrace.RecordAndSample,
+		},
+		{
+			name:                   "ratio 1",
+			samplingRatio:          ptr.To(1.0),
+			expectedRatio:          1,
+			expectedRootDecision:   sdktrace.RecordAndSample,
+			expectedParentDecision: sdktrace.RecordAndSample,
+		},
+		{
+			name:                   "ratio 0",
+			samplingRatio:          ptr.To(0.0),
+			expectedRatio:          0,
+			expectedRootDecision:   sdktrace.Drop,
+			expectedParentDecision: sdktrace.RecordAndSample,
+		},
+	}
+
+	for _, testCase := range testCases {
+		sampler, ratio := newSampler(testCase.samplingRatio)
+		assert.Equal(t, ratio, testCase.expectedRatio, testCase.name)
+
+		root := sampler.ShouldSample(sdktrace.SamplingParameters{ParentContext: context.TODO(), TraceID: trace.TraceID{0xff, 0xff}})
+		assert.Equal(t, root.Decision, testCase.expectedRootDecision, testCase.name)
+
+		child := sampler.ShouldSample(sdktrace.SamplingParameters{ParentContext: parent, TraceID: trace.TraceID{1}})
+		assert.Equal(t, child.Decision, testCase.expectedParentDecision, testCase.name)
+	}
+}
+
+func TestWithSpan(t *testing.T) {
+	recorder := &spanRecorder{}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	// the handler sees the span of the filter and its spans are children of it
+	var handlerSpan trace.SpanContext
+	handler := WithSpan(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		handlerSpan = trace.SpanContextFromContext(req.Context())
+		_, span := StartSpan(req.Context(), "handler")
+		span.End()
+	}), "filter")
+
+	ctx, parent := StartSpan(context.TODO(), "parent")
+	ctx = request.WithRequestInfo(ctx, &request.RequestInfo{IsResourceRequest: true, Verb: "get", Resource: "pods", Namespace: "default", Name: "test"})
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/namespaces/default/pods/test", nil).WithContext(ctx)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	parent.End()
+
+	spans := recorder.byName()
+	assert.Equal(t, len(spans), 3)
+	assert.Equal(t, spans["filter"].Parent().SpanID(), spans["parent"].SpanContext().SpanID())
+	assert.Equal(t, spans["filter"].SpanContext().TraceID(), spans["parent"].SpanContext().TraceID())
+	assert.Equal(t, spans["handler"].Parent().SpanID(), spans["filter"].SpanContext().SpanID())
+	assert.Equal(t, handlerSpan.SpanID(), spans["filter"].SpanContext().SpanID())
+
+	attributes := map[string]string{}
+	for _, attribute := range spans["filter"].Attributes() {
+		attributes[string(attribute.Key)] = attribute.Value.Emit()
+	}
+	assert.Equal(t, attributes["http.method"], http.MethodGet)
+	assert.Equal(t, attributes["k8s.verb"], "get")
+	assert.Equal(t, attributes["k8s.resource"], "pods")
+	assert.Equal(t, attributes["k8s.name"], "test")
+}
+
+// spanRecorder keeps every ended span in memory
+type spanRecorder struct {
+	m     sync.Mutex
+	spans []sdktrace.ReadOnlySpan
+}
+
+func (r *spanRecorder) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (r *spanRecorder) OnEnd(span sdktrace.ReadOnlySpan) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.spans = append(r.spans, span)
+}
+
+func (r *spanRecorder) Shutdown(context.Context) error { return nil }
+
+func (r *spanRecorder) ForceFlush(context.Context) error { return nil }
+
+func (r *spanRecorder) byName() map[string]sdktrace.ReadOnlySpan {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range r.spans {
+		spans[span.Name()] = span
+	}
+	return spans
+}
@@ -128,3 +128,26 @@ func NewImpersonatingClient(config *rest.Config, mapper meta.RESTMapper, user us
 	// Create client
 	return client.New(restConfig, client.Options{Scheme: scheme, Mapper: mapper})
 }
+
+// AuthorizeNonResource checks with a subject access review if the user is allowed to access the non resource url
+// with the given verb
+func AuthorizeNonResource(ctx context.Context, c client.Client, userInfo user.Info, path, verb string) (bool, string, error) {
+	accessReview := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   userInfo.GetName(),
+			UID:    userInfo.GetUID(),
+			Groups: userInfo.GetGroups(),
+			Extra:  ConvertExtra(userInfo.GetExtra()),
+			NonResourceAttributes: &authorizationv1.NonResourceAttributes{
+				Path: path,
+				Verb: verb,
+			},
+		},
+	}
+	err := c.Create(ctx, accessReview)
+	if err != nil {
+		return false, "", fmt.Errorf("create subject access review: %w", err)
+	}
+
+	return accessReview.Status.Allowed && !accessReview.Status.Denied, accessReview.Status.Reason, nil
+}
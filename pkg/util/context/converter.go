@@ -16,5 +16,7 @@ func ToRegisterContext(ctx *config.ControllerContext) *synccontext.RegisterConte
 
 		VirtualManager:  ctx.VirtualManager,
 		PhysicalManager: ctx.LocalManager,
+
+		Sharding: ctx.Sharding,
 	}
 }
@@ -0,0 +1,178 @@
+package nodedebug
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/vcluster/config"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// UserAnnotation is set by the vCluster proxy on approved node debug pods and holds the user that created the pod
+	UserAnnotation = "vcluster.loft.sh/node-debug-user"
+
+	// SignatureAnnotation is set by the vCluster proxy on approved node debug pods and holds a signature over the pod
+	// uid, name, node and user. Tenants can set annotations through controllers that bypass the proxy, so only the
+	// signature marks a pod as approved.
+	SignatureAnnotation = "vcluster.loft.sh/node-debug-signature"
+
+	// SigningWindow is the time the vCluster proxy has to sign an approved pod after it was created
+	SigningWindow = 10 * time.Second
+
+	// keySecretKey is the key in the signing key secret that holds the key
+	keySecretKey = "key"
+
+	// podNamePrefix is the prefix kubectl uses for the names of node debug pods
+	podNamePrefix = "node-debugger-"
+
+	managedByLabel = "app.kubernetes.io/managed-by"
+	managedByValue = "kubectl-debug"
+)
+
+// IsNodeDebugPod returns true if the pod was created by `kubectl debug node/NAME`
+func IsNodeDebugPod(pod *corev1.Pod) bool {
+	if pod == nil || pod.Spec.NodeName == "" {
+		return false
+	}
+
+	return pod.Labels[managedByLabel] == managedByValue || strings.HasPrefix(pod.Name, podNamePrefix)
+}
+
+// IsApproved returns true if the pod is a node debug pod that was signed by the vCluster proxy and only uses
+// approved images
+func IsApproved(nodeDebug config.SyncPodNodeDebug, key []byte, pod *corev1.Pod) bool {
+	if !nodeDebug.Enabled || len(key) == 0 || !IsNodeDebugPod(pod) || pod.UID == "" || pod.Annotations[UserAnnotation] == "" {
+		return false
+	}
+
+	signature, err := hex.DecodeString(pod.Annotations[SignatureAnnotation])
+	if err != nil || !hmac.Equal(signature, sign(key, pod)) {
+		return false
+	}
+
+	return len(UnapprovedImages(nodeDebug, pod)) == 0
+}
+
+// IsAwaitingSignature returns true if the pod was approved by the vCluster proxy, but wasn't signed yet. The proxy signs
+// pods right after they were created, so pods that are still unsigned after the signing window are never approved.
+func IsAwaitingSignature(pod *corev1.Pod) bool {
+	if !IsNodeDebugPod(pod) || pod.Annotations[UserAnnotation] == "" || pod.Annotations[SignatureAnnotation] != "" {
+		return false
+	}
+
+	return time.Since(pod.CreationTimestamp.Time) < SigningWindow
+}
+
+// Sign returns the value of the signature annotation for the given pod. The pod needs to have a uid, so it can only
+// be signed after it was created.
+func Sign(key []byte, pod *corev1.Pod) string {
+	return hex.EncodeToString(sign(key, pod))
+}
+
+func sign(key []byte, pod *corev1.Pod) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strings.Join([]string{string(pod.UID), pod.Namespace, pod.Name, pod.Spec.NodeName, pod.Annotations[UserAnnotation]}, "\n")))
+	return mac.Sum(nil)
+}
+
+// KeySecretName returns the name of the host secret that holds the signing key of the given vCluster
+func KeySecretName(vClusterName string) string {
+	return vClusterName + "-node-debug"
+}
+
+// EnsureSigningKey returns the key approvals are signed with. The key is stored in a secret in the vCluster namespace
+// of the host cluster, which tenants can't read, and is created on first use.
+func EnsureSigningKey(ctx context.Context, hostClient kubernetes.Interface, namespace, vClusterName string) ([]byte, error) {
+	secret, err := hostClient.CoreV1().Secrets(namespace).Get(ctx, KeySecretName(vClusterName), metav1.GetOptions{})
+	if err == nil {
+		if len(secret.Data[keySecretKey]) == 0 {
+			return nil, fmt.Errorf("secret %s/%s has no key %s", namespace, secret.Name, keySecretKey)
+		}
+
+		return secret.Data[keySecretKey], nil
+	} else if !kerrors.IsNotFound(err) {
+		return nil, fmt.Errorf("get node debug signing key: %w", err)
+	}
+
+	key := make([]byte, 32)
+	_, err = rand.Read(key)
+	if err != nil {
+		return nil, fmt.Errorf("generate node debug signing key: %w", err)
+	}
+
+	_, err = hostClient.CoreV1().Secrets(namespace).Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: KeySecretName(vClusterName), Namespace: namespace},
+		Data:       map[string][]byte{keySecretKey: key},
+	}, metav1.CreateOptions{})
+	if kerrors.IsAlreadyExists(err) {
+		// another replica was faster
+		return EnsureSigningKey(ctx, hostClient, namespace, vClusterName)
+	} else if err != nil {
+		return nil, fmt.Errorf("create node debug signing key: %w", err)
+	}
+
+	return key, nil
+}
+
+// Validate checks if the user is allowed to create the given node debug pod
+func Validate(nodeDebug config.SyncPodNodeDebug, userInfo user.Info, pod *corev1.Pod) error {
+	if !nodeDebug.Enabled {
+		return fmt.Errorf("node debugging is not enabled in this virtual cluster")
+	}
+
+	allowed := false
+	for _, group := range userInfo.GetGroups() {
+		for _, allowedGroup := range nodeDebug.Groups {
+			if group == allowedGroup {
+				allowed = true
+			}
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("user %s is not a member of any group that is allowed to debug nodes", userInfo.GetName())
+	}
+
+	unapproved := UnapprovedImages(nodeDebug, pod)
+	if len(unapproved) > 0 {
+		return fmt.Errorf("images %s are not approved for node debugging, allowed images are: %s", strings.Join(unapproved, ", "), strings.Join(nodeDebug.Images, ", "))
+	}
+
+	return nil
+}
+
+// UnapprovedImages returns all images of the pod that are not part of the approved debug images
+func UnapprovedImages(nodeDebug config.SyncPodNodeDebug, pod *corev1.Pod) []string {
+	unapproved := []string{}
+	check := func(image string) {
+		for _, approved := range nodeDebug.Images {
+			if image == approved || (strings.HasSuffix(approved, "*") && strings.HasPrefix(image, strings.TrimSuffix(approved, "*"))) {
+				return
+			}
+		}
+
+		unapproved = append(unapproved, image)
+	}
+
+	for _, container := range pod.Spec.InitContainers {
+		check(container.Image)
+	}
+	for _, container := range pod.Spec.Containers {
+		check(container.Image)
+	}
+	for _, container := range pod.Spec.EphemeralContainers {
+		check(container.Image)
+	}
+
+	return unapproved
+}
@@ -0,0 +1,175 @@
+package nodedebug
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/vcluster/config"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestValidate(t *testing.T) {
+	nodeDebug := config.SyncPodNodeDebug{
+		Enabled: true,
+		Groups:  []string{"node-admins"},
+		Images:  []string{"busybox:*", "registry.example.com/debug:1.0"},
+	}
+	newPod := func(images ...string) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-debugger-node1-abcde", Namespace: "default"},
+			Spec:       corev1.PodSpec{NodeName: "node1"},
+		}
+		for _, image := range images {
+			pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: "debugger", Image: image})
+		}
+		return pod
+	}
+	admin := &user.DefaultInfo{Name: "alice", Groups: []string{"system:authenticated", "node-admins"}}
+	developer := &user.DefaultInfo{Name: "bob", Groups: []string{"system:authenticated"}}
+
+	testCases := []struct {
+		name        string
+		nodeDebug   config.SyncPodNodeDebug
+		user        user.Info
+		pod         *corev1.Pod
+		expectedErr string
+	}{
+		{
+			name:      "allowed",
+			nodeDebug: nodeDebug,
+			user:      admin,
+			pod:       newPod("busybox:1.36"),
+		},
+		{
+			name:        "disabled",
+			user:        admin,
+			pod:         newPod("busybox:1.36"),
+			expectedErr: "node debugging is not enabled in this virtual cluster",
+		},
+		{
+			name:        "group not allowed",
+			nodeDebug:   nodeDebug,
+			user:        developer,
+			pod:         newPod("busybox:1.36"),
+			expectedErr: "user bob is not a member of any group that is allowed to debug nodes",
+		},
+		{
+			name:        "image not approved",
+			nodeDebug:   nodeDebug,
+			user:        admin,
+			pod:         newPod("registry.example.com/debug:1.0", "ubuntu"),
+			expectedErr: "images ubuntu are not approved for node debugging, allowed images are: busybox:*, registry.example.com/debug:1.0",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Assert(t, IsNodeDebugPod(testCase.pod))
+			err := Validate(testCase.nodeDebug, testCase.user, testCase.pod)
+			if testCase.expectedErr == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, testCase.expectedErr)
+			}
+		})
+	}
+}
+
+func TestIsApproved(t *testing.T) {
+	nodeDebug := config.SyncPodNodeDebug{
+		Enabled: true,
+		Groups:  []string{"node-admins"},
+		Images:  []string{"busybox:*"},
+	}
+	key := []byte("test-key")
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "debug",
+			Namespace:   "default",
+			UID:         "uid-1",
+			Labels:      map[string]string{managedByLabel: managedByValue},
+			Annotations: map[string]string{UserAnnotation: "alice"},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:   "node1",
+			Containers: []corev1.Container{{Name: "debugger", Image: "busybox:1.36"}},
+		},
+	}
+	pod.Annotations[SignatureAnnotation] = Sign(key, pod)
+	assert.Assert(t, IsApproved(nodeDebug, key, pod))
+
+	// images are checked again, so the signature alone doesn't allow arbitrary images
+	unapprovedImage := pod.DeepCopy()
+	unapprovedImage.Spec.Containers[0].Image = "ubuntu"
+	assert.Assert(t, !IsApproved(nodeDebug, key, unapprovedImage))
+
+	notAnnotated := pod.DeepCopy()
+	notAnnotated.Annotations = nil
+	assert.Assert(t, !IsApproved(nodeDebug, key, notAnnotated))
+
+	notScheduled := pod.DeepCopy()
+	notScheduled.Spec.NodeName = ""
+	assert.Assert(t, !IsApproved(nodeDebug, key, notScheduled))
+
+	// annotations copied to another pod, e.g. through a pod template, are not valid
+	copied := pod.DeepCopy()
+	copied.UID = "uid-2"
+	assert.Assert(t, !IsApproved(nodeDebug, key, copied))
+
+	otherNode := pod.DeepCopy()
+	otherNode.Spec.NodeName = "node2"
+	assert.Assert(t, !IsApproved(nodeDebug, key, otherNode))
+
+	otherUser := pod.DeepCopy()
+	otherUser.Annotations[UserAnnotation] = "bob"
+	assert.Assert(t, !IsApproved(nodeDebug, key, otherUser))
+
+	unsigned := pod.DeepCopy()
+	delete(unsigned.Annotations, SignatureAnnotation)
+	assert.Assert(t, !IsApproved(nodeDebug, key, unsigned))
+
+	assert.Assert(t, !IsApproved(nodeDebug, []byte("other-key"), pod))
+	assert.Assert(t, !IsApproved(nodeDebug, nil, pod))
+	assert.Assert(t, !IsApproved(config.SyncPodNodeDebug{}, key, pod))
+}
+
+func TestIsAwaitingSignature(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "node-debugger-node1-abcde",
+			Namespace:         "default",
+			CreationTimestamp: metav1.Now(),
+			Annotations:       map[string]string{UserAnnotation: "alice"},
+		},
+		Spec: corev1.PodSpec{NodeName: "node1"},
+	}
+	assert.Assert(t, IsAwaitingSignature(pod))
+
+	signed := pod.DeepCopy()
+	signed.Annotations[SignatureAnnotation] = "signature"
+	assert.Assert(t, !IsAwaitingSignature(signed))
+
+	notApproved := pod.DeepCopy()
+	notApproved.Annotations = nil
+	assert.Assert(t, !IsAwaitingSignature(notApproved))
+
+	expired := pod.DeepCopy()
+	expired.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * SigningWindow))
+	assert.Assert(t, !IsAwaitingSignature(expired))
+}
+
+func TestEnsureSigningKey(t *testing.T) {
+	hostClient := fake.NewSimpleClientset()
+	key, err := EnsureSigningKey(context.TODO(), hostClient, "vcluster", "my-vcluster")
+	assert.NilError(t, err)
+	assert.Equal(t, len(key), 32)
+
+	existing, err := EnsureSigningKey(context.TODO(), hostClient, "vcluster", "my-vcluster")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, existing, key)
+}
@@ -0,0 +1,535 @@
+package noderules
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/loft-sh/vcluster/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// PoolLabel is the label on virtual nodes that holds the name of the node pool
+const PoolLabel = "vcluster.loft.sh/node-pool"
+
+// Rules translate the labels and taints of host nodes to virtual nodes and the node selection of virtual pods back to
+// the host node labels. A nil Rules doesn't change anything.
+type Rules struct {
+	rewrite        map[string]string
+	reverseRewrite map[string]string
+	strip          []string
+	add            map[string]string
+
+	addTaints    []corev1.Taint
+	removeTaints []string
+
+	pools []config.SyncNodePool
+}
+
+// New creates the rules for the given node sync config. It returns nil if no rules are configured.
+func New(nodes config.SyncNodes) (*Rules, error) {
+	labelRules := nodes.Rules.Labels
+	taintRules := nodes.Rules.Taints
+	if len(labelRules.Rewrite) == 0 && len(labelRules.Strip) == 0 && len(labelRules.Add) == 0 && len(taintRules.Add) == 0 && len(taintRules.Remove) == 0 && len(nodes.Pools) == 0 {
+		return nil, nil
+	}
+
+	rules := &Rules{
+		rewrite:        labelRules.Rewrite,
+		reverseRewrite: map[string]string{},
+		strip:          labelRules.Strip,
+		add:            labelRules.Add,
+		removeTaints:   taintRules.Remove,
+		pools:          nodes.Pools,
+	}
+	for hostKey, virtualKey := range labelRules.Rewrite {
+		if _, ok := rules.reverseRewrite[virtualKey]; ok {
+			return nil, fmt.Errorf("sync.fromHost.nodes.rules.labels.rewrite: multiple host labels are rewritten to %s", virtualKey)
+		}
+
+		rules.reverseRewrite[virtualKey] = hostKey
+	}
+	for _, t := range taintRules.Add {
+		taint, err := ParseTaint(t)
+		if err != nil {
+			return nil, fmt.Errorf("sync.fromHost.nodes.rules.taints.add: %w", err)
+		}
+
+		rules.addTaints = append(rules.addTaints, taint)
+	}
+
+	poolNames := map[string]bool{}
+	for i, pool := range nodes.Pools {
+		if pool.Name == "" {
+			return nil, fmt.Errorf("sync.fromHost.nodes.pools: name is required")
+		} else if errs := validation.IsValidLabelValue(pool.Name); len(errs) > 0 {
+			return nil, fmt.Errorf("sync.fromHost.nodes.pools: invalid name %s: %s", pool.Name, strings.Join(errs, "; "))
+		} else if poolNames[pool.Name] {
+			return nil, fmt.Errorf("sync.fromHost.nodes.pools: duplicate pool %s", pool.Name)
+		} else if len(pool.Selector) == 0 {
+			return nil, fmt.Errorf("sync.fromHost.nodes.pools: selector of pool %s is empty", pool.Name)
+		}
+
+		for _, earlier := range nodes.Pools[:i] {
+			if exclusion := excludingTerms(earlier.Selector, pool.Selector); exclusion != nil && len(exclusion) == 0 {
+				return nil, fmt.Errorf("sync.fromHost.nodes.pools: pool %s never matches, as all of its nodes belong to pool %s", pool.Name, earlier.Name)
+			}
+		}
+
+		poolNames[pool.Name] = true
+	}
+
+	return rules, nil
+}
+
+// ParseTaint parses a taint in the form key[=value]:effect
+func ParseTaint(st string) (corev1.Taint, error) {
+	taint := corev1.Taint{}
+	keyValue, effect, found := strings.Cut(st, ":")
+	if !found {
+		return taint, fmt.Errorf("invalid taint spec %s, expected key[=value]:effect", st)
+	}
+
+	switch corev1.TaintEffect(effect) {
+	case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+	default:
+		return taint, fmt.Errorf("invalid taint effect %s in %s", effect, st)
+	}
+
+	key, value, _ := strings.Cut(keyValue, "=")
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return taint, fmt.Errorf("invalid taint key in %s: %s", st, strings.Join(errs, "; "))
+	} else if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+		return taint, fmt.Errorf("invalid taint value in %s: %s", st, strings.Join(errs, "; "))
+	}
+
+	taint.Key = key
+	taint.Value = value
+	taint.Effect = corev1.TaintEffect(effect)
+	return taint, nil
+}
+
+// TranslateNode returns a copy of the host node with the labels and taints the virtual node should have
+func (r *Rules) TranslateNode(pNode *corev1.Node) *corev1.Node {
+	if r == nil {
+		return pNode
+	}
+
+	translated := pNode.DeepCopy()
+	translated.Labels = r.TranslateLabels(pNode.Labels)
+	translated.Spec.Taints = nil
+	for _, taint := range pNode.Spec.Taints {
+		if !matchesKey(r.removeTaints, taint.Key) {
+			translated.Spec.Taints = append(translated.Spec.Taints, taint)
+		}
+	}
+	for _, taint := range r.addTaints {
+		translated.Spec.Taints = upsertTaint(translated.Spec.Taints, taint)
+	}
+
+	return translated
+}
+
+// TranslateLabels translates host node labels to virtual node labels
+func (r *Rules) TranslateLabels(hostLabels map[string]string) map[string]string {
+	if r == nil {
+		return hostLabels
+	}
+
+	virtualLabels := map[string]string{}
+	for k, v := range hostLabels {
+		if virtualKey, ok := r.rewrite[k]; ok {
+			virtualLabels[virtualKey] = v
+		} else if !matchesKey(r.strip, k) {
+			virtualLabels[k] = v
+		}
+	}
+	if pool := r.PoolFor(hostLabels); pool != nil {
+		for k, v := range pool.Labels {
+			virtualLabels[k] = v
+		}
+		virtualLabels[PoolLabel] = pool.Name
+	}
+	for k, v := range r.add {
+		virtualLabels[k] = v
+	}
+
+	return virtualLabels
+}
+
+// TranslateNodeBackwards returns the host labels and taints for changes of the virtual node. Labels and taints that
+// only exist within the virtual cluster are removed, while stripped host labels and removed host taints are kept.
+func (r *Rules) TranslateNodeBackwards(pNode, vNode *corev1.Node) (map[string]string, []corev1.Taint) {
+	if r == nil {
+		return vNode.Labels, vNode.Spec.Taints
+	}
+
+	synthesized := r.syntheticLabelKeys(pNode.Labels)
+	hostLabels := map[string]string{}
+	for k, v := range pNode.Labels {
+		if _, rewritten := r.rewrite[k]; !rewritten && matchesKey(r.strip, k) {
+			hostLabels[k] = v
+		}
+	}
+	for k, v := range vNode.Labels {
+		if synthesized[k] {
+			continue
+		} else if hostKey, ok := r.reverseRewrite[k]; ok {
+			hostLabels[hostKey] = v
+			continue
+		}
+
+		hostLabels[k] = v
+	}
+
+	hostTaints := []corev1.Taint{}
+	for _, taint := range pNode.Spec.Taints {
+		if matchesKey(r.removeTaints, taint.Key) {
+			hostTaints = append(hostTaints, taint)
+		}
+	}
+	for _, taint := range vNode.Spec.Taints {
+		if !slices.ContainsFunc(r.addTaints, func(added corev1.Taint) bool { return added.MatchTaint(&taint) }) {
+			hostTaints = append(hostTaints, taint)
+		}
+	}
+
+	return hostLabels, hostTaints
+}
+
+// PoolFor returns the first pool whose selector matches the host node labels
+func (r *Rules) PoolFor(hostLabels map[string]string) *config.SyncNodePool {
+	if r == nil {
+		return nil
+	}
+
+	for i := range r.pools {
+		if labels.SelectorFromSet(r.pools[i].Selector).Matches(labels.Set(hostLabels)) {
+			return &r.pools[i]
+		}
+	}
+
+	return nil
+}
+
+// TranslatePodNodeSelection translates the node selector and the node affinity of a virtual pod to the host node
+// labels. Requirements that can't be expressed as host node selector are moved into the required node affinity.
+func (r *Rules) TranslatePodNodeSelection(nodeSelector map[string]string, affinity *corev1.Affinity) (map[string]string, *corev1.Affinity) {
+	if r == nil {
+		return nodeSelector, affinity
+	}
+
+	// translate the node selector
+	var hostNodeSelector map[string]string
+	required := []corev1.NodeSelectorTerm{{}}
+	keys := make([]string, 0, len(nodeSelector))
+	for k := range nodeSelector {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		terms := r.translateRequirement(corev1.NodeSelectorRequirement{Key: k, Operator: corev1.NodeSelectorOpIn, Values: []string{nodeSelector[k]}})
+		if len(terms) == 1 && len(terms[0].MatchFields) == 0 && allSingleValueIn(terms[0].MatchExpressions) {
+			for _, requirement := range terms[0].MatchExpressions {
+				if hostNodeSelector == nil {
+					hostNodeSelector = map[string]string{}
+				}
+				hostNodeSelector[requirement.Key] = requirement.Values[0]
+			}
+			continue
+		}
+
+		required = and(required, terms)
+	}
+	nodeSelectorMoved := !(len(required) == 1 && isEmptyTerm(required[0]))
+	if !nodeSelectorMoved && (affinity == nil || affinity.NodeAffinity == nil) {
+		return hostNodeSelector, affinity
+	}
+
+	// translate the node affinity
+	affinity = affinity.DeepCopy()
+	if affinity == nil {
+		affinity = &corev1.Affinity{}
+	}
+	if affinity.NodeAffinity == nil {
+		affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	if affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		terms := []corev1.NodeSelectorTerm{}
+		for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+			terms = append(terms, r.translateTerm(term)...)
+		}
+
+		required = and(required, terms)
+	}
+	if nodeSelectorMoved || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		if len(required) == 0 {
+			// nothing in the host cluster can match, so make sure the pod stays pending
+			affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{unsatisfiableTerm()}}
+		} else if slices.ContainsFunc(required, isEmptyTerm) {
+			// an empty term would match no node, but here it means that every node matches
+			affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = nil
+		} else {
+			affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{NodeSelectorTerms: required}
+		}
+	}
+
+	// a preference term might match several host terms, each of them gets the same weight
+	preferred := []corev1.PreferredSchedulingTerm{}
+	for _, term := range affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+		for _, translated := range r.translateTerm(term.Preference) {
+			if !isEmptyTerm(translated) {
+				preferred = append(preferred, corev1.PreferredSchedulingTerm{Weight: term.Weight, Preference: translated})
+			}
+		}
+	}
+	affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = nil
+	if len(preferred) > 0 {
+		affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = preferred
+	}
+
+	// clean up empty structs
+	if affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil && affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution == nil {
+		affinity.NodeAffinity = nil
+	}
+	if affinity.NodeAffinity == nil && affinity.PodAffinity == nil && affinity.PodAntiAffinity == nil {
+		affinity = nil
+	}
+
+	return hostNodeSelector, affinity
+}
+
+// translateTerm translates a node selector term to host terms, of which one has to match
+func (r *Rules) translateTerm(term corev1.NodeSelectorTerm) []corev1.NodeSelectorTerm {
+	translated := []corev1.NodeSelectorTerm{{MatchFields: term.MatchFields}}
+	for _, requirement := range term.MatchExpressions {
+		translated = and(translated, r.translateRequirement(requirement))
+	}
+
+	return translated
+}
+
+// translateRequirement translates a requirement on virtual node labels to host terms, of which one has to match. An
+// empty result means that no host node can match.
+func (r *Rules) translateRequirement(requirement corev1.NodeSelectorRequirement) []corev1.NodeSelectorTerm {
+	// labels that are added to all nodes are either always or never matching
+	if value, ok := r.add[requirement.Key]; ok {
+		if matches(requirement, map[string]string{requirement.Key: value}) {
+			return []corev1.NodeSelectorTerm{{}}
+		}
+
+		return nil
+	}
+
+	// pool labels match all nodes of the pools that have matching labels
+	if r.isPoolLabel(requirement.Key) {
+		terms := []corev1.NodeSelectorTerm{}
+		for i, pool := range r.pools {
+			poolLabels := map[string]string{PoolLabel: pool.Name}
+			for k, v := range pool.Labels {
+				poolLabels[k] = v
+			}
+			if !matches(requirement, poolLabels) {
+				continue
+			}
+
+			terms = append(terms, r.poolTerms(i)...)
+		}
+
+		// nodes that belong to no pool don't have any pool labels, e.g. for NotIn or DoesNotExist
+		if matches(requirement, map[string]string{}) {
+			terms = append(terms, r.unpooledTerms()...)
+		}
+
+		return terms
+	}
+
+	// rewritten labels only need the key translated
+	if hostKey, ok := r.reverseRewrite[requirement.Key]; ok {
+		requirement = *requirement.DeepCopy()
+		requirement.Key = hostKey
+	}
+
+	return []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{requirement}}}
+}
+
+// poolTerms returns the host terms that match the nodes of the pool. A node belongs to the first pool whose selector
+// matches, so the nodes of earlier overlapping pools are excluded.
+func (r *Rules) poolTerms(index int) []corev1.NodeSelectorTerm {
+	pool := r.pools[index]
+	term := corev1.NodeSelectorTerm{}
+	for _, k := range sortedKeys(pool.Selector) {
+		term.MatchExpressions = append(term.MatchExpressions, corev1.NodeSelectorRequirement{Key: k, Operator: corev1.NodeSelectorOpIn, Values: []string{pool.Selector[k]}})
+	}
+
+	terms := []corev1.NodeSelectorTerm{term}
+	for _, earlier := range r.pools[:index] {
+		exclusion := excludingTerms(earlier.Selector, pool.Selector)
+		if exclusion == nil {
+			continue
+		}
+
+		terms = and(terms, exclusion)
+	}
+
+	return terms
+}
+
+// unpooledTerms returns the host terms that match the nodes that belong to no pool
+func (r *Rules) unpooledTerms() []corev1.NodeSelectorTerm {
+	terms := []corev1.NodeSelectorTerm{{}}
+	for _, pool := range r.pools {
+		terms = and(terms, excludingTerms(pool.Selector, map[string]string{}))
+	}
+
+	return terms
+}
+
+// excludingTerms returns the terms that exclude the nodes matched by the selector from the nodes matched by the pool
+// selector, of which one has to match. It returns nil if both selectors can't match the same node.
+func excludingTerms(selector, poolSelector map[string]string) []corev1.NodeSelectorTerm {
+	terms := []corev1.NodeSelectorTerm{}
+	for _, k := range sortedKeys(selector) {
+		if poolValue, ok := poolSelector[k]; ok {
+			if poolValue != selector[k] {
+				return nil
+			}
+
+			continue
+		}
+
+		terms = append(terms, corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: k, Operator: corev1.NodeSelectorOpNotIn, Values: []string{selector[k]}}}})
+	}
+
+	return terms
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func (r *Rules) isPoolLabel(key string) bool {
+	if key == PoolLabel {
+		return true
+	}
+
+	for _, pool := range r.pools {
+		if _, ok := pool.Labels[key]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// syntheticLabelKeys returns the label keys of the virtual node that don't exist on the host node
+func (r *Rules) syntheticLabelKeys(hostLabels map[string]string) map[string]bool {
+	keys := map[string]bool{}
+	if pool := r.PoolFor(hostLabels); pool != nil {
+		keys[PoolLabel] = true
+		for k := range pool.Labels {
+			keys[k] = true
+		}
+	}
+	for k := range r.add {
+		keys[k] = true
+	}
+
+	return keys
+}
+
+// and combines two lists of alternative terms so that a term of both lists has to match
+func and(a, b []corev1.NodeSelectorTerm) []corev1.NodeSelectorTerm {
+	combined := []corev1.NodeSelectorTerm{}
+	for _, termA := range a {
+		for _, termB := range b {
+			term := corev1.NodeSelectorTerm{}
+			term.MatchExpressions = append(append(term.MatchExpressions, termA.MatchExpressions...), termB.MatchExpressions...)
+			term.MatchFields = append(append(term.MatchFields, termA.MatchFields...), termB.MatchFields...)
+			combined = append(combined, term)
+		}
+	}
+
+	return combined
+}
+
+func matches(requirement corev1.NodeSelectorRequirement, nodeLabels map[string]string) bool {
+	var op selection.Operator
+	switch requirement.Operator {
+	case corev1.NodeSelectorOpIn:
+		op = selection.In
+	case corev1.NodeSelectorOpNotIn:
+		op = selection.NotIn
+	case corev1.NodeSelectorOpExists:
+		op = selection.Exists
+	case corev1.NodeSelectorOpDoesNotExist:
+		op = selection.DoesNotExist
+	case corev1.NodeSelectorOpGt:
+		op = selection.GreaterThan
+	case corev1.NodeSelectorOpLt:
+		op = selection.LessThan
+	default:
+		return false
+	}
+
+	labelRequirement, err := labels.NewRequirement(requirement.Key, op, requirement.Values)
+	if err != nil {
+		return false
+	}
+
+	return labelRequirement.Matches(labels.Set(nodeLabels))
+}
+
+func matchesKey(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(key, prefix) {
+			return true
+		} else if pattern == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+func upsertTaint(taints []corev1.Taint, taint corev1.Taint) []corev1.Taint {
+	for i := range taints {
+		if taints[i].MatchTaint(&taint) {
+			taints[i] = taint
+			return taints
+		}
+	}
+
+	return append(taints, taint)
+}
+
+func allSingleValueIn(requirements []corev1.NodeSelectorRequirement) bool {
+	for _, requirement := range requirements {
+		if requirement.Operator != corev1.NodeSelectorOpIn || len(requirement.Values) != 1 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isEmptyTerm(term corev1.NodeSelectorTerm) bool {
+	return len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0
+}
+
+func unsatisfiableTerm() corev1.NodeSelectorTerm {
+	return corev1.NodeSelectorTerm{
+		MatchExpressions: []corev1.NodeSelectorRequirement{
+			{Key: PoolLabel, Operator: corev1.NodeSelectorOpExists},
+			{Key: PoolLabel, Operator: corev1.NodeSelectorOpDoesNotExist},
+		},
+	}
+}
@@ -0,0 +1,208 @@
+package noderules
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/config"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestRules(t *testing.T) *Rules {
+	rules, err := New(config.SyncNodes{
+		Rules: config.SyncNodeRules{
+			Labels: config.SyncNodeLabelRules{
+				Rewrite: map[string]string{"cloud.example.com/instance-type": "example.com/instance-type"},
+				Strip:   []string{"cloud.example.com/*"},
+				Add:     map[string]string{"example.com/tenant": "a"},
+			},
+			Taints: config.SyncNodeTaintRules{
+				Add:    []string{"example.com/virtual=true:NoSchedule"},
+				Remove: []string{"cloud.example.com/*"},
+			},
+		},
+		Pools: []config.SyncNodePool{
+			{Name: "gpu", Selector: map[string]string{"cloud.example.com/accelerator": "nvidia"}, Labels: map[string]string{"example.com/tier": "gpu"}},
+			{Name: "general", Selector: map[string]string{"cloud.example.com/pool": "default"}, Labels: map[string]string{"example.com/tier": "general"}},
+		},
+	})
+	assert.NilError(t, err)
+	return rules
+}
+
+func TestNew(t *testing.T) {
+	rules, err := New(config.SyncNodes{})
+	assert.NilError(t, err)
+	assert.Assert(t, rules == nil)
+
+	_, err = New(config.SyncNodes{Rules: config.SyncNodeRules{Taints: config.SyncNodeTaintRules{Add: []string{"key=value"}}}})
+	assert.ErrorContains(t, err, "invalid taint spec")
+
+	_, err = New(config.SyncNodes{Pools: []config.SyncNodePool{{Name: "a", Selector: map[string]string{"a": "b"}}, {Name: "a", Selector: map[string]string{"a": "c"}}}})
+	assert.ErrorContains(t, err, "duplicate pool")
+
+	_, err = New(config.SyncNodes{Pools: []config.SyncNodePool{{Name: "a", Selector: map[string]string{"a": "b"}}, {Name: "b", Selector: map[string]string{"a": "b", "c": "d"}}}})
+	assert.ErrorContains(t, err, "pool b never matches")
+}
+
+func TestTranslateNode(t *testing.T) {
+	rules := newTestRules(t)
+	pNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-1",
+			Labels: map[string]string{
+				"kubernetes.io/hostname":          "node-1",
+				"cloud.example.com/instance-type": "large",
+				"cloud.example.com/accelerator":   "nvidia",
+				"cloud.example.com/zone-id":       "123",
+			},
+		},
+		Spec: corev1.NodeSpec{
+			Taints: []corev1.Taint{
+				{Key: "cloud.example.com/maintenance", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "nvidia.com/gpu", Effect: corev1.TaintEffectNoSchedule},
+			},
+		},
+	}
+
+	vNode := rules.TranslateNode(pNode)
+	assert.DeepEqual(t, vNode.Labels, map[string]string{
+		"kubernetes.io/hostname":    "node-1",
+		"example.com/instance-type": "large",
+		"example.com/tier":          "gpu",
+		"example.com/tenant":        "a",
+		PoolLabel:                   "gpu",
+	})
+	assert.DeepEqual(t, vNode.Spec.Taints, []corev1.Taint{
+		{Key: "nvidia.com/gpu", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "example.com/virtual", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+	})
+
+	// changes within the virtual cluster are translated back to the host labels and taints
+	vNode.Labels["example.com/instance-type"] = "small"
+	vNode.Labels["custom"] = "label"
+	vNode.Spec.Taints = append(vNode.Spec.Taints, corev1.Taint{Key: "custom", Effect: corev1.TaintEffectNoExecute})
+	hostLabels, hostTaints := rules.TranslateNodeBackwards(pNode, vNode)
+	assert.DeepEqual(t, hostLabels, map[string]string{
+		"kubernetes.io/hostname":          "node-1",
+		"cloud.example.com/instance-type": "small",
+		"cloud.example.com/accelerator":   "nvidia",
+		"cloud.example.com/zone-id":       "123",
+		"custom":                          "label",
+	})
+	assert.DeepEqual(t, hostTaints, []corev1.Taint{
+		{Key: "cloud.example.com/maintenance", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "nvidia.com/gpu", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "custom", Effect: corev1.TaintEffectNoExecute},
+	})
+}
+
+func TestTranslatePodNodeSelection(t *testing.T) {
+	rules := newTestRules(t)
+	testCases := []struct {
+		name             string
+		nodeSelector     map[string]string
+		affinity         *corev1.Affinity
+		expectedSelector map[string]string
+		expectedAffinity *corev1.Affinity
+	}{
+		{
+			name:             "rewritten and added labels",
+			nodeSelector:     map[string]string{"example.com/instance-type": "large", "example.com/tenant": "a", "kubernetes.io/os": "linux"},
+			expectedSelector: map[string]string{"cloud.example.com/instance-type": "large", "kubernetes.io/os": "linux"},
+		},
+		{
+			name:             "pool",
+			nodeSelector:     map[string]string{PoolLabel: "gpu"},
+			expectedSelector: map[string]string{"cloud.example.com/accelerator": "nvidia"},
+		},
+		{
+			name:         "pool overlapping with an earlier pool",
+			nodeSelector: map[string]string{PoolLabel: "general"},
+			expectedAffinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{
+					{Key: "cloud.example.com/pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"default"}},
+					{Key: "cloud.example.com/accelerator", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"nvidia"}},
+				}}},
+			}}},
+		},
+		{
+			name:         "unknown pool",
+			nodeSelector: map[string]string{PoolLabel: "unknown"},
+			expectedAffinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{unsatisfiableTerm()},
+			}}},
+		},
+		{
+			name: "not in pool",
+			affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+					MatchExpressions: []corev1.NodeSelectorRequirement{{Key: PoolLabel, Operator: corev1.NodeSelectorOpNotIn, Values: []string{"gpu"}}},
+				}}},
+			}},
+			expectedAffinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{MatchExpressions: []corev1.NodeSelectorRequirement{
+						{Key: "cloud.example.com/pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"default"}},
+						{Key: "cloud.example.com/accelerator", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"nvidia"}},
+					}},
+					{MatchExpressions: []corev1.NodeSelectorRequirement{
+						{Key: "cloud.example.com/accelerator", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"nvidia"}},
+						{Key: "cloud.example.com/pool", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"default"}},
+					}},
+				}},
+			}},
+		},
+		{
+			name: "no pool",
+			affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+					MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "example.com/tier", Operator: corev1.NodeSelectorOpDoesNotExist}},
+				}}},
+			}},
+			expectedAffinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{MatchExpressions: []corev1.NodeSelectorRequirement{
+						{Key: "cloud.example.com/accelerator", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"nvidia"}},
+						{Key: "cloud.example.com/pool", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"default"}},
+					}},
+				}},
+			}},
+		},
+		{
+			name: "pool affinity",
+			affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+					MatchExpressions: []corev1.NodeSelectorRequirement{
+						{Key: "example.com/tier", Operator: corev1.NodeSelectorOpIn, Values: []string{"gpu", "general"}},
+						{Key: "example.com/instance-type", Operator: corev1.NodeSelectorOpExists},
+					},
+				}}},
+				PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{{
+					Weight:     10,
+					Preference: corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "example.com/tenant", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}}}},
+				}},
+			}},
+			expectedAffinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{MatchExpressions: []corev1.NodeSelectorRequirement{
+						{Key: "cloud.example.com/accelerator", Operator: corev1.NodeSelectorOpIn, Values: []string{"nvidia"}},
+						{Key: "cloud.example.com/instance-type", Operator: corev1.NodeSelectorOpExists},
+					}},
+					{MatchExpressions: []corev1.NodeSelectorRequirement{
+						{Key: "cloud.example.com/pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"default"}},
+						{Key: "cloud.example.com/accelerator", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"nvidia"}},
+						{Key: "cloud.example.com/instance-type", Operator: corev1.NodeSelectorOpExists},
+					}},
+				}},
+			}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		nodeSelector, affinity := rules.TranslatePodNodeSelection(testCase.nodeSelector, testCase.affinity)
+		assert.DeepEqual(t, nodeSelector, testCase.expectedSelector)
+		assert.DeepEqual(t, affinity, testCase.expectedAffinity)
+	}
+}
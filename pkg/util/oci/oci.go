@@ -0,0 +1,312 @@
+package oci
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/retry"
+)
+
+const (
+	MediaTypeDockerManifest           = "application/vnd.docker.distribution.manifest.v2+json"
+	MediaTypeDockerManifestList       = "application/vnd.docker.distribution.manifest.list.v2+json"
+	MaxLayerSize                int64 = 64 * 1024 * 1024
+
+	dockerHubRegistry = "docker.io"
+	dockerHubEndpoint = "registry-1.docker.io"
+)
+
+// Options are the options used to pull an artifact
+type Options struct {
+	Username string
+	Password string
+	Insecure bool
+}
+
+// Reference is a parsed OCI reference
+type Reference struct {
+	Registry   string
+	Repository string
+	// Tag or digest of the artifact
+	Reference string
+}
+
+func (r Reference) String() string {
+	if strings.HasPrefix(r.Reference, "sha256:") {
+		return r.Registry + "/" + r.Repository + "@" + r.Reference
+	}
+
+	return r.Registry + "/" + r.Repository + ":" + r.Reference
+}
+
+// Artifact is a pulled OCI artifact
+type Artifact struct {
+	Digest string
+	Layers []Layer
+}
+
+// Layer is a single layer of an OCI artifact
+type Layer struct {
+	MediaType   string
+	Digest      string
+	Annotations map[string]string
+	Data        []byte
+}
+
+// ParseReference parses references in the form of registry/repository:tag or registry/repository@digest. References
+// without registry point to docker hub.
+func ParseReference(ref string) (*Reference, error) {
+	ref = strings.TrimPrefix(ref, "oci://")
+	if ref == "" {
+		return nil, fmt.Errorf("empty reference")
+	}
+
+	reference := &Reference{}
+	if idx := strings.Index(ref, "@"); idx >= 0 {
+		reference.Reference = ref[idx+1:]
+		ref = ref[:idx]
+	} else if idx := strings.LastIndex(ref, ":"); idx >= 0 && !strings.Contains(ref[idx+1:], "/") {
+		reference.Reference = ref[idx+1:]
+		ref = ref[:idx]
+	} else {
+		reference.Reference = "latest"
+	}
+
+	// check if the first part is a registry
+	parts := strings.SplitN(ref, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		reference.Registry = parts[0]
+		reference.Repository = parts[1]
+	} else {
+		reference.Registry = dockerHubRegistry
+		reference.Repository = ref
+	}
+	if reference.Registry == dockerHubRegistry && !strings.Contains(reference.Repository, "/") {
+		reference.Repository = "library/" + reference.Repository
+	}
+	if reference.Repository == "" || reference.Reference == "" {
+		return nil, fmt.Errorf("invalid reference %s", ref)
+	}
+
+	return reference, nil
+}
+
+// Resolve returns the digest of the manifest the given reference points to without pulling any layers
+func Resolve(ctx context.Context, ref string, options Options) (string, error) {
+	reference, err := ParseReference(ref)
+	if err != nil {
+		return "", err
+	} else if strings.HasPrefix(reference.Reference, "sha256:") {
+		return reference.Reference, nil
+	}
+
+	var desc ocispec.Descriptor
+	err = withRepository(*reference, options, func(repository *remote.Repository) (err error) {
+		desc, err = repository.Resolve(ctx, reference.Reference)
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("resolve %s: %w", reference.String(), err)
+	}
+
+	return desc.Digest.String(), nil
+}
+
+// Pull pulls the manifest and all layers of the given artifact. The registry is not trusted, the content of every
+// manifest and layer is verified against its digest.
+func Pull(ctx context.Context, ref string, options Options) (*Artifact, error) {
+	reference, err := ParseReference(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var artifact *Artifact
+	err = withRepository(*reference, options, func(repository *remote.Repository) (err error) {
+		artifact, err = pull(ctx, repository, reference.Reference)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pull %s: %w", reference.String(), err)
+	}
+
+	return artifact, nil
+}
+
+func pull(ctx context.Context, repository *remote.Repository, reference string) (*Artifact, error) {
+	desc, rawManifest, err := fetchManifest(ctx, repository, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	// resolve index to the manifest of the current platform
+	if desc.MediaType == ocispec.MediaTypeImageIndex || desc.MediaType == MediaTypeDockerManifestList {
+		index := &ocispec.Index{}
+		err = json.Unmarshal(rawManifest, index)
+		if err != nil {
+			return nil, fmt.Errorf("parse index: %w", err)
+		}
+
+		selected, err := selectManifest(index.Manifests, runtime.GOOS, runtime.GOARCH)
+		if err != nil {
+			return nil, fmt.Errorf("index: %w", err)
+		}
+
+		desc, rawManifest, err = fetchManifest(ctx, repository, selected.Digest.String())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	manifest := &ocispec.Manifest{}
+	err = json.Unmarshal(rawManifest, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("parse manifest: %w", err)
+	}
+
+	artifact := &Artifact{
+		Digest: desc.Digest.String(),
+	}
+	for _, layer := range manifest.Layers {
+		if layer.Size > MaxLayerSize {
+			return nil, fmt.Errorf("layer %s exceeds maximum size of %d bytes", layer.Digest, MaxLayerSize)
+		} else if layer.Digest.Algorithm() != digest.SHA256 {
+			return nil, fmt.Errorf("unsupported digest algorithm in %s", layer.Digest)
+		}
+
+		data, err := content.FetchAll(ctx, repository.Blobs(), layer)
+		if err != nil {
+			return nil, fmt.Errorf("get layer %s: %w", layer.Digest, err)
+		}
+
+		artifact.Layers = append(artifact.Layers, Layer{
+			MediaType:   layer.MediaType,
+			Digest:      layer.Digest.String(),
+			Annotations: layer.Annotations,
+			Data:        data,
+		})
+	}
+
+	return artifact, nil
+}
+
+// fetchManifest fetches the manifest of the given tag or digest. Manifests that are fetched by digest have to match
+// the digest.
+func fetchManifest(ctx context.Context, repository *remote.Repository, reference string) (ocispec.Descriptor, []byte, error) {
+	desc, rc, err := repository.FetchReference(ctx, reference)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("get manifest %s: %w", reference, err)
+	}
+	defer rc.Close()
+
+	if desc.Size > MaxLayerSize {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("manifest %s exceeds maximum size of %d bytes", reference, MaxLayerSize)
+	}
+
+	data, err := content.ReadAll(rc, desc)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("get manifest %s: %w", reference, err)
+	}
+
+	return desc, data, nil
+}
+
+// selectManifest selects the manifest of the given platform from an index. Artifacts that are not platform specific
+// usually omit the platform, so a manifest without platform is used if there is no exact match.
+func selectManifest(manifests []ocispec.Descriptor, os, architecture string) (ocispec.Descriptor, error) {
+	var fallback *ocispec.Descriptor
+	for i, m := range manifests {
+		if m.Platform == nil {
+			if fallback == nil {
+				fallback = &manifests[i]
+			}
+			continue
+		}
+
+		if m.Platform.OS == os && m.Platform.Architecture == architecture {
+			return m, nil
+		}
+	}
+	if fallback != nil {
+		return *fallback, nil
+	} else if len(manifests) == 0 {
+		return ocispec.Descriptor{}, fmt.Errorf("index has no manifests")
+	}
+
+	return ocispec.Descriptor{}, fmt.Errorf("index has no manifest for platform %s/%s", os, architecture)
+}
+
+// withRepository calls fn with a client for the repository of the reference. Insecure registries are tried with
+// plain http first and then with tls without verification.
+func withRepository(reference Reference, options Options, fn func(repository *remote.Repository) error) error {
+	if !options.Insecure {
+		return fn(newRepository(reference, options, false))
+	}
+
+	err := fn(newRepository(reference, options, true))
+	if err == nil || !isConnectionError(err) {
+		return err
+	}
+
+	return fn(newRepository(reference, options, false))
+}
+
+func newRepository(reference Reference, options Options, plainHTTP bool) *remote.Repository {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if options.Insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} // #nosec G402 -- explicitly requested by the user
+	}
+
+	host := reference.Registry
+	if host == dockerHubRegistry {
+		host = dockerHubEndpoint
+	}
+
+	authClient := &auth.Client{
+		Client: &http.Client{
+			Transport: retry.NewTransport(transport),
+			Timeout:   5 * time.Minute,
+		},
+		Cache: auth.NewCache(),
+	}
+	if options.Username != "" || options.Password != "" {
+		authClient.Credential = auth.StaticCredential(host, auth.Credential{
+			Username: options.Username,
+			Password: options.Password,
+		})
+	}
+
+	return &remote.Repository{
+		Client: authClient,
+		Reference: registry.Reference{
+			Registry:   host,
+			Repository: reference.Repository,
+		},
+		PlainHTTP: plainHTTP,
+		ManifestMediaTypes: []string{
+			ocispec.MediaTypeImageManifest,
+			ocispec.MediaTypeImageIndex,
+			MediaTypeDockerManifest,
+			MediaTypeDockerManifestList,
+		},
+	}
+}
+
+// isConnectionError checks if the request failed without a response from the registry, e.g. because it only serves tls
+func isConnectionError(err error) bool {
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,152 @@
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+)
+
+func TestParseReference(t *testing.T) {
+	testTable := []struct {
+		desc string
+		ref  string
+
+		expected Reference
+	}{
+		{
+			desc:     "docker hub short",
+			ref:      "bundle",
+			expected: Reference{Registry: "docker.io", Repository: "library/bundle", Reference: "latest"},
+		},
+		{
+			desc:     "registry with tag",
+			ref:      "oci://ghcr.io/my-org/bundle:v1.0.0",
+			expected: Reference{Registry: "ghcr.io", Repository: "my-org/bundle", Reference: "v1.0.0"},
+		},
+		{
+			desc:     "registry with port and digest",
+			ref:      "localhost:5000/bundle@sha256:abc",
+			expected: Reference{Registry: "localhost:5000", Repository: "bundle", Reference: "sha256:abc"},
+		},
+		{
+			desc:     "registry with port without tag",
+			ref:      "localhost:5000/my-org/bundle",
+			expected: Reference{Registry: "localhost:5000", Repository: "my-org/bundle", Reference: "latest"},
+		},
+	}
+
+	for _, testCase := range testTable {
+		reference, err := ParseReference(testCase.ref)
+		assert.NilError(t, err, testCase.desc)
+		assert.DeepEqual(t, *reference, testCase.expected)
+	}
+}
+
+func TestPull(t *testing.T) {
+	layer := []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: test\n")
+	otherLayer := []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: other\n")
+	blobs := map[string][]byte{digest.FromBytes(layer).String(): layer, digest.FromBytes(otherLayer).String(): otherLayer}
+	manifests := map[string][]byte{}
+	mediaTypes := map[string]string{}
+	addManifest := func(mediaType string, m interface{}) digest.Digest {
+		data, err := json.Marshal(m)
+		assert.NilError(t, err)
+		manifests[digest.FromBytes(data).String()] = data
+		mediaTypes[digest.FromBytes(data).String()] = mediaType
+		return digest.FromBytes(data)
+	}
+	layerDescriptor := func(data []byte) ocispec.Descriptor {
+		return ocispec.Descriptor{MediaType: "application/yaml", Digest: digest.FromBytes(data), Size: int64(len(data))}
+	}
+
+	platformManifest := addManifest(ocispec.MediaTypeImageManifest, ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest, Layers: []ocispec.Descriptor{layerDescriptor(layer)}})
+	otherManifest := addManifest(ocispec.MediaTypeImageManifest, ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest, Layers: []ocispec.Descriptor{layerDescriptor(otherLayer)}})
+	index := addManifest(ocispec.MediaTypeImageIndex, ocispec.Index{MediaType: ocispec.MediaTypeImageIndex, Manifests: []ocispec.Descriptor{
+		{MediaType: ocispec.MediaTypeImageManifest, Digest: otherManifest, Size: int64(len(manifests[otherManifest.String()])), Platform: &ocispec.Platform{OS: runtime.GOOS, Architecture: "other"}},
+		{MediaType: ocispec.MediaTypeImageManifest, Digest: platformManifest, Size: int64(len(manifests[platformManifest.String()])), Platform: &ocispec.Platform{OS: runtime.GOOS, Architecture: runtime.GOARCH}},
+	}})
+	manifests["v1"], mediaTypes["v1"] = manifests[index.String()], mediaTypes[index.String()]
+	tampered := digest.FromBytes([]byte("tampered")).String()
+	manifests[tampered], mediaTypes[tampered] = manifests[platformManifest.String()], ocispec.MediaTypeImageManifest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if username, password, _ := req.BasicAuth(); username != "user" || password != "password" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		name := path.Base(req.URL.Path)
+		var data []byte
+		if strings.Contains(req.URL.Path, "/manifests/") && manifests[name] != nil {
+			data = manifests[name]
+			w.Header().Set("Content-Type", mediaTypes[name])
+			if !strings.HasPrefix(name, "sha256:") {
+				w.Header().Set("Docker-Content-Digest", digest.FromBytes(data).String())
+			}
+		} else if strings.Contains(req.URL.Path, "/blobs/") && blobs[name] != nil {
+			data = blobs[name]
+			w.Header().Set("Content-Type", "application/octet-stream")
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		if req.Method != http.MethodHead {
+			_, _ = w.Write(data)
+		}
+	}))
+	defer server.Close()
+	registry := strings.TrimPrefix(server.URL, "http://")
+	options := Options{Username: "user", Password: "password", Insecure: true}
+
+	// the index is resolved to the manifest of the current platform
+	artifact, err := Pull(context.TODO(), registry+"/bundle:v1", options)
+	assert.NilError(t, err)
+	assert.Equal(t, artifact.Digest, platformManifest.String())
+	assert.Equal(t, len(artifact.Layers), 1)
+	assert.DeepEqual(t, artifact.Layers[0].Data, layer)
+
+	resolved, err := Resolve(context.TODO(), registry+"/bundle:v1", options)
+	assert.NilError(t, err)
+	assert.Equal(t, resolved, index.String())
+
+	// a pinned digest has to match the manifest served by the registry
+	_, err = Pull(context.TODO(), registry+"/bundle@"+tampered, options)
+	assert.ErrorContains(t, err, "mismatch")
+
+	// credentials are required by the registry
+	_, err = Pull(context.TODO(), registry+"/bundle:v1", Options{Insecure: true})
+	assert.ErrorContains(t, err, "credential not found")
+}
+
+func TestSelectManifest(t *testing.T) {
+	amd64 := ocispec.Descriptor{Digest: "sha256:amd64", Platform: &ocispec.Platform{OS: "linux", Architecture: "amd64"}}
+	arm64 := ocispec.Descriptor{Digest: "sha256:arm64", Platform: &ocispec.Platform{OS: "linux", Architecture: "arm64"}}
+	artifact := ocispec.Descriptor{Digest: "sha256:artifact"}
+
+	selected, err := selectManifest([]ocispec.Descriptor{amd64, arm64}, "linux", "arm64")
+	assert.NilError(t, err)
+	assert.Equal(t, selected.Digest, arm64.Digest)
+
+	selected, err = selectManifest([]ocispec.Descriptor{amd64, artifact}, "linux", "arm64")
+	assert.NilError(t, err)
+	assert.Equal(t, selected.Digest, artifact.Digest)
+
+	_, err = selectManifest([]ocispec.Descriptor{amd64}, "linux", "arm64")
+	assert.ErrorContains(t, err, "no manifest for platform linux/arm64")
+
+	_, err = selectManifest(nil, "linux", "arm64")
+	assert.ErrorContains(t, err, "no manifests")
+}
@@ -0,0 +1,38 @@
+package translate
+
+import (
+	"fmt"
+
+	"github.com/loft-sh/vcluster/config"
+)
+
+// TranslateClassName resolves a storage or volume snapshot class name used within the virtual cluster with the given
+// mapping. The default of the mapping is only used if no class is set, an explicitly empty class is kept. It returns
+// false if the mapping doesn't cover the class and the caller should fall back to its default behaviour.
+func TranslateClassName(mapping config.ClassMapping, className *string) (string, bool, error) {
+	if className == nil {
+		return mapping.Default, mapping.Default != "", nil
+	}
+
+	name := *className
+	if name == "" {
+		return "", false, nil
+	}
+
+	denyAll := false
+	for _, denied := range mapping.Deny {
+		if denied == name {
+			return "", false, fmt.Errorf("class %s is not allowed within the virtual cluster", name)
+		} else if denied == "*" {
+			denyAll = true
+		}
+	}
+
+	if hostName, ok := mapping.Mappings[name]; ok {
+		return hostName, true, nil
+	} else if denyAll {
+		return "", false, fmt.Errorf("class %s is not allowed within the virtual cluster", name)
+	}
+
+	return "", false, nil
+}
@@ -6,12 +6,16 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"text/template"
+
+	"github.com/loft-sh/vcluster/config"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -23,8 +27,106 @@ func NewMultiNamespaceTranslator(currentNamespace string) Translator {
 	}
 }
 
+// NewMultiNamespaceTranslatorWithOptions creates a multi namespace translator that uses the namespace name
+// template and namespace mappings of the given multi namespace mode config
+func NewMultiNamespaceTranslatorWithOptions(currentNamespace string, options config.ExperimentalMultiNamespaceMode) (Translator, error) {
+	translator := &multiNamespace{
+		currentNamespace:  currentNamespace,
+		namespaceMappings: options.NamespaceMappings,
+		hostNamespaces:    map[string]bool{},
+	}
+	for _, hostNamespace := range options.NamespaceMappings {
+		translator.hostNamespaces[hostNamespace] = true
+	}
+
+	if options.NamespaceNameTemplate != "" {
+		var err error
+		translator.namespaceNameTemplate, err = ParseNamespaceNameTemplate(options.NamespaceNameTemplate)
+		if err != nil {
+			return nil, err
+		}
+
+		translator.namespaceNameRegEx, err = namespaceNameTemplateRegEx(translator.namespaceNameTemplate, currentNamespace)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return translator, nil
+}
+
 type multiNamespace struct {
 	currentNamespace string
+
+	// namespaceMappings maps virtual namespaces to host namespaces
+	namespaceMappings map[string]string
+	// hostNamespaces holds the host namespaces of namespaceMappings
+	hostNamespaces map[string]bool
+
+	namespaceNameTemplate *template.Template
+	namespaceNameRegEx    *regexp.Regexp
+}
+
+// NamespaceNameTemplateData is the data the namespace name template is executed with
+type NamespaceNameTemplateData struct {
+	VirtualNamespace  string
+	Hash              string
+	VClusterName      string
+	VClusterNamespace string
+}
+
+// ParseNamespaceNameTemplate parses the given namespace name template
+func ParseNamespaceNameTemplate(namespaceNameTemplate string) (*template.Template, error) {
+	parsed, err := template.New("namespace").Option("missingkey=error").Parse(namespaceNameTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("parse namespace name template: %w", err)
+	}
+
+	return parsed, nil
+}
+
+// ExecuteNamespaceNameTemplate renders the host namespace name of the given virtual namespace
+func ExecuteNamespaceNameTemplate(namespaceNameTemplate *template.Template, currentNamespace, vNamespace string) (string, error) {
+	sha := sha256.Sum256([]byte(vNamespace))
+	return executeNamespaceNameTemplate(namespaceNameTemplate, NamespaceNameTemplateData{
+		VirtualNamespace:  vNamespace,
+		Hash:              hex.EncodeToString(sha[0:])[0:8],
+		VClusterName:      VClusterName,
+		VClusterNamespace: currentNamespace,
+	})
+}
+
+func executeNamespaceNameTemplate(namespaceNameTemplate *template.Template, data NamespaceNameTemplateData) (string, error) {
+	out := &strings.Builder{}
+	err := namespaceNameTemplate.Execute(out, data)
+	if err != nil {
+		return "", fmt.Errorf("execute namespace name template: %w", err)
+	}
+
+	return out.String(), nil
+}
+
+// namespaceNameTemplateRegEx builds a regular expression that matches all namespace names the template can produce
+func namespaceNameTemplateRegEx(namespaceNameTemplate *template.Template, currentNamespace string) (*regexp.Regexp, error) {
+	const (
+		namespacePlaceholder = "\x00namespace\x00"
+		hashPlaceholder      = "\x00hash\x00"
+	)
+
+	out, err := executeNamespaceNameTemplate(namespaceNameTemplate, NamespaceNameTemplateData{
+		VirtualNamespace:  namespacePlaceholder,
+		Hash:              hashPlaceholder,
+		VClusterName:      VClusterName,
+		VClusterNamespace: currentNamespace,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	expression := regexp.QuoteMeta(out)
+	expression = strings.ReplaceAll(expression, regexp.QuoteMeta(namespacePlaceholder), "[a-z0-9]([-a-z0-9]*[a-z0-9])?")
+	expression = strings.ReplaceAll(expression, regexp.QuoteMeta(hashPlaceholder), "[0-9a-f]{8}")
+	return regexp.Compile("^" + expression + "$")
 }
 
 func (s *multiNamespace) SingleNamespaceTarget() bool {
@@ -90,6 +192,12 @@ func (s *multiNamespace) IsManagedCluster(obj runtime.Object) bool {
 }
 
 func (s *multiNamespace) IsTargetedNamespace(ns string) bool {
+	if s.hostNamespaces[ns] {
+		return true
+	} else if s.namespaceNameRegEx != nil {
+		return s.namespaceNameRegEx.MatchString(ns)
+	}
+
 	return strings.HasPrefix(ns, s.getNamespacePrefix()) && strings.HasSuffix(ns, getNamespaceSuffix(s.currentNamespace, VClusterName))
 }
 
@@ -103,9 +211,25 @@ func (s *multiNamespace) getNamespacePrefix() string {
 }
 
 func (s *multiNamespace) PhysicalNamespace(vNamespace string) string {
+	if hostNamespace, ok := s.namespaceMappings[vNamespace]; ok {
+		return hostNamespace
+	} else if s.namespaceNameTemplate != nil {
+		hostNamespace, err := ExecuteNamespaceNameTemplate(s.namespaceNameTemplate, s.currentNamespace, vNamespace)
+		if err == nil {
+			return hostNamespace
+		}
+
+		klog.Errorf("Error translating namespace %s: %v", vNamespace, err)
+	}
+
 	return PhysicalNamespace(s.currentNamespace, vNamespace, s.getNamespacePrefix(), VClusterName)
 }
 
+// IsSystemNamespace checks if the host namespace belongs to Kubernetes itself and must never be used by a vCluster
+func IsSystemNamespace(namespace string) bool {
+	return namespace == metav1.NamespaceDefault || strings.HasPrefix(namespace, "kube-")
+}
+
 func PhysicalNamespace(currentNamespace, vNamespace, prefix, suffix string) string {
 	sha := sha256.Sum256([]byte(vNamespace))
 	return fmt.Sprintf("%s-%s-%s", prefix, hex.EncodeToString(sha[0:])[0:8], getNamespaceSuffix(currentNamespace, suffix))
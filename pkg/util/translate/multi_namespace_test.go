@@ -0,0 +1,44 @@
+package translate
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/config"
+	"gotest.tools/v3/assert"
+)
+
+func TestMultiNamespacePhysicalNamespace(t *testing.T) {
+	translator, err := NewMultiNamespaceTranslatorWithOptions("vcluster", config.ExperimentalMultiNamespaceMode{
+		NamespaceNameTemplate: "vc-{{ .VClusterName }}-{{ .VirtualNamespace }}",
+		NamespaceMappings: map[string]string{
+			"team-a": "prod-team-a-ns",
+		},
+	})
+	assert.NilError(t, err)
+
+	testCases := []struct {
+		vNamespace       string
+		expected         string
+		expectedTargeted bool
+	}{
+		{
+			vNamespace:       "team-a",
+			expected:         "prod-team-a-ns",
+			expectedTargeted: true,
+		},
+		{
+			vNamespace:       "default",
+			expected:         "vc-" + VClusterName + "-default",
+			expectedTargeted: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		pNamespace := translator.PhysicalNamespace(testCase.vNamespace)
+		assert.Equal(t, pNamespace, testCase.expected, testCase.vNamespace)
+		assert.Equal(t, translator.IsTargetedNamespace(pNamespace), testCase.expectedTargeted, testCase.vNamespace)
+	}
+
+	assert.Equal(t, translator.IsTargetedNamespace("kube-system"), false)
+	assert.Equal(t, translator.IsTargetedNamespace("vc-other-default"), false)
+}
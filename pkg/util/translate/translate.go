@@ -31,6 +31,10 @@ var (
 	LabelPrefix     = "vcluster.loft.sh/label"
 	ControllerLabel = "vcluster.loft.sh/controlled-by"
 
+	// AdoptedLabel marks host namespaces that existed before and were adopted by vCluster in multi namespace mode.
+	// Adopted namespaces are not deleted by vCluster.
+	AdoptedLabel = "vcluster.loft.sh/adopted"
+
 	// VClusterName is the vcluster name, usually set at start time
 	VClusterName = "suffix"
 